@@ -0,0 +1,301 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/audit"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type auditReportMsg struct {
+	report *audit.Report
+}
+
+type auditExportedMsg struct {
+	path string
+	err  error
+}
+
+// auditVisibleFindings 列表一次显示的问题数
+const auditVisibleFindings = 8
+
+// AuditModel 安全审计：只读检查、评分，并可跳转到修复界面或导出报告
+type AuditModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	running bool
+	report  *audit.Report
+	cursor  int
+
+	exportPath string
+	exportErr  error
+}
+
+func NewAuditModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) AuditModel {
+	return AuditModel{
+		parent:  parent,
+		cfg:     cfg,
+		logger:  logger,
+		running: true,
+	}
+}
+
+func (m AuditModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.runCmd())
+}
+
+func (m AuditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case auditReportMsg:
+		m.running = false
+		m.report = msg.report
+		m.cursor = 0
+		return m, nil
+
+	case auditExportedMsg:
+		m.exportPath = msg.path
+		m.exportErr = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.running {
+			return m, nil
+		}
+
+		switch msg.Type {
+		case tea.KeyEsc:
+			return m.parent, nil
+		case tea.KeyUp:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case tea.KeyDown:
+			if m.cursor < len(m.report.Findings)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case tea.KeyEnter:
+			if len(m.report.Findings) == 0 {
+				return m, nil
+			}
+			if next := auditFixModel(m.report.Findings[m.cursor].Fix, m.parent, m.cfg, m.logger); next != nil {
+				return next, next.Init()
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "r":
+			m.running = true
+			m.exportPath, m.exportErr = "", nil
+			return m, m.runCmd()
+		case "t":
+			return m, m.exportCmd(audit.FormatText)
+		case "m":
+			return m, m.exportCmd(audit.FormatMarkdown)
+		case "j":
+			return m, m.exportCmd(audit.FormatJSON)
+		}
+	}
+
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m AuditModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("audit_title")) + "\n\n")
+
+	if m.running {
+		b.WriteString(tui.InfoStyle.Render(i18n.T("audit_running")) + "\n")
+		return tui.BorderStyle.Width(62).Render(b.String())
+	}
+
+	r := m.report
+	scoreStyle := tui.SuccessStyle
+	switch {
+	case r.Score < 60:
+		scoreStyle = tui.ErrorStyle
+	case r.Score < 90:
+		scoreStyle = tui.WarningStyle
+	}
+	b.WriteString(scoreStyle.Render(i18n.T("audit_score", r.Score, r.Grade)) + "\n")
+
+	var skipped int
+	for _, c := range r.Checks {
+		if c.Status == audit.StatusSkipped {
+			skipped++
+		}
+	}
+	b.WriteString(tui.DimStyle.Render(i18n.T("audit_checks", len(r.Checks), len(r.Findings), skipped)) + "\n\n")
+
+	if len(r.Findings) == 0 {
+		b.WriteString(tui.SuccessStyle.Render(i18n.T("audit_no_findings")) + "\n")
+	}
+
+	start := max(0, m.cursor-auditVisibleFindings+1)
+	end := min(len(r.Findings), start+auditVisibleFindings)
+	for i := start; i < end; i++ {
+		f := r.Findings[i]
+		line := fmt.Sprintf("%-8s %s", strings.ToUpper(string(f.Severity)), auditTitle(f))
+		if i == m.cursor {
+			b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + severityStyle(f.Severity).Render(line) + "\n")
+		}
+	}
+
+	if len(r.Findings) > 0 {
+		f := r.Findings[m.cursor]
+		b.WriteString("\n")
+		if f.Detail != "" {
+			b.WriteString(tui.NormalStyle.Render(f.Detail) + "\n")
+		}
+		if f.Fix != "" {
+			b.WriteString(tui.InfoStyle.Render(i18n.T("audit_fix", auditFixLabel(f.Fix))) + "\n")
+		} else {
+			b.WriteString(tui.DimStyle.Render(i18n.T("audit_fix_manual")) + "\n")
+		}
+	}
+
+	for _, c := range r.Checks {
+		if c.Status == audit.StatusSkipped {
+			b.WriteString(tui.DimStyle.Render(i18n.T("audit_skipped", c.ID, c.Reason)) + "\n")
+		}
+	}
+
+	if m.exportErr != nil {
+		b.WriteString("\n" + tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.exportErr)) + "\n")
+	} else if m.exportPath != "" {
+		b.WriteString("\n" + tui.SuccessStyle.Render(i18n.T("audit_exported", m.exportPath)) + "\n")
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("audit_keys")) + "\n")
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+// auditTitle 问题标题（有翻译时使用翻译，否则使用报告中的英文标题）
+func auditTitle(f audit.Finding) string {
+	key := "audit_" + strings.ReplaceAll(f.ID, ".", "_")
+	if title := i18n.T(key); title != key {
+		return title
+	}
+	return f.Title
+}
+
+func severityStyle(s audit.Severity) lipgloss.Style {
+	switch s {
+	case audit.SeverityCritical, audit.SeverityHigh:
+		return tui.ErrorStyle
+	case audit.SeverityMedium:
+		return tui.WarningStyle
+	}
+	return tui.NormalStyle
+}
+
+// auditFixLabel 修复动作对应的菜单路径
+func auditFixLabel(fix string) string {
+	switch fix {
+	case audit.FixDisablePassword:
+		return i18n.T("menu_ssh") + " > " + i18n.T("ssh_disable_pwd")
+	case audit.FixListKeys:
+		return i18n.T("menu_ssh") + " > " + i18n.T("ssh_list_keys")
+	case audit.FixAutoUpgrade:
+		return i18n.T("menu_system") + " > " + i18n.T("menu_autoupgrade")
+	case audit.FixPackages:
+		return i18n.T("menu_system") + " > " + i18n.T("menu_packages")
+	case audit.FixServices:
+		return i18n.T("menu_system") + " > " + i18n.T("menu_services")
+	}
+	return fix
+}
+
+// auditFixModel 修复动作对应的界面；无对应界面时返回 nil
+func auditFixModel(fix string, parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) tea.Model {
+	switch fix {
+	case audit.FixDisablePassword:
+		return NewSSHDisablePasswordModel(parent, cfg, logger)
+	case audit.FixListKeys:
+		return NewSSHListKeysModel(parent, cfg, logger)
+	case audit.FixAutoUpgrade:
+		return NewAutoUpgradeWizard(parent, cfg, logger)
+	case audit.FixPackages:
+		return NewPackagesModel(parent, cfg, logger)
+	case audit.FixServices:
+		return NewServicesModel(parent, cfg, logger)
+	}
+	return nil
+}
+
+func (m AuditModel) runCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		return auditReportMsg{report: audit.NewAuditor(logger).Run()}
+	}
+}
+
+// exportCmd 把报告写入当前目录
+func (m AuditModel) exportCmd(format audit.Format) tea.Cmd {
+	report := m.report
+	return func() tea.Msg {
+		data, err := report.Export(format)
+		if err != nil {
+			return auditExportedMsg{err: err}
+		}
+		name := fmt.Sprintf("audit-%s-%s%s", report.Hostname, report.GeneratedAt.Format("20060102-150405"), format.Ext())
+		path, err := filepath.Abs(name)
+		if err != nil {
+			return auditExportedMsg{err: err}
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return auditExportedMsg{err: fmt.Errorf("failed to write %s: %w", path, err)}
+		}
+		return auditExportedMsg{path: path}
+	}
+}
+
+// runAuditCommand 命令行：server-toolkit audit [-format text|markdown|json] [-o FILE]
+func runAuditCommand(args []string, logger *internal.Logger) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	formatName := fs.String("format", "text", "report format: text, markdown or json")
+	output := fs.String("o", "", "write the report to FILE instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	format, err := audit.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	report := audit.NewAuditor(logger).Run()
+
+	data, err := report.Export(format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if *output == "" {
+		_, _ = os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
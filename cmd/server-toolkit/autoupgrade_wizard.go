@@ -0,0 +1,398 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/autoupgrade"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type autoUpgradeStep int
+
+const (
+	autoUpgradeStepLoading autoUpgradeStep = iota
+	autoUpgradeStepStatus
+	autoUpgradeStepRebootConfirm
+	autoUpgradeStepRebootTime
+	autoUpgradeStepEmail
+	autoUpgradeStepApplyConfirm
+	autoUpgradeStepApplying
+	autoUpgradeStepResult
+)
+
+type autoUpgradeStatusMsg struct {
+	distro *system.DistroInfo
+	status *autoupgrade.Status
+	err    error
+}
+
+type autoUpgradeAppliedMsg struct{ err error }
+
+// AutoUpgradeWizardModel 自动安全更新：查看状态 + 一步启用
+type AutoUpgradeWizardModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step autoUpgradeStep
+
+	distro    *system.DistroInfo
+	current   *autoupgrade.Status
+	statusErr error
+
+	autoReboot    bool
+	timeInput     textinput.Model
+	emailInput    textinput.Model
+	confirmCursor int // 0: No, 1: Yes
+	status        string
+
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewAutoUpgradeWizard(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) AutoUpgradeWizardModel {
+	timeTI := textinput.New()
+	timeTI.Placeholder = "02:00"
+	timeTI.CharLimit = 5
+	timeTI.Width = 50
+
+	emailTI := textinput.New()
+	emailTI.Placeholder = "root@example.com"
+	emailTI.CharLimit = 254
+	emailTI.Width = 50
+
+	return AutoUpgradeWizardModel{
+		parent:        parent,
+		cfg:           cfg,
+		logger:        logger,
+		step:          autoUpgradeStepLoading,
+		timeInput:     timeTI,
+		emailInput:    emailTI,
+		confirmCursor: 1,
+	}
+}
+
+func (m AutoUpgradeWizardModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(tea.Batch(textinput.Blink, m.loadStatusCmd()))
+}
+
+func (m AutoUpgradeWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case autoUpgradeStatusMsg:
+		m.distro = msg.distro
+		m.current = msg.status
+		m.statusErr = msg.err
+		if m.current != nil {
+			m.autoReboot = m.current.AutoReboot
+			if m.current.RebootTime != "" {
+				m.timeInput.SetValue(m.current.RebootTime)
+			}
+			if m.current.Email != "" {
+				m.emailInput.SetValue(m.current.Email)
+			}
+		}
+		m.step = autoUpgradeStepStatus
+		return m, nil
+
+	case autoUpgradeAppliedMsg:
+		m.resultErr = msg.err
+		m.step = autoUpgradeStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case autoUpgradeStepLoading, autoUpgradeStepApplying:
+			return m, nil
+
+		case autoUpgradeStepStatus:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyEnter:
+				if m.statusErr != nil || m.current == nil || !m.current.Supported {
+					return m.parent, nil
+				}
+				m.confirmCursor = 0
+				if m.autoReboot {
+					m.confirmCursor = 1
+				}
+				m.step = autoUpgradeStepRebootConfirm
+				return m, nil
+			}
+
+		case autoUpgradeStepRebootConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = autoUpgradeStepStatus
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				m.autoReboot = (m.confirmCursor == 1)
+				if m.autoReboot {
+					m.timeInput.Focus()
+					m.step = autoUpgradeStepRebootTime
+				} else {
+					m.emailInput.Focus()
+					m.step = autoUpgradeStepEmail
+				}
+				return m, nil
+			}
+
+		case autoUpgradeStepRebootTime:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.status = ""
+				m.timeInput.Blur()
+				m.step = autoUpgradeStepRebootConfirm
+				return m, nil
+			case tea.KeyEnter:
+				m.status = ""
+				if err := autoupgrade.ValidateRebootTime(strings.TrimSpace(m.timeInput.Value())); err != nil {
+					m.status = i18n.T("autoupgrade_invalid_time")
+					return m, nil
+				}
+				m.timeInput.Blur()
+				m.emailInput.Focus()
+				m.step = autoUpgradeStepEmail
+				return m, nil
+			}
+
+		case autoUpgradeStepEmail:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.status = ""
+				m.emailInput.Blur()
+				if m.autoReboot {
+					m.timeInput.Focus()
+					m.step = autoUpgradeStepRebootTime
+				} else {
+					m.step = autoUpgradeStepRebootConfirm
+				}
+				return m, nil
+			case tea.KeyEnter:
+				m.status = ""
+				email := strings.TrimSpace(m.emailInput.Value())
+				if err := autoupgrade.ValidateEmail(email); err != nil {
+					m.status = i18n.T("autoupgrade_invalid_email")
+					return m, nil
+				}
+				m.emailInput.Blur()
+				m.confirmCursor = 1
+				m.step = autoUpgradeStepApplyConfirm
+				return m, nil
+			}
+
+		case autoUpgradeStepApplyConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					return m.parent, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = autoUpgradeStepApplying
+				return m, m.applyCmd()
+			}
+
+		case autoUpgradeStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				return m.parent, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.step {
+	case autoUpgradeStepRebootTime:
+		m.timeInput, cmd = m.timeInput.Update(msg)
+	case autoUpgradeStepEmail:
+		m.emailInput, cmd = m.emailInput.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+func (m AutoUpgradeWizardModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("autoupgrade_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case autoUpgradeStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case autoUpgradeStepStatus:
+		b.WriteString(m.statusView())
+
+	case autoUpgradeStepRebootConfirm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("autoupgrade_reboot_prompt")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case autoUpgradeStepRebootTime:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("autoupgrade_reboot_time")) + "\n")
+		b.WriteString(m.timeInput.View() + "\n")
+
+	case autoUpgradeStepEmail:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("autoupgrade_email")) + "\n")
+		b.WriteString(m.emailInput.View() + "\n")
+
+	case autoUpgradeStepApplyConfirm:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("autoupgrade_actions")) + "\n")
+		for _, line := range m.actionLines() {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("autoupgrade_confirm_apply")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case autoUpgradeStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("autoupgrade_applying")) + "\n")
+
+	case autoUpgradeStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("autoupgrade_success", m.backend())) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("autoupgrade_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	if m.status != "" {
+		b.WriteString("\n" + tui.ErrorStyle.Render(m.status) + "\n")
+	}
+
+	switch m.step {
+	case autoUpgradeStepStatus, autoUpgradeStepRebootConfirm, autoUpgradeStepRebootTime, autoUpgradeStepEmail, autoUpgradeStepApplyConfirm:
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")+" / "+i18n.T("press_esc")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m AutoUpgradeWizardModel) statusView() string {
+	var b strings.Builder
+
+	if m.statusErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.statusErr)) + "\n")
+		return b.String()
+	}
+	if m.current == nil || !m.current.Supported {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("autoupgrade_unsupported")) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(tui.SubtitleStyle.Render(i18n.T("autoupgrade_status")) + "\n")
+	lines := []string{
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_backend"), m.current.Backend),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_installed"), onOff(m.current.Installed)),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_enabled"), onOff(m.current.Enabled)),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_active"), onOff(m.current.Active)),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_auto_reboot"), rebootLabel(m.current.AutoReboot, m.current.RebootTime)),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_notify"), emailLabel(m.current.Email)),
+	}
+	for _, line := range lines {
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+	}
+	b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("autoupgrade_enable_prompt")) + "\n")
+	return b.String()
+}
+
+func (m AutoUpgradeWizardModel) backend() string {
+	if m.current == nil {
+		return ""
+	}
+	return m.current.Backend
+}
+
+func (m AutoUpgradeWizardModel) options() autoupgrade.Options {
+	opts := autoupgrade.Options{
+		AutoReboot: m.autoReboot,
+		Email:      strings.TrimSpace(m.emailInput.Value()),
+	}
+	if m.autoReboot {
+		opts.RebootTime = strings.TrimSpace(m.timeInput.Value())
+	}
+	return opts
+}
+
+func (m AutoUpgradeWizardModel) actionLines() []string {
+	opts := m.options()
+	return []string{
+		i18n.T("autoupgrade_action_install", m.backend()),
+		i18n.T("autoupgrade_action_config"),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_auto_reboot"), rebootLabel(opts.AutoReboot, opts.RebootTime)),
+		fmt.Sprintf("%s: %s", i18n.T("autoupgrade_notify"), emailLabel(opts.Email)),
+		i18n.T("autoupgrade_action_service"),
+	}
+}
+
+func rebootLabel(enabled bool, at string) string {
+	if !enabled {
+		return i18n.T("no")
+	}
+	if at == "" {
+		return i18n.T("yes")
+	}
+	return fmt.Sprintf("%s (%s)", i18n.T("yes"), at)
+}
+
+func emailLabel(email string) string {
+	if email == "" {
+		return i18n.T("no")
+	}
+	return email
+}
+
+func (m AutoUpgradeWizardModel) loadStatusCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		distro, err := system.DetectDistro()
+		if err != nil {
+			return autoUpgradeStatusMsg{err: err}
+		}
+		status, err := autoupgrade.NewManager(distro, true, logger).Status()
+		return autoUpgradeStatusMsg{distro: distro, status: status, err: err}
+	}
+}
+
+func (m AutoUpgradeWizardModel) applyCmd() tea.Cmd {
+	distro := m.distro
+	opts := m.options()
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return autoUpgradeAppliedMsg{err: autoupgrade.NewManager(distro, dryRun, logger).Enable(opts)}
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/cloudinit"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type cloudInitExportStep int
+
+const (
+	cloudInitExportStepLoading cloudInitExportStep = iota
+	cloudInitExportStepPreview
+	cloudInitExportStepPath
+	cloudInitExportStepSaving
+	cloudInitExportStepResult
+)
+
+type cloudInitSpecMsg struct {
+	spec *cloudinit.Spec
+	err  error
+}
+
+type cloudInitSavedMsg struct {
+	path string
+	err  error
+}
+
+// CloudInitExportModel 将当前主机名、用户公钥与 sshd 选项导出为 #cloud-config user-data
+type CloudInitExportModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step cloudInitExportStep
+
+	spec      *cloudinit.Spec
+	rendered  []byte
+	renderErr error
+
+	preview   viewport.Model
+	pathInput textinput.Model
+
+	savedPath string
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewCloudInitExportModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) CloudInitExportModel {
+	ti := textinput.New()
+	ti.Placeholder = "user-data"
+	ti.CharLimit = 256
+	ti.Width = 50
+	if wd, err := os.Getwd(); err == nil {
+		ti.SetValue(filepath.Join(wd, "user-data"))
+	}
+
+	return CloudInitExportModel{
+		parent:    parent,
+		cfg:       cfg,
+		logger:    logger,
+		step:      cloudInitExportStepLoading,
+		preview:   viewport.New(58, 14),
+		pathInput: ti,
+	}
+}
+
+func (m CloudInitExportModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.collectCmd())
+}
+
+func (m CloudInitExportModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case cloudInitSpecMsg:
+		m.spec = msg.spec
+		m.renderErr = msg.err
+		if msg.err == nil {
+			m.render()
+		}
+		m.step = cloudInitExportStepPreview
+		return m, nil
+
+	case cloudInitSavedMsg:
+		m.savedPath = msg.path
+		m.resultErr = msg.err
+		m.step = cloudInitExportStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case cloudInitExportStepLoading, cloudInitExportStepSaving:
+			return m, nil
+
+		case cloudInitExportStepPreview:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyRunes:
+				if m.spec == nil {
+					return m, nil
+				}
+				switch msg.String() {
+				case "s":
+					if m.renderErr == nil {
+						m.step = cloudInitExportStepPath
+						m.pathInput.Focus()
+						return m, textinput.Blink
+					}
+				case "d":
+					m.spec.KeepDefaultUser = !m.spec.KeepDefaultUser
+					m.render()
+				case "m":
+					m.spec.ManageEtcHosts = !m.spec.ManageEtcHosts
+					m.render()
+				case "p":
+					m.spec.PasswordAuth = !m.spec.PasswordAuth
+					m.render()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+
+		case cloudInitExportStepPath:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.pathInput.Blur()
+				m.step = cloudInitExportStepPreview
+				return m, nil
+			case tea.KeyEnter:
+				path := strings.TrimSpace(m.pathInput.Value())
+				if path == "" {
+					return m, nil
+				}
+				m.pathInput.Blur()
+				m.logStart = logMark(m.cfg)
+				m.step = cloudInitExportStepSaving
+				return m, m.saveCmd(path)
+			}
+
+		case cloudInitExportStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				return m.parent, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == cloudInitExportStepPath {
+		m.pathInput, cmd = m.pathInput.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+// render 按当前 spec 重新生成 user-data 并刷新预览
+func (m *CloudInitExportModel) render() {
+	m.rendered, m.renderErr = cloudinit.Render(m.spec)
+	if m.renderErr == nil {
+		m.preview.SetContent(string(m.rendered))
+		m.preview.GotoTop()
+	}
+}
+
+func (m CloudInitExportModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("cloudinit_export_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case cloudInitExportStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case cloudInitExportStepPreview:
+		if m.spec == nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.renderErr)) + "\n")
+			b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+			break
+		}
+		b.WriteString(m.summaryView())
+		if m.renderErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.renderErr)) + "\n")
+		} else {
+			b.WriteString(m.preview.View() + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("cloudinit_export_keys")) + "\n")
+
+	case cloudInitExportStepPath:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("cloudinit_export_path")) + "\n\n")
+		b.WriteString(m.pathInput.View() + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("cloudinit_export_path_hint")) + "\n")
+
+	case cloudInitExportStepSaving:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("cloudinit_export_saving")) + "\n")
+
+	case cloudInitExportStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("cloudinit_export_saved", m.savedPath)) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("cloudinit_export_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m CloudInitExportModel) summaryView() string {
+	var b strings.Builder
+	keys := 0
+	for _, u := range m.spec.Users {
+		keys += len(u.AuthorizedKeys)
+	}
+	lines := []string{
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_export_users"), i18n.T("cloudinit_export_users_value", len(m.spec.Users), keys)),
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_export_default_user"), onOff(m.spec.KeepDefaultUser)),
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_export_manage_hosts"), onOff(m.spec.ManageEtcHosts)),
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_export_pwauth"), onOff(m.spec.PasswordAuth)),
+	}
+	for _, line := range lines {
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m CloudInitExportModel) collectCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		spec, err := cloudinit.Collect(defaultUsername(), logger)
+		return cloudInitSpecMsg{spec: spec, err: err}
+	}
+}
+
+func (m CloudInitExportModel) saveCmd(path string) tea.Cmd {
+	data := m.rendered
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return cloudInitSavedMsg{path: path, err: err}
+		}
+		return cloudInitSavedMsg{path: abs, err: cloudinit.WriteUserData(abs, data, dryRun, logger)}
+	}
+}
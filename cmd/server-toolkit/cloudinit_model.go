@@ -0,0 +1,318 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/cloudinit"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type cloudInitStep int
+
+const (
+	cloudInitStepLoading cloudInitStep = iota
+	cloudInitStepStatus
+	cloudInitStepConfig
+	cloudInitStepConfirm
+	cloudInitStepApplying
+	cloudInitStepResult
+)
+
+type cloudInitReportMsg struct {
+	report *cloudinit.Report
+	err    error
+}
+
+type cloudInitAppliedMsg struct{ err error }
+
+// cloudInitWatchedModules 与本工具修改相关、需要重点展示的模块
+var cloudInitWatchedModules = map[string]bool{
+	"set_hostname":     true,
+	"update_hostname":  true,
+	"update_etc_hosts": true,
+	"ssh":              true,
+	"set_passwords":    true,
+	"users_groups":     true,
+	"write_files":      true,
+}
+
+// CloudInitModel cloud-init：状态、数据源、将执行的模块、合并配置与禁用
+type CloudInitModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step cloudInitStep
+
+	report    *cloudinit.Report
+	reportErr error
+
+	config viewport.Model
+
+	confirmCursor int // 0: No, 1: Yes
+
+	resultMsg string
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewCloudInitModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) CloudInitModel {
+	return CloudInitModel{
+		parent: parent,
+		cfg:    cfg,
+		logger: logger,
+		step:   cloudInitStepLoading,
+		config: viewport.New(58, 16),
+	}
+}
+
+func (m CloudInitModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.inspectCmd())
+}
+
+func (m CloudInitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case cloudInitReportMsg:
+		m.report = msg.report
+		m.reportErr = msg.err
+		m.step = cloudInitStepStatus
+		return m, nil
+
+	case cloudInitAppliedMsg:
+		m.resultErr = msg.err
+		m.step = cloudInitStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case cloudInitStepLoading, cloudInitStepApplying:
+			return m, nil
+
+		case cloudInitStepStatus:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyRunes:
+				switch msg.String() {
+				case "r":
+					m.step = cloudInitStepLoading
+					return m, m.inspectCmd()
+				case "c":
+					if m.report != nil && m.report.Config != nil {
+						content, err := cloudinit.RenderConfig(m.report.Config)
+						if err != nil {
+							content = err.Error()
+						}
+						m.config.SetContent(content)
+						m.config.GotoTop()
+						m.step = cloudInitStepConfig
+					}
+				case "x":
+					if m.report != nil && m.report.Present {
+						m.confirmCursor = 0
+						m.step = cloudInitStepConfirm
+					}
+				}
+				return m, nil
+			}
+
+		case cloudInitStepConfig:
+			switch msg.Type {
+			case tea.KeyEsc, tea.KeyEnter:
+				m.step = cloudInitStepStatus
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.config, cmd = m.config.Update(msg)
+			return m, cmd
+
+		case cloudInitStepConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = cloudInitStepStatus
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = cloudInitStepStatus
+					return m, nil
+				}
+				enable := m.report.Disabled
+				m.resultMsg = i18n.T("cloudinit_disabled_done")
+				if enable {
+					m.resultMsg = i18n.T("cloudinit_enabled_done")
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = cloudInitStepApplying
+				return m, m.toggleCmd(enable)
+			}
+
+		case cloudInitStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = cloudInitStepLoading
+				return m, m.inspectCmd()
+			}
+		}
+	}
+
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m CloudInitModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("cloudinit_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case cloudInitStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case cloudInitStepStatus:
+		b.WriteString(m.statusView())
+
+	case cloudInitStepConfig:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("cloudinit_merged_config")) + "\n")
+		b.WriteString(m.config.View() + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("cloudinit_config_keys")) + "\n")
+
+	case cloudInitStepConfirm:
+		prompt := i18n.T("cloudinit_confirm_disable")
+		if m.report.Disabled {
+			prompt = i18n.T("cloudinit_confirm_enable")
+		}
+		b.WriteString(tui.NormalStyle.Render(prompt) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case cloudInitStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("cloudinit_applying")) + "\n")
+
+	case cloudInitStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(m.resultMsg) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("cloudinit_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m CloudInitModel) statusView() string {
+	var b strings.Builder
+
+	if m.reportErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.reportErr)) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+		return b.String()
+	}
+	if m.report == nil || !m.report.Present {
+		b.WriteString(tui.InfoStyle.Render(i18n.T("cloudinit_not_installed")) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+		return b.String()
+	}
+
+	r := m.report
+	status := r.Status
+	if status == "" {
+		status = "-"
+	}
+	datasource := r.Datasource
+	if datasource == "" {
+		datasource = "-"
+	}
+	lines := []string{
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_status"), status),
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_datasource"), datasource),
+		fmt.Sprintf("%s: %s", i18n.T("cloudinit_disabled"), onOff(r.Disabled)),
+		fmt.Sprintf("%s: %d", i18n.T("cloudinit_config_files"), len(r.ConfigFiles)),
+	}
+	for _, line := range lines {
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+	}
+	for _, e := range r.Errors {
+		b.WriteString("  " + tui.ErrorStyle.Render(e) + "\n")
+	}
+
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("cloudinit_modules")) + "\n")
+	shown := 0
+	for _, mod := range r.Modules {
+		if !cloudInitWatchedModules[mod.Name] {
+			continue
+		}
+		line := mod.Name
+		if mod.Frequency != "" {
+			line += " (" + mod.Frequency + ")"
+		}
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		shown++
+	}
+	if shown == 0 {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("cloudinit_no_modules")) + "\n")
+	}
+
+	if len(r.Warnings) > 0 && !r.Disabled {
+		b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("cloudinit_warnings")) + "\n")
+		for _, w := range r.Warnings {
+			msg := i18n.T("cloudinit_warn_"+w.Kind, w.Module, w.Detail)
+			if w.NextInstance {
+				b.WriteString("  " + tui.DimStyle.Render("· "+msg+i18n.T("cloudinit_warn_next_instance")) + "\n")
+				continue
+			}
+			b.WriteString("  " + tui.WarningStyle.Render("! "+msg) + "\n")
+		}
+	}
+
+	keys := i18n.T("cloudinit_keys_disable")
+	if r.Disabled {
+		keys = i18n.T("cloudinit_keys_enable")
+	}
+	b.WriteString("\n" + tui.DimStyle.Render(keys) + "\n")
+	return b.String()
+}
+
+func (m CloudInitModel) inspectCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		report, err := cloudinit.NewManager(true, logger).Inspect()
+		return cloudInitReportMsg{report: report, err: err}
+	}
+}
+
+func (m CloudInitModel) toggleCmd(enable bool) tea.Cmd {
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		mgr := cloudinit.NewManager(dryRun, logger)
+		if enable {
+			return cloudInitAppliedMsg{err: mgr.Enable()}
+		}
+		return cloudInitAppliedMsg{err: mgr.Disable()}
+	}
+}
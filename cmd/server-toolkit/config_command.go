@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+)
+
+const configUsage = `usage:
+  server-toolkit config get [key]        print the effective config (or one key)
+  server-toolkit config set <key> <value> validate and save one key (to the user config when not root)
+  server-toolkit config validate [file]   check the config file and SERVER_TOOLKIT_* overrides`
+
+// runConfigCommand 处理 config get|set|validate；返回退出码：0 成功，1 配置无效，2 用法或其他错误
+func runConfigCommand(args []string, cfg *internal.Config) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, configUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "get":
+		return configGet(args[1:], cfg)
+	case "set":
+		return configSet(args[1:])
+	case "validate":
+		return configValidate(args[1:])
+	}
+	fmt.Fprintln(os.Stderr, configUsage)
+	return 2
+}
+
+func configGet(args []string, cfg *internal.Config) int {
+	switch len(args) {
+	case 0:
+		for _, key := range internal.ConfigKeys() {
+			value, _ := cfg.Get(key)
+			if cfg.Overridden(key) {
+				value += "  (" + internal.EnvPrefix + "*)"
+			}
+			fmt.Printf("%s = %s\n", key, value)
+		}
+		return 0
+	case 1:
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+		fmt.Println(value)
+		return 0
+	}
+	fmt.Fprintln(os.Stderr, configUsage)
+	return 2
+}
+
+// configSet 修改配置文件中的值（不含环境变量覆盖），非 root 时写入用户配置；其他配置项无效时拒绝写入
+func configSet(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, configUsage)
+		return 2
+	}
+	key, value := args[0], args[1]
+
+	path := strings.Join(internal.ConfigLayers(), ", ")
+	cfg, err := internal.LoadFiles()
+	if err != nil {
+		verrs, ok := err.(internal.ValidationErrors)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+		for _, fe := range verrs {
+			if fe.Key != key {
+				fmt.Fprintf(os.Stderr, "error: %s: %v\n", path, err)
+				return 1
+			}
+		}
+	}
+
+	if err := cfg.Set(key, value); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := internal.Save(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	return 0
+}
+
+func configValidate(args []string) int {
+	switch len(args) {
+	case 0:
+	case 1:
+		internal.SetConfigPath(args[0])
+	default:
+		fmt.Fprintln(os.Stderr, configUsage)
+		return 2
+	}
+
+	path := strings.Join(internal.ConfigLayers(), ", ")
+	if _, err := internal.Load(); err != nil {
+		verrs, ok := err.(internal.ValidationErrors)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+		for _, fe := range verrs {
+			fmt.Printf("%s: %v\n", path, fe)
+		}
+		return 1
+	}
+	fmt.Printf("%s: ok\n", path)
+	return 0
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"language": "fr", "log_level": "WARN"}`), 0644))
+	internal.SetConfigPath(path)
+	t.Cleanup(func() { internal.SetConfigPath("") })
+
+	assert.Equal(t, 1, runConfigCommand([]string{"validate"}, internal.Default()))
+
+	// 其他配置项无效时拒绝写入；修正出错的配置项本身是允许的
+	assert.Equal(t, 1, runConfigCommand([]string{"set", "dry_run", "true"}, internal.Default()))
+	assert.Equal(t, 1, runConfigCommand([]string{"set", "language", "de"}, internal.Default()))
+	assert.Equal(t, 0, runConfigCommand([]string{"set", "language", "en_US"}, internal.Default()))
+	assert.Equal(t, 0, runConfigCommand([]string{"validate"}, internal.Default()))
+
+	cfg, err := internal.LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "en_US", cfg.Language)
+	assert.Equal(t, "WARN", cfg.LogLevel)
+
+	assert.Equal(t, 0, runConfigCommand([]string{"get", "log_level"}, cfg))
+	assert.Equal(t, 2, runConfigCommand([]string{"get", "nope"}, cfg))
+	assert.Equal(t, 2, runConfigCommand([]string{"frobnicate"}, cfg))
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+const publicIPTimeout = 3 * time.Second
+
+// dashboardRefreshInterval 重新读取系统信息的间隔（刷新 ticker 更频繁，读取全部挂载点与接口没有必要那么频繁）
+const dashboardRefreshInterval = 5 * time.Second
+
+type dashboardInfoMsg struct {
+	info *system.SystemInfo
+	err  error
+}
+
+type dashboardPublicIPMsg struct {
+	ip  string
+	err error
+}
+
+// DashboardModel 系统信息面板（随刷新 ticker 定期更新）
+type DashboardModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	info    *system.SystemInfo
+	infoErr error
+
+	publicIP    string
+	publicIPErr error
+	loading     bool
+
+	// refreshing 系统信息读取中；refreshedAt 上次读取完成的时间
+	refreshing  bool
+	refreshedAt time.Time
+}
+
+func NewDashboardModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) DashboardModel {
+	return DashboardModel{
+		parent:     parent,
+		cfg:        cfg,
+		logger:     logger,
+		loading:    true,
+		refreshing: true,
+	}
+}
+
+func (m DashboardModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(tea.Batch(loadSystemInfoCmd(), m.loadPublicIPCmd()))
+}
+
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case dashboardInfoMsg:
+		m.info = msg.info
+		m.infoErr = msg.err
+		m.loading = false
+		m.refreshing = false
+		m.refreshedAt = time.Now()
+		return m, nil
+
+	case dashboardPublicIPMsg:
+		m.publicIP = msg.ip
+		m.publicIPErr = msg.err
+		return m, nil
+
+	case tui.RefreshMenuMsg:
+		var cmd tea.Cmd
+		if !m.refreshing && time.Since(m.refreshedAt) >= dashboardRefreshInterval {
+			m.refreshing = true
+			cmd = loadSystemInfoCmd()
+		}
+		return m, keepRefreshTickerCmd(msg, cmd)
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEsc, tea.KeyEnter:
+			return m.parent, nil
+		case tea.KeyRunes:
+			if msg.String() == "r" {
+				m.publicIPErr = nil
+				m.refreshing = true
+				return m, tea.Batch(loadSystemInfoCmd(), m.loadPublicIPCmd())
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m DashboardModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("dashboard_title")) + "\n\n")
+
+	switch {
+	case m.loading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+	case m.infoErr != nil:
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.infoErr)) + "\n")
+	case m.info != nil:
+		b.WriteString(m.infoView())
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("dashboard_keys")) + "\n")
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m DashboardModel) infoView() string {
+	info := m.info
+	var b strings.Builder
+
+	row := func(label, value string) {
+		if value == "" {
+			value = "N/A"
+		}
+		b.WriteString("  " + tui.NormalStyle.Render(fmt.Sprintf("%-10s %s", label, value)) + "\n")
+	}
+
+	b.WriteString(tui.SubtitleStyle.Render(i18n.T("dashboard_system")) + "\n")
+	row(i18n.T("dashboard_hostname"), info.Hostname)
+	row(i18n.T("dashboard_os"), info.OS)
+	row(i18n.T("dashboard_kernel"), info.Kernel)
+	row(i18n.T("dashboard_virt"), info.Virtualization)
+	row(i18n.T("dashboard_uptime"), system.FormatUptime(info.Uptime))
+
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("dashboard_resources")) + "\n")
+	cpu := info.CPU.Model
+	if info.CPU.Cores > 0 {
+		cpu = fmt.Sprintf("%d x %s", info.CPU.Cores, cpu)
+	}
+	row(i18n.T("dashboard_cpu"), cpu)
+	row(i18n.T("dashboard_load"), fmt.Sprintf("%.2f %.2f %.2f", info.Load.One, info.Load.Five, info.Load.Fifteen))
+	if info.Memory.Total > 0 {
+		row(i18n.T("dashboard_memory"), usageLabel(info.Memory.Used(), info.Memory.Total))
+	}
+	if info.Memory.SwapTotal > 0 {
+		row(i18n.T("dashboard_swap"), usageLabel(info.Memory.SwapTotal-info.Memory.SwapFree, info.Memory.SwapTotal))
+	}
+
+	if len(info.Disks) > 0 {
+		b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("dashboard_disks")) + "\n")
+		for _, d := range info.Disks {
+			row(d.Mount, fmt.Sprintf("%s (%s)", usageLabel(d.Used, d.Total), d.FSType))
+		}
+	}
+
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("dashboard_network")) + "\n")
+	for _, iface := range info.Interfaces {
+		state := i18n.T("dashboard_down")
+		if iface.Up {
+			state = i18n.T("dashboard_up")
+		}
+		addrs := strings.Join(iface.Addrs, ", ")
+		if addrs == "" {
+			addrs = "-"
+		}
+		row(iface.Name, fmt.Sprintf("[%s] %s", state, addrs))
+	}
+	row(i18n.T("dashboard_private_ip"), strings.Join(info.PrivateIPs, ", "))
+	row(i18n.T("dashboard_public_ip"), m.publicIPLabel())
+
+	return b.String()
+}
+
+// publicIPEnabled 是否查询公网 IP：需在配置中开启，dry-run 与离线根目录（--root）下不访问外部服务
+func (m DashboardModel) publicIPEnabled() bool {
+	return m.cfg != nil && m.cfg.PublicIPLookup && !m.cfg.DryRun && !system.HasRoot()
+}
+
+func (m DashboardModel) publicIPLabel() string {
+	switch {
+	case m.cfg == nil || !m.cfg.PublicIPLookup:
+		return i18n.T("dashboard_public_ip_disabled")
+	case !m.publicIPEnabled():
+		return i18n.T("dashboard_public_ip_skipped")
+	}
+	if m.publicIPErr != nil {
+		return i18n.T("dashboard_public_ip_failed")
+	}
+	if m.publicIP == "" {
+		return i18n.T("loading")
+	}
+	return m.publicIP
+}
+
+// usageLabel 格式化为 "used / total (xx%)"
+func usageLabel(used, total uint64) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s / %s (%.0f%%)", system.FormatBytes(used), system.FormatBytes(total), float64(used)*100/float64(total))
+}
+
+func loadSystemInfoCmd() tea.Cmd {
+	return func() tea.Msg {
+		info, err := system.GetSystemInfo()
+		return dashboardInfoMsg{info: info, err: err}
+	}
+}
+
+func (m DashboardModel) loadPublicIPCmd() tea.Cmd {
+	if !m.publicIPEnabled() {
+		return nil
+	}
+	logger := m.logger
+	return func() tea.Msg {
+		ip, err := system.GetPublicIP(publicIPTimeout)
+		if err != nil && logger != nil {
+			logger.Debug("Public IP lookup failed: %v", err)
+		}
+		return dashboardPublicIPMsg{ip: ip, err: err}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardThrottlesRefresh(t *testing.T) {
+	i18n.Init()
+	m := NewDashboardModel(tui.NewMenu("main", "", nil), internal.Default(), internal.NewLogger(internal.ERROR, os.Stdout))
+
+	next, _ := m.Update(dashboardInfoMsg{info: &system.SystemInfo{}})
+	m = next.(DashboardModel)
+	require.False(t, m.refreshing)
+
+	// 刷新 ticker 比读取间隔频繁：间隔内不重新读取
+	next, _ = m.Update(tui.RefreshMenuMsg{})
+	m = next.(DashboardModel)
+	assert.False(t, m.refreshing)
+
+	m.refreshedAt = time.Now().Add(-dashboardRefreshInterval)
+	next, _ = m.Update(tui.RefreshMenuMsg{})
+	m = next.(DashboardModel)
+	assert.True(t, m.refreshing)
+}
+
+func TestDashboardPublicIPLookupIsOptIn(t *testing.T) {
+	i18n.Init()
+	cfg := internal.Default()
+	m := NewDashboardModel(tui.NewMenu("main", "", nil), cfg, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	assert.False(t, m.publicIPEnabled())
+	assert.Nil(t, m.loadPublicIPCmd())
+	assert.Equal(t, i18n.T("dashboard_public_ip_disabled"), m.publicIPLabel())
+
+	cfg.PublicIPLookup = true
+	assert.True(t, m.publicIPEnabled())
+
+	// dry-run 下不访问外部服务
+	cfg.DryRun = true
+	assert.False(t, m.publicIPEnabled())
+	assert.Equal(t, i18n.T("dashboard_public_ip_skipped"), m.publicIPLabel())
+}
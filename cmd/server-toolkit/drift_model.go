@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/drift"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type driftStep int
+
+const (
+	driftStepLoading driftStep = iota
+	driftStepList
+	driftStepConfirm
+	driftStepApplying
+	driftStepResult
+)
+
+type driftReportMsg struct {
+	report *drift.Report
+	err    error
+}
+
+type driftAppliedMsg struct {
+	err error
+}
+
+// driftVisibleItems 列表一次显示的漂移项数
+const driftVisibleItems = 8
+
+// DriftModel 配置漂移：比较当前配置与上次应用的期望值，并可重新应用
+type DriftModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step   driftStep
+	report *drift.Report
+	err    error
+	cursor int
+
+	confirmCursor int // 0: No, 1: Yes
+
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewDriftModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) DriftModel {
+	return DriftModel{
+		parent: parent,
+		cfg:    cfg,
+		logger: logger,
+		step:   driftStepLoading,
+	}
+}
+
+func (m DriftModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.detectCmd())
+}
+
+func (m DriftModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case driftReportMsg:
+		m.report = msg.report
+		m.err = msg.err
+		m.cursor = 0
+		m.step = driftStepList
+		return m, nil
+
+	case driftAppliedMsg:
+		m.resultErr = msg.err
+		m.step = driftStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case driftStepLoading, driftStepApplying:
+			return m, nil
+
+		case driftStepList:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyUp:
+				if m.cursor > 0 {
+					m.cursor--
+				}
+			case tea.KeyDown:
+				if m.report != nil && m.cursor < len(m.report.Items)-1 {
+					m.cursor++
+				}
+			case tea.KeyEnter:
+				if m.report != nil && len(m.report.Items) > 0 {
+					m.confirmCursor = 0
+					m.step = driftStepConfirm
+				}
+			case tea.KeyRunes:
+				if msg.String() == "r" {
+					m.step = driftStepLoading
+					return m, m.detectCmd()
+				}
+			}
+			return m, nil
+
+		case driftStepConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = driftStepList
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = driftStepList
+					return m, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = driftStepApplying
+				return m, m.reapplyCmd()
+			}
+			return m, nil
+
+		case driftStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = driftStepLoading
+				return m, m.detectCmd()
+			}
+			return m, nil
+		}
+	}
+
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m DriftModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("drift_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case driftStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case driftStepList:
+		if m.err != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.err)) + "\n")
+			b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+			break
+		}
+		r := m.report
+		b.WriteString(tui.DimStyle.Render(i18n.T("drift_state", r.StatePath)) + "\n")
+		switch {
+		case !r.Recorded:
+			b.WriteString("\n" + tui.InfoStyle.Render(i18n.T("drift_no_state")) + "\n")
+		case len(r.Items) == 0:
+			b.WriteString(tui.DimStyle.Render(i18n.T("drift_updated", r.Updated.Format("2006-01-02 15:04:05"))) + "\n")
+			b.WriteString("\n" + tui.SuccessStyle.Render(i18n.T("drift_none")) + "\n")
+		default:
+			b.WriteString(tui.DimStyle.Render(i18n.T("drift_updated", r.Updated.Format("2006-01-02 15:04:05"))) + "\n")
+			b.WriteString("\n" + tui.WarningStyle.Render(i18n.T("drift_found", len(r.Items))) + "\n\n")
+			start := max(0, m.cursor-driftVisibleItems+1)
+			end := min(len(r.Items), start+driftVisibleItems)
+			for i := start; i < end; i++ {
+				line := fmt.Sprintf("%-16s %s", driftKindLabel(r.Items[i].Kind), r.Items[i].Key)
+				if i == m.cursor {
+					b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+				} else {
+					b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+				}
+			}
+
+			item := r.Items[m.cursor]
+			have := item.Have
+			if have == "" {
+				have = i18n.T("drift_missing")
+			}
+			b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("drift_want", item.WantLabel())) + "\n")
+			b.WriteString(tui.NormalStyle.Render(i18n.T("drift_have", have)) + "\n")
+			b.WriteString(tui.DimStyle.Render(item.Path) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("drift_keys")) + "\n")
+
+	case driftStepConfirm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("drift_confirm", len(m.report.Items))) + "\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case driftStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("drift_applying")) + "\n")
+
+	case driftStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("drift_applied")) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+// driftKindLabel 漂移项类型的显示文本
+func driftKindLabel(kind drift.Kind) string {
+	return i18n.T("drift_kind_" + string(kind))
+}
+
+func (m DriftModel) detectCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		report, err := drift.NewManager(true, logger).Detect()
+		return driftReportMsg{report: report, err: err}
+	}
+}
+
+func (m DriftModel) reapplyCmd() tea.Cmd {
+	report := m.report
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return driftAppliedMsg{err: drift.NewManager(dryRun, logger).Reapply(report)}
+	}
+}
+
+// runDriftCommand 命令行：server-toolkit drift [-format text|json] [-apply]
+// 退出码：0 无漂移（或已重新应用），1 存在漂移，2 出错
+func runDriftCommand(args []string, cfg *internal.Config, logger *internal.Logger) int {
+	fs := flag.NewFlagSet("drift", flag.ContinueOnError)
+	formatName := fs.String("format", "text", "output format: text or json")
+	apply := fs.Bool("apply", false, "re-apply the recorded values")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *formatName != "text" && *formatName != "json" {
+		fmt.Fprintf(os.Stderr, "error: unknown output format: %q (expected text or json)\n", *formatName)
+		return 2
+	}
+
+	mgr := drift.NewManager(cfg.DryRun, logger)
+	report, err := mgr.Detect()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if *formatName == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Print(report.Text())
+	}
+
+	if len(report.Items) == 0 {
+		return 0
+	}
+	if !*apply {
+		return 1
+	}
+	if needsRoot(cfg) {
+		fmt.Fprintf(os.Stderr, "error: %s\n", i18n.T("root_required_desc"))
+		return 2
+	}
+	if err := mgr.Reapply(report); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/fail2ban"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type fail2banStep int
+
+const (
+	fail2banStepLoading fail2banStep = iota
+	fail2banStepStatus
+	fail2banStepEnableConfirm
+	fail2banStepUnbanConfirm
+	fail2banStepApplying
+	fail2banStepResult
+)
+
+type fail2banStatusMsg struct {
+	distro *system.DistroInfo
+	status *fail2ban.Status
+	jail   *fail2ban.Jail
+	err    error
+}
+
+type fail2banAppliedMsg struct{ err error }
+
+// Fail2banModel fail2ban：查看状态 / 启用 sshd jail / 解封 IP
+type Fail2banModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step fail2banStep
+
+	distro    *system.DistroInfo
+	current   *fail2ban.Status
+	jail      *fail2ban.Jail
+	statusErr error
+
+	ipCursor      int
+	unbanIP       string
+	confirmCursor int // 0: No, 1: Yes
+
+	resultMsg string
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewFail2banModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) Fail2banModel {
+	return Fail2banModel{
+		parent:        parent,
+		cfg:           cfg,
+		logger:        logger,
+		step:          fail2banStepLoading,
+		confirmCursor: 1,
+	}
+}
+
+func (m Fail2banModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.loadStatusCmd())
+}
+
+func (m Fail2banModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case fail2banStatusMsg:
+		m.distro = msg.distro
+		m.current = msg.status
+		m.jail = msg.jail
+		m.statusErr = msg.err
+		if m.ipCursor >= len(m.bannedIPs()) {
+			m.ipCursor = 0
+		}
+		m.step = fail2banStepStatus
+		return m, nil
+
+	case fail2banAppliedMsg:
+		m.resultErr = msg.err
+		m.step = fail2banStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case fail2banStepLoading, fail2banStepApplying:
+			return m, nil
+
+		case fail2banStepStatus:
+			ips := m.bannedIPs()
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyUp:
+				if m.ipCursor > 0 {
+					m.ipCursor--
+				}
+				return m, nil
+			case tea.KeyDown:
+				if m.ipCursor < len(ips)-1 {
+					m.ipCursor++
+				}
+				return m, nil
+			case tea.KeyEnter:
+				if len(ips) > 0 {
+					m.unbanIP = ips[m.ipCursor]
+					m.confirmCursor = 0
+					m.step = fail2banStepUnbanConfirm
+				}
+				return m, nil
+			case tea.KeyRunes:
+				switch msg.String() {
+				case "e":
+					if m.statusErr == nil && m.jail != nil {
+						m.confirmCursor = 1
+						m.step = fail2banStepEnableConfirm
+					}
+				case "r":
+					m.step = fail2banStepLoading
+					return m, m.loadStatusCmd()
+				}
+				return m, nil
+			}
+
+		case fail2banStepEnableConfirm, fail2banStepUnbanConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = fail2banStepStatus
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = fail2banStepStatus
+					return m, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				cmd := m.enableCmd()
+				m.resultMsg = i18n.T("fail2ban_enabled")
+				if m.step == fail2banStepUnbanConfirm {
+					cmd = m.unbanCmd(m.unbanIP)
+					m.resultMsg = i18n.T("fail2ban_unbanned", m.unbanIP)
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = fail2banStepApplying
+				return m, cmd
+			}
+
+		case fail2banStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = fail2banStepLoading
+				return m, m.loadStatusCmd()
+			}
+		}
+	}
+
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m Fail2banModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("fail2ban_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case fail2banStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case fail2banStepStatus:
+		b.WriteString(m.statusView())
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("fail2ban_keys")) + "\n")
+
+	case fail2banStepEnableConfirm:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("fail2ban_actions")) + "\n")
+		for _, line := range m.actionLines() {
+			b.WriteString("  " + line + "\n")
+		}
+		b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("fail2ban_confirm_apply")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case fail2banStepUnbanConfirm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("fail2ban_confirm_unban", m.unbanIP)) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case fail2banStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("fail2ban_applying")) + "\n")
+
+	case fail2banStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(m.resultMsg) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("fail2ban_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m Fail2banModel) statusView() string {
+	var b strings.Builder
+
+	if m.statusErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.statusErr)) + "\n")
+		return b.String()
+	}
+	if m.current == nil {
+		return b.String()
+	}
+
+	b.WriteString(tui.SubtitleStyle.Render(i18n.T("fail2ban_status")) + "\n")
+	lines := []string{
+		fmt.Sprintf("%s: %s", i18n.T("fail2ban_installed"), onOff(m.current.Installed)),
+		fmt.Sprintf("%s: %s", i18n.T("fail2ban_active"), onOff(m.current.Active)),
+		fmt.Sprintf("%s: %s", i18n.T("fail2ban_configured"), onOff(m.current.Configured)),
+	}
+	if m.jail != nil {
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", i18n.T("fail2ban_jail"), strings.Join(m.jail.Ports, ","), m.jail.Backend))
+	}
+	if jail := m.current.Jail; jail != nil {
+		lines = append(lines, i18n.T("fail2ban_counters", jail.CurrentlyFailed, jail.CurrentlyBanned, jail.TotalBanned))
+	}
+	for _, line := range lines {
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+	}
+
+	ips := m.bannedIPs()
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("fail2ban_banned")) + "\n")
+	if len(ips) == 0 {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("fail2ban_no_banned")) + "\n")
+	}
+	for i, ip := range ips {
+		if i == m.ipCursor {
+			b.WriteString(tui.SelectedStyle.Render("> "+ip) + "\n")
+		} else {
+			b.WriteString("  " + tui.NormalStyle.Render(ip) + "\n")
+		}
+	}
+	return b.String()
+}
+
+func (m Fail2banModel) bannedIPs() []string {
+	if m.current == nil || m.current.Jail == nil {
+		return nil
+	}
+	return m.current.Jail.BannedIPs
+}
+
+func (m Fail2banModel) actionLines() []string {
+	opts := fail2ban.DefaultOptions()
+	lines := []string{i18n.T("fail2ban_action_install")}
+	if m.jail != nil {
+		lines = append(lines, i18n.T("fail2ban_action_jail", strings.Join(m.jail.Ports, ","), m.jail.Backend))
+	}
+	lines = append(lines,
+		i18n.T("fail2ban_action_limits", opts.MaxRetry, opts.FindTime, opts.BanTime),
+		i18n.T("fail2ban_action_service"),
+	)
+	return lines
+}
+
+func (m Fail2banModel) loadStatusCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		distro, err := system.DetectDistro()
+		if err != nil {
+			return fail2banStatusMsg{err: err}
+		}
+		mgr := fail2ban.NewManager(distro, true, logger)
+		status, err := mgr.Status()
+		if err != nil {
+			return fail2banStatusMsg{distro: distro, err: err}
+		}
+		jail, err := mgr.PlanJail(fail2ban.DefaultOptions())
+		return fail2banStatusMsg{distro: distro, status: status, jail: jail, err: err}
+	}
+}
+
+func (m Fail2banModel) enableCmd() tea.Cmd {
+	distro := m.distro
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return fail2banAppliedMsg{err: fail2ban.NewManager(distro, dryRun, logger).Enable(fail2ban.DefaultOptions())}
+	}
+}
+
+func (m Fail2banModel) unbanCmd(ip string) tea.Cmd {
+	distro := m.distro
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return fail2banAppliedMsg{err: fail2ban.NewManager(distro, dryRun, logger).Unban(ip)}
+	}
+}
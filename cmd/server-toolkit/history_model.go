@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type historyLoadedMsg struct {
+	records []history.Record
+	err     error
+}
+
+// historyPageSize 列表一次显示的记录数
+const historyPageSize = 8
+
+// HistoryModel 操作历史：浏览审计日志（最新在前），按关键字过滤并查看文件校验和
+type HistoryModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	loading bool
+	records []history.Record // 最新在前
+	err     error
+	cursor  int
+
+	filter     textinput.Model
+	filtering  bool
+	errorsOnly bool
+}
+
+func NewHistoryModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) HistoryModel {
+	ti := textinput.New()
+	ti.Placeholder = i18n.T("history_filter_placeholder")
+	ti.CharLimit = 64
+	ti.Width = 40
+
+	return HistoryModel{
+		parent:  parent,
+		cfg:     cfg,
+		logger:  logger,
+		loading: true,
+		filter:  ti,
+	}
+}
+
+func (m HistoryModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(loadHistoryCmd())
+}
+
+func loadHistoryCmd() tea.Cmd {
+	return func() tea.Msg {
+		records, err := history.Load()
+		// 反转为最新在前
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+		return historyLoadedMsg{records: records, err: err}
+	}
+}
+
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case historyLoadedMsg:
+		m.loading = false
+		m.records = msg.records
+		m.err = msg.err
+		m.cursor = 0
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.loading {
+			return m, nil
+		}
+
+		if m.filtering {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.filter.SetValue("")
+				fallthrough
+			case tea.KeyEnter:
+				m.filtering = false
+				m.filter.Blur()
+				m.cursor = 0
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filter, cmd = m.filter.Update(msg)
+			m.cursor = 0
+			return m, cmd
+		}
+
+		visible := m.visible()
+		switch msg.Type {
+		case tea.KeyEsc:
+			return m.parent, nil
+		case tea.KeyUp:
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case tea.KeyDown:
+			if m.cursor < len(visible)-1 {
+				m.cursor++
+			}
+		case tea.KeyPgUp:
+			m.cursor = max(m.cursor-historyPageSize, 0)
+		case tea.KeyPgDown:
+			m.cursor = max(min(m.cursor+historyPageSize, len(visible)-1), 0)
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "/":
+				m.filtering = true
+				m.filter.Focus()
+				return m, textinput.Blink
+			case "e":
+				m.errorsOnly = !m.errorsOnly
+				m.cursor = 0
+			case "r":
+				m.loading = true
+				return m, loadHistoryCmd()
+			}
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if m.filtering {
+		m.filter, cmd = m.filter.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+// visible 按关键字与“仅失败”过滤后的记录
+func (m HistoryModel) visible() []history.Record {
+	var out []history.Record
+	for _, r := range m.records {
+		if m.errorsOnly && r.Result != history.ResultError {
+			continue
+		}
+		if r.Matches(m.filter.Value()) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (m HistoryModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("history_title")) + "\n\n")
+
+	if m.loading {
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+		return tui.BorderStyle.Width(62).Render(b.String())
+	}
+
+	b.WriteString(tui.DimStyle.Render(i18n.T("history_path", history.Path())) + "\n\n")
+	if m.err != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.err)) + "\n\n")
+	}
+	if m.filtering || m.filter.Value() != "" {
+		b.WriteString(tui.NormalStyle.Render(i18n.T("services_filter")) + " " + m.filter.View() + "\n")
+	}
+	if m.errorsOnly {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("history_errors_only")) + "\n")
+	}
+
+	visible := m.visible()
+	if len(visible) == 0 {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("history_empty")) + "\n")
+	}
+
+	start := max(0, m.cursor-historyPageSize+1)
+	end := min(len(visible), start+historyPageSize)
+	for i := start; i < end; i++ {
+		r := visible[i]
+		line := fmt.Sprintf("%s %-9s %s", r.Time.Local().Format("01-02 15:04"), r.Module, r.Operation)
+		switch {
+		case i == m.cursor:
+			b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+		case r.Result == history.ResultError:
+			b.WriteString("  " + tui.ErrorStyle.Render(line) + "\n")
+		default:
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+	}
+
+	if len(visible) > 0 {
+		b.WriteString("\n" + historyDetail(visible[m.cursor]))
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("history_keys")) + "\n")
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+// historyDetail 选中记录的操作者、详情与文件校验和
+func historyDetail(r history.Record) string {
+	var b strings.Builder
+	b.WriteString(tui.NormalStyle.Render(i18n.T("history_time", r.Time.Local().Format("2006-01-02 15:04:05"))) + "\n")
+	b.WriteString(tui.NormalStyle.Render(i18n.T("history_actor", r.Actor.String())) + "\n")
+	if r.Actor.TTY != "" {
+		b.WriteString(tui.DimStyle.Render(i18n.T("history_tty", r.Actor.TTY)) + "\n")
+	}
+	if r.Detail != "" {
+		b.WriteString(tui.NormalStyle.Render(r.Detail) + "\n")
+	}
+	if r.Root != "" {
+		b.WriteString(tui.DimStyle.Render(i18n.T("root_target", r.Root)) + "\n")
+	}
+	for _, f := range r.Files {
+		style := tui.DimStyle
+		if f.Changed() {
+			style = tui.InfoStyle
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%s  %s → %s", f.Path, shortSum(f.Before), shortSum(f.After))) + "\n")
+	}
+	if r.Result == history.ResultError {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", r.Error)) + "\n")
+	}
+	return b.String()
+}
+
+// shortSum 校验和前 8 位；文件不存在时显示 "-"
+func shortSum(sum string) string {
+	if sum == "" {
+		return "-"
+	}
+	return sum[:min(8, len(sum))]
+}
@@ -19,12 +19,29 @@ type hostnameWizardStep int
 const (
 	hostnameWizardStepShort hostnameWizardStep = iota
 	hostnameWizardStepFQDN
+	hostnameWizardStepPretty
+	hostnameWizardStepOptions
 	hostnameWizardStepCloudInitConfirm
 	hostnameWizardStepApplyConfirm
 	hostnameWizardStepApplying
 	hostnameWizardStepResult
 )
 
+type hostnameWizardImpactMsg struct {
+	report *hostnameModule.ImpactReport
+	err    error
+}
+
+type hostnameWizardSuggestMsg struct {
+	suggestions []hostnameModule.Suggestion
+	err         error
+}
+
+type hostnameWizardForwardMsg struct {
+	fqdn string
+	err  error
+}
+
 type hostnameWizardAppliedMsg struct {
 	err     error
 	summary string
@@ -41,11 +58,31 @@ type HostnameWizardModel struct {
 
 	step hostnameWizardStep
 
-	shortInput textinput.Model
-	fqdnInput  textinput.Model
+	shortInput  textinput.Model
+	fqdnInput   textinput.Model
+	prettyInput textinput.Model
+
+	short  string
+	fqdn   string
+	pretty string
+
+	// 主机名类型（hostnamectl --static/--transient）
+	setStatic    bool
+	setTransient bool
+
+	// 引用旧主机名的配置：扫描结果与勾选的路径
+	impact       *hostnameModule.ImpactReport
+	impactErr    error
+	impactSelect map[string]bool
+	optionCursor int
 
-	short string
-	fqdn  string
+	// 候选主机名（PTR / 云元数据 / 命名模板）
+	suggestions   []hostnameModule.Suggestion
+	suggestCursor int
+	suggestErr    error // 命名模板无法确定空闲序号
+
+	// FQDN 正向解析检查结果（不阻止执行，仅提示）
+	forwardErr error
 
 	cloudInitPresent  bool
 	preserveCloudInit bool
@@ -55,6 +92,7 @@ type HostnameWizardModel struct {
 
 	resultErr     error
 	resultSummary string
+	logStart      int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
 }
 
 func NewHostnameWizard(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger, doHostname, doHosts bool) HostnameWizardModel {
@@ -69,6 +107,11 @@ func NewHostnameWizard(parent tui.MenuModel, cfg *internal.Config, logger *inter
 	fqdnTI.CharLimit = 253
 	fqdnTI.Width = 50
 
+	prettyTI := textinput.New()
+	prettyTI.Placeholder = "Web Server 01"
+	prettyTI.CharLimit = 64
+	prettyTI.Width = 50
+
 	return HostnameWizardModel{
 		parent: parent,
 		cfg:    cfg,
@@ -79,8 +122,13 @@ func NewHostnameWizard(parent tui.MenuModel, cfg *internal.Config, logger *inter
 
 		step: hostnameWizardStepShort,
 
-		shortInput: shortTI,
-		fqdnInput:  fqdnTI,
+		shortInput:  shortTI,
+		fqdnInput:   fqdnTI,
+		prettyInput: prettyTI,
+
+		setStatic:    true,
+		setTransient: true,
+		impactSelect: make(map[string]bool),
 
 		cloudInitPresent:  hostnameModule.IsPresent(),
 		preserveCloudInit: false,
@@ -91,11 +139,36 @@ func NewHostnameWizard(parent tui.MenuModel, cfg *internal.Config, logger *inter
 }
 
 func (m HostnameWizardModel) Init() tea.Cmd {
-	return initRefreshTickerCmd(textinput.Blink)
+	if !m.doHostname {
+		return initRefreshTickerCmd(tea.Batch(textinput.Blink, m.suggestCmd()))
+	}
+	return initRefreshTickerCmd(tea.Batch(textinput.Blink, m.suggestCmd(), m.scanImpactCmd()))
 }
 
 func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case hostnameWizardImpactMsg:
+		m.impact = msg.report
+		m.impactErr = msg.err
+		if msg.report != nil {
+			// 默认全部勾选可自动更新的项
+			for _, item := range msg.report.Updatable() {
+				m.impactSelect[item.Path] = true
+			}
+		}
+		return m, nil
+
+	case hostnameWizardSuggestMsg:
+		m.suggestions = msg.suggestions
+		m.suggestErr = msg.err
+		return m, nil
+
+	case hostnameWizardForwardMsg:
+		if msg.fqdn == m.fqdn {
+			m.forwardErr = msg.err
+		}
+		return m, nil
+
 	case hostnameWizardAppliedMsg:
 		m.resultErr = msg.err
 		m.resultSummary = msg.summary
@@ -113,6 +186,24 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyEsc:
 				return m.parent, nil
+			case tea.KeyUp:
+				if m.suggestCursor > 0 {
+					m.suggestCursor--
+				}
+				return m, nil
+			case tea.KeyDown:
+				if m.suggestCursor < len(m.suggestions)-1 {
+					m.suggestCursor++
+				}
+				return m, nil
+			case tea.KeyTab:
+				if m.suggestCursor < len(m.suggestions) {
+					s := m.suggestions[m.suggestCursor]
+					m.shortInput.SetValue(s.Short)
+					m.shortInput.CursorEnd()
+					m.fqdnInput.SetValue(s.FQDN)
+				}
+				return m, nil
 			case tea.KeyEnter:
 				m.status = ""
 				if err := m.validateAndStoreShort(); err != nil {
@@ -140,22 +231,80 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
+				m.fqdnInput.Blur()
+				if m.doHostname {
+					m.prettyInput.Focus()
+					m.step = hostnameWizardStepPretty
+					return m, nil
+				}
+				return m.toApplyConfirm()
+			}
+
+		case hostnameWizardStepPretty:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.status = ""
+				m.prettyInput.Blur()
+				m.fqdnInput.Focus()
+				m.step = hostnameWizardStepFQDN
+				return m, nil
+			case tea.KeyEnter:
+				m.pretty = strings.TrimSpace(m.prettyInput.Value())
+				m.prettyInput.Blur()
+				m.optionCursor = 0
+				m.step = hostnameWizardStepOptions
+				return m, nil
+			}
+
+		case hostnameWizardStepOptions:
+			paths := m.impactPaths()
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.status = ""
+				m.prettyInput.Focus()
+				m.step = hostnameWizardStepPretty
+				return m, nil
+			case tea.KeyUp:
+				if m.optionCursor > 0 {
+					m.optionCursor--
+				}
+				return m, nil
+			case tea.KeyDown:
+				if m.optionCursor < len(paths)+1 {
+					m.optionCursor++
+				}
+				return m, nil
+			case tea.KeySpace:
+				switch m.optionCursor {
+				case 0:
+					m.setStatic = !m.setStatic
+				case 1:
+					m.setTransient = !m.setTransient
+				default:
+					path := paths[m.optionCursor-2]
+					m.impactSelect[path] = !m.impactSelect[path]
+				}
+				return m, nil
+			case tea.KeyEnter:
+				m.status = ""
+				if !m.setStatic && !m.setTransient {
+					m.status = i18n.T("hostname_wizard_kind_required")
+					return m, nil
+				}
 				// 仅在“设置主机名”流程里提示 cloud-init
-				if m.doHostname && m.cloudInitPresent {
+				if m.cloudInitPresent {
 					m.confirmCursor = 0 // 默认 No，避免误改云环境策略
 					m.step = hostnameWizardStepCloudInitConfirm
-				} else {
-					m.confirmCursor = 1
-					m.step = hostnameWizardStepApplyConfirm
+					return m, nil
 				}
-				return m, nil
+				return m.toApplyConfirm()
 			}
 
 		case hostnameWizardStepCloudInitConfirm:
 			switch msg.Type {
 			case tea.KeyEsc:
 				m.confirmCursor = 1
-				m.step = hostnameWizardStepFQDN
+				m.step = hostnameWizardStepOptions
 				return m, nil
 			case tea.KeyLeft, tea.KeyShiftTab:
 				m.confirmCursor = 0
@@ -165,9 +314,7 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case tea.KeyEnter:
 				m.preserveCloudInit = (m.confirmCursor == 1)
-				m.confirmCursor = 1
-				m.step = hostnameWizardStepApplyConfirm
-				return m, nil
+				return m.toApplyConfirm()
 			}
 
 		case hostnameWizardStepApplyConfirm:
@@ -184,6 +331,10 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.confirmCursor == 0 {
 					return m.parent, nil
 				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
 				m.step = hostnameWizardStepApplying
 				return m, m.applyCmd()
 			}
@@ -193,6 +344,9 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case hostnameWizardStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
 			switch msg.Type {
 			case tea.KeyEnter, tea.KeyEsc:
 				return m.parent, nil
@@ -206,6 +360,8 @@ func (m HostnameWizardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.shortInput, cmd = m.shortInput.Update(msg)
 	case hostnameWizardStepFQDN:
 		m.fqdnInput, cmd = m.fqdnInput.Update(msg)
+	case hostnameWizardStepPretty:
+		m.prettyInput, cmd = m.prettyInput.Update(msg)
 	}
 	return m, keepRefreshTickerCmd(msg, cmd)
 }
@@ -223,11 +379,19 @@ func (m HostnameWizardModel) View() string {
 	case hostnameWizardStepShort:
 		content.WriteString(tui.NormalStyle.Render(i18n.T("hostname_new")) + "\n")
 		content.WriteString(m.shortInput.View() + "\n")
+		content.WriteString(m.suggestionsView())
 
 	case hostnameWizardStepFQDN:
 		content.WriteString(tui.NormalStyle.Render(i18n.T("hostname_fqdn")) + "\n")
 		content.WriteString(m.fqdnInput.View() + "\n")
 
+	case hostnameWizardStepPretty:
+		content.WriteString(tui.NormalStyle.Render(i18n.T("hostname_wizard_pretty")) + "\n")
+		content.WriteString(m.prettyInput.View() + "\n")
+
+	case hostnameWizardStepOptions:
+		content.WriteString(m.optionsView())
+
 	case hostnameWizardStepCloudInitConfirm:
 		content.WriteString(tui.NormalStyle.Render(i18n.T("hostname_wizard_cloudinit_prompt")) + "\n\n")
 		content.WriteString(renderYesNo(m.confirmCursor) + "\n")
@@ -237,6 +401,9 @@ func (m HostnameWizardModel) View() string {
 		for _, line := range m.actionLines() {
 			content.WriteString("  " + line + "\n")
 		}
+		if m.forwardErr != nil {
+			content.WriteString("\n" + tui.WarningStyle.Render(i18n.T("hostname_wizard_forward_warn", m.forwardErr)) + "\n")
+		}
 		content.WriteString("\n" + tui.NormalStyle.Render(i18n.T("hostname_wizard_confirm_apply")) + "\n\n")
 		content.WriteString(renderYesNo(m.confirmCursor) + "\n")
 
@@ -254,19 +421,180 @@ func (m HostnameWizardModel) View() string {
 			}
 		}
 		content.WriteString("\n" + tui.DimStyle.Render(i18n.T("hostname_wizard_done")) + "\n")
+		content.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
 	}
 
 	if m.status != "" {
 		content.WriteString("\n" + tui.ErrorStyle.Render(m.status) + "\n")
 	}
 
-	if m.step == hostnameWizardStepShort || m.step == hostnameWizardStepFQDN || m.step == hostnameWizardStepApplyConfirm || m.step == hostnameWizardStepCloudInitConfirm {
+	switch m.step {
+	case hostnameWizardStepShort, hostnameWizardStepFQDN, hostnameWizardStepPretty, hostnameWizardStepApplyConfirm, hostnameWizardStepCloudInitConfirm:
 		content.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")+" / "+i18n.T("press_esc")) + "\n")
+	case hostnameWizardStepOptions:
+		content.WriteString("\n" + tui.DimStyle.Render(i18n.T("hostname_wizard_options_keys")) + "\n")
 	}
 
 	return tui.BorderStyle.Width(62).Render(content.String())
 }
 
+// suggestionsView 候选主机名列表（Tab 填入）
+func (m HostnameWizardModel) suggestionsView() string {
+	var b strings.Builder
+	if m.suggestErr != nil {
+		b.WriteString("\n" + tui.WarningStyle.Render(i18n.T("hostname_wizard_template_failed", m.suggestErr)) + "\n")
+	}
+	if len(m.suggestions) == 0 {
+		return b.String()
+	}
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("hostname_wizard_suggestions")) + "\n")
+	for i, s := range m.suggestions {
+		line := fmt.Sprintf("%s  (%s: %s)", s.Name(), i18n.T("hostname_source_"+s.Source), s.Detail)
+		if i == m.suggestCursor {
+			b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString(tui.DimStyle.Render(i18n.T("hostname_wizard_suggest_keys")) + "\n")
+	return b.String()
+}
+
+// toApplyConfirm 进入确认页，并在后台检查 FQDN 是否解析回本机
+func (m HostnameWizardModel) toApplyConfirm() (tea.Model, tea.Cmd) {
+	m.confirmCursor = 1
+	m.forwardErr = nil
+	m.step = hostnameWizardStepApplyConfirm
+	if m.fqdn == "" {
+		return m, nil
+	}
+	fqdn := m.fqdn
+	return m, func() tea.Msg {
+		return hostnameWizardForwardMsg{fqdn: fqdn, err: hostnameModule.VerifyForward(fqdn)}
+	}
+}
+
+func (m HostnameWizardModel) suggestCmd() tea.Cmd {
+	var opts hostnameModule.SuggestOptions
+	if m.cfg != nil {
+		opts = hostnameModule.SuggestOptions{
+			Template: m.cfg.HostnameTemplate,
+			Role:     m.cfg.HostnameRole,
+			Region:   m.cfg.HostnameRegion,
+			Domain:   m.cfg.HostnameDomain,
+		}
+	}
+	return func() tea.Msg {
+		suggestions, err := hostnameModule.Suggest(opts)
+		return hostnameWizardSuggestMsg{suggestions: suggestions, err: err}
+	}
+}
+
+// optionsView 主机名类型与引用旧主机名的配置
+func (m HostnameWizardModel) optionsView() string {
+	var b strings.Builder
+	b.WriteString(tui.SubtitleStyle.Render(i18n.T("hostname_wizard_kinds")) + "\n")
+
+	row := func(i int, checked bool, label string) {
+		box := "[ ]"
+		if checked {
+			box = "[x]"
+		}
+		line := box + " " + label
+		if i == m.optionCursor {
+			b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+	}
+	row(0, m.setStatic, i18n.T("hostname_wizard_kind_static"))
+	row(1, m.setTransient, i18n.T("hostname_wizard_kind_transient"))
+
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("hostname_wizard_refs")) + "\n")
+	switch {
+	case m.impactErr != nil:
+		b.WriteString("  " + tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.impactErr)) + "\n")
+	case m.impact == nil:
+		b.WriteString("  " + tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+	case !m.impactChanged() || len(m.impact.Items) == 0:
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("hostname_wizard_refs_none")) + "\n")
+	default:
+		for i, item := range m.impact.Updatable() {
+			row(i+2, m.impactSelect[item.Path], item.Path)
+			for _, line := range item.Lines {
+				b.WriteString("      " + tui.DimStyle.Render(line) + "\n")
+			}
+		}
+		for _, item := range m.impact.Manual() {
+			b.WriteString("  " + tui.WarningStyle.Render("! "+impactManualLine(item)) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// impactChanged 扫描结果是否针对与新名称不同的旧名称
+func (m HostnameWizardModel) impactChanged() bool {
+	if m.impact == nil {
+		return false
+	}
+	return !strings.EqualFold(m.impact.OldShort, m.short) || !strings.EqualFold(m.impact.OldFQDN, m.fqdn)
+}
+
+// impactPaths 可自动更新的配置路径（与选项列表顺序一致）
+func (m HostnameWizardModel) impactPaths() []string {
+	if !m.impactChanged() {
+		return nil
+	}
+	var paths []string
+	for _, item := range m.impact.Updatable() {
+		paths = append(paths, item.Path)
+	}
+	return paths
+}
+
+// selectedImpactPaths 已勾选的配置路径
+func (m HostnameWizardModel) selectedImpactPaths() []string {
+	var paths []string
+	for _, path := range m.impactPaths() {
+		if m.impactSelect[path] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// impactManualLine 需要手动处理的项
+func impactManualLine(item hostnameModule.Impact) string {
+	detail := item.Path
+	if len(item.Lines) > 0 {
+		detail = item.Lines[0]
+	}
+	return i18n.T("hostname_impact_"+item.Kind, detail)
+}
+
+func (m HostnameWizardModel) scanImpactCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		name, err := hostnameModule.NewManager(true, logger).GetHostname()
+		if err != nil {
+			return hostnameWizardImpactMsg{err: err}
+		}
+		name = hostnameModule.NormalizeHostname(name)
+		if name == "" {
+			return hostnameWizardImpactMsg{report: &hostnameModule.ImpactReport{}}
+		}
+		short := hostnameModule.GetShortHostname(name)
+		fqdn := ""
+		if hostnameModule.IsFQDN(name) {
+			fqdn = name
+		} else {
+			fqdn = hostnameModule.FQDNFromHosts(short)
+		}
+		report, err := hostnameModule.ScanImpact(short, fqdn)
+		return hostnameWizardImpactMsg{report: report, err: err}
+	}
+}
+
 func (m *HostnameWizardModel) validateAndStoreShort() error {
 	raw := strings.TrimSpace(m.shortInput.Value())
 	if raw == "" {
@@ -312,7 +640,25 @@ func (m HostnameWizardModel) actionLines() []string {
 	}
 
 	if m.doHostname {
-		lines = append(lines, i18n.T("hostname_wizard_action_set", target))
+		switch {
+		case m.setStatic && m.setTransient:
+			lines = append(lines, i18n.T("hostname_wizard_action_set", target))
+		case m.setStatic:
+			lines = append(lines, i18n.T("hostname_wizard_action_static", target))
+		default:
+			lines = append(lines, i18n.T("hostname_wizard_action_transient", target))
+		}
+		if m.pretty != "" {
+			lines = append(lines, i18n.T("hostname_wizard_action_pretty", m.pretty))
+		}
+		for _, path := range m.selectedImpactPaths() {
+			lines = append(lines, i18n.T("hostname_wizard_action_ref", path))
+		}
+		if m.impactChanged() {
+			for _, item := range m.impact.Manual() {
+				lines = append(lines, tui.WarningStyle.Render(impactManualLine(item)))
+			}
+		}
 	}
 	if m.doHosts {
 		lines = append(lines, i18n.T("hostname_wizard_action_hosts", target))
@@ -342,6 +688,10 @@ func renderYesNo(cursor int) string {
 func (m HostnameWizardModel) applyCmd() tea.Cmd {
 	short := m.short
 	fqdn := m.fqdn
+	opts := hostnameModule.SetOptions{Static: m.setStatic, Transient: m.setTransient, Pretty: m.pretty}
+	if paths := m.selectedImpactPaths(); len(paths) > 0 {
+		opts.References = m.impact.Select(paths)
+	}
 	doHostname := m.doHostname
 	doHosts := m.doHosts
 	doCloudInit := m.doHostname && m.cloudInitPresent && m.preserveCloudInit
@@ -362,10 +712,15 @@ func (m HostnameWizardModel) applyCmd() tea.Cmd {
 
 		if doHostname {
 			mgr := hostnameModule.NewManager(dryRun, logger)
-			if err := mgr.SetHostname(short, fqdn); err != nil {
+			if err := mgr.SetHostnameWithOptions(short, fqdn, opts); err != nil {
 				return hostnameWizardAppliedMsg{err: err}
 			}
 			summaryParts = append(summaryParts, i18n.T("hostname_success", short))
+			if opts.References != nil {
+				for _, item := range opts.References.Updatable() {
+					summaryParts = append(summaryParts, i18n.T("hostname_wizard_action_ref", item.Path))
+				}
+			}
 		}
 
 		if doHosts {
@@ -414,6 +769,7 @@ type CloudInitPreserveModel struct {
 	cursor int // 0: No, 1: Yes
 
 	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
 }
 
 func NewCloudInitPreserveModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) CloudInitPreserveModel {
@@ -456,12 +812,19 @@ func (m CloudInitPreserveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if !m.present || m.cursor == 0 {
 					return m.parent, nil
 				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
 				m.step = cloudInitPreserveStepApplying
 				return m, m.applyCmd()
 			}
 		case cloudInitPreserveStepApplying:
 			return m, nil
 		case cloudInitPreserveStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
 			switch msg.Type {
 			case tea.KeyEnter, tea.KeyEsc:
 				return m.parent, nil
@@ -501,6 +864,7 @@ func (m CloudInitPreserveModel) View() string {
 			content.WriteString(tui.SuccessStyle.Render(i18n.T("success")) + "\n")
 		}
 		content.WriteString("\n" + tui.DimStyle.Render(i18n.T("hostname_wizard_done")) + "\n")
+		content.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
 	}
 
 	return tui.BorderStyle.Width(62).Render(content.String())
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type hostsStep int
+
+const (
+	hostsStepLoading hostsStep = iota
+	hostsStepList
+	hostsStepEdit
+	hostsStepDeleteConfirm
+	hostsStepSaveConfirm
+	hostsStepDiscardConfirm
+	hostsStepSaving
+	hostsStepResult
+)
+
+const hostsPageSize = 12
+
+type hostsLoadedMsg struct {
+	file *hostname.HostsFile
+	err  error
+}
+
+type hostsSavedMsg struct{ err error }
+
+// HostsModel /etc/hosts 编辑器：增删改条目，检测重复与冲突
+type HostsModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step hostsStep
+
+	file    *hostname.HostsFile
+	loadErr error
+	dirty   bool
+	cursor  int
+
+	// 编辑表单：editIndex < 0 表示新增
+	editIndex int
+	inputs    []textinput.Model // IP、主机名（空格分隔）、注释
+	focus     int
+	editErr   error
+
+	confirmCursor int // 0: No, 1: Yes
+
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewHostsModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) HostsModel {
+	placeholders := []string{"10.0.0.5 / fd00::5", "db.internal db", i18n.T("hosts_comment_placeholder")}
+	inputs := make([]textinput.Model, len(placeholders))
+	for i, p := range placeholders {
+		ti := textinput.New()
+		ti.Placeholder = p
+		ti.CharLimit = 255
+		ti.Width = 40
+		inputs[i] = ti
+	}
+
+	return HostsModel{
+		parent: parent,
+		cfg:    cfg,
+		logger: logger,
+		step:   hostsStepLoading,
+		inputs: inputs,
+	}
+}
+
+func (m HostsModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(loadHostsCmd())
+}
+
+func (m HostsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case hostsLoadedMsg:
+		m.file = msg.file
+		m.loadErr = msg.err
+		m.dirty = false
+		m.cursor = 0
+		m.step = hostsStepList
+		return m, nil
+
+	case hostsSavedMsg:
+		m.resultErr = msg.err
+		if msg.err == nil {
+			m.dirty = false
+		}
+		m.step = hostsStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case hostsStepLoading, hostsStepSaving:
+			return m, nil
+
+		case hostsStepList:
+			return m.updateList(msg)
+
+		case hostsStepEdit:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = hostsStepList
+				return m, nil
+			case tea.KeyTab, tea.KeyDown:
+				return m.focusInput((m.focus + 1) % len(m.inputs))
+			case tea.KeyShiftTab, tea.KeyUp:
+				return m.focusInput((m.focus + len(m.inputs) - 1) % len(m.inputs))
+			case tea.KeyEnter:
+				return m.applyEdit()
+			}
+
+		case hostsStepDeleteConfirm, hostsStepSaveConfirm, hostsStepDiscardConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = hostsStepList
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = hostsStepList
+					return m, nil
+				}
+				switch m.step {
+				case hostsStepDeleteConfirm:
+					if err := m.file.Remove(m.cursor); err == nil {
+						m.dirty = true
+						if m.cursor >= len(m.file.Entries()) && m.cursor > 0 {
+							m.cursor--
+						}
+					}
+					m.step = hostsStepList
+					return m, nil
+				case hostsStepSaveConfirm:
+					if needsRoot(m.cfg) {
+						return newRootRequiredModel(m, m.cfg), nil
+					}
+					m.logStart = logMark(m.cfg)
+					m.step = hostsStepSaving
+					return m, m.saveCmd()
+				default:
+					return m.parent, nil
+				}
+			}
+
+		case hostsStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = hostsStepList
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == hostsStepEdit {
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+func (m HostsModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var entries []hostname.HostsEntry
+	if m.file != nil {
+		entries = m.file.Entries()
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		if m.dirty {
+			m.confirmCursor = 0
+			m.step = hostsStepDiscardConfirm
+			return m, nil
+		}
+		return m.parent, nil
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.cursor < len(entries)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case tea.KeyPgUp:
+		m.cursor = max(m.cursor-hostsPageSize, 0)
+		return m, nil
+	case tea.KeyPgDown:
+		m.cursor = max(min(m.cursor+hostsPageSize, len(entries)-1), 0)
+		return m, nil
+	case tea.KeyEnter:
+		if len(entries) > 0 {
+			return m.startEdit(m.cursor, entries[m.cursor])
+		}
+		return m, nil
+	case tea.KeyRunes:
+		if m.file == nil {
+			return m, nil
+		}
+		switch msg.String() {
+		case "a":
+			return m.startEdit(-1, hostname.HostsEntry{})
+		case "e":
+			if len(entries) > 0 {
+				return m.startEdit(m.cursor, entries[m.cursor])
+			}
+		case "d":
+			if len(entries) > 0 {
+				m.confirmCursor = 0
+				m.step = hostsStepDeleteConfirm
+			}
+		case "s":
+			if m.dirty {
+				m.confirmCursor = 1
+				m.step = hostsStepSaveConfirm
+			}
+		case "r":
+			m.step = hostsStepLoading
+			return m, loadHostsCmd()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m HostsModel) startEdit(index int, entry hostname.HostsEntry) (tea.Model, tea.Cmd) {
+	m.editIndex = index
+	m.editErr = nil
+	m.inputs[0].SetValue(entry.IP)
+	m.inputs[1].SetValue(strings.TrimSpace(strings.Join(entry.Names(), " ")))
+	m.inputs[2].SetValue(entry.Comment)
+	m.step = hostsStepEdit
+	return m.focusInput(0)
+}
+
+func (m HostsModel) focusInput(i int) (tea.Model, tea.Cmd) {
+	for j := range m.inputs {
+		m.inputs[j].Blur()
+	}
+	m.focus = i
+	m.inputs[i].Focus()
+	return m, textinput.Blink
+}
+
+func (m HostsModel) applyEdit() (tea.Model, tea.Cmd) {
+	names := strings.Fields(strings.ToLower(m.inputs[1].Value()))
+	entry := hostname.HostsEntry{
+		IP:      strings.TrimSpace(m.inputs[0].Value()),
+		Comment: strings.TrimSpace(m.inputs[2].Value()),
+	}
+	if len(names) > 0 {
+		entry.Canonical = names[0]
+		entry.Aliases = names[1:]
+	}
+
+	var err error
+	if m.editIndex < 0 {
+		err = m.file.Add(entry)
+		if err == nil {
+			m.cursor = len(m.file.Entries()) - 1
+		}
+	} else {
+		err = m.file.Update(m.editIndex, entry)
+	}
+	if err != nil {
+		m.editErr = err
+		return m, nil
+	}
+
+	m.dirty = true
+	m.step = hostsStepList
+	return m, nil
+}
+
+func (m HostsModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("hosts_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case hostsStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case hostsStepList:
+		b.WriteString(m.listView())
+
+	case hostsStepEdit:
+		title := i18n.T("hosts_edit")
+		if m.editIndex < 0 {
+			title = i18n.T("hosts_add")
+		}
+		b.WriteString(tui.SubtitleStyle.Render(title) + "\n\n")
+		labels := []string{i18n.T("hosts_ip"), i18n.T("hosts_names"), i18n.T("hosts_comment")}
+		for i, label := range labels {
+			b.WriteString(tui.NormalStyle.Render(label) + "\n")
+			b.WriteString(m.inputs[i].View() + "\n\n")
+		}
+		if m.editErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(m.editErr.Error()) + "\n\n")
+		}
+		b.WriteString(tui.DimStyle.Render(i18n.T("hosts_edit_keys")) + "\n")
+
+	case hostsStepDeleteConfirm:
+		entry := m.file.Entries()[m.cursor]
+		b.WriteString(tui.NormalStyle.Render(i18n.T("hosts_confirm_delete", entry.IP, strings.Join(entry.Names(), " "))) + "\n")
+		if entry.IsSystem() {
+			b.WriteString(tui.WarningStyle.Render(i18n.T("hosts_system_entry")) + "\n")
+		}
+		b.WriteString("\n" + renderYesNo(m.confirmCursor) + "\n")
+
+	case hostsStepSaveConfirm:
+		if problems := m.file.Problems(); len(problems) > 0 {
+			b.WriteString(tui.WarningStyle.Render(i18n.T("hosts_save_with_problems", len(problems))) + "\n\n")
+		}
+		b.WriteString(tui.NormalStyle.Render(i18n.T("hosts_confirm_save")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case hostsStepDiscardConfirm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("hosts_confirm_discard")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case hostsStepSaving:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("hosts_saving")) + "\n")
+
+	case hostsStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("hosts_saved")) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m HostsModel) listView() string {
+	var b strings.Builder
+
+	if m.loadErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.loadErr)) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+		return b.String()
+	}
+
+	entries := m.file.Entries()
+	b.WriteString(tui.DimStyle.Render(fmt.Sprintf("  %-24s %s", i18n.T("hosts_ip"), i18n.T("hosts_col_names"))) + "\n")
+	if len(entries) == 0 {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("hosts_empty")) + "\n")
+	}
+
+	start := 0
+	if m.cursor >= hostsPageSize {
+		start = m.cursor - hostsPageSize + 1
+	}
+	end := min(start+hostsPageSize, len(entries))
+	for i := start; i < end; i++ {
+		entry := entries[i]
+		line := fmt.Sprintf("%-24s %s", entry.IP, strings.Join(entry.Names(), " "))
+		if entry.Comment != "" {
+			line += "  # " + entry.Comment
+		}
+		if r := []rune(line); len(r) > 56 {
+			line = string(r[:55]) + "…"
+		}
+		switch {
+		case i == m.cursor:
+			b.WriteString(tui.SelectedStyle.Render("> "+line) + "\n")
+		case entry.IsSystem():
+			b.WriteString("  " + tui.DimStyle.Render(line) + "\n")
+		default:
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+	}
+
+	if problems := m.file.Problems(); len(problems) > 0 {
+		b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("hosts_problems")) + "\n")
+		for _, p := range problems {
+			msg := i18n.T("hosts_problem_"+p.Kind, p.Name, strings.Join(p.IPs, ", "))
+			b.WriteString("  " + tui.WarningStyle.Render("! "+msg) + "\n")
+		}
+	}
+
+	if m.dirty {
+		b.WriteString("\n" + tui.WarningStyle.Render(i18n.T("hosts_unsaved")) + "\n")
+	}
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("hosts_keys")) + "\n")
+	return b.String()
+}
+
+func loadHostsCmd() tea.Cmd {
+	return func() tea.Msg {
+		file, err := hostname.LoadHosts()
+		return hostsLoadedMsg{file: file, err: err}
+	}
+}
+
+func (m HostsModel) saveCmd() tea.Cmd {
+	file := m.file
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return hostsSavedMsg{err: hostname.SaveHosts(file, dryRun, logger)}
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+const (
+	// logsTailBytes 从菜单进入时读取的日志末尾大小
+	logsTailBytes = 256 * 1024
+	// logsMaxLines 内存中保留的最多行数
+	logsMaxLines = 5000
+)
+
+type logsReadMsg struct {
+	from  int64
+	lines []string
+	next  int64
+	err   error
+}
+
+// LogsModel 日志查看：实时追踪工具日志文件，按级别过滤、搜索文本并突出显示 dry-run 记录。
+// 从向导结果页进入时只显示本次操作开始之后的日志，Esc 返回该结果页。
+type LogsModel struct {
+	back   tea.Model
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	path      string
+	operation bool  // 是否只显示一次操作的日志
+	offset    int64 // 下次读取的位置；-1 表示读取末尾
+	reading   bool
+	loaded    bool
+	lines     []internal.LogLine
+	err       error
+
+	minLevel  internal.LogLevel
+	search    textinput.Model
+	searching bool
+	viewport  viewport.Model
+}
+
+func NewLogsModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) LogsModel {
+	return newLogsModel(parent, cfg, logger, -1)
+}
+
+// newOperationLogsModel 从向导结果页查看一次操作的日志；since 为操作开始时的日志位置（见 logMark）
+func newOperationLogsModel(back tea.Model, cfg *internal.Config, logger *internal.Logger, since int64) LogsModel {
+	m := newLogsModel(back, cfg, logger, since)
+	m.operation = true
+	return m
+}
+
+func newLogsModel(back tea.Model, cfg *internal.Config, logger *internal.Logger, offset int64) LogsModel {
+	ti := textinput.New()
+	ti.Placeholder = i18n.T("logs_search_placeholder")
+	ti.CharLimit = 64
+	ti.Width = 40
+
+	m := LogsModel{
+		back:     back,
+		cfg:      cfg,
+		logger:   logger,
+		offset:   offset,
+		minLevel: internal.DEBUG,
+		search:   ti,
+		viewport: viewport.New(58, 14),
+	}
+	// 日志行较长，←/→ 左右移动
+	m.viewport.SetHorizontalStep(8)
+	if cfg != nil {
+		m.path = cfg.LogPath
+	}
+	return m
+}
+
+// logMark 日志文件当前的位置，向导开始执行操作时记录，结果页据此只显示本次操作的日志
+func logMark(cfg *internal.Config) int64 {
+	if cfg == nil || cfg.LogPath == "" {
+		return 0
+	}
+	return internal.LogSize(cfg.LogPath)
+}
+
+// isViewLogKey 结果页中打开本次操作日志的按键
+func isViewLogKey(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyRunes && msg.String() == "l"
+}
+
+// viewOperationLog 从结果页打开本次操作的日志；沿用结果页的刷新定时器，不再启动新的
+func viewOperationLog(back tea.Model, cfg *internal.Config, logger *internal.Logger, since int64) (tea.Model, tea.Cmd) {
+	m := newOperationLogsModel(back, cfg, logger, since)
+	return m, m.readCmd()
+}
+
+func (m LogsModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.readCmd())
+}
+
+func (m LogsModel) readCmd() tea.Cmd {
+	if m.path == "" {
+		return nil
+	}
+	path, from := m.path, m.offset
+	return func() tea.Msg {
+		offset, maxBytes := from, int64(0)
+		if from < 0 {
+			offset, maxBytes = 0, logsTailBytes
+		}
+		lines, next, err := internal.ReadLogLines(path, offset, maxBytes)
+		return logsReadMsg{from: from, lines: lines, next: next, err: err}
+	}
+}
+
+func (m LogsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logsReadMsg:
+		m.reading = false
+		m.loaded = true
+		m.err = msg.err
+		if msg.from != m.offset {
+			return m, nil
+		}
+		m.offset = msg.next
+		if len(msg.lines) > 0 {
+			m.appendLines(msg.lines)
+		}
+		return m, nil
+
+	case tui.RefreshMenuMsg:
+		// 定时读取新写入的日志
+		var cmd tea.Cmd
+		if !m.reading {
+			if cmd = m.readCmd(); cmd != nil {
+				m.reading = true
+			}
+		}
+		return m, keepRefreshTickerCmd(msg, cmd)
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		if m.searching {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.search.SetValue("")
+				fallthrough
+			case tea.KeyEnter:
+				m.searching = false
+				m.search.Blur()
+				m.render(true)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.search, cmd = m.search.Update(msg)
+			m.render(true)
+			return m, cmd
+		}
+
+		switch msg.Type {
+		case tea.KeyEsc:
+			return m.back, nil
+		case tea.KeyHome:
+			m.viewport.GotoTop()
+			return m, nil
+		case tea.KeyEnd:
+			m.viewport.GotoBottom()
+			return m, nil
+		case tea.KeyRunes:
+			switch msg.String() {
+			case "/":
+				m.searching = true
+				m.search.Focus()
+				return m, textinput.Blink
+			case "v":
+				m.minLevel = (m.minLevel + 1) % (internal.ERROR + 1)
+				m.render(true)
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	if m.searching {
+		m.search, cmd = m.search.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+// appendLines 解析新读取的行并刷新显示；停在末尾时继续跟随新日志
+func (m *LogsModel) appendLines(raw []string) {
+	prev := internal.INFO
+	if n := len(m.lines); n > 0 {
+		prev = m.lines[n-1].Level
+	}
+	for _, s := range raw {
+		l := internal.ParseLogLine(s)
+		if !l.Parsed {
+			l.Level = prev
+		}
+		prev = l.Level
+		m.lines = append(m.lines, l)
+	}
+	if n := len(m.lines); n > logsMaxLines {
+		m.lines = append([]internal.LogLine(nil), m.lines[n-logsMaxLines:]...)
+	}
+	m.render(m.viewport.AtBottom() || m.viewport.TotalLineCount() == 0)
+}
+
+// render 按级别与搜索词过滤后重新设置内容
+func (m *LogsModel) render(toBottom bool) {
+	var b strings.Builder
+	for _, l := range m.visible() {
+		b.WriteString(logLineStyle(l).Render(l.Text) + "\n")
+	}
+	m.viewport.SetContent(strings.TrimSuffix(b.String(), "\n"))
+	if toBottom {
+		m.viewport.GotoBottom()
+	}
+}
+
+// visible 级别不低于过滤级别且包含搜索词（不区分大小写）的行
+func (m LogsModel) visible() []internal.LogLine {
+	query := strings.ToLower(strings.TrimSpace(m.search.Value()))
+	var out []internal.LogLine
+	for _, l := range m.lines {
+		if l.Level < m.minLevel {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(l.Text), query) {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// logLineStyle 错误与警告按级别着色，dry-run 记录突出显示
+func logLineStyle(l internal.LogLine) lipgloss.Style {
+	switch {
+	case l.Level == internal.ERROR:
+		return tui.ErrorStyle
+	case l.Level == internal.WARN:
+		return tui.WarningStyle
+	case l.DryRun:
+		return tui.InfoStyle
+	case l.Level == internal.DEBUG:
+		return tui.DimStyle
+	default:
+		return tui.NormalStyle
+	}
+}
+
+func (m LogsModel) View() string {
+	var b strings.Builder
+	title := i18n.T("logs_title")
+	if m.operation {
+		title = i18n.T("logs_operation_title")
+	}
+	b.WriteString(tui.TitleStyle.Width(60).Render(title) + "\n\n")
+
+	if m.path == "" {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("logs_no_file")) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+		return tui.BorderStyle.Width(62).Render(b.String())
+	}
+
+	b.WriteString(tui.DimStyle.Render(i18n.T("logs_path", m.path)) + "\n")
+	b.WriteString(tui.NormalStyle.Render(i18n.T("logs_level", m.minLevel)) + "\n")
+	if m.searching || m.search.Value() != "" {
+		b.WriteString(tui.NormalStyle.Render(i18n.T("logs_search")) + " " + m.search.View() + "\n")
+	}
+	if m.err != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.err)) + "\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case !m.loaded:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+	case len(m.visible()) == 0:
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("logs_empty")) + "\n")
+	default:
+		b.WriteString(m.viewport.View() + "\n")
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("logs_keys")) + "\n")
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLogs(t *testing.T) {
+	i18n.Init()
+	cfg := internal.Default()
+	cfg.LogPath = filepath.Join(t.TempDir(), "toolkit.log")
+
+	f, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+	logger := internal.NewLogger(internal.DEBUG, f)
+
+	logger.Info("before the operation")
+	mark := logMark(cfg)
+	logger.With(internal.Fields{DryRun: true}).Info("[DRY-RUN] Would write to file: /etc/hostname")
+	logger.Debug("details")
+	logger.Warn("hostnamectl not found")
+
+	back := tui.NewMenu("main", "", nil)
+	model, cmd := viewOperationLog(back, cfg, logger, mark)
+	require.NotNil(t, cmd)
+	model, _ = model.Update(cmd())
+	m := model.(LogsModel)
+
+	lines := m.visible()
+	require.Len(t, lines, 3)
+	assert.True(t, lines[0].DryRun)
+	assert.Contains(t, m.View(), "Would write to file")
+
+	// v 依次提高过滤级别：INFO、WARN
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = model.(LogsModel)
+	require.Len(t, m.visible(), 1)
+	assert.Equal(t, internal.WARN, m.visible()[0].Level)
+
+	// 搜索不区分大小写
+	m.minLevel = internal.DEBUG
+	m.search.SetValue("DETAILS")
+	require.Len(t, m.visible(), 1)
+
+	// 定时刷新读取新写入的日志
+	m.search.SetValue("")
+	logger.Error("reload failed")
+	model, cmd = m.Update(tui.RefreshMenuMsg{})
+	require.NotNil(t, cmd)
+	model, _ = model.Update(m.readCmd()())
+	m = model.(LogsModel)
+	require.Len(t, m.visible(), 4)
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.IsType(t, back, model)
+}
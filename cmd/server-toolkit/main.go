@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
 	"github.com/Akuma-real/server-toolkit/pkg/i18n"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 	"github.com/Akuma-real/server-toolkit/pkg/tui"
@@ -39,23 +41,50 @@ var (
 func main() {
 	i18n.Init()
 
-	cfg, err := internal.Load()
-	if err != nil || cfg == nil {
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to load config, using defaults: %v\n", err)
-		}
-		cfg = internal.Default()
-	}
-	i18n.SetLanguage(cfg.Language)
-
 	showVersion := flag.Bool("version", false, "print version and exit")
+	root := flag.String("root", "", "operate on an offline root filesystem (e.g. a mounted image)")
+	configFlag := flag.String("config", os.Getenv(internal.EnvPrefix+"CONFIG"), "config file path")
 	flag.Parse()
 	if *showVersion {
 		fmt.Println(version)
 		return
 	}
 
+	internal.SetConfigPath(*configFlag)
+	cfg, err := internal.Load()
+	if err != nil && !(flag.Arg(0) == "config" && flag.Arg(1) == "validate") {
+		// 无效的配置项已恢复为默认值（环境变量则被忽略），其余配置照常生效
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	if cfg == nil {
+		cfg = internal.Default()
+	}
+	i18n.SetLanguage(cfg.Language)
+	if err := system.SetRoot(*root); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	history.SetPath(cfg.AuditLog())
+
+	// 子命令：日志写到 stderr，避免混入报告输出
+	switch flag.Arg(0) {
+	case "audit", "drift":
+		logger := internal.NewLogger(internal.ParseLevel(cfg.LogLevel), os.Stderr)
+		system.ConfigureRunner(cfg.DryRun, logger)
+		if flag.Arg(0) == "audit" {
+			os.Exit(runAuditCommand(flag.Args()[1:], logger))
+		}
+		os.Exit(runDriftCommand(flag.Args()[1:], cfg, logger))
+	case "config":
+		os.Exit(runConfigCommand(flag.Args()[1:], cfg))
+	case "self-update":
+		logger := internal.NewLogger(internal.ParseLevel(cfg.LogLevel), os.Stderr)
+		os.Exit(runSelfUpdateCommand(flag.Args()[1:], cfg, logger))
+	}
+
 	logger := newLogger(cfg)
+	system.ConfigureRunner(cfg.DryRun, logger)
 	startAsyncUpdateCheck(cfg)
 
 	model := buildMainMenu(cfg, logger)
@@ -69,15 +98,24 @@ func main() {
 func newLogger(cfg *internal.Config) *internal.Logger {
 	level := internal.ParseLevel(cfg.LogLevel)
 
-	out := os.Stdout
+	var out io.Writer = os.Stdout
 	if cfg.LogPath != "" {
-		f, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-		if err == nil {
+		maxSize, maxBackups := cfg.LogRotation()
+		if f, err := internal.OpenRotatingFile(cfg.LogPath, maxSize, maxBackups); err == nil {
 			out = f
 		}
 	}
 
-	return internal.NewLogger(level, out)
+	logger := internal.NewLogger(level, out)
+	logger.SetFormat(internal.ParseLogFormat(cfg.LogFormat))
+
+	sink, err := internal.NewLogSink(cfg.LogSink)
+	if err != nil {
+		logger.Warn("%v", err)
+	} else if sink != nil {
+		logger.AddSink(sink)
+	}
+	return logger
 }
 
 func buildSubtitle(updateState updateStatus) string {
@@ -91,6 +129,14 @@ func buildSubtitle(updateState updateStatus) string {
 
 	parts = append(parts, fmt.Sprintf("GOOS=%s GOARCH=%s", runtime.GOOS, runtime.GOARCH))
 
+	if system.HasRoot() {
+		parts = append(parts, i18n.T("root_target", system.Root()))
+	}
+
+	if !isRoot() {
+		parts = append(parts, i18n.T("app_nonroot"))
+	}
+
 	if updateState.Available {
 		parts = append(parts, i18n.T("update_available", updateState.Latest))
 	} else if updateState.CheckFailed {
@@ -102,15 +148,50 @@ func buildSubtitle(updateState updateStatus) string {
 
 func buildMainMenu(cfg *internal.Config, logger *internal.Logger) tui.MenuModel {
 	unimplemented := i18n.T("menu_unimplemented")
+	// 非 root（且未开启 dry-run）时置灰只用于修改系统的向导；其余界面在执行修改前提示通过 sudo 重新启动
+	rootRequired := rootOnly(cfg)
 	subtitle := buildSubtitle(getUpdateStatus())
 
+	cloudInitMenu := tui.NewMenu(
+		i18n.T("menu_cloudinit"),
+		"",
+		[]tui.MenuItem{
+			{ID: "cloudinit_status", Label: i18n.T("menu_cloudinit_status"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewCloudInitModel(parent, cfg, logger)
+			}},
+			{ID: "cloudinit_export", Label: i18n.T("menu_cloudinit_export"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewCloudInitExportModel(parent, cfg, logger)
+			}},
+			{ID: "back", Label: i18n.T("menu_back"), Action: func() tea.Cmd { return func() tea.Msg { return tui.ParentMenuMsg{} } }},
+		},
+	).SetUnimplementedMessage(unimplemented)
+
 	systemMenu := tui.NewMenu(
 		i18n.T("menu_system"),
 		"",
 		[]tui.MenuItem{
-			{ID: "hostname", Label: i18n.T("hostname_setting"), Next: func(parent tui.MenuModel) tea.Model {
+			{ID: "hostname", Label: i18n.T("hostname_setting"), Disabled: rootRequired, Next: func(parent tui.MenuModel) tea.Model {
 				return NewHostnameWizard(parent, cfg, logger, true, true)
 			}},
+			{ID: "hosts", Label: i18n.T("menu_hosts"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewHostsModel(parent, cfg, logger)
+			}},
+			{ID: "network", Label: i18n.T("menu_network"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewNetworkModel(parent, cfg, logger)
+			}},
+			{ID: "resolver", Label: i18n.T("menu_resolver"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewResolverModel(parent, cfg, logger)
+			}},
+			{ID: "cloudinit", Label: i18n.T("menu_cloudinit"), Submenu: &cloudInitMenu},
+			{ID: "autoupgrade", Label: i18n.T("menu_autoupgrade"), Disabled: rootRequired, Next: func(parent tui.MenuModel) tea.Model {
+				return NewAutoUpgradeWizard(parent, cfg, logger)
+			}},
+			{ID: "packages", Label: i18n.T("menu_packages"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewPackagesModel(parent, cfg, logger)
+			}},
+			{ID: "services", Label: i18n.T("menu_services"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewServicesModel(parent, cfg, logger)
+			}},
 			{ID: "back", Label: i18n.T("menu_back"), Action: func() tea.Cmd { return func() tea.Msg { return tui.ParentMenuMsg{} } }},
 		},
 	).SetUnimplementedMessage(unimplemented)
@@ -119,15 +200,18 @@ func buildMainMenu(cfg *internal.Config, logger *internal.Logger) tui.MenuModel
 		i18n.T("menu_ssh"),
 		"",
 		[]tui.MenuItem{
-			{ID: "install_keys", Label: i18n.T("ssh_install_keys"), Next: func(parent tui.MenuModel) tea.Model {
+			{ID: "install_keys", Label: i18n.T("ssh_install_keys"), Disabled: rootRequired, Next: func(parent tui.MenuModel) tea.Model {
 				return NewSSHInstallKeysWizard(parent, cfg, logger)
 			}},
 			{ID: "list_keys", Label: i18n.T("ssh_list_keys"), Next: func(parent tui.MenuModel) tea.Model {
 				return NewSSHListKeysModel(parent, cfg, logger)
 			}},
-			{ID: "disable_pwd", Label: i18n.T("ssh_disable_pwd"), Next: func(parent tui.MenuModel) tea.Model {
+			{ID: "disable_pwd", Label: i18n.T("ssh_disable_pwd"), Disabled: rootRequired, Next: func(parent tui.MenuModel) tea.Model {
 				return NewSSHDisablePasswordModel(parent, cfg, logger)
 			}},
+			{ID: "fail2ban", Label: i18n.T("menu_fail2ban"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewFail2banModel(parent, cfg, logger)
+			}},
 			{ID: "back", Label: i18n.T("menu_back"), Action: func() tea.Cmd { return func() tea.Msg { return tui.ParentMenuMsg{} } }},
 		},
 	).SetUnimplementedMessage(unimplemented)
@@ -136,8 +220,26 @@ func buildMainMenu(cfg *internal.Config, logger *internal.Logger) tui.MenuModel
 		i18n.T("app_title"),
 		subtitle,
 		[]tui.MenuItem{
+			{ID: "dashboard", Label: i18n.T("menu_dashboard"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewDashboardModel(parent, cfg, logger)
+			}},
 			{ID: "system", Label: i18n.T("menu_system"), Submenu: &systemMenu},
 			{ID: "ssh", Label: i18n.T("menu_ssh"), Submenu: &sshMenu},
+			{ID: "audit", Label: i18n.T("menu_audit"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewAuditModel(parent, cfg, logger)
+			}},
+			{ID: "drift", Label: i18n.T("menu_drift"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewDriftModel(parent, cfg, logger)
+			}},
+			{ID: "history", Label: i18n.T("menu_history"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewHistoryModel(parent, cfg, logger)
+			}},
+			{ID: "logs", Label: i18n.T("menu_logs"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewLogsModel(parent, cfg, logger)
+			}},
+			{ID: "update", Label: i18n.T("menu_update"), Next: func(parent tui.MenuModel) tea.Model {
+				return NewUpdateModel(parent, cfg, logger)
+			}},
 			{ID: "settings", Label: i18n.T("menu_settings"), Next: func(parent tui.MenuModel) tea.Model {
 				return NewSettingsModel(parent, cfg, logger, func() tui.MenuModel {
 					return buildMainMenu(cfg, logger)
@@ -152,12 +254,12 @@ func buildMainMenu(cfg *internal.Config, logger *internal.Logger) tui.MenuModel
 	return mainMenu
 }
 
-func maybeCheckUpdates(logger *internal.Logger) updateStatus {
+func maybeCheckUpdates(cfg *internal.Config, logger *internal.Logger) updateStatus {
 	if version == "" || version == "dev" {
 		return updateStatus{}
 	}
 
-	updater := internal.NewUpdater(version, logger)
+	updater := internal.NewUpdaterWithOptions(version, cfg.UpdateOptions(), logger)
 	latest, hasUpdate, err := updater.Check()
 	if err != nil {
 		logger.Warn("Auto update check failed: %v", err)
@@ -222,7 +324,7 @@ func startAsyncUpdateCheck(cfg *internal.Config) {
 	logger := newUpdateCheckLogger()
 
 	go func(expected int64) {
-		status := maybeCheckUpdates(logger)
+		status := maybeCheckUpdates(cfg, logger)
 		setUpdateStatusIfGeneration(expected, status)
 	}(generation)
 }
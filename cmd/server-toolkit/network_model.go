@@ -0,0 +1,517 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/network"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type networkStep int
+
+const (
+	networkStepLoading networkStep = iota
+	networkStepOverview
+	networkStepForm
+	networkStepPreview
+	networkStepApplying
+	networkStepGuard
+	networkStepResult
+)
+
+type networkStatusMsg struct {
+	status *network.Status
+	err    error
+}
+
+type networkPlanMsg struct {
+	plan *network.Plan
+	err  error
+}
+
+type networkAppliedMsg struct {
+	guard *network.Guard
+	err   error
+}
+
+type networkGuardDoneMsg struct {
+	reverted bool
+	err      error
+}
+
+// 表单字段
+const (
+	networkFieldAddresses = iota
+	networkFieldGateway4
+	networkFieldGateway6
+	networkFieldDNS
+	networkFieldSearch
+	networkFieldCount
+)
+
+// NetworkModel 网络：查看接口/路由，生成静态地址配置并在确认超时后自动回滚
+type NetworkModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step networkStep
+
+	status    *network.Status
+	statusErr error
+	cursor    int
+
+	inputs  []textinput.Model
+	focus   int
+	formErr error
+
+	config  *network.StaticConfig
+	plan    *network.Plan
+	preview viewport.Model
+
+	guard *network.Guard
+
+	confirmCursor int // 0: No, 1: Yes
+
+	resultMsg string
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewNetworkModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) NetworkModel {
+	placeholders := []string{
+		"192.0.2.10/24, 2001:db8::10/64",
+		"192.0.2.1",
+		"2001:db8::1",
+		"1.1.1.1, 8.8.8.8",
+		"example.com",
+	}
+	inputs := make([]textinput.Model, networkFieldCount)
+	for i, p := range placeholders {
+		ti := textinput.New()
+		ti.Placeholder = p
+		ti.CharLimit = 255
+		ti.Width = 44
+		inputs[i] = ti
+	}
+
+	return NetworkModel{
+		parent:  parent,
+		cfg:     cfg,
+		logger:  logger,
+		step:    networkStepLoading,
+		inputs:  inputs,
+		preview: viewport.New(58, 14),
+	}
+}
+
+func (m NetworkModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.loadCmd())
+}
+
+func (m NetworkModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case networkStatusMsg:
+		m.status = msg.status
+		m.statusErr = msg.err
+		if m.status != nil && m.cursor >= len(m.status.Interfaces) {
+			m.cursor = 0
+		}
+		m.step = networkStepOverview
+		return m, nil
+
+	case networkPlanMsg:
+		if msg.err != nil {
+			m.formErr = msg.err
+			return m, nil
+		}
+		m.plan = msg.plan
+		m.preview.SetContent(describePlan(msg.plan))
+		m.preview.GotoTop()
+		m.confirmCursor = 0
+		m.step = networkStepPreview
+		return m, nil
+
+	case networkAppliedMsg:
+		if msg.err != nil || msg.guard == nil {
+			m.resultErr = msg.err
+			m.resultMsg = i18n.T("network_applied")
+			m.step = networkStepResult
+			return m, nil
+		}
+		m.guard = msg.guard
+		m.step = networkStepGuard
+		return m, nil
+
+	case networkGuardDoneMsg:
+		m.guard = nil
+		m.resultErr = msg.err
+		m.resultMsg = i18n.T("network_kept")
+		if msg.reverted {
+			m.resultMsg = i18n.T("network_reverted")
+		}
+		m.step = networkStepResult
+		return m, nil
+
+	case tui.RefreshMenuMsg:
+		// 确认期限已过：定时任务会自行回滚，这里同步执行以便立即显示结果
+		if m.step == networkStepGuard && m.guard.Expired(time.Now()) {
+			m.step = networkStepApplying
+			return m, keepRefreshTickerCmd(msg, m.finishGuardCmd(false))
+		}
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			// 确认期间退出不会保留配置：定时任务到期后回滚
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case networkStepLoading, networkStepApplying:
+			return m, nil
+
+		case networkStepOverview:
+			return m.updateOverview(msg)
+
+		case networkStepForm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = networkStepOverview
+				return m, nil
+			case tea.KeyTab, tea.KeyDown:
+				return m.focusInput((m.focus + 1) % len(m.inputs))
+			case tea.KeyShiftTab, tea.KeyUp:
+				return m.focusInput((m.focus + len(m.inputs) - 1) % len(m.inputs))
+			case tea.KeyEnter:
+				m.formErr = nil
+				m.config = m.formConfig()
+				if err := m.config.Validate(); err != nil {
+					m.formErr = err
+					return m, nil
+				}
+				return m, m.planCmd()
+			}
+
+		case networkStepPreview:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = networkStepForm
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = networkStepForm
+					return m, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = networkStepApplying
+				return m, m.applyCmd()
+			}
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+
+		case networkStepGuard:
+			switch msg.String() {
+			case "y":
+				m.step = networkStepApplying
+				// 期限已过时回滚任务可能已执行，不再接受确认
+				return m, m.finishGuardCmd(!m.guard.Expired(time.Now()))
+			case "n":
+				m.step = networkStepApplying
+				return m, m.finishGuardCmd(false)
+			}
+			return m, nil
+
+		case networkStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = networkStepLoading
+				return m, m.loadCmd()
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == networkStepForm {
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+func (m NetworkModel) updateOverview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var count int
+	if m.status != nil {
+		count = len(m.status.Interfaces)
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.parent, nil
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown:
+		if m.cursor < count-1 {
+			m.cursor++
+		}
+	case tea.KeyEnter:
+		return m.startForm()
+	case tea.KeyRunes:
+		switch msg.String() {
+		case "e":
+			return m.startForm()
+		case "r":
+			m.step = networkStepLoading
+			return m, m.loadCmd()
+		}
+	}
+	return m, nil
+}
+
+// startForm 以接口当前地址、默认网关与 DNS 预填表单
+func (m NetworkModel) startForm() (tea.Model, tea.Cmd) {
+	if m.status == nil || len(m.status.Interfaces) == 0 || m.status.Stack == network.StackUnknown {
+		return m, nil
+	}
+	current := m.status.CurrentConfig(m.status.Interfaces[m.cursor].Name)
+	m.inputs[networkFieldAddresses].SetValue(strings.Join(current.Addresses, ", "))
+	m.inputs[networkFieldGateway4].SetValue(current.Gateway4)
+	m.inputs[networkFieldGateway6].SetValue(current.Gateway6)
+	m.inputs[networkFieldDNS].SetValue(strings.Join(current.DNS, ", "))
+	m.inputs[networkFieldSearch].SetValue(strings.Join(current.Search, ", "))
+	m.formErr = nil
+	m.step = networkStepForm
+	return m.focusInput(0)
+}
+
+func (m NetworkModel) focusInput(i int) (tea.Model, tea.Cmd) {
+	for j := range m.inputs {
+		m.inputs[j].Blur()
+	}
+	m.focus = i
+	m.inputs[i].Focus()
+	return m, textinput.Blink
+}
+
+// formConfig 读取表单（列表字段以逗号或空格分隔）
+func (m NetworkModel) formConfig() *network.StaticConfig {
+	list := func(i int) []string {
+		return strings.FieldsFunc(m.inputs[i].Value(), func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	return &network.StaticConfig{
+		Interface: m.status.Interfaces[m.cursor].Name,
+		Addresses: list(networkFieldAddresses),
+		Gateway4:  strings.TrimSpace(m.inputs[networkFieldGateway4].Value()),
+		Gateway6:  strings.TrimSpace(m.inputs[networkFieldGateway6].Value()),
+		DNS:       list(networkFieldDNS),
+		Search:    list(networkFieldSearch),
+	}
+}
+
+func (m NetworkModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("network_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case networkStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case networkStepOverview:
+		b.WriteString(m.overviewView())
+
+	case networkStepForm:
+		iface := m.status.Interfaces[m.cursor].Name
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("network_static_for", iface)) + "\n\n")
+		labels := []string{
+			i18n.T("network_addresses"),
+			i18n.T("network_gateway4"),
+			i18n.T("network_gateway6"),
+			i18n.T("network_dns"),
+			i18n.T("network_search"),
+		}
+		for i, label := range labels {
+			b.WriteString(tui.NormalStyle.Render(label) + "\n")
+			b.WriteString(m.inputs[i].View() + "\n")
+		}
+		if m.formErr != nil {
+			b.WriteString("\n" + tui.ErrorStyle.Render(m.formErr.Error()) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("network_form_keys")) + "\n")
+
+	case networkStepPreview:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("network_preview", m.plan.Stack)) + "\n")
+		b.WriteString(m.preview.View() + "\n\n")
+		b.WriteString(tui.WarningStyle.Render(i18n.T("network_guard_note", int(network.DefaultGuardTimeout.Seconds()))) + "\n\n")
+		b.WriteString(tui.NormalStyle.Render(i18n.T("network_confirm_apply")) + "\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case networkStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("network_applying")) + "\n")
+
+	case networkStepGuard:
+		remaining := max(int(time.Until(m.guard.Deadline).Seconds()), 0)
+		b.WriteString(tui.SuccessStyle.Render(i18n.T("network_applied")) + "\n\n")
+		b.WriteString(tui.WarningStyle.Render(i18n.T("network_guard_countdown", remaining)) + "\n\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("network_guard_keys")) + "\n")
+
+	case networkStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(m.resultMsg) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m NetworkModel) overviewView() string {
+	var b strings.Builder
+	if m.statusErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.statusErr)) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+		return b.String()
+	}
+
+	stack := string(m.status.Stack)
+	if m.status.Stack == network.StackUnknown {
+		stack = i18n.T("network_stack_unknown")
+	}
+	b.WriteString(tui.NormalStyle.Render(i18n.T("network_stack", stack)) + "\n")
+	if len(m.status.DNS) > 0 {
+		b.WriteString(tui.NormalStyle.Render(i18n.T("network_dns_current", strings.Join(m.status.DNS, ", "))) + "\n")
+	}
+
+	b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("network_interfaces")) + "\n")
+	if len(m.status.Interfaces) == 0 {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("network_no_interfaces")) + "\n")
+	}
+	for i, iface := range m.status.Interfaces {
+		state := i18n.T("network_down")
+		if iface.Up {
+			state = i18n.T("network_up")
+		}
+		line := fmt.Sprintf("%-12s %-5s %s", iface.Name, state, iface.MAC)
+		if i == m.cursor {
+			b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+		} else {
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+		for _, addr := range iface.Addrs {
+			b.WriteString("      " + tui.DimStyle.Render(addr) + "\n")
+		}
+	}
+
+	if len(m.status.Interfaces) > 0 {
+		name := m.status.Interfaces[m.cursor].Name
+		b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("network_routes", name)) + "\n")
+		routes := m.status.RoutesFor(name)
+		if len(routes) == 0 {
+			b.WriteString("  " + tui.DimStyle.Render("-") + "\n")
+		}
+		for _, r := range routes {
+			line := r.Dest
+			if r.Gateway != "" {
+				line += " via " + r.Gateway
+			}
+			if r.Proto != "" {
+				line += " (" + r.Proto + ")"
+			}
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+	}
+
+	keys := i18n.T("network_keys")
+	if m.status.Stack == network.StackUnknown {
+		keys = i18n.T("network_keys_readonly")
+	}
+	b.WriteString("\n" + tui.DimStyle.Render(keys) + "\n")
+	return b.String()
+}
+
+// describePlan 预览：将写入的文件与将执行的命令
+func describePlan(plan *network.Plan) string {
+	var b strings.Builder
+	for _, f := range plan.Files {
+		b.WriteString(i18n.T("network_plan_file", f.Path) + "\n")
+		b.WriteString(f.Content)
+		if !strings.HasSuffix(f.Content, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(plan.Apply) == 0 {
+		return b.String()
+	}
+	b.WriteString(i18n.T("network_plan_commands") + "\n")
+	for _, argv := range plan.Apply {
+		b.WriteString("  $ " + strings.Join(argv, " ") + "\n")
+	}
+	return b.String()
+}
+
+func (m NetworkModel) loadCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		status, err := network.NewManager(true, logger).GetStatus()
+		return networkStatusMsg{status: status, err: err}
+	}
+}
+
+func (m NetworkModel) planCmd() tea.Cmd {
+	stack := m.status.Stack
+	config := m.config
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		plan, err := network.NewManager(dryRun, logger).BuildPlan(stack, config)
+		return networkPlanMsg{plan: plan, err: err}
+	}
+}
+
+func (m NetworkModel) applyCmd() tea.Cmd {
+	plan := m.plan
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		guard, err := network.NewManager(dryRun, logger).Apply(plan, network.DefaultGuardTimeout)
+		return networkAppliedMsg{guard: guard, err: err}
+	}
+}
+
+func (m NetworkModel) finishGuardCmd(keep bool) tea.Cmd {
+	guard := m.guard
+	return func() tea.Msg {
+		if keep {
+			return networkGuardDoneMsg{err: guard.Confirm()}
+		}
+		return networkGuardDoneMsg{reverted: true, err: guard.Revert()}
+	}
+}
@@ -0,0 +1,651 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/packages"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+const (
+	maxStreamLines     = 1000
+	maxUpgradesPreview = 10
+	maxSearchResults   = 50 // 搜索结果最多列出的包数
+	maxChecklistRows   = 12 // 勾选列表一屏显示的行数
+)
+
+type packagesStep int
+
+const (
+	packagesStepMenu packagesStep = iota
+	packagesStepLoading
+	packagesStepUpgrades
+	packagesStepTools
+	packagesStepSearch
+	packagesStepConfirm
+	packagesStepRunning
+	packagesStepDone
+)
+
+type packagesOp int
+
+const (
+	packagesOpRefresh packagesOp = iota
+	packagesOpInstall
+	packagesOpUpgrade
+)
+
+type packagesUpgradesMsg struct {
+	upgrades []system.PackageUpgrade
+	err      error
+}
+
+type packagesToolsMsg struct {
+	installed map[string]bool
+}
+
+type packagesSearchMsg struct {
+	term      string
+	pkgs      []system.PackageInfo
+	installed map[string]bool
+	err       error
+}
+
+// PackagesModel 软件包管理：检查更新 / 安装常用工具 / 搜索安装 / 升级预览，命令输出实时显示
+type PackagesModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	mgr    *packages.Manager
+	mgrErr error
+
+	step       packagesStep
+	menuCursor int
+	menuItems  []string
+
+	upgrades []system.PackageUpgrade
+
+	// 勾选安装列表：常用工具或搜索结果
+	tools      []string
+	installed  map[string]bool
+	selected   map[string]bool
+	toolCursor int
+
+	searchInput textinput.Model
+	searchTerm  string            // 非空表示勾选列表为该关键字的搜索结果
+	searchTotal int               // 搜索结果总数（列表最多 maxSearchResults 个）
+	summaries   map[string]string // 搜索结果的包描述
+
+	op            packagesOp
+	confirmCursor int // 0: No, 1: Yes
+
+	stream   *outputStream
+	output   []string
+	viewport viewport.Model
+	runErr   error
+	logStart int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+
+	status string
+}
+
+func NewPackagesModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) PackagesModel {
+	dryRun := cfg != nil && cfg.DryRun
+
+	searchTI := textinput.New()
+	searchTI.Placeholder = "nginx"
+	searchTI.CharLimit = 64
+	searchTI.Width = 50
+
+	m := PackagesModel{
+		parent:      parent,
+		cfg:         cfg,
+		logger:      logger,
+		step:        packagesStepMenu,
+		menuItems:   []string{"check", "tools", "search", "upgrade", "back"},
+		tools:       packages.CommonTools,
+		selected:    make(map[string]bool),
+		searchInput: searchTI,
+		viewport:    viewport.New(58, 12),
+	}
+
+	distro, err := system.DetectDistro()
+	if err != nil {
+		m.mgrErr = err
+		return m
+	}
+	pm, err := system.DetectPackageManager(distro.Family)
+	if err != nil {
+		m.mgrErr = err
+		return m
+	}
+	m.mgr = packages.NewManager(pm, distro.Family, dryRun, logger)
+	return m
+}
+
+func (m PackagesModel) Init() tea.Cmd { return initRefreshTickerCmd(nil) }
+
+func (m PackagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case streamLineMsg:
+		if msg.stream != m.stream {
+			return m, nil
+		}
+		m.appendOutput(msg.line)
+		return m, m.stream.next()
+
+	case streamDoneMsg:
+		if msg.stream != m.stream {
+			return m, nil
+		}
+		m.runErr = msg.err
+		if msg.err == nil && m.op == packagesOpRefresh {
+			m.step = packagesStepLoading
+			return m, m.loadUpgradesCmd()
+		}
+		m.step = packagesStepDone
+		return m, nil
+
+	case packagesUpgradesMsg:
+		if msg.err != nil {
+			m.runErr = msg.err
+			m.step = packagesStepDone
+			return m, nil
+		}
+		m.upgrades = msg.upgrades
+		m.step = packagesStepUpgrades
+		return m, nil
+
+	case packagesToolsMsg:
+		m.installed = msg.installed
+		m.step = packagesStepTools
+		return m, nil
+
+	case packagesSearchMsg:
+		if msg.err != nil || len(msg.pkgs) == 0 {
+			m.status = i18n.T("packages_search_none", msg.term)
+			if msg.err != nil {
+				m.status = i18n.T("err_operation_failed", msg.err)
+			}
+			m.step = packagesStepSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		}
+		m.searchTerm = msg.term
+		m.searchTotal = len(msg.pkgs)
+		m.setChecklist(nil)
+		m.summaries = make(map[string]string, len(msg.pkgs))
+		for i, pkg := range msg.pkgs {
+			if i == maxSearchResults {
+				break
+			}
+			m.tools = append(m.tools, pkg.Name)
+			m.summaries[pkg.Name] = pkg.Summary
+		}
+		m.installed = msg.installed
+		m.step = packagesStepTools
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case packagesStepMenu:
+			return m.updateMenu(msg)
+
+		case packagesStepLoading:
+			return m, nil
+
+		case packagesStepUpgrades:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = packagesStepMenu
+				return m, nil
+			case tea.KeyEnter:
+				if len(m.upgrades) == 0 {
+					m.step = packagesStepMenu
+					return m, nil
+				}
+				m.op = packagesOpUpgrade
+				m.confirmCursor = 1
+				m.step = packagesStepConfirm
+				return m, nil
+			}
+
+		case packagesStepTools:
+			return m.updateTools(msg)
+
+		case packagesStepSearch:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.status = ""
+				m.searchInput.Blur()
+				m.step = packagesStepMenu
+				return m, nil
+			case tea.KeyEnter:
+				term := strings.TrimSpace(m.searchInput.Value())
+				if term == "" {
+					return m, nil
+				}
+				m.status = ""
+				m.searchInput.Blur()
+				m.step = packagesStepLoading
+				return m, m.searchCmd(term)
+			}
+			var cmd tea.Cmd
+			m.searchInput, cmd = m.searchInput.Update(msg)
+			return m, cmd
+
+		case packagesStepConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = packagesStepMenu
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = packagesStepMenu
+					return m, nil
+				}
+				return m.startOp(m.op)
+			}
+
+		case packagesStepRunning:
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+
+		case packagesStepDone:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = packagesStepMenu
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == packagesStepSearch {
+		m.searchInput, cmd = m.searchInput.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+func (m PackagesModel) updateMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.parent, nil
+	case tea.KeyUp, tea.KeyShiftTab:
+		m.status = ""
+		if m.menuCursor == 0 {
+			m.menuCursor = len(m.menuItems) - 1
+		} else {
+			m.menuCursor--
+		}
+	case tea.KeyDown, tea.KeyTab:
+		m.status = ""
+		if m.menuCursor == len(m.menuItems)-1 {
+			m.menuCursor = 0
+		} else {
+			m.menuCursor++
+		}
+	case tea.KeyEnter:
+		selected := m.menuItems[m.menuCursor]
+		if selected == "back" {
+			return m.parent, nil
+		}
+		if m.mgr == nil {
+			m.status = i18n.T("err_operation_failed", m.mgrErr)
+			return m, nil
+		}
+		m.status = ""
+		switch selected {
+		case "check":
+			return m.startOp(packagesOpRefresh)
+		case "tools":
+			m.searchTerm = ""
+			m.summaries = nil
+			m.setChecklist(packages.CommonTools)
+			m.step = packagesStepLoading
+			return m, m.loadToolsCmd()
+		case "search":
+			m.step = packagesStepSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		case "upgrade":
+			m.step = packagesStepLoading
+			return m, m.loadUpgradesCmd()
+		}
+	}
+	return m, nil
+}
+
+func (m PackagesModel) updateTools(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.status = ""
+		if m.searchTerm != "" {
+			// 搜索结果返回搜索框，可修改关键字重新搜索
+			m.step = packagesStepSearch
+			m.searchInput.Focus()
+			return m, textinput.Blink
+		}
+		m.step = packagesStepMenu
+	case tea.KeyUp, tea.KeyShiftTab:
+		if m.toolCursor == 0 {
+			m.toolCursor = len(m.tools) - 1
+		} else {
+			m.toolCursor--
+		}
+	case tea.KeyDown, tea.KeyTab:
+		if m.toolCursor == len(m.tools)-1 {
+			m.toolCursor = 0
+		} else {
+			m.toolCursor++
+		}
+	case tea.KeySpace:
+		tool := m.tools[m.toolCursor]
+		if !m.installed[tool] {
+			m.selected[tool] = !m.selected[tool]
+		}
+	case tea.KeyEnter:
+		if len(m.selectedTools()) == 0 {
+			m.status = i18n.T("packages_none_selected")
+			return m, nil
+		}
+		m.status = ""
+		m.op = packagesOpInstall
+		m.confirmCursor = 1
+		m.step = packagesStepConfirm
+	}
+	return m, nil
+}
+
+// setChecklist 切换勾选列表并清空已选，避免安装不在当前列表中的包
+func (m *PackagesModel) setChecklist(names []string) {
+	m.tools = names
+	m.toolCursor = 0
+	m.selected = make(map[string]bool)
+}
+
+func (m PackagesModel) startOp(op packagesOp) (tea.Model, tea.Cmd) {
+	if needsRoot(m.cfg) {
+		return newRootRequiredModel(m, m.cfg), nil
+	}
+	m.op = op
+	m.runErr = nil
+	m.output = nil
+	m.viewport.SetContent("")
+	m.stream = newOutputStream()
+	m.logStart = logMark(m.cfg)
+	m.step = packagesStepRunning
+
+	mgr := m.mgr
+	tools := m.selectedTools()
+
+	var fn func(w io.Writer) error
+	switch op {
+	case packagesOpRefresh:
+		fn = mgr.Refresh
+	case packagesOpInstall:
+		fn = func(w io.Writer) error { return mgr.Install(w, tools) }
+	default:
+		fn = func(w io.Writer) error { return mgr.Upgrade(w, nil) }
+	}
+
+	if op == packagesOpInstall {
+		m.selected = make(map[string]bool)
+	}
+	return m, m.stream.start(fn)
+}
+
+func (m *PackagesModel) appendOutput(line string) {
+	m.output = append(m.output, line)
+	if len(m.output) > maxStreamLines {
+		m.output = m.output[len(m.output)-maxStreamLines:]
+	}
+	m.viewport.SetContent(strings.Join(m.output, "\n"))
+	m.viewport.GotoBottom()
+}
+
+func (m PackagesModel) selectedTools() []string {
+	var tools []string
+	for _, tool := range m.tools {
+		if m.selected[tool] {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+func (m PackagesModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("packages_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+	if m.mgr != nil {
+		b.WriteString(tui.DimStyle.Render(i18n.T("packages_manager", m.mgr.Name())) + "\n\n")
+	}
+
+	switch m.step {
+	case packagesStepMenu:
+		for i, key := range m.menuItems {
+			label := m.menuLabel(key)
+			if i == m.menuCursor {
+				b.WriteString(tui.CursorStyle.Render("> "+label) + "\n")
+			} else {
+				b.WriteString(tui.NormalStyle.Render("  "+label) + "\n")
+			}
+		}
+
+	case packagesStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case packagesStepUpgrades:
+		b.WriteString(m.upgradesView())
+
+	case packagesStepTools:
+		if m.searchTerm != "" {
+			b.WriteString(tui.NormalStyle.Render(i18n.T("packages_search_results", m.searchTerm, m.searchTotal)) + "\n")
+			if m.searchTotal > len(m.tools) {
+				b.WriteString(tui.DimStyle.Render(i18n.T("packages_search_truncated", len(m.tools))) + "\n")
+			}
+			b.WriteString("\n")
+		} else {
+			b.WriteString(tui.NormalStyle.Render(i18n.T("packages_tools_prompt")) + "\n\n")
+		}
+		start, end := checklistWindow(m.toolCursor, len(m.tools))
+		for i := start; i < end; i++ {
+			tool := m.tools[i]
+			mark := "[ ]"
+			if m.installed[tool] {
+				mark = "[" + i18n.T("packages_installed_mark") + "]"
+			} else if m.selected[tool] {
+				mark = "[x]"
+			}
+			line := fmt.Sprintf("%s %s", mark, tool)
+			if summary := m.summaries[tool]; summary != "" {
+				line += "  " + summary
+			}
+			if r := []rune(line); len(r) > 56 {
+				line = string(r[:55]) + "…"
+			}
+			switch {
+			case i == m.toolCursor:
+				b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+			case m.installed[tool]:
+				b.WriteString(tui.DimStyle.Render("  "+line) + "\n")
+			default:
+				b.WriteString(tui.NormalStyle.Render("  "+line) + "\n")
+			}
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("packages_tools_hint")) + "\n")
+
+	case packagesStepSearch:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("packages_search_prompt")) + "\n\n")
+		b.WriteString(m.searchInput.View() + "\n")
+
+	case packagesStepConfirm:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("packages_actions")) + "\n")
+		if m.op == packagesOpInstall {
+			b.WriteString("  " + i18n.T("packages_action_install", strings.Join(m.selectedTools(), " ")) + "\n")
+		} else {
+			b.WriteString("  " + i18n.T("packages_action_upgrade", len(m.upgrades)) + "\n")
+		}
+		b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("packages_confirm_apply")) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case packagesStepRunning:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("packages_running")) + "\n\n")
+		b.WriteString(m.viewport.View() + "\n")
+
+	case packagesStepDone:
+		if m.runErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.runErr)) + "\n\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("success")) + "\n\n")
+		}
+		if len(m.output) > 0 {
+			b.WriteString(m.viewport.View() + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("packages_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	if m.status != "" {
+		b.WriteString("\n" + tui.ErrorStyle.Render(m.status) + "\n")
+	}
+
+	switch m.step {
+	case packagesStepMenu, packagesStepUpgrades, packagesStepTools, packagesStepSearch, packagesStepConfirm:
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")+" / "+i18n.T("press_esc")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m PackagesModel) menuLabel(key string) string {
+	switch key {
+	case "check":
+		return i18n.T("packages_check")
+	case "tools":
+		return i18n.T("packages_tools")
+	case "search":
+		return i18n.T("packages_search")
+	case "upgrade":
+		return i18n.T("packages_upgrade")
+	default:
+		return i18n.T("menu_back")
+	}
+}
+
+func (m PackagesModel) upgradesView() string {
+	var b strings.Builder
+
+	if len(m.upgrades) == 0 {
+		b.WriteString(tui.SuccessStyle.Render(i18n.T("packages_up_to_date")) + "\n")
+		return b.String()
+	}
+
+	security := packages.SecurityUpgrades(m.upgrades)
+	b.WriteString(tui.InfoStyle.Render(i18n.T("packages_pending", len(m.upgrades), len(security))) + "\n\n")
+
+	if len(security) > 0 {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("packages_security_list")) + "\n")
+		for _, line := range upgradeLines(security, maxUpgradesPreview) {
+			b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(tui.SubtitleStyle.Render(i18n.T("packages_all_list")) + "\n")
+	for _, line := range upgradeLines(m.upgrades, maxUpgradesPreview) {
+		b.WriteString("  " + tui.DimStyle.Render(line) + "\n")
+	}
+
+	b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("packages_upgrade_prompt")) + "\n")
+	return b.String()
+}
+
+func upgradeLines(upgrades []system.PackageUpgrade, limit int) []string {
+	var lines []string
+	for i, u := range upgrades {
+		if i == limit {
+			lines = append(lines, i18n.T("packages_more", len(upgrades)-limit))
+			break
+		}
+		if u.Current != "" {
+			lines = append(lines, fmt.Sprintf("%s %s -> %s", u.Name, u.Current, u.Candidate))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s %s", u.Name, u.Candidate))
+		}
+	}
+	return lines
+}
+
+func (m PackagesModel) loadUpgradesCmd() tea.Cmd {
+	mgr := m.mgr
+	return func() tea.Msg {
+		upgrades, err := mgr.PendingUpgrades()
+		return packagesUpgradesMsg{upgrades: upgrades, err: err}
+	}
+}
+
+// checklistWindow 返回勾选列表可见的 [start, end)，保持光标在窗口内
+func checklistWindow(cursor, n int) (int, int) {
+	if n <= maxChecklistRows {
+		return 0, n
+	}
+	start := min(max(cursor-maxChecklistRows/2, 0), n-maxChecklistRows)
+	return start, start + maxChecklistRows
+}
+
+func (m PackagesModel) searchCmd(term string) tea.Cmd {
+	mgr := m.mgr
+	return func() tea.Msg {
+		pkgs, err := mgr.Search(term)
+		if err != nil {
+			return packagesSearchMsg{term: term, err: err}
+		}
+		names := make([]string, 0, min(len(pkgs), maxSearchResults))
+		for i, pkg := range pkgs {
+			if i == maxSearchResults {
+				break
+			}
+			names = append(names, pkg.Name)
+		}
+		return packagesSearchMsg{term: term, pkgs: pkgs, installed: mgr.InstalledStatus(names)}
+	}
+}
+
+func (m PackagesModel) loadToolsCmd() tea.Cmd {
+	mgr := m.mgr
+	tools := m.tools
+	return func() tea.Msg {
+		return packagesToolsMsg{installed: mgr.InstalledStatus(tools)}
+	}
+}
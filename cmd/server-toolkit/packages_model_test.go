@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackagesSearchResultsChecklist(t *testing.T) {
+	i18n.Init()
+	m := NewPackagesModel(tui.NewMenu("main", "", nil), internal.Default(), internal.NewLogger(internal.ERROR, os.Stdout))
+	m.selected["curl"] = true
+
+	var pkgs []system.PackageInfo
+	for i := 0; i < maxSearchResults+10; i++ {
+		pkgs = append(pkgs, system.PackageInfo{Name: fmt.Sprintf("nginx-mod-%02d", i), Summary: "module"})
+	}
+	next, _ := m.Update(packagesSearchMsg{term: "nginx", pkgs: pkgs, installed: map[string]bool{"nginx-mod-00": true}})
+	m = next.(PackagesModel)
+
+	require.Equal(t, packagesStepTools, m.step)
+	assert.Len(t, m.tools, maxSearchResults)
+	assert.Equal(t, maxSearchResults+10, m.searchTotal)
+	// 切换列表时清空之前勾选的常用工具
+	assert.Empty(t, m.selectedTools())
+
+	// 已安装的包不能勾选
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = next.(PackagesModel)
+	assert.Empty(t, m.selectedTools())
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = next.(PackagesModel)
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = next.(PackagesModel)
+	assert.Equal(t, []string{"nginx-mod-01"}, m.selectedTools())
+	assert.Contains(t, m.View(), "nginx-mod-01  module")
+
+	// Esc 回到搜索框而不是菜单
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(PackagesModel)
+	assert.Equal(t, packagesStepSearch, m.step)
+
+	next, _ = m.Update(packagesSearchMsg{term: "nosuch"})
+	m = next.(PackagesModel)
+	assert.Equal(t, packagesStepSearch, m.step)
+	assert.Contains(t, m.status, "nosuch")
+}
+
+func TestChecklistWindow(t *testing.T) {
+	start, end := checklistWindow(3, 5)
+	assert.Equal(t, [2]int{0, 5}, [2]int{start, end})
+
+	start, end = checklistWindow(0, 50)
+	assert.Equal(t, [2]int{0, maxChecklistRows}, [2]int{start, end})
+
+	start, end = checklistWindow(49, 50)
+	assert.Equal(t, [2]int{50 - maxChecklistRows, 50}, [2]int{start, end})
+
+	start, end = checklistWindow(20, 50)
+	assert.True(t, start <= 20 && 20 < end)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+// isRoot 是否以 root 运行（测试中可替换）
+var isRoot = system.IsRoot
+
+// needsRoot 修改系统前是否缺少 root 权限；dry-run 不修改系统，无需 root
+func needsRoot(cfg *internal.Config) bool {
+	return !isRoot() && (cfg == nil || !cfg.DryRun)
+}
+
+// rootOnly 非 root 时置灰仅用于修改系统的菜单项
+func rootOnly(cfg *internal.Config) string {
+	if needsRoot(cfg) {
+		return i18n.T("menu_root_required")
+	}
+	return ""
+}
+
+// canWrite 当前用户能否写入 path（用于自更新：二进制位于用户目录时无需 root）
+func canWrite(path string) bool {
+	const wOK = 2 // access(2) 的 W_OK
+	return syscall.Access(path, wOK) == nil
+}
+
+type sudoExitedMsg struct {
+	err error
+}
+
+// RootRequiredModel 修改系统前缺少 root 权限时的提示：可通过 sudo 以 root 重新启动，退出后回到原界面
+type RootRequiredModel struct {
+	back tea.Model
+	cfg  *internal.Config
+	err  error
+}
+
+// newRootRequiredModel 在各向导执行修改前调用：Esc 回到 back（停留在确认步骤）
+func newRootRequiredModel(back tea.Model, cfg *internal.Config) RootRequiredModel {
+	return RootRequiredModel{back: back, cfg: cfg}
+}
+
+func (m RootRequiredModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(nil)
+}
+
+// sudoCmd 通过 sudo 以相同参数重新启动本程序
+func sudoCmd() tea.Cmd {
+	sudo, err := exec.LookPath("sudo")
+	if err != nil {
+		return func() tea.Msg { return sudoExitedMsg{err: errors.New(i18n.T("root_sudo_missing"))} }
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return func() tea.Msg { return sudoExitedMsg{err: err} }
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	cmd := exec.Command(sudo, append([]string{exe}, os.Args[1:]...)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return sudoExitedMsg{err: err} })
+}
+
+func (m RootRequiredModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sudoExitedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m.back, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEsc:
+			return m.back, nil
+		case tea.KeyRunes:
+			if strings.EqualFold(msg.String(), "s") {
+				m.err = nil
+				return m, sudoCmd()
+			}
+		}
+	}
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m RootRequiredModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("root_required_title")) + "\n\n")
+	b.WriteString(tui.WarningStyle.Render(i18n.T("root_required_desc")) + "\n\n")
+	b.WriteString(tui.DimStyle.Render(i18n.T("root_required_dryrun_hint")) + "\n")
+	if m.err != nil {
+		b.WriteString("\n" + tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.err)) + "\n")
+	}
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("root_required_keys")) + "\n")
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWizardRequiresRootBeforeApplying(t *testing.T) {
+	i18n.Init()
+	old := isRoot
+	isRoot = func() bool { return false }
+	t.Cleanup(func() { isRoot = old })
+
+	cfg := internal.Default()
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	parent := tui.NewMenu("main", "", nil)
+
+	var model tea.Model = NewServicesModel(parent, cfg, logger)
+	model, _ = model.Update(servicesListMsg{units: []system.ServiceUnit{{Name: "nginx.service", Active: "active"}}})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.Nil(t, cmd)
+
+	// 未执行操作，停在 root 提示；Esc 回到确认步骤
+	gate, ok := model.(RootRequiredModel)
+	require.True(t, ok)
+	assert.Contains(t, gate.View(), i18n.T("root_required_title"))
+	model, _ = gate.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	services := model.(ServicesModel)
+	assert.Equal(t, servicesStepConfirm, services.step)
+
+	// sudo 会话失败时显示错误，成功后回到原界面
+	model, _ = gate.Update(sudoExitedMsg{err: assert.AnError})
+	assert.Contains(t, model.View(), assert.AnError.Error())
+	model, _ = gate.Update(sudoExitedMsg{})
+	assert.IsType(t, ServicesModel{}, model)
+
+	// dry-run 不修改系统，无需 root
+	cfg.DryRun = true
+	assert.False(t, needsRoot(cfg))
+	assert.Empty(t, rootOnly(cfg))
+	cfg.DryRun = false
+	assert.NotEmpty(t, rootOnly(cfg))
+}
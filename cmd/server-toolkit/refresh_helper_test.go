@@ -27,6 +27,22 @@ func TestWizardModelsKeepRefreshTicker(t *testing.T) {
 		NewSSHInstallKeysWizard(parent, cfg, logger),
 		NewSSHListKeysModel(parent, cfg, logger),
 		NewSSHDisablePasswordModel(parent, cfg, logger),
+		NewAutoUpgradeWizard(parent, cfg, logger),
+		NewPackagesModel(parent, cfg, logger),
+		NewFail2banModel(parent, cfg, logger),
+		NewDashboardModel(parent, cfg, logger),
+		NewServicesModel(parent, cfg, logger),
+		NewCloudInitExportModel(parent, cfg, logger),
+		NewCloudInitModel(parent, cfg, logger),
+		NewHostsModel(parent, cfg, logger),
+		NewNetworkModel(parent, cfg, logger),
+		NewResolverModel(parent, cfg, logger),
+		NewAuditModel(parent, cfg, logger),
+		NewDriftModel(parent, cfg, logger),
+		NewHistoryModel(parent, cfg, logger),
+		NewLogsModel(parent, cfg, logger),
+		NewUpdateModel(parent, cfg, logger),
+		newRootRequiredModel(parent, cfg),
 	}
 
 	for _, model := range models {
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/network"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type resolverStep int
+
+const (
+	resolverStepLoading resolverStep = iota
+	resolverStepOverview
+	resolverStepForm
+	resolverStepSymlink
+	resolverStepPreview
+	resolverStepApplying
+	resolverStepResult
+)
+
+type resolverStatusMsg struct {
+	status *network.ResolverStatus
+	err    error
+}
+
+type resolverPlanMsg struct {
+	plan *network.Plan
+	err  error
+}
+
+type resolverAppliedMsg struct {
+	err error
+}
+
+// resolverDoTModes DNS-over-TLS 可选值（"" 表示沿用 resolved 默认）
+var resolverDoTModes = []string{"", network.DoTOff, network.DoTOpportunistic, network.DoTStrict}
+
+const (
+	resolverFieldNameservers = iota
+	resolverFieldSearch
+	resolverFieldDoT // 仅 systemd-resolved
+)
+
+// ResolverModel DNS 解析器：显示 resolv.conf 的管理方与生效的 nameserver，并在正确的位置修改
+type ResolverModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step resolverStep
+
+	status    *network.ResolverStatus
+	statusErr error
+
+	inputs  []textinput.Model
+	focus   int
+	dot     int // resolverDoTModes 下标
+	formErr error
+
+	config  *network.ResolverConfig
+	plan    *network.Plan
+	preview viewport.Model
+
+	confirmCursor int // 0: No, 1: Yes
+
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewResolverModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) ResolverModel {
+	ns := textinput.New()
+	ns.Placeholder = "1.1.1.1, 9.9.9.9"
+	ns.CharLimit = 255
+	ns.Width = 44
+
+	search := textinput.New()
+	search.Placeholder = "example.com"
+	search.CharLimit = 255
+	search.Width = 44
+
+	return ResolverModel{
+		parent:  parent,
+		cfg:     cfg,
+		logger:  logger,
+		step:    resolverStepLoading,
+		inputs:  []textinput.Model{ns, search},
+		preview: viewport.New(58, 10),
+	}
+}
+
+func (m ResolverModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.loadCmd())
+}
+
+func (m ResolverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case resolverStatusMsg:
+		m.status = msg.status
+		m.statusErr = msg.err
+		m.step = resolverStepOverview
+		return m, nil
+
+	case resolverPlanMsg:
+		m.confirmCursor = 0
+		if errors.Is(msg.err, network.ErrResolvConfSymlink) {
+			m.step = resolverStepSymlink
+			return m, nil
+		}
+		if msg.err != nil {
+			m.formErr = msg.err
+			m.step = resolverStepForm
+			return m, nil
+		}
+		m.plan = msg.plan
+		m.preview.SetContent(describePlan(msg.plan))
+		m.preview.GotoTop()
+		m.step = resolverStepPreview
+		return m, nil
+
+	case resolverAppliedMsg:
+		m.resultErr = msg.err
+		m.step = resolverStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case resolverStepLoading, resolverStepApplying:
+			return m, nil
+
+		case resolverStepOverview:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyEnter:
+				return m.startForm()
+			case tea.KeyRunes:
+				switch msg.String() {
+				case "e":
+					return m.startForm()
+				case "r":
+					m.step = resolverStepLoading
+					return m, m.loadCmd()
+				}
+			}
+			return m, nil
+
+		case resolverStepForm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = resolverStepOverview
+				return m, nil
+			case tea.KeyTab, tea.KeyDown:
+				return m.focusField((m.focus + 1) % m.fieldCount())
+			case tea.KeyShiftTab, tea.KeyUp:
+				return m.focusField((m.focus + m.fieldCount() - 1) % m.fieldCount())
+			case tea.KeyEnter:
+				m.formErr = nil
+				m.config = m.formConfig()
+				return m, m.planCmd()
+			}
+			if m.focus == resolverFieldDoT {
+				switch msg.String() {
+				case "left":
+					m.dot = (m.dot + len(resolverDoTModes) - 1) % len(resolverDoTModes)
+				case "right", " ":
+					m.dot = (m.dot + 1) % len(resolverDoTModes)
+				}
+				return m, nil
+			}
+
+		case resolverStepSymlink:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = resolverStepForm
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = resolverStepForm
+					return m, nil
+				}
+				m.config.ReplaceSymlink = true
+				return m, m.planCmd()
+			}
+			return m, nil
+
+		case resolverStepPreview:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = resolverStepForm
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = resolverStepForm
+					return m, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = resolverStepApplying
+				return m, m.applyCmd()
+			}
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+
+		case resolverStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = resolverStepLoading
+				return m, m.loadCmd()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.step == resolverStepForm && m.focus < len(m.inputs) {
+		m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+// fieldCount 表单字段数（DoT 仅在 systemd-resolved 下可设置）
+func (m ResolverModel) fieldCount() int {
+	if m.status != nil && m.status.Mode == network.ResolverResolved {
+		return len(m.inputs) + 1
+	}
+	return len(m.inputs)
+}
+
+// startForm 以当前生效的 nameserver、搜索域与 DoT 设置预填表单
+func (m ResolverModel) startForm() (tea.Model, tea.Cmd) {
+	if m.status == nil {
+		return m, nil
+	}
+	m.inputs[resolverFieldNameservers].SetValue(strings.Join(m.status.Nameservers, ", "))
+	m.inputs[resolverFieldSearch].SetValue(strings.Join(m.status.Search, ", "))
+	m.dot = 0
+	for i, mode := range resolverDoTModes {
+		if mode == m.status.DNSOverTLS {
+			m.dot = i
+		}
+	}
+	m.formErr = nil
+	m.step = resolverStepForm
+	return m.focusField(0)
+}
+
+func (m ResolverModel) focusField(i int) (tea.Model, tea.Cmd) {
+	for j := range m.inputs {
+		m.inputs[j].Blur()
+	}
+	m.focus = i
+	if i < len(m.inputs) {
+		m.inputs[i].Focus()
+		return m, textinput.Blink
+	}
+	return m, nil
+}
+
+func (m ResolverModel) formConfig() *network.ResolverConfig {
+	list := func(i int) []string {
+		return strings.FieldsFunc(m.inputs[i].Value(), func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	c := &network.ResolverConfig{
+		Nameservers: list(resolverFieldNameservers),
+		Search:      list(resolverFieldSearch),
+	}
+	if m.status.Mode == network.ResolverResolved {
+		c.DNSOverTLS = resolverDoTModes[m.dot]
+	}
+	return c
+}
+
+func (m ResolverModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("resolver_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case resolverStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case resolverStepOverview:
+		if m.statusErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.statusErr)) + "\n")
+			b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_esc")) + "\n")
+			break
+		}
+		b.WriteString(tui.NormalStyle.Render(i18n.T("resolver_mode", m.status.Mode)) + "\n")
+		if m.status.Symlink != "" {
+			b.WriteString(tui.DimStyle.Render(i18n.T("resolver_symlink", m.status.Symlink)) + "\n")
+		}
+		b.WriteString("\n" + tui.SubtitleStyle.Render(i18n.T("resolver_nameservers")) + "\n")
+		if len(m.status.Nameservers) == 0 {
+			b.WriteString("  " + tui.DimStyle.Render("-") + "\n")
+		}
+		for _, ns := range m.status.Nameservers {
+			b.WriteString("  " + tui.NormalStyle.Render(ns) + "\n")
+		}
+		search := "-"
+		if len(m.status.Search) > 0 {
+			search = strings.Join(m.status.Search, " ")
+		}
+		b.WriteString("\n" + tui.NormalStyle.Render(i18n.T("resolver_search", search)) + "\n")
+		if m.status.Mode == network.ResolverResolved {
+			b.WriteString(tui.NormalStyle.Render(i18n.T("resolver_dot", dotLabel(m.status.DNSOverTLS))) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("resolver_target_"+string(m.status.Mode))) + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("resolver_keys")) + "\n")
+
+	case resolverStepForm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("resolver_nameservers_input")) + "\n")
+		b.WriteString(m.inputs[resolverFieldNameservers].View() + "\n")
+		b.WriteString(tui.NormalStyle.Render(i18n.T("network_search")) + "\n")
+		b.WriteString(m.inputs[resolverFieldSearch].View() + "\n")
+		if m.fieldCount() > len(m.inputs) {
+			line := i18n.T("resolver_dot", "< "+dotLabel(resolverDoTModes[m.dot])+" >")
+			if m.focus == resolverFieldDoT {
+				b.WriteString(tui.CursorStyle.Render("> "+line) + "\n")
+			} else {
+				b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+			}
+			b.WriteString(tui.DimStyle.Render(i18n.T("resolver_dot_hint")) + "\n")
+		}
+		if m.formErr != nil {
+			b.WriteString("\n" + tui.ErrorStyle.Render(m.formErr.Error()) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("resolver_form_keys")) + "\n")
+
+	case resolverStepSymlink:
+		b.WriteString(tui.WarningStyle.Render(i18n.T("resolver_symlink_warn", m.status.Symlink)) + "\n\n")
+		b.WriteString(tui.NormalStyle.Render(i18n.T("resolver_symlink_confirm")) + "\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case resolverStepPreview:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("resolver_preview")) + "\n")
+		b.WriteString(m.preview.View() + "\n\n")
+		b.WriteString(tui.NormalStyle.Render(i18n.T("resolver_confirm_apply")) + "\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case resolverStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("network_applying")) + "\n")
+
+	case resolverStepResult:
+		if m.resultErr != nil {
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		} else {
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("resolver_applied")) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+// dotLabel DoT 取值的显示文本
+func dotLabel(mode string) string {
+	if mode == "" {
+		return i18n.T("resolver_dot_default")
+	}
+	return mode
+}
+
+func (m ResolverModel) loadCmd() tea.Cmd {
+	logger := m.logger
+	return func() tea.Msg {
+		status, err := network.NewManager(true, logger).GetResolverStatus()
+		return resolverStatusMsg{status: status, err: err}
+	}
+}
+
+func (m ResolverModel) planCmd() tea.Cmd {
+	status := m.status
+	config := m.config
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		plan, err := network.NewManager(dryRun, logger).ResolverPlan(status, config)
+		return resolverPlanMsg{plan: plan, err: err}
+	}
+}
+
+func (m ResolverModel) applyCmd() tea.Cmd {
+	plan := m.plan
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		return resolverAppliedMsg{err: network.NewManager(dryRun, logger).ApplyResolver(plan)}
+	}
+}
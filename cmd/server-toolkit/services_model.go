@@ -0,0 +1,438 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+const (
+	servicesPageSize     = 12
+	serviceJournalLines  = 30
+	serviceNameColumnLen = 28
+)
+
+type servicesStep int
+
+const (
+	servicesStepLoading servicesStep = iota
+	servicesStepList
+	servicesStepConfirm
+	servicesStepRunning
+	servicesStepDetail
+)
+
+// serviceActions 按键到服务操作的映射
+var serviceActions = map[string]string{
+	"s": "start",
+	"x": "stop",
+	"r": "restart",
+	"e": "enable",
+	"d": "disable",
+}
+
+type servicesListMsg struct {
+	units []system.ServiceUnit
+	err   error
+}
+
+type serviceActionMsg struct {
+	action string
+	name   string
+	err    error
+}
+
+type serviceDetailMsg struct {
+	name    string
+	status  string
+	journal []string
+	err     error
+}
+
+// ServicesModel 服务管理：列表 / 过滤 / 启停 / 开机启动 / 日志详情
+type ServicesModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step servicesStep
+
+	units   []system.ServiceUnit
+	listErr error
+	cursor  int
+
+	filter    textinput.Model
+	filtering bool
+
+	action        string
+	target        string
+	confirmCursor int // 0: No, 1: Yes
+
+	detailName string
+	detail     viewport.Model
+
+	status    string
+	statusErr bool
+}
+
+func NewServicesModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) ServicesModel {
+	ti := textinput.New()
+	ti.Placeholder = i18n.T("services_filter_placeholder")
+	ti.CharLimit = 64
+	ti.Width = 40
+
+	return ServicesModel{
+		parent: parent,
+		cfg:    cfg,
+		logger: logger,
+		step:   servicesStepLoading,
+		filter: ti,
+		detail: viewport.New(58, 16),
+	}
+}
+
+func (m ServicesModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(loadServicesCmd())
+}
+
+func (m ServicesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case servicesListMsg:
+		m.units = msg.units
+		m.listErr = msg.err
+		if m.cursor >= len(m.visible()) {
+			m.cursor = 0
+		}
+		if m.step == servicesStepLoading || m.step == servicesStepRunning {
+			m.step = servicesStepList
+		}
+		return m, nil
+
+	case serviceActionMsg:
+		if msg.err != nil {
+			m.status = i18n.T("err_operation_failed", msg.err)
+			m.statusErr = true
+		} else {
+			m.status = i18n.T("services_action_done", msg.action, msg.name)
+			m.statusErr = false
+		}
+		return m, loadServicesCmd()
+
+	case serviceDetailMsg:
+		if msg.name != m.detailName {
+			return m, nil
+		}
+		m.detail.SetContent(detailContent(msg))
+		m.detail.GotoBottom()
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case servicesStepLoading, servicesStepRunning:
+			return m, nil
+
+		case servicesStepList:
+			if m.filtering {
+				switch msg.Type {
+				case tea.KeyEsc:
+					m.filter.SetValue("")
+					fallthrough
+				case tea.KeyEnter:
+					m.filtering = false
+					m.filter.Blur()
+					m.cursor = 0
+					return m, nil
+				}
+				var cmd tea.Cmd
+				m.filter, cmd = m.filter.Update(msg)
+				m.cursor = 0
+				return m, cmd
+			}
+			return m.updateList(msg)
+
+		case servicesStepConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = servicesStepList
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = servicesStepList
+					return m, nil
+				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.step = servicesStepRunning
+				return m, m.actionCmd(m.action, m.target)
+			}
+
+		case servicesStepDetail:
+			switch msg.Type {
+			case tea.KeyEsc, tea.KeyEnter:
+				m.step = servicesStepList
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.detail, cmd = m.detail.Update(msg)
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.filtering {
+		m.filter, cmd = m.filter.Update(msg)
+	}
+	return m, keepRefreshTickerCmd(msg, cmd)
+}
+
+func (m ServicesModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	visible := m.visible()
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.parent, nil
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case tea.KeyDown:
+		if m.cursor < len(visible)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case tea.KeyPgUp:
+		m.cursor = max(m.cursor-servicesPageSize, 0)
+		return m, nil
+	case tea.KeyPgDown:
+		m.cursor = max(min(m.cursor+servicesPageSize, len(visible)-1), 0)
+		return m, nil
+	case tea.KeyEnter:
+		if len(visible) == 0 {
+			return m, nil
+		}
+		m.detailName = visible[m.cursor].Name
+		m.detail.SetContent(i18n.T("loading"))
+		m.step = servicesStepDetail
+		return m, loadServiceDetailCmd(m.detailName)
+	case tea.KeyRunes:
+		key := msg.String()
+		switch key {
+		case "/":
+			m.filtering = true
+			m.filter.Focus()
+			return m, textinput.Blink
+		case "l":
+			m.status = ""
+			return m, loadServicesCmd()
+		}
+		if action, ok := serviceActions[key]; ok && len(visible) > 0 {
+			m.action = action
+			m.target = visible[m.cursor].Name
+			m.confirmCursor = 0
+			m.status = ""
+			m.step = servicesStepConfirm
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m ServicesModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("services_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case servicesStepLoading:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("loading")) + "\n")
+
+	case servicesStepList:
+		b.WriteString(m.listView())
+
+	case servicesStepConfirm:
+		b.WriteString(tui.NormalStyle.Render(i18n.T("services_confirm", m.action, m.target)) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case servicesStepRunning:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("services_running", m.action, m.target)) + "\n")
+
+	case servicesStepDetail:
+		b.WriteString(tui.SubtitleStyle.Render(m.detailName) + "\n")
+		b.WriteString(m.detail.View() + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("services_detail_keys")) + "\n")
+	}
+
+	if m.status != "" && m.step == servicesStepList {
+		style := tui.SuccessStyle
+		if m.statusErr {
+			style = tui.ErrorStyle
+		}
+		b.WriteString("\n" + style.Render(m.status) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m ServicesModel) listView() string {
+	var b strings.Builder
+
+	if m.listErr != nil {
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.listErr)) + "\n")
+	}
+
+	if m.filtering || m.filter.Value() != "" {
+		b.WriteString(tui.NormalStyle.Render(i18n.T("services_filter")) + " " + m.filter.View() + "\n\n")
+	}
+
+	visible := m.visible()
+	if len(visible) == 0 && m.listErr == nil {
+		b.WriteString("  " + tui.DimStyle.Render(i18n.T("services_empty")) + "\n")
+	}
+
+	start := 0
+	if m.cursor >= servicesPageSize {
+		start = m.cursor - servicesPageSize + 1
+	}
+	end := min(start+servicesPageSize, len(visible))
+	for i := start; i < end; i++ {
+		unit := visible[i]
+		name := unit.Name
+		if len(name) > serviceNameColumnLen {
+			name = name[:serviceNameColumnLen-1] + "…"
+		}
+		state := serviceStateStyle(unit.Active).Render(fmt.Sprintf("%-10s %s", unit.Active, unit.Sub))
+		line := fmt.Sprintf("%-*s ", serviceNameColumnLen, name)
+		if i == m.cursor {
+			b.WriteString(tui.SelectedStyle.Render("> "+line) + state + "\n")
+		} else {
+			b.WriteString("  " + tui.NormalStyle.Render(line) + state + "\n")
+		}
+	}
+	if len(visible) > 0 {
+		b.WriteString(tui.DimStyle.Render(fmt.Sprintf("  %d/%d", m.cursor+1, len(visible))) + "\n")
+	}
+
+	b.WriteString("\n" + tui.DimStyle.Render(i18n.T("services_keys")) + "\n")
+	return b.String()
+}
+
+// visible 返回过滤后的服务列表
+func (m ServicesModel) visible() []system.ServiceUnit {
+	query := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	if query == "" {
+		return m.units
+	}
+	var result []system.ServiceUnit
+	for _, unit := range m.units {
+		if strings.Contains(strings.ToLower(unit.Name), query) ||
+			strings.Contains(strings.ToLower(unit.Description), query) ||
+			unit.Active == query {
+			result = append(result, unit)
+		}
+	}
+	return result
+}
+
+func serviceStateStyle(active string) lipgloss.Style {
+	switch active {
+	case "active":
+		return tui.SuccessStyle
+	case "failed":
+		return tui.ErrorStyle
+	case "activating", "deactivating", "reloading":
+		return tui.WarningStyle
+	default:
+		return tui.DimStyle
+	}
+}
+
+func detailContent(msg serviceDetailMsg) string {
+	var b strings.Builder
+	if msg.status != "" {
+		b.WriteString(strings.TrimRight(msg.status, "\n") + "\n\n")
+	}
+	if msg.err != nil {
+		b.WriteString(i18n.T("err_operation_failed", msg.err) + "\n")
+		return b.String()
+	}
+	b.WriteString(i18n.T("services_journal") + "\n")
+	if len(msg.journal) == 0 {
+		b.WriteString(i18n.T("services_journal_empty") + "\n")
+	}
+	for _, line := range msg.journal {
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func loadServicesCmd() tea.Cmd {
+	return func() tea.Msg {
+		units, err := system.NewServiceManager().ListServices()
+		return servicesListMsg{units: units, err: err}
+	}
+}
+
+func loadServiceDetailCmd(name string) tea.Cmd {
+	return func() tea.Msg {
+		svc := system.NewServiceManager()
+		status, _ := svc.GetServiceStatus(name)
+		journal, err := svc.JournalLines(name, serviceJournalLines)
+		return serviceDetailMsg{name: name, status: status, journal: journal, err: err}
+	}
+}
+
+func (m ServicesModel) actionCmd(action, name string) tea.Cmd {
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	logger := m.logger
+	return func() tea.Msg {
+		if dryRun {
+			internal.NewDryRunManager(true, logger).LogServiceOperation(action, name)
+			return serviceActionMsg{action: action, name: name}
+		}
+
+		svc := system.NewServiceManager()
+		var err error
+		switch action {
+		case "start":
+			err = svc.Start(name)
+		case "stop":
+			err = svc.Stop(name)
+		case "restart":
+			err = svc.Restart(name)
+		case "enable":
+			err = svc.Enable(name)
+		case "disable":
+			err = svc.Disable(name)
+		default:
+			err = fmt.Errorf("unknown action: %s", action)
+		}
+		if err == nil {
+			logger.Info("Service %s: %s", action, name)
+		}
+		return serviceActionMsg{action: action, name: name, err: err}
+	}
+}
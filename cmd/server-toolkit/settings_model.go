@@ -8,6 +8,7 @@ import (
 
 	"github.com/Akuma-real/server-toolkit/internal"
 	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
 	"github.com/Akuma-real/server-toolkit/pkg/tui"
 )
 
@@ -39,6 +40,7 @@ func NewSettingsModel(parent tui.MenuModel, cfg *internal.Config, logger *intern
 			"dryrun",
 			"loglevel",
 			"autoupdate",
+			"publicip",
 			"back",
 		},
 	}
@@ -111,6 +113,8 @@ func (m settingsModel) applySelection() (tea.Model, tea.Cmd) {
 		nextCfg.LogLevel = nextLogLevel(nextCfg.LogLevel)
 	case "autoupdate":
 		nextCfg.AutoUpdate = !nextCfg.AutoUpdate
+	case "publicip":
+		nextCfg.PublicIPLookup = !nextCfg.PublicIPLookup
 	default:
 		return m, nil
 	}
@@ -125,6 +129,8 @@ func (m settingsModel) applySelection() (tea.Model, tea.Cmd) {
 	switch selected {
 	case "lang":
 		i18n.SetLanguage(m.cfg.Language)
+	case "dryrun":
+		system.ConfigureRunner(m.cfg.DryRun, m.logger)
 	case "loglevel":
 		if m.logger != nil {
 			m.logger.SetLevel(internal.ParseLevel(m.cfg.LogLevel))
@@ -182,6 +188,8 @@ func (m settingsModel) itemLabel(key string) string {
 		return fmt.Sprintf("%s: %s", i18n.T("settings_loglevel"), m.cfg.LogLevel)
 	case "autoupdate":
 		return fmt.Sprintf("%s: %s", i18n.T("settings_autoupdate"), onOff(m.cfg.AutoUpdate))
+	case "publicip":
+		return fmt.Sprintf("%s: %s", i18n.T("settings_publicip"), onOff(m.cfg.PublicIPLookup))
 	default:
 		return i18n.T("menu_back")
 	}
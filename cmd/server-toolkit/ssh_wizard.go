@@ -50,7 +50,8 @@ type SSHInstallKeysWizard struct {
 
 	status string
 
-	result sshKeysResultMsg
+	result   sshKeysResultMsg
+	logStart int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
 }
 
 func NewSSHInstallKeysWizard(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) SSHInstallKeysWizard {
@@ -104,7 +105,7 @@ func (m SSHInstallKeysWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				m.status = ""
 				if strings.TrimSpace(m.userInput.Value()) == "" {
-					m.status = errors.New(i18n.T("err_invalid_input")).Error()
+					m.status = i18n.T("err_invalid_input")
 					return m, nil
 				}
 				m.userInput.Blur()
@@ -149,7 +150,7 @@ func (m SSHInstallKeysWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				m.status = ""
 				if strings.TrimSpace(m.valueInput.Value()) == "" {
-					m.status = errors.New(i18n.T("err_invalid_input")).Error()
+					m.status = i18n.T("err_invalid_input")
 					return m, nil
 				}
 				m.valueInput.Blur()
@@ -191,6 +192,10 @@ func (m SSHInstallKeysWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.confirmCursor == 0 {
 					return m.parent, nil
 				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
 				m.step = sshWizardStepApplying
 				return m, m.applyCmd()
 			}
@@ -199,6 +204,9 @@ func (m SSHInstallKeysWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case sshWizardStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
 			switch msg.Type {
 			case tea.KeyEnter, tea.KeyEsc:
 				return m.parent, nil
@@ -276,6 +284,7 @@ func (m SSHInstallKeysWizard) View() string {
 			b.WriteString("\n" + tui.DimStyle.Render(line))
 		}
 		b.WriteString("\n\n" + tui.DimStyle.Render(i18n.T("ssh_wizard_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
 	}
 
 	if m.status != "" {
@@ -350,8 +359,9 @@ type SSHListKeysModel struct {
 	step      sshWizardStep
 	userInput textinput.Model
 
-	status string
-	result sshKeysResultMsg
+	status   string
+	result   sshKeysResultMsg
+	logStart int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
 }
 
 func NewSSHListKeysModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) SSHListKeysModel {
@@ -393,16 +403,20 @@ func (m SSHListKeysModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				m.status = ""
 				if strings.TrimSpace(m.userInput.Value()) == "" {
-					m.status = errors.New(i18n.T("err_invalid_input")).Error()
+					m.status = i18n.T("err_invalid_input")
 					return m, nil
 				}
 				m.userInput.Blur()
+				m.logStart = logMark(m.cfg)
 				m.step = sshWizardStepApplying
 				return m, m.listCmd()
 			}
 		case sshWizardStepApplying:
 			return m, nil
 		case sshWizardStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
 			switch msg.Type {
 			case tea.KeyEnter, tea.KeyEsc:
 				return m.parent, nil
@@ -441,6 +455,7 @@ func (m SSHListKeysModel) View() string {
 			}
 		}
 		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("ssh_wizard_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
 	}
 
 	if m.status != "" {
@@ -480,7 +495,8 @@ type SSHDisablePasswordModel struct {
 	confirmCursor int
 	status        string
 
-	result sshKeysResultMsg
+	result   sshKeysResultMsg
+	logStart int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
 }
 
 func NewSSHDisablePasswordModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) SSHDisablePasswordModel {
@@ -522,7 +538,7 @@ func (m SSHDisablePasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case tea.KeyEnter:
 				m.status = ""
 				if strings.TrimSpace(m.userInput.Value()) == "" {
-					m.status = errors.New(i18n.T("err_invalid_input")).Error()
+					m.status = i18n.T("err_invalid_input")
 					return m, nil
 				}
 				m.userInput.Blur()
@@ -545,6 +561,10 @@ func (m SSHDisablePasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.confirmCursor == 0 {
 					return m.parent, nil
 				}
+				if needsRoot(m.cfg) {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
 				m.step = sshWizardStepApplying
 				return m, m.applyCmd()
 			}
@@ -553,6 +573,9 @@ func (m SSHDisablePasswordModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case sshWizardStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
 			switch msg.Type {
 			case tea.KeyEnter, tea.KeyEsc:
 				return m.parent, nil
@@ -594,6 +617,7 @@ func (m SSHDisablePasswordModel) View() string {
 			b.WriteString(tui.SuccessStyle.Render(i18n.T("success")) + "\n")
 		}
 		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("ssh_wizard_done")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
 	}
 
 	if m.status != "" {
@@ -617,7 +641,7 @@ func (m SSHDisablePasswordModel) applyCmd() tea.Cmd {
 			return sshKeysResultMsg{err: errors.New(i18n.T("ssh_wizard_no_keys"))}
 		}
 
-		cfg, err := sshModule.NewConfig("/etc/ssh/sshd_config", dryRun, logger)
+		cfg, err := sshModule.NewConfig(sshModule.DefaultConfigPath(), dryRun, logger)
 		if err != nil {
 			return sshKeysResultMsg{err: err}
 		}
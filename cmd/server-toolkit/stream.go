@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// streamLineMsg 命令输出的一行
+type streamLineMsg struct {
+	stream *outputStream
+	line   string
+}
+
+// streamDoneMsg 命令执行结束
+type streamDoneMsg struct {
+	stream *outputStream
+	err    error
+}
+
+// outputStream 把后台命令的输出按行转发给 Bubble Tea（实现 io.Writer）
+type outputStream struct {
+	ch  chan tea.Msg
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newOutputStream() *outputStream {
+	return &outputStream{ch: make(chan tea.Msg, 256)}
+}
+
+// Write 按行（\n 或 \r）切分后发送
+func (s *outputStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Write(p)
+	for {
+		data := s.buf.Bytes()
+		idx := bytes.IndexAny(data, "\r\n")
+		if idx == -1 {
+			break
+		}
+		line := string(data[:idx])
+		s.buf.Next(idx + 1)
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s.ch <- streamLineMsg{stream: s, line: line}
+	}
+	return len(p), nil
+}
+
+func (s *outputStream) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rest := strings.TrimSpace(s.buf.String()); rest != "" {
+		s.ch <- streamLineMsg{stream: s, line: rest}
+	}
+	s.buf.Reset()
+}
+
+// start 在后台执行 fn，并返回读取第一条消息的命令
+func (s *outputStream) start(fn func(w io.Writer) error) tea.Cmd {
+	go func() {
+		err := fn(s)
+		s.flush()
+		s.ch <- streamDoneMsg{stream: s, err: err}
+		close(s.ch)
+	}()
+	return s.next()
+}
+
+// next 等待下一条输出消息；收到 streamLineMsg 后需再次调用
+func (s *outputStream) next() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-s.ch
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
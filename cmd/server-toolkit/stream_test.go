@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputStreamSplitsLinesAndReportsDone(t *testing.T) {
+	stream := newOutputStream()
+	cmd := stream.start(func(w io.Writer) error {
+		fmt.Fprint(w, "Reading package lists...\r")
+		fmt.Fprint(w, "Get:1 http://deb.debian.org bookworm InRelease\n\npartial")
+		return errors.New("exit status 100")
+	})
+
+	var lines []string
+	var done *streamDoneMsg
+	for done == nil {
+		switch msg := cmd().(type) {
+		case streamLineMsg:
+			lines = append(lines, msg.line)
+		case streamDoneMsg:
+			done = &msg
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+
+	assert.Equal(t, []string{
+		"Reading package lists...",
+		"Get:1 http://deb.debian.org bookworm InRelease",
+		"partial",
+	}, lines)
+	require.Error(t, done.err)
+	assert.Nil(t, stream.next()())
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/i18n"
+	"github.com/Akuma-real/server-toolkit/pkg/tui"
+)
+
+type updateStep int
+
+const (
+	updateStepChecking updateStep = iota
+	updateStepOverview
+	updateStepNotes
+	updateStepConfirm
+	updateStepApplying
+	updateStepResult
+)
+
+type updateCheckedMsg struct {
+	info      internal.UpdateInfo
+	hasBackup bool
+	err       error
+}
+
+type updateAppliedMsg struct {
+	installed string
+	err       error
+}
+
+// UpdateModel 自更新：检查所选渠道的新版本，查看发布说明后下载校验并替换二进制，或回滚到更新前的版本
+type UpdateModel struct {
+	parent tui.MenuModel
+	cfg    *internal.Config
+	logger *internal.Logger
+
+	step updateStep
+
+	info      internal.UpdateInfo
+	hasBackup bool
+	checkErr  error
+	notes     viewport.Model
+
+	rollback      bool // 确认/执行的是回滚
+	confirmCursor int  // 0: No, 1: Yes
+
+	installed string
+	resultErr error
+	logStart  int64 // 开始执行时的日志位置，结果页据此查看本次操作的日志
+}
+
+func NewUpdateModel(parent tui.MenuModel, cfg *internal.Config, logger *internal.Logger) UpdateModel {
+	return UpdateModel{
+		parent: parent,
+		cfg:    cfg,
+		logger: logger,
+		step:   updateStepChecking,
+		notes:  viewport.New(58, 12),
+	}
+}
+
+func (m UpdateModel) Init() tea.Cmd {
+	return initRefreshTickerCmd(m.checkCmd(false))
+}
+
+// updater 按配置创建更新器
+func (m UpdateModel) updater() *internal.Updater {
+	opts := internal.UpdateOptions{}
+	if m.cfg != nil {
+		opts = m.cfg.UpdateOptions()
+	}
+	return internal.NewUpdaterWithOptions(version, opts, m.logger)
+}
+
+// canReplace 当前用户能否替换二进制（及写入 .bak）
+func (m UpdateModel) canReplace() bool {
+	execPath, err := m.updater().ExecPath()
+	return err == nil && canWrite(filepath.Dir(execPath))
+}
+
+// checkCmd 检查更新；refresh 为 true 时忽略缓存
+func (m UpdateModel) checkCmd(refresh bool) tea.Cmd {
+	updater := m.updater()
+	return func() tea.Msg {
+		info, err := updater.CheckInfo(refresh)
+		return updateCheckedMsg{info: info, hasBackup: updater.HasBackup(), err: err}
+	}
+}
+
+func (m UpdateModel) applyCmd() tea.Cmd {
+	updater := m.updater()
+	dryRun := m.cfg != nil && m.cfg.DryRun
+	rollback := m.rollback
+	logger := m.logger
+	return func() tea.Msg {
+		installed, err := runSelfUpdate(updater, dryRun, rollback, logger)
+		return updateAppliedMsg{installed: installed, err: err}
+	}
+}
+
+func (m UpdateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case updateCheckedMsg:
+		m.info = msg.info
+		m.hasBackup = msg.hasBackup
+		m.checkErr = msg.err
+		m.step = updateStepOverview
+		return m, nil
+
+	case updateAppliedMsg:
+		m.installed = msg.installed
+		m.resultErr = msg.err
+		if msg.err == nil && !m.rollback && msg.installed != "" {
+			clearUpdateStatus()
+		}
+		m.step = updateStepResult
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+
+		switch m.step {
+		case updateStepChecking, updateStepApplying:
+			return m, nil
+
+		case updateStepOverview:
+			switch msg.Type {
+			case tea.KeyEsc:
+				return m.parent, nil
+			case tea.KeyEnter:
+				if m.checkErr == nil && m.info.Available {
+					m.rollback = false
+					m.confirmCursor = 0
+					m.notes.SetContent(renderReleaseNotes(m.info.Notes, 56))
+					m.notes.GotoTop()
+					m.step = updateStepNotes
+				}
+			case tea.KeyRunes:
+				switch msg.String() {
+				case "b":
+					if m.hasBackup {
+						m.rollback = true
+						m.confirmCursor = 0
+						m.step = updateStepConfirm
+					}
+				case "r":
+					m.step = updateStepChecking
+					return m, m.checkCmd(true)
+				}
+			}
+			return m, nil
+
+		case updateStepNotes:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = updateStepOverview
+				return m, nil
+			case tea.KeyEnter:
+				m.step = updateStepConfirm
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.notes, cmd = m.notes.Update(msg)
+			return m, cmd
+
+		case updateStepConfirm:
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.step = updateStepOverview
+				return m, nil
+			case tea.KeyLeft, tea.KeyShiftTab:
+				m.confirmCursor = 0
+				return m, nil
+			case tea.KeyRight, tea.KeyTab:
+				m.confirmCursor = 1
+				return m, nil
+			case tea.KeyEnter:
+				if m.confirmCursor == 0 {
+					m.step = updateStepOverview
+					return m, nil
+				}
+				// 二进制位于当前用户可写的目录（如 ~/.local/bin）时无需 root
+				if needsRoot(m.cfg) && !m.canReplace() {
+					return newRootRequiredModel(m, m.cfg), nil
+				}
+				m.logStart = logMark(m.cfg)
+				m.step = updateStepApplying
+				return m, m.applyCmd()
+			}
+
+		case updateStepResult:
+			if isViewLogKey(msg) {
+				return viewOperationLog(m, m.cfg, m.logger, m.logStart)
+			}
+			switch msg.Type {
+			case tea.KeyEnter, tea.KeyEsc:
+				m.step = updateStepChecking
+				return m, m.checkCmd(true)
+			}
+		}
+	}
+
+	return m, keepRefreshTickerCmd(msg, nil)
+}
+
+func (m UpdateModel) View() string {
+	var b strings.Builder
+	b.WriteString(tui.TitleStyle.Width(60).Render(i18n.T("update_title")) + "\n\n")
+
+	if m.cfg != nil && m.cfg.DryRun {
+		b.WriteString(tui.WarningStyle.Render(i18n.T("settings_dryrun_on")) + "\n\n")
+	}
+
+	switch m.step {
+	case updateStepChecking:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("update_checking")) + "\n")
+
+	case updateStepOverview:
+		b.WriteString(m.overviewView())
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("update_keys")) + "\n")
+
+	case updateStepNotes:
+		b.WriteString(tui.SubtitleStyle.Render(i18n.T("update_notes_title", m.info.Latest)) + "\n")
+		if m.info.URL != "" {
+			b.WriteString(tui.DimStyle.Render(m.info.URL) + "\n")
+		}
+		b.WriteString("\n" + m.notes.View() + "\n")
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("update_notes_keys")) + "\n")
+
+	case updateStepConfirm:
+		prompt := i18n.T("update_confirm", version, m.info.Latest)
+		if m.rollback {
+			prompt = i18n.T("update_confirm_rollback")
+		}
+		b.WriteString(tui.NormalStyle.Render(prompt) + "\n\n")
+		b.WriteString(renderYesNo(m.confirmCursor) + "\n")
+
+	case updateStepApplying:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("update_applying")) + "\n")
+
+	case updateStepResult:
+		switch {
+		case m.resultErr != nil:
+			b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.resultErr)) + "\n")
+		case m.rollback:
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("update_rolled_back")) + "\n")
+		case m.installed == "":
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("update_up_to_date", version)) + "\n")
+		default:
+			b.WriteString(tui.SuccessStyle.Render(i18n.T("update_installed", m.installed)) + "\n")
+		}
+		if m.resultErr == nil && (m.rollback || m.installed != "") {
+			b.WriteString(tui.WarningStyle.Render(i18n.T("update_restart")) + "\n")
+		}
+		b.WriteString("\n" + tui.DimStyle.Render(i18n.T("press_enter")) + "\n")
+		b.WriteString(tui.DimStyle.Render(i18n.T("logs_view_hint")) + "\n")
+	}
+
+	return tui.BorderStyle.Width(62).Render(b.String())
+}
+
+func (m UpdateModel) overviewView() string {
+	var b strings.Builder
+	opts := internal.UpdateOptions{}
+	if m.cfg != nil {
+		opts = m.cfg.UpdateOptions()
+	}
+	channel := opts.Channel
+	if channel == "" {
+		channel = internal.ChannelStable
+	}
+
+	lines := []string{
+		i18n.T("update_current", version),
+		i18n.T("update_channel", channel),
+	}
+	if opts.Version != "" {
+		lines = append(lines, i18n.T("update_pinned", opts.Version))
+	}
+	if opts.BaseURL != "" {
+		lines = append(lines, i18n.T("update_base_url", opts.BaseURL))
+	}
+	for _, line := range lines {
+		b.WriteString("  " + tui.NormalStyle.Render(line) + "\n")
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.checkErr != nil:
+		b.WriteString(tui.ErrorStyle.Render(i18n.T("err_operation_failed", m.checkErr)) + "\n")
+	case m.info.Available:
+		b.WriteString(tui.InfoStyle.Render(i18n.T("update_available", m.info.Latest)) + "\n")
+	default:
+		b.WriteString(tui.SuccessStyle.Render(i18n.T("update_up_to_date", version)) + "\n")
+	}
+	if !m.info.CheckedAt.IsZero() {
+		b.WriteString(tui.DimStyle.Render(i18n.T("update_checked_at", m.info.CheckedAt.Local().Format("2006-01-02 15:04"))) + "\n")
+	}
+	if m.hasBackup {
+		b.WriteString(tui.DimStyle.Render(i18n.T("update_has_backup")) + "\n")
+	}
+	return b.String()
+}
+
+// renderReleaseNotes 将发布说明（Markdown）渲染为终端文本：标题加粗、列表项改为圆点、按宽度换行
+func renderReleaseNotes(body string, width int) string {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return tui.DimStyle.Render(i18n.T("update_notes_empty"))
+	}
+
+	wrap := lipgloss.NewStyle().Width(width)
+	var out []string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			out = append(out, tui.SubtitleStyle.Render(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))))
+		case strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "* "):
+			indent := strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " ")))
+			out = append(out, wrap.Render(indent+"• "+strings.TrimSpace(trimmed[2:])))
+		default:
+			out = append(out, wrap.Render(line))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// runSelfUpdate 执行更新或回滚并写入审计记录；返回安装的版本（已是最新或回滚时为空）
+func runSelfUpdate(updater *internal.Updater, dryRun, rollback bool, logger *internal.Logger) (installed string, err error) {
+	execPath, err := updater.ExecPath()
+	if err != nil {
+		return "", err
+	}
+
+	operation := "self_update"
+	if rollback {
+		operation = "rollback"
+	}
+	op := history.Begin(dryRun, "update", operation, version, execPath, execPath+".bak")
+	defer func() { op.End(err, logger) }()
+
+	if rollback {
+		return "", updater.Rollback()
+	}
+	return updater.DoUpdate()
+}
+
+// runSelfUpdateCommand server-toolkit self-update：退出码 0 成功（或已是最新），1 有可用更新（-check），2 出错
+func runSelfUpdateCommand(args []string, cfg *internal.Config, logger *internal.Logger) int {
+	opts := cfg.UpdateOptions()
+	fs := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	channel := fs.String("channel", opts.Channel, "update channel: stable or nightly")
+	pin := fs.String("version", opts.Version, "install a specific version (e.g. v0.2.0)")
+	baseURL := fs.String("base-url", opts.BaseURL, "release API base URL (mirror), default GitHub")
+	rollback := fs.Bool("rollback", false, "restore the binary saved before the last update (.bak)")
+	check := fs.Bool("check", false, "only check whether an update is available")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *channel != "" && *channel != internal.ChannelStable && *channel != internal.ChannelNightly {
+		fmt.Fprintf(os.Stderr, "error: unknown channel: %q (expected stable or nightly)\n", *channel)
+		return 2
+	}
+
+	opts.Channel, opts.Version, opts.BaseURL = *channel, *pin, *baseURL
+	updater := internal.NewUpdaterWithOptions(version, opts, logger)
+
+	if *check {
+		info, err := updater.CheckInfo(true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			return 2
+		}
+		if !info.Available {
+			fmt.Println(i18n.T("update_up_to_date", version))
+			return 0
+		}
+		fmt.Println(i18n.T("update_available", info.Latest))
+		if notes := strings.TrimSpace(info.Notes); notes != "" {
+			fmt.Println("\n" + notes)
+		}
+		return 1
+	}
+
+	installed, err := runSelfUpdate(updater, cfg.DryRun, *rollback, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+	switch {
+	case *rollback:
+		fmt.Println(i18n.T("update_rolled_back"))
+	case installed == "":
+		fmt.Println(i18n.T("update_up_to_date", version))
+	default:
+		fmt.Println(i18n.T("update_installed", installed))
+	}
+	return 0
+}
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"time"
 )
 
 var (
 	configDir = "/etc/server-toolkit"
+	// configPath 由 --config 或 SERVER_TOOLKIT_CONFIG 指定的配置文件，为空使用 configDir 下的 config.json
+	configPath string
 )
 
 const (
@@ -17,42 +21,218 @@ const (
 
 // Config 配置结构
 type Config struct {
+	// 配置格式版本，加载时按版本迁移旧配置
+	ConfigVersion int `json:"config_version"`
+
 	Language   string `json:"language"`
 	DryRun     bool   `json:"dry_run"`
 	LogLevel   string `json:"log_level"`
 	AutoUpdate bool   `json:"auto_update"`
 	LogPath    string `json:"log_path"`
+
+	// 日志格式（text/json）、按大小轮转（MB，0 使用默认值，-1 不轮转）与保留的旧文件数，以及额外输出（syslog/journald）
+	LogFormat     string `json:"log_format,omitempty"`
+	LogMaxSizeMB  int    `json:"log_max_size_mb,omitempty"`
+	LogMaxBackups int    `json:"log_max_backups,omitempty"`
+	LogSink       string `json:"log_sink,omitempty"`
+
+	// 审计日志：记录每次修改系统的操作者与文件校验和（只追加），为空使用默认路径
+	AuditLogPath string `json:"audit_log_path,omitempty"`
+
+	// 主机名向导的命名模板，如 {role}-{region}-{nn}（{region} 未设置时取云元数据中的区域）
+	HostnameTemplate string `json:"hostname_template,omitempty"`
+	HostnameRole     string `json:"hostname_role,omitempty"`
+	HostnameRegion   string `json:"hostname_region,omitempty"`
+	HostnameDomain   string `json:"hostname_domain,omitempty"`
+
+	// 系统信息面板是否通过外部服务查询公网 IP（默认关闭；dry-run 与 --root 下始终不查询）
+	PublicIPLookup bool `json:"public_ip_lookup,omitempty"`
+
+	// 自更新：渠道（stable/nightly）、固定版本与镜像地址（为空使用 GitHub）
+	UpdateChannel string `json:"update_channel,omitempty"`
+	UpdateVersion string `json:"update_version,omitempty"`
+	UpdateBaseURL string `json:"update_base_url,omitempty"`
+	// 检查结果缓存的小时数（0 使用默认值，-1 不缓存）
+	UpdateCheckTTLHours int `json:"update_check_ttl_hours,omitempty"`
+	// UpdatePublicKey 额外受信任的发布签名公钥（minisign 格式），用于内部镜像自行签名的发布
+	UpdatePublicKey string `json:"update_public_key,omitempty"`
+
+	// 被环境变量覆盖的配置项，保存时写回配置文件中的原值
+	envOverrides map[string]envOverride
+}
+
+// SetConfigPath 指定配置文件路径（--config），为空恢复默认路径
+func SetConfigPath(path string) {
+	configPath = path
+}
+
+// isRoot 是否以 root 运行（测试中可替换）
+var isRoot = func() bool { return os.Geteuid() == 0 }
+
+// userDir 返回 XDG 目录（环境变量未设置时为 $HOME 下的 fallback）中的 server-toolkit 子目录；无法确定时返回空
+func userDir(env, fallback string) string {
+	base := os.Getenv(env)
+	if !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil || home == "" {
+			return ""
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, "server-toolkit")
+}
+
+// UserConfigPath 用户配置文件：$XDG_CONFIG_HOME/server-toolkit/config.json
+func UserConfigPath() string {
+	dir := userDir("XDG_CONFIG_HOME", ".config")
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, configFile)
+}
+
+// ConfigLayers 按加载顺序返回配置文件：指定 --config 时只有该文件；
+// 否则为系统配置 /etc/server-toolkit/config.json，非 root 时再叠加用户配置
+func ConfigLayers() []string {
+	if configPath != "" {
+		return []string{configPath}
+	}
+	layers := []string{filepath.Join(configDir, configFile)}
+	if !isRoot() {
+		if path := UserConfigPath(); path != "" {
+			layers = append(layers, path)
+		}
+	}
+	return layers
+}
+
+// ConfigPath 返回保存配置时写入的文件（最后一层：非 root 时为用户配置）
+func ConfigPath() string {
+	layers := ConfigLayers()
+	return layers[len(layers)-1]
 }
 
-// Load 加载配置
+// Load 加载配置：依次叠加各配置层，缺失的配置项使用默认值，旧版本配置按 config_version 迁移，
+// 再应用 SERVER_TOOLKIT_* 环境变量覆盖。出错时仍返回可用的配置：
+// 无效的配置项恢复为默认值，无效的环境变量值被忽略
 func Load() (*Config, error) {
-	path := filepath.Join(configDir, configFile)
+	cfg, err := LoadFiles()
+
+	if envErr := cfg.applyEnv(os.LookupEnv); envErr != nil {
+		switch verrs, ok := err.(ValidationErrors); {
+		case err == nil:
+			err = envErr
+		case ok:
+			err = append(verrs, envErr.(ValidationErrors)...)
+		}
+	}
+	return cfg, err
+}
+
+// LoadFiles 读取并校验全部配置层（不应用环境变量）；无法读取或解析的配置层被跳过并返回其错误
+func LoadFiles() (*Config, error) {
+	return loadLayers(ConfigLayers())
+}
+
+// LoadFile 读取并校验单个配置文件（不应用环境变量）；文件不存在时返回默认配置。
+// 校验失败时返回 ValidationErrors，出错的配置项恢复为默认值
+func LoadFile(path string) (*Config, error) {
+	return loadLayers([]string{path})
+}
+
+func loadLayers(layers []string) (*Config, error) {
+	cfg := Default()
+	var loadErr error
+	for _, path := range layers {
+		if err := loadInto(cfg, path); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		cfg.resetToDefault(err.(ValidationErrors).Keys())
+	}
+	cfg.applyUserPaths()
+	if loadErr != nil {
+		return cfg, loadErr
+	}
+	return cfg, err
+}
+
+// loadInto 将配置文件迁移后解码到 cfg 上：文件中缺失的配置项保持不变；文件不存在时不做修改
+func loadInto(cfg *Config, path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		// 文件不存在：使用默认配置
 		if os.IsNotExist(err) {
-			return Default(), nil
+			return nil
 		}
-		return Default(), fmt.Errorf("failed to read config %s: %w", path, err)
+		return fmt.Errorf("failed to read config %s: %w", path, err)
 	}
 
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return Default(), fmt.Errorf("failed to parse config %s: %w", path, err)
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := migrateConfig(raw); err != nil {
+		return fmt.Errorf("failed to migrate config %s: %w", path, err)
+	}
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
 	}
 
-	return &cfg, nil
+	// 解码到副本：类型错误时不留下部分修改
+	next := *cfg
+	if err := json.Unmarshal(migrated, &next); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	*cfg = next
+	return nil
 }
 
-// Save 保存配置
+// applyUserPaths 非 root 运行时把仍为系统默认值的日志与审计日志路径改到 $XDG_STATE_HOME/server-toolkit 下
+func (c *Config) applyUserPaths() {
+	if isRoot() {
+		return
+	}
+	dir := userDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+	if dir == "" {
+		return
+	}
+	if c.LogPath == DefaultLogPath {
+		c.LogPath = filepath.Join(dir, filepath.Base(DefaultLogPath))
+	}
+	if c.AuditLogPath == DefaultAuditLogPath {
+		c.AuditLogPath = filepath.Join(dir, filepath.Base(DefaultAuditLogPath))
+	}
+}
+
+// Save 校验并保存配置到 ConfigPath（被环境变量覆盖的配置项保留配置文件中的原值）。
+// 写入用户配置时只保存与下层配置不同的配置项，系统配置的后续修改仍然生效
 func Save(cfg *Config) error {
+	layers := ConfigLayers()
+	path := layers[len(layers)-1]
+	dir := filepath.Dir(path)
 	// 确保目录存在
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config dir %s: %w", configDir, err)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config dir %s: %w", dir, err)
 	}
 
-	path := filepath.Join(configDir, configFile)
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	out := cfg.forSave()
+	if err := out.Validate(); err != nil {
+		return err
+	}
+	var v interface{} = out
+	if len(layers) > 1 {
+		base, _ := loadLayers(layers[:len(layers)-1])
+		diff, err := configDiff(base, out)
+		if err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		v = diff
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
@@ -64,13 +244,127 @@ func Save(cfg *Config) error {
 	return nil
 }
 
+// configDiff 返回 cfg 中与 base 不同的配置项（始终包含 config_version）
+func configDiff(base, cfg *Config) (map[string]interface{}, error) {
+	toMap := func(c *Config) (map[string]interface{}, error) {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{}
+		return m, json.Unmarshal(data, &m)
+	}
+	baseMap, err := toMap(base)
+	if err != nil {
+		return nil, err
+	}
+	cfgMap, err := toMap(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]interface{}{"config_version": CurrentConfigVersion}
+	for key, value := range cfgMap {
+		if !reflect.DeepEqual(baseMap[key], value) {
+			diff[key] = value
+		}
+	}
+	// omitempty 的配置项在 cfg 中为空而下层有值时，需显式写入空值覆盖
+	for key := range baseMap {
+		if _, ok := cfgMap[key]; !ok {
+			if f, ok := cfg.configField(key); ok {
+				diff[key] = f.Interface()
+			}
+		}
+	}
+	return diff, nil
+}
+
 // Default 返回默认配置
 func Default() *Config {
 	return &Config{
+		ConfigVersion: CurrentConfigVersion,
+
 		Language:   "zh_CN",
 		DryRun:     false,
 		LogLevel:   "INFO",
 		AutoUpdate: true,
-		LogPath:    "/var/log/server-toolkit.log",
+		LogPath:    DefaultLogPath,
+
+		LogFormat:     string(LogFormatText),
+		LogMaxSizeMB:  DefaultLogMaxSizeMB,
+		LogMaxBackups: DefaultLogMaxBackups,
+		AuditLogPath:  DefaultAuditLogPath,
+	}
+}
+
+// 日志与审计日志的默认路径（非 root 运行时改到 $XDG_STATE_HOME/server-toolkit 下）
+const (
+	DefaultLogPath      = "/var/log/server-toolkit.log"
+	DefaultAuditLogPath = "/var/log/server-toolkit-audit.log"
+)
+
+// AuditLog 返回生效的审计日志路径
+func (c *Config) AuditLog() string {
+	if c.AuditLogPath == "" {
+		return DefaultAuditLogPath
+	}
+	return c.AuditLogPath
+}
+
+// 自更新检查结果的缓存
+const (
+	DefaultUpdateCachePath     = "/var/cache/server-toolkit/update-check.json"
+	DefaultUpdateCheckTTLHours = 24
+)
+
+// updateCachePath 更新检查缓存：非 root 运行时使用 $XDG_CACHE_HOME/server-toolkit
+func updateCachePath() string {
+	if isRoot() {
+		return DefaultUpdateCachePath
+	}
+	dir := userDir("XDG_CACHE_HOME", ".cache")
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, filepath.Base(DefaultUpdateCachePath))
+}
+
+// UpdateOptions 返回配置中的自更新来源与检查缓存
+func (c *Config) UpdateOptions() UpdateOptions {
+	ttl := c.UpdateCheckTTLHours
+	if ttl == 0 {
+		ttl = DefaultUpdateCheckTTLHours
+	}
+	opts := UpdateOptions{
+		Channel:   c.UpdateChannel,
+		Version:   c.UpdateVersion,
+		BaseURL:   c.UpdateBaseURL,
+		DryRun:    c.DryRun,
+		CachePath: updateCachePath(),
+		CacheTTL:  time.Duration(ttl) * time.Hour,
+	}
+	if c.UpdatePublicKey != "" {
+		opts.PublicKeys = []string{c.UpdatePublicKey}
+	}
+	return opts
+}
+
+// 日志轮转默认值
+const (
+	DefaultLogMaxSizeMB  = 10
+	DefaultLogMaxBackups = 5
+)
+
+// LogRotation 返回生效的轮转上限（字节，<= 0 表示不轮转）与保留数量
+func (c *Config) LogRotation() (int64, int) {
+	size := c.LogMaxSizeMB
+	if size == 0 {
+		size = DefaultLogMaxSizeMB
+	}
+	backups := c.LogMaxBackups
+	if backups == 0 {
+		backups = DefaultLogMaxBackups
 	}
+	return int64(size) * 1024 * 1024, backups
 }
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -10,6 +11,7 @@ import (
 )
 
 func TestLoadReturnsErrorOnInvalidJSON(t *testing.T) {
+	stubRoot(t, true)
 	tmpDir := t.TempDir()
 	oldConfigDir := configDir
 	configDir = tmpDir
@@ -25,6 +27,7 @@ func TestLoadReturnsErrorOnInvalidJSON(t *testing.T) {
 }
 
 func TestLoadReturnsDefaultWhenConfigMissing(t *testing.T) {
+	stubRoot(t, true)
 	tmpDir := t.TempDir()
 	oldConfigDir := configDir
 	configDir = tmpDir
@@ -35,3 +38,170 @@ func TestLoadReturnsDefaultWhenConfigMissing(t *testing.T) {
 	require.NotNil(t, cfg)
 	assert.Equal(t, "zh_CN", cfg.Language)
 }
+
+// stubRoot 固定 root 判断，避免测试结果依赖运行用户
+func stubRoot(t *testing.T, root bool) {
+	old := isRoot
+	isRoot = func() bool { return root }
+	t.Cleanup(func() { isRoot = old })
+}
+
+func useConfigFile(t *testing.T, content string) string {
+	stubRoot(t, true)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if content != "" {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	SetConfigPath(path)
+	t.Cleanup(func() { SetConfigPath("") })
+	return path
+}
+
+func TestLoadMergesDefaultsAndMigrates(t *testing.T) {
+	// 版本 1（无 config_version）：大小写不规范的取值被迁移，缺失的配置项使用默认值
+	useConfigFile(t, `{"language": "en_US", "log_level": "debug", "log_format": "JSON", "log_sink": "Journald"}`)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, CurrentConfigVersion, cfg.ConfigVersion)
+	assert.Equal(t, "en_US", cfg.Language)
+	assert.Equal(t, "DEBUG", cfg.LogLevel)
+	assert.Equal(t, "json", cfg.LogFormat)
+	assert.Equal(t, "journald", cfg.LogSink)
+	assert.True(t, cfg.AutoUpdate)
+	assert.Equal(t, DefaultAuditLogPath, cfg.AuditLogPath)
+	assert.Equal(t, DefaultLogMaxBackups, cfg.LogMaxBackups)
+
+	useConfigFile(t, `{"config_version": 99}`)
+	_, err = Load()
+	require.ErrorContains(t, err, "newer than supported")
+}
+
+func TestLoadValidatesConfig(t *testing.T) {
+	useConfigFile(t, `{"config_version": 2, "language": "fr", "log_level": "verbose", "dry_run": true, "update_base_url": "ftp://mirror"}`)
+
+	cfg, err := Load()
+	var verrs ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	assert.Equal(t, []string{"language", "log_level", "update_base_url"}, verrs.Keys())
+
+	// 无效的配置项恢复为默认值，其余保留
+	assert.Equal(t, "zh_CN", cfg.Language)
+	assert.Equal(t, "INFO", cfg.LogLevel)
+	assert.Empty(t, cfg.UpdateBaseURL)
+	assert.True(t, cfg.DryRun)
+
+	assert.Error(t, Save(&Config{Language: "fr"}))
+}
+
+func TestLoadAppliesEnvOverrides(t *testing.T) {
+	path := useConfigFile(t, `{"config_version": 2, "log_level": "WARN", "language": "en_US"}`)
+	t.Setenv("SERVER_TOOLKIT_LOG_LEVEL", "DEBUG")
+	t.Setenv("SERVER_TOOLKIT_DRY_RUN", "1")
+	t.Setenv("SERVER_TOOLKIT_LANGUAGE", "fr")
+
+	cfg, err := Load()
+	require.ErrorContains(t, err, "SERVER_TOOLKIT_LANGUAGE")
+	assert.Equal(t, "DEBUG", cfg.LogLevel)
+	assert.True(t, cfg.DryRun)
+	assert.True(t, cfg.Overridden("dry_run"))
+	// 无效的环境变量值被忽略
+	assert.Equal(t, "en_US", cfg.Language)
+	assert.False(t, cfg.Overridden("language"))
+
+	// 保存时不把环境变量的值写入配置文件，显式修改的值照常保存
+	require.NoError(t, cfg.Set("dry_run", "false"))
+	require.NoError(t, cfg.Set("auto_update", "false"))
+	require.NoError(t, Save(cfg))
+	saved, err := LoadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "WARN", saved.LogLevel)
+	assert.False(t, saved.DryRun)
+	assert.False(t, saved.AutoUpdate)
+}
+
+func TestConfigGetSet(t *testing.T) {
+	cfg := Default()
+	require.NoError(t, cfg.Set("log_max_size_mb", "-1"))
+	v, err := cfg.Get("log_max_size_mb")
+	require.NoError(t, err)
+	assert.Equal(t, "-1", v)
+
+	require.NoError(t, cfg.Set("update_version", "v0.2.0-beta.1"))
+	require.Error(t, cfg.Set("update_version", "latest"))
+	require.Error(t, cfg.Set("log_max_backups", "many"))
+	require.Error(t, cfg.Set("auto_update", "maybe"))
+	require.Error(t, cfg.Set("log_path", "relative.log"))
+	require.Error(t, cfg.Set("no_such_key", "x"))
+	_, err = cfg.Get("no_such_key")
+	require.Error(t, err)
+
+	assert.Contains(t, ConfigKeys(), "update_public_key")
+	assert.NotContains(t, ConfigKeys(), "envOverrides")
+}
+
+func TestLoadLayersUserConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldConfigDir := configDir
+	configDir = filepath.Join(tmpDir, "etc")
+	t.Cleanup(func() { configDir = oldConfigDir })
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, "state"))
+	stubRoot(t, false)
+
+	systemPath := filepath.Join(configDir, configFile)
+	userPath := filepath.Join(tmpDir, "config", "server-toolkit", configFile)
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(userPath), 0755))
+	require.NoError(t, os.WriteFile(systemPath, []byte(`{"language": "en_US", "log_level": "WARN", "update_channel": "nightly"}`), 0644))
+	require.NoError(t, os.WriteFile(userPath, []byte(`{"log_level": "DEBUG"}`), 0644))
+
+	assert.Equal(t, []string{systemPath, userPath}, ConfigLayers())
+	assert.Equal(t, userPath, ConfigPath())
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "en_US", cfg.Language)
+	assert.Equal(t, "DEBUG", cfg.LogLevel)
+	assert.Equal(t, ChannelNightly, cfg.UpdateChannel)
+	// 非 root：默认日志路径改到用户目录
+	assert.Equal(t, filepath.Join(tmpDir, "state", "server-toolkit", "server-toolkit.log"), cfg.LogPath)
+	assert.Equal(t, filepath.Join(tmpDir, "state", "server-toolkit", "server-toolkit-audit.log"), cfg.AuditLog())
+
+	// 用户配置只保存与系统配置不同的配置项
+	require.NoError(t, cfg.Set("dry_run", "true"))
+	require.NoError(t, cfg.Set("update_channel", ""))
+	require.NoError(t, Save(cfg))
+	var saved map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(readConfig(t, userPath)), &saved))
+	assert.Equal(t, map[string]interface{}{
+		"config_version": float64(CurrentConfigVersion),
+		"dry_run":        true,
+		"log_level":      "DEBUG",
+		"update_channel": "",
+	}, saved)
+	assert.Contains(t, readConfig(t, systemPath), "nightly")
+
+	// 系统配置的后续修改仍然生效
+	require.NoError(t, os.WriteFile(systemPath, []byte(`{"language": "zh_CN"}`), 0644))
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, "zh_CN", cfg.Language)
+	assert.Equal(t, "DEBUG", cfg.LogLevel)
+	assert.True(t, cfg.DryRun)
+	assert.Empty(t, cfg.UpdateChannel)
+
+	// root 只使用系统配置
+	stubRoot(t, true)
+	assert.Equal(t, []string{systemPath}, ConfigLayers())
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.DryRun)
+	assert.Equal(t, DefaultLogPath, cfg.LogPath)
+}
+
+func readConfig(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CurrentConfigVersion 当前配置格式版本；缺少 config_version 的旧配置视为版本 1
+const CurrentConfigVersion = 2
+
+// EnvPrefix 环境变量覆盖配置的前缀：SERVER_TOOLKIT_LOG_LEVEL=DEBUG 覆盖 log_level
+const EnvPrefix = "SERVER_TOOLKIT_"
+
+// SupportedLanguages 界面语言
+var SupportedLanguages = []string{"zh_CN", "en_US"}
+
+// configMigrations 将版本 N 的原始配置迁移到 N+1
+var configMigrations = map[int]func(raw map[string]interface{}){
+	// 1 -> 2：旧版加载时大小写不敏感的取值统一为规范写法，避免被新的校验拒绝
+	1: func(raw map[string]interface{}) {
+		for key, normalize := range map[string]func(string) string{
+			"log_level":      strings.ToUpper,
+			"log_format":     strings.ToLower,
+			"log_sink":       strings.ToLower,
+			"update_channel": strings.ToLower,
+		} {
+			if s, ok := raw[key].(string); ok {
+				raw[key] = normalize(strings.TrimSpace(s))
+			}
+		}
+	},
+}
+
+// migrateConfig 将原始配置迁移到当前版本
+func migrateConfig(raw map[string]interface{}) error {
+	version := 1
+	if v, ok := raw["config_version"]; ok {
+		f, ok := v.(float64)
+		if !ok || f != float64(int(f)) || f < 1 {
+			return fmt.Errorf("invalid config_version %v", v)
+		}
+		version = int(f)
+	}
+	if version > CurrentConfigVersion {
+		return fmt.Errorf("config_version %d is newer than supported version %d", version, CurrentConfigVersion)
+	}
+	for ; version < CurrentConfigVersion; version++ {
+		if migrate, ok := configMigrations[version]; ok {
+			migrate(raw)
+		}
+	}
+	raw["config_version"] = CurrentConfigVersion
+	return nil
+}
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationErrors 配置校验错误
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// Keys 出错的配置项
+func (e ValidationErrors) Keys() []string {
+	keys := make([]string, len(e))
+	for i, fe := range e {
+		keys[i] = fe.Key
+	}
+	return keys
+}
+
+// configRules 各配置项的取值约束；未列出的配置项只检查类型
+var configRules = map[string]func(v reflect.Value) string{
+	"config_version": func(v reflect.Value) string {
+		if v.Int() != CurrentConfigVersion {
+			return fmt.Sprintf("must be %d", CurrentConfigVersion)
+		}
+		return ""
+	},
+	"language":               oneOf(SupportedLanguages...),
+	"log_level":              oneOf("DEBUG", "INFO", "WARN", "ERROR"),
+	"log_format":             oneOf("", string(LogFormatText), string(LogFormatJSON)),
+	"log_sink":               oneOf("", "syslog", "journald"),
+	"update_channel":         oneOf("", ChannelStable, ChannelNightly),
+	"log_path":               absolutePath,
+	"audit_log_path":         absolutePath,
+	"log_max_size_mb":        atLeast(-1),
+	"log_max_backups":        atLeast(-1),
+	"update_check_ttl_hours": atLeast(-1),
+	"update_version": func(v reflect.Value) string {
+		if s := v.String(); s != "" && s != ChannelNightly {
+			if _, ok := ParseVersion(s); !ok {
+				return fmt.Sprintf("%q is not a version like v0.2.0", s)
+			}
+		}
+		return ""
+	},
+	"update_base_url": func(v reflect.Value) string {
+		if s := v.String(); s != "" {
+			u, err := url.Parse(s)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Sprintf("%q is not an http(s) URL", s)
+			}
+		}
+		return ""
+	},
+	"update_public_key": func(v reflect.Value) string {
+		if s := v.String(); s != "" {
+			if _, err := ParsePublicKey(s); err != nil {
+				return err.Error()
+			}
+		}
+		return ""
+	},
+}
+
+func oneOf(allowed ...string) func(v reflect.Value) string {
+	return func(v reflect.Value) string {
+		if slices.Contains(allowed, v.String()) {
+			return ""
+		}
+		var names []string
+		for _, a := range allowed {
+			if a != "" {
+				names = append(names, a)
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", v.String(), strings.Join(names, ", "))
+	}
+}
+
+func atLeast(min int64) func(v reflect.Value) string {
+	return func(v reflect.Value) string {
+		if v.Int() < min {
+			return fmt.Sprintf("must be >= %d", min)
+		}
+		return ""
+	}
+}
+
+func absolutePath(v reflect.Value) string {
+	if s := v.String(); s != "" && !filepath.IsAbs(s) {
+		return fmt.Sprintf("%q is not an absolute path", s)
+	}
+	return ""
+}
+
+// configField 按 JSON 键查找配置项
+func (c *Config) configField(key string) (reflect.Value, bool) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if jsonKey(t.Field(i)) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func jsonKey(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// ConfigKeys 全部配置项的 JSON 键（按结构体字段顺序）
+func ConfigKeys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if key := jsonKey(t.Field(i)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Get 按 JSON 键读取配置项
+func (c *Config) Get(key string) (string, error) {
+	f, ok := c.configField(key)
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+	switch f.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool()), nil
+	case reflect.Int:
+		return strconv.FormatInt(f.Int(), 10), nil
+	}
+	return f.String(), nil
+}
+
+// Set 按 JSON 键设置配置项，值须通过该项的校验
+func (c *Config) Set(key, value string) error {
+	f, ok := c.configField(key)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+
+	var next reflect.Value
+	switch f.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return FieldError{Key: key, Message: fmt.Sprintf("%q is not a boolean", value)}
+		}
+		next = reflect.ValueOf(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return FieldError{Key: key, Message: fmt.Sprintf("%q is not an integer", value)}
+		}
+		next = reflect.ValueOf(n)
+	default:
+		next = reflect.ValueOf(value)
+	}
+
+	if rule, ok := configRules[key]; ok {
+		if msg := rule(next); msg != "" {
+			return FieldError{Key: key, Message: msg}
+		}
+	}
+	f.Set(next)
+	return nil
+}
+
+// Validate 校验全部配置项，返回 ValidationErrors
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	for _, key := range ConfigKeys() {
+		rule, ok := configRules[key]
+		if !ok {
+			continue
+		}
+		f, _ := c.configField(key)
+		if msg := rule(f); msg != "" {
+			errs = append(errs, FieldError{Key: key, Message: msg})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// resetToDefault 将指定配置项恢复为默认值
+func (c *Config) resetToDefault(keys []string) {
+	def := Default()
+	for _, key := range keys {
+		f, ok := c.configField(key)
+		d, _ := def.configField(key)
+		if ok {
+			f.Set(d)
+		}
+	}
+}
+
+// applyEnv 应用 SERVER_TOOLKIT_* 环境变量覆盖，记录被覆盖项的原值以免保存时写入配置文件
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var errs ValidationErrors
+	for _, key := range ConfigKeys() {
+		if key == "config_version" {
+			continue
+		}
+		value, ok := lookup(EnvPrefix + strings.ToUpper(key))
+		if !ok {
+			continue
+		}
+		old, _ := c.Get(key)
+		if err := c.Set(key, value); err != nil {
+			msg := err.Error()
+			if fe, ok := err.(FieldError); ok {
+				msg = fe.Message
+			}
+			errs = append(errs, FieldError{Key: key, Message: EnvPrefix + strings.ToUpper(key) + ": " + msg})
+			continue
+		}
+		value, _ = c.Get(key) // 规范形式（如 1 -> true），保存时据此判断是否仍为环境变量的值
+		if c.envOverrides == nil {
+			c.envOverrides = map[string]envOverride{}
+		}
+		if prev, ok := c.envOverrides[key]; ok {
+			old = prev.file
+		}
+		c.envOverrides[key] = envOverride{file: old, env: value}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// envOverride 被环境变量覆盖的配置项：配置文件中的原值与环境变量的值
+type envOverride struct {
+	file string
+	env  string
+}
+
+// Overridden 返回被环境变量覆盖的配置项
+func (c *Config) Overridden(key string) bool {
+	_, ok := c.envOverrides[key]
+	return ok
+}
+
+// forSave 返回要写入配置文件的副本：仍为环境变量值的配置项恢复为文件中的原值
+func (c *Config) forSave() *Config {
+	out := *c
+	out.envOverrides = nil
+	out.ConfigVersion = CurrentConfigVersion
+	for key, o := range c.envOverrides {
+		if v, _ := out.Get(key); v == o.env {
+			if f, ok := out.configField(key); ok {
+				// 原值来自已校验的配置，直接按类型写回
+				setRaw(f, o.file)
+			}
+		}
+	}
+	return &out
+}
+
+func setRaw(f reflect.Value, value string) {
+	switch f.Kind() {
+	case reflect.Bool:
+		b, _ := strconv.ParseBool(value)
+		f.SetBool(b)
+	case reflect.Int:
+		n, _ := strconv.Atoi(value)
+		f.SetInt(int64(n))
+	default:
+		f.SetString(value)
+	}
+}
@@ -27,7 +27,7 @@ func (m *DryRunManager) SetEnabled(enabled bool) {
 // LogOperation 记录操作日志
 func (m *DryRunManager) LogOperation(op string, args ...interface{}) {
 	if m.enabled {
-		m.logger.Info("[DRY-RUN] "+op, args...)
+		m.logger.With(Fields{DryRun: true}).Info("[DRY-RUN] "+op, args...)
 	}
 }
 
@@ -38,28 +38,28 @@ func (m *DryRunManager) LogCommand(cmd string, args ...string) {
 		for _, arg := range args {
 			cmdStr += " " + arg
 		}
-		m.logger.Info("[DRY-RUN] Would execute: %s", cmdStr)
+		m.logger.With(Fields{DryRun: true, Operation: "exec"}).Info("[DRY-RUN] Would execute: %s", cmdStr)
 	}
 }
 
 // LogFileWrite 记录文件写入
 func (m *DryRunManager) LogFileWrite(path string, content string) {
 	if m.enabled {
-		m.logger.Info("[DRY-RUN] Would write to file: %s (%d bytes)", path, len(content))
+		m.logger.With(Fields{DryRun: true, Operation: "write", Path: path}).Info("[DRY-RUN] Would write to file: %s (%d bytes)", path, len(content))
 	}
 }
 
 // LogFileOperation 记录文件操作
 func (m *DryRunManager) LogFileOperation(op string, path string) {
 	if m.enabled {
-		m.logger.Info("[DRY-RUN] Would %s: %s", op, path)
+		m.logger.With(Fields{DryRun: true, Operation: op, Path: path}).Info("[DRY-RUN] Would %s: %s", op, path)
 	}
 }
 
 // LogServiceOperation 记录服务操作
 func (m *DryRunManager) LogServiceOperation(op string, service string) {
 	if m.enabled {
-		m.logger.Info("[DRY-RUN] Would %s service: %s", op, service)
+		m.logger.With(Fields{DryRun: true, Operation: op}).Info("[DRY-RUN] Would %s service: %s", op, service)
 	}
 }
 
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile 按大小轮转的日志文件：超过 maxSize 时依次重命名为 path.1、path.2…，只保留 maxBackups 个
+type RotatingFile struct {
+	path       string
+	maxSize    int64 // 字节；<= 0 表示不轮转
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotatingFile 以追加方式打开日志文件
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	// 非 root 运行时日志位于 $XDG_STATE_HOME 下，目录可能尚不存在
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log dir for %s: %w", r.path, err)
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", r.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat log file %s: %w", r.path, err)
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write 写入一条日志；写入后会超过上限时先轮转
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate path.N-1 -> path.N … path -> path.1，超出保留数量的文件被删除
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", r.path, err)
+	}
+
+	if r.maxBackups <= 0 {
+		_ = os.Remove(r.path)
+	} else {
+		_ = os.Remove(r.backupName(r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			_ = os.Rename(r.backupName(i), r.backupName(i+1))
+		}
+		if err := os.Rename(r.path, r.backupName(1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log file %s: %w", r.path, err)
+		}
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
+
+// Close 关闭日志文件
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
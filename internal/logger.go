@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+	"io"
+	"strings"
 	"sync"
 	"time"
 )
@@ -17,37 +19,179 @@ const (
 	ERROR
 )
 
+// LogFormat 日志行格式
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+// Fields 日志记录的结构化字段；零值字段不输出
+type Fields struct {
+	Module    string        // 模块，如 hostname、ssh
+	Operation string        // 操作，如 write、exec
+	Path      string        // 涉及的文件
+	User      string        // 涉及的用户
+	DryRun    bool          // 是否为 dry-run
+	Duration  time.Duration // 耗时
+	Err       error         // 错误
+}
+
+// merge 用 o 中的非零字段覆盖 f
+func (f Fields) merge(o Fields) Fields {
+	if o.Module != "" {
+		f.Module = o.Module
+	}
+	if o.Operation != "" {
+		f.Operation = o.Operation
+	}
+	if o.Path != "" {
+		f.Path = o.Path
+	}
+	if o.User != "" {
+		f.User = o.User
+	}
+	if o.DryRun {
+		f.DryRun = true
+	}
+	if o.Duration != 0 {
+		f.Duration = o.Duration
+	}
+	if o.Err != nil {
+		f.Err = o.Err
+	}
+	return f
+}
+
+// Entry 一条日志记录
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  Fields
+}
+
+// Sink 额外的日志输出（如 syslog、journald）
+type Sink interface {
+	WriteEntry(e Entry) error
+}
+
+// logCore 同一日志记录器及其 With 派生出的记录器共享的输出与设置
+type logCore struct {
+	mu     sync.Mutex
+	level  LogLevel
+	format LogFormat
+	output io.Writer
+	sinks  []Sink
+}
+
 // Logger 日志记录器
 type Logger struct {
-	level  LogLevel
-	output *os.File
-	mu     sync.Mutex
+	core   *logCore
+	fields Fields
 }
 
-// NewLogger 创建新日志记录器
-func NewLogger(level LogLevel, output *os.File) *Logger {
-	return &Logger{
+// NewLogger 创建新日志记录器（文本格式）
+func NewLogger(level LogLevel, output io.Writer) *Logger {
+	return &Logger{core: &logCore{
 		level:  level,
+		format: LogFormatText,
 		output: output,
+	}}
+}
+
+// With 返回附带字段的记录器，与原记录器共享输出与级别；nil 记录器返回 nil
+func (l *Logger) With(fields Fields) *Logger {
+	if l == nil {
+		return nil
 	}
+	return &Logger{core: l.core, fields: l.fields.merge(fields)}
 }
 
 // log 记录日志
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
-	if level < l.level {
+	c := l.core
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if level < c.level {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	e := Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  l.fields,
+	}
+	if c.format == LogFormatJSON {
+		_, _ = c.output.Write(formatJSON(e))
+	} else {
+		_, _ = io.WriteString(c.output, formatText(e))
+	}
+	for _, s := range c.sinks {
+		_ = s.WriteEntry(e)
+	}
+}
 
-	prefix := fmt.Sprintf("[%s] [%s] ",
-		time.Now().Format("2006-01-02 15:04:05"),
-		levelName(level),
-	)
-	message := fmt.Sprintf(format, args...)
+// formatText 文本格式：[时间] [级别] 消息 key=value...
+func formatText(e Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%s] %s", e.Time.Format("2006-01-02 15:04:05"), levelName(e.Level), e.Message)
+	for _, kv := range e.Fields.pairs() {
+		value := kv[1]
+		if strings.ContainsAny(value, " \t\"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&b, " %s=%s", kv[0], value)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatJSON JSON 行格式
+func formatJSON(e Entry) []byte {
+	record := map[string]interface{}{
+		"time":  e.Time.Format(time.RFC3339Nano),
+		"level": levelName(e.Level),
+		"msg":   e.Message,
+	}
+	for _, kv := range e.Fields.pairs() {
+		record[kv[0]] = kv[1]
+	}
+	if e.Fields.DryRun {
+		record["dry_run"] = true
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"level": levelName(e.Level), "msg": e.Message})
+	}
+	return append(data, '\n')
+}
 
-	fmt.Fprintf(l.output, "%s%s\n", prefix, message)
+// pairs 按固定顺序返回非零字段（dry_run 以字符串 "true" 表示）
+func (f Fields) pairs() [][2]string {
+	var kv [][2]string
+	add := func(k, v string) {
+		if v != "" {
+			kv = append(kv, [2]string{k, v})
+		}
+	}
+	add("module", f.Module)
+	add("operation", f.Operation)
+	add("path", f.Path)
+	add("user", f.User)
+	if f.DryRun {
+		add("dry_run", "true")
+	}
+	if f.Duration != 0 {
+		add("duration", f.Duration.String())
+	}
+	if f.Err != nil {
+		add("error", f.Err.Error())
+	}
+	return kv
 }
 
 // Debug 记录 DEBUG 级别日志
@@ -72,9 +216,23 @@ func (l *Logger) Error(format string, args ...interface{}) {
 
 // SetLevel 设置日志级别
 func (l *Logger) SetLevel(level LogLevel) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.level = level
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.level = level
+}
+
+// SetFormat 设置日志行格式
+func (l *Logger) SetFormat(format LogFormat) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.format = format
+}
+
+// AddSink 增加额外的日志输出
+func (l *Logger) AddSink(s Sink) {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.sinks = append(l.core.sinks, s)
 }
 
 // levelName 返回日志级别名称
@@ -93,6 +251,11 @@ func levelName(level LogLevel) string {
 	}
 }
 
+// String 返回日志级别名称
+func (l LogLevel) String() string {
+	return levelName(l)
+}
+
 // ParseLevel 解析日志级别字符串
 func ParseLevel(s string) LogLevel {
 	switch s {
@@ -108,3 +271,11 @@ func ParseLevel(s string) LogLevel {
 		return INFO
 	}
 }
+
+// ParseLogFormat 解析日志格式，未知值返回文本格式
+func ParseLogFormat(s string) LogFormat {
+	if strings.EqualFold(s, string(LogFormatJSON)) {
+		return LogFormatJSON
+	}
+	return LogFormatText
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordSink struct{ entries []Entry }
+
+func (s *recordSink) WriteEntry(e Entry) error {
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func TestLoggerTextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(INFO, &buf)
+	logger.Debug("hidden")
+	logger.With(Fields{Module: "hostname"}).With(Fields{Operation: "write", Path: "/etc/my hosts"}).Info("written")
+
+	line := buf.String()
+	assert.NotContains(t, line, "hidden")
+	assert.True(t, strings.HasSuffix(line, `[INFO] written module=hostname operation=write path="/etc/my hosts"`+"\n"), line)
+}
+
+func TestLoggerJSONAndSink(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(DEBUG, &buf)
+	logger.SetFormat(ParseLogFormat("JSON"))
+	sink := &recordSink{}
+	logger.AddSink(sink)
+
+	// With 派生的记录器共享级别
+	child := logger.With(Fields{Module: "ssh", User: "alice"})
+	logger.SetLevel(WARN)
+	child.Info("ignored")
+	child.With(Fields{DryRun: true, Duration: 1500 * time.Millisecond, Err: errors.New("boom")}).Error("failed")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "failed", record["msg"])
+	assert.Equal(t, "ssh", record["module"])
+	assert.Equal(t, "alice", record["user"])
+	assert.Equal(t, true, record["dry_run"])
+	assert.Equal(t, "1.5s", record["duration"])
+	assert.Equal(t, "boom", record["error"])
+
+	require.Len(t, sink.entries, 1)
+	assert.Equal(t, ERROR, sink.entries[0].Level)
+	assert.Contains(t, string(journaldMessage(sink.entries[0])), "PRIORITY=3\nSYSLOG_IDENTIFIER=server-toolkit\nTOOLKIT_MODULE=ssh\n")
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "toolkit.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close() })
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+}
@@ -0,0 +1,149 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// LogLine 从日志文件读取的一行
+type LogLine struct {
+	Level  LogLevel
+	Text   string // 文本格式的内容；JSON 行转换为文本格式显示
+	DryRun bool
+	Parsed bool // 是否识别出级别；多行消息的续行为 false，沿用上一行的级别
+}
+
+// ParseLogLine 解析文本或 JSON 格式的日志行
+func ParseLogLine(line string) LogLine {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "{") {
+		if e, ok := parseJSONEntry(line); ok {
+			return LogLine{
+				Level:  e.Level,
+				Text:   strings.TrimSuffix(formatText(e), "\n"),
+				DryRun: e.Fields.DryRun || strings.Contains(e.Message, "[DRY-RUN]"),
+				Parsed: true,
+			}
+		}
+	}
+
+	l := LogLine{Text: line, DryRun: strings.Contains(line, "[DRY-RUN]")}
+	// [时间] [级别] 消息
+	if strings.HasPrefix(line, "[") {
+		if i := strings.Index(line, "] ["); i > 0 {
+			rest := line[i+3:]
+			if j := strings.Index(rest, "]"); j > 0 {
+				if level, ok := lookupLevel(rest[:j]); ok {
+					l.Level = level
+					l.Parsed = true
+				}
+			}
+		}
+	}
+	return l
+}
+
+// parseJSONEntry 将 formatJSON 输出的行还原为日志记录
+func parseJSONEntry(line string) (Entry, bool) {
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return Entry{}, false
+	}
+	str := func(key string) string {
+		s, _ := record[key].(string)
+		return s
+	}
+	level, ok := lookupLevel(str("level"))
+	if !ok {
+		return Entry{}, false
+	}
+
+	e := Entry{Level: level, Message: str("msg")}
+	e.Time, _ = time.Parse(time.RFC3339Nano, str("time"))
+	e.Fields = Fields{
+		Module:    str("module"),
+		Operation: str("operation"),
+		Path:      str("path"),
+		User:      str("user"),
+	}
+	e.Fields.DryRun, _ = record["dry_run"].(bool)
+	if d, err := time.ParseDuration(str("duration")); err == nil {
+		e.Fields.Duration = d
+	}
+	if s := str("error"); s != "" {
+		e.Fields.Err = errors.New(s)
+	}
+	return e, true
+}
+
+// lookupLevel 识别日志级别名称；与 ParseLevel 不同，未知名称返回 false
+func lookupLevel(s string) (LogLevel, bool) {
+	for _, level := range []LogLevel{DEBUG, INFO, WARN, ERROR} {
+		if s == levelName(level) {
+			return level, true
+		}
+	}
+	return INFO, false
+}
+
+// ReadLogLines 从 offset 开始读取日志文件中的完整行，返回这些行与下次读取的位置。
+// offset 超过文件大小（文件已轮转）时从头读取；未读部分超过 maxBytes 时只读取末尾，并丢弃被截断的首行。
+func ReadLogLines(path string, offset, maxBytes int64) ([]string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, offset, fmt.Errorf("failed to open log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, offset, fmt.Errorf("failed to stat log %s: %w", path, err)
+	}
+	size := info.Size()
+	if offset < 0 || offset > size {
+		offset = 0
+	}
+	start := offset
+	truncated := false
+	if maxBytes > 0 && size-start > maxBytes {
+		start = size - maxBytes
+		truncated = true
+	}
+
+	data := make([]byte, size-start)
+	if _, err := f.ReadAt(data, start); err != nil && err != io.EOF {
+		return nil, offset, fmt.Errorf("failed to read log %s: %w", path, err)
+	}
+
+	// 最后一行尚未写完时留到下次读取
+	end := bytes.LastIndexByte(data, '\n') + 1
+	next := start + int64(end)
+	data = data[:end]
+	if truncated {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	if len(data) == 0 {
+		return nil, next, nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), next, nil
+}
+
+// LogSize 日志文件当前大小；文件不存在时为 0，可作为一次操作日志的起点
+func LogSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
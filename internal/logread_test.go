@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLine(t *testing.T) {
+	l := ParseLogLine("[2024-05-01 10:00:00] [WARN] disk almost full module=system\n")
+	assert.True(t, l.Parsed)
+	assert.Equal(t, WARN, l.Level)
+	assert.False(t, l.DryRun)
+	assert.Equal(t, "[2024-05-01 10:00:00] [WARN] disk almost full module=system", l.Text)
+
+	l = ParseLogLine("[2024-05-01 10:00:00] [INFO] [DRY-RUN] Would execute: hostnamectl")
+	assert.True(t, l.DryRun)
+	assert.Equal(t, INFO, l.Level)
+
+	// 多行消息的续行
+	l = ParseLogLine("  continued output")
+	assert.False(t, l.Parsed)
+
+	var buf bytes.Buffer
+	logger := NewLogger(DEBUG, &buf)
+	logger.SetFormat(LogFormatJSON)
+	logger.With(Fields{Module: "ssh", DryRun: true, Path: "/etc/ssh/sshd_config"}).Error("write failed")
+
+	l = ParseLogLine(buf.String())
+	assert.True(t, l.Parsed)
+	assert.Equal(t, ERROR, l.Level)
+	assert.True(t, l.DryRun)
+	assert.Contains(t, l.Text, "[ERROR] write failed module=ssh path=/etc/ssh/sshd_config dry_run=true")
+
+	// 不是日志记录的 JSON 按普通文本处理
+	l = ParseLogLine(`{"foo":"bar"}`)
+	assert.False(t, l.Parsed)
+	assert.Equal(t, `{"foo":"bar"}`, l.Text)
+}
+
+func TestReadLogLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "toolkit.log")
+
+	lines, next, err := ReadLogLines(path, 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+	assert.Zero(t, next)
+
+	require.NoError(t, os.WriteFile(path, []byte("one\ntwo\nthr"), 0644))
+	lines, next, err = ReadLogLines(path, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, lines)
+	assert.Equal(t, int64(8), next)
+
+	// 写完的行在下次读取时返回
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, _ = f.WriteString("ee\nfour\n")
+	require.NoError(t, f.Close())
+	lines, next, err = ReadLogLines(path, next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "four"}, lines)
+	assert.Equal(t, LogSize(path), next)
+
+	// 只读取末尾时丢弃被截断的首行
+	lines, _, err = ReadLogLines(path, 0, 8)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"four"}, lines)
+
+	// 文件轮转后从头读取
+	require.NoError(t, os.WriteFile(path, []byte("new\n"), 0644))
+	lines, next, err = ReadLogLines(path, next, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new"}, lines)
+	assert.Equal(t, int64(4), next)
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/syslog"
+	"net"
+	"strings"
+)
+
+const logIdentifier = "server-toolkit"
+
+// journaldSocket journald 原生协议的套接字
+var journaldSocket = "/run/systemd/journal/socket"
+
+// NewLogSink 按名称创建额外的日志输出：syslog、journald；空字符串返回 nil
+func NewLogSink(name string) (Sink, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return nil, nil
+	case "syslog":
+		w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, logIdentifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		return &syslogSink{w: w}, nil
+	case "journald":
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to journald: %w", err)
+		}
+		return &journaldSink{conn: conn}, nil
+	}
+	return nil, fmt.Errorf("unknown log sink: %q (expected syslog or journald)", name)
+}
+
+// syslogSink 通过本机 syslog 记录，字段以 key=value 附加在消息后
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func (s *syslogSink) WriteEntry(e Entry) error {
+	msg := e.Message
+	for _, kv := range e.Fields.pairs() {
+		msg += fmt.Sprintf(" %s=%q", kv[0], kv[1])
+	}
+	switch e.Level {
+	case DEBUG:
+		return s.w.Debug(msg)
+	case WARN:
+		return s.w.Warning(msg)
+	case ERROR:
+		return s.w.Err(msg)
+	default:
+		return s.w.Info(msg)
+	}
+}
+
+// journaldSink 通过 journald 原生协议记录，字段作为 TOOLKIT_* 日志字段
+type journaldSink struct {
+	conn *net.UnixConn
+}
+
+func (s *journaldSink) WriteEntry(e Entry) error {
+	_, err := s.conn.Write(journaldMessage(e))
+	return err
+}
+
+// journaldMessage 编码 journald 原生协议数据报
+func journaldMessage(e Entry) []byte {
+	var b bytes.Buffer
+	put := func(key, value string) {
+		if !strings.Contains(value, "\n") {
+			b.WriteString(key + "=" + value + "\n")
+			return
+		}
+		// 含换行的值：KEY\n + 64 位小端长度 + 值 + \n
+		b.WriteString(key + "\n")
+		_ = binary.Write(&b, binary.LittleEndian, uint64(len(value)))
+		b.WriteString(value + "\n")
+	}
+	put("MESSAGE", e.Message)
+	put("PRIORITY", fmt.Sprint(syslogPriority(e.Level)))
+	put("SYSLOG_IDENTIFIER", logIdentifier)
+	for _, kv := range e.Fields.pairs() {
+		put("TOOLKIT_"+strings.ToUpper(kv[0]), kv[1])
+	}
+	return b.Bytes()
+}
+
+// syslogPriority 日志级别对应的 syslog 优先级
+func syslogPriority(level LogLevel) int {
+	switch level {
+	case DEBUG:
+		return 7
+	case WARN:
+		return 4
+	case ERROR:
+		return 3
+	default:
+		return 6
+	}
+}
@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UpdatePublicKey 发布签名公钥（minisign 格式），构建时通过
+// -ldflags "-X github.com/Akuma-real/server-toolkit/internal.UpdatePublicKey=RWQ..." 嵌入
+var UpdatePublicKey = ""
+
+// ChecksumsSignatureSuffix checksums.txt 的分离签名资产后缀（minisign）
+const ChecksumsSignatureSuffix = ".minisig"
+
+// ErrNoTrustedKey 未嵌入也未配置签名公钥时拒绝更新
+var ErrNoTrustedKey = errors.New("no trusted update signing key: build with UpdatePublicKey or set update_public_key")
+
+// minisign 签名算法：Ed 为对原文签名（minisign -l），ED 为对 BLAKE2b 摘要签名
+const (
+	sigAlgEd       = "Ed"
+	sigAlgPrehash  = "ED"
+	keyIDLen       = 8
+	publicKeyLen   = 2 + keyIDLen + ed25519.PublicKeySize
+	signatureLen   = 2 + keyIDLen + ed25519.SignatureSize
+	trustedComment = "trusted comment: "
+)
+
+// PublicKey minisign 公钥
+type PublicKey struct {
+	ID  uint64
+	Key ed25519.PublicKey
+}
+
+// ParsePublicKey 解析 minisign 公钥：可以是 .pub 文件的完整内容，也可以只是其中的 base64 行
+func ParsePublicKey(s string) (PublicKey, error) {
+	var line string
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "untrusted comment:") {
+			line = l
+			break
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(raw) != publicKeyLen {
+		return PublicKey{}, fmt.Errorf("invalid minisign public key %q", line)
+	}
+	if string(raw[:2]) != sigAlgEd {
+		return PublicKey{}, fmt.Errorf("unsupported public key algorithm %q", raw[:2])
+	}
+	return PublicKey{
+		ID:  binary.LittleEndian.Uint64(raw[2 : 2+keyIDLen]),
+		Key: ed25519.PublicKey(raw[2+keyIDLen:]),
+	}, nil
+}
+
+// String 返回 minisign 格式的 base64 公钥
+func (k PublicKey) String() string {
+	raw := make([]byte, 0, publicKeyLen)
+	raw = append(raw, sigAlgEd...)
+	raw = binary.LittleEndian.AppendUint64(raw, k.ID)
+	raw = append(raw, k.Key...)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// parsePublicKeys 解析全部受信任公钥，跳过空值
+func parsePublicKeys(keys []string) ([]PublicKey, error) {
+	var parsed []PublicKey
+	for _, s := range keys {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		key, err := ParsePublicKey(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, key)
+	}
+	if len(parsed) == 0 {
+		return nil, ErrNoTrustedKey
+	}
+	return parsed, nil
+}
+
+// VerifySignature 校验 minisign 分离签名：签名须来自受信任公钥之一，且 trusted comment 的全局签名有效，
+// 返回经过验证的 trusted comment。仅支持对原文签名（minisign -S -l），不支持默认的 BLAKE2b 预哈希签名
+func VerifySignature(data, sig []byte, keys []PublicKey) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(string(sig), "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], trustedComment) {
+		return "", errors.New("malformed minisign signature")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(raw) != signatureLen {
+		return "", errors.New("malformed minisign signature")
+	}
+	switch string(raw[:2]) {
+	case sigAlgEd:
+	case sigAlgPrehash:
+		return "", errors.New("prehashed minisign signatures are not supported; sign with minisign -S -l")
+	default:
+		return "", fmt.Errorf("unsupported signature algorithm %q", raw[:2])
+	}
+
+	id := binary.LittleEndian.Uint64(raw[2 : 2+keyIDLen])
+	var key *PublicKey
+	for i := range keys {
+		if keys[i].ID == id {
+			key = &keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return "", fmt.Errorf("signature made by untrusted key %016X", id)
+	}
+
+	signature := raw[2+keyIDLen:]
+	if !ed25519.Verify(key.Key, data, signature) {
+		return "", errors.New("signature verification failed")
+	}
+
+	// 全局签名覆盖签名本身与 trusted comment，防止替换注释
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return "", errors.New("malformed minisign global signature")
+	}
+	comment := strings.TrimPrefix(lines[2], trustedComment)
+	if !ed25519.Verify(key.Key, append(bytes.Clone(signature), comment...), global) {
+		return "", errors.New("trusted comment signature verification failed")
+	}
+	return comment, nil
+}
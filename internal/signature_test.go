@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	id, priv, pub := newSigningKey(t, 7)
+	data := []byte("checksums")
+	sig := signMinisign(data, id, priv, "server-toolkit v0.2.0")
+
+	// 公钥既可以是 .pub 文件内容，也可以只是 base64 行
+	key, err := ParsePublicKey("untrusted comment: minisign public key 7\n" + pub + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), key.ID)
+	assert.Equal(t, pub, key.String())
+	keys := []PublicKey{key}
+
+	comment, err := VerifySignature(data, sig, keys)
+	require.NoError(t, err)
+	assert.Equal(t, "server-toolkit v0.2.0", comment)
+	_, err = VerifySignature([]byte("checksums!"), sig, keys)
+	require.ErrorContains(t, err, "signature verification failed")
+
+	// 替换 trusted comment
+	forged := strings.Replace(string(sig), "v0.2.0", "v0.3.0", 1)
+	_, err = VerifySignature(data, []byte(forged), keys)
+	require.ErrorContains(t, err, "trusted comment")
+
+	// 预哈希签名（minisign 默认）给出明确提示
+	lines := strings.Split(string(sig), "\n")
+	raw, err := base64.StdEncoding.DecodeString(lines[1])
+	require.NoError(t, err)
+	raw[1] = 'D'
+	lines[1] = base64.StdEncoding.EncodeToString(raw)
+	_, err = VerifySignature(data, []byte(strings.Join(lines, "\n")), keys)
+	require.ErrorContains(t, err, "minisign -S -l")
+
+	_, err = VerifySignature(data, []byte("garbage"), keys)
+	require.ErrorContains(t, err, "malformed")
+
+	_, err = ParsePublicKey("not a key")
+	require.Error(t, err)
+	_, err = parsePublicKeys([]string{"", " "})
+	require.ErrorIs(t, err, ErrNoTrustedKey)
+}
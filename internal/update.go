@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -16,141 +17,357 @@ import (
 )
 
 const (
-	repo   = "Akuma-real/server-toolkit"
-	apiURL = "https://api.github.com/repos/" + repo + "/releases/latest"
-	dlBase = "https://github.com/" + repo + "/releases/download"
+	repo = "Akuma-real/server-toolkit"
+	// DefaultUpdateBaseURL 默认的发布信息地址（GitHub API）
+	DefaultUpdateBaseURL = "https://api.github.com/repos/" + repo
+	dlBase               = "https://github.com/" + repo + "/releases/download"
+)
+
+// 更新渠道（与安装脚本的 --nightly 对应）
+const (
+	ChannelStable  = "stable"
+	ChannelNightly = "nightly"
 )
 
 var httpClient = &http.Client{Timeout: 30 * time.Second}
 
 // Release GitHub Release 信息
 type Release struct {
-	TagName string `json:"tag_name"`
-	HTMLURL string `json:"html_url"`
-	Body    string `json:"body"`
-	Assets  []struct {
+	TagName    string `json:"tag_name"`
+	HTMLURL    string `json:"html_url"`
+	Body       string `json:"body"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
 		Name               string `json:"name"`
 		BrowserDownloadURL string `json:"browser_download_url"`
 	} `json:"assets"`
 }
 
+// UpdateOptions 更新来源
+type UpdateOptions struct {
+	Channel string // stable（默认，最新正式版）或 nightly（预发布版）
+	Version string // 固定安装的版本（如 v0.2.0），为空表示渠道的最新版本
+	// BaseURL 发布信息地址，为空使用 GitHub。镜像需提供与 GitHub API 相同的路径：
+	// {base}/releases/latest、{base}/releases/tags/{tag}，
+	// 资产按 browser_download_url 下载，缺失时使用 {base}/releases/download/{tag}/{name}
+	BaseURL string
+	DryRun  bool // 只下载并校验，不替换二进制
+
+	// PublicKeys 额外受信任的签名公钥（minisign），与构建时嵌入的 UpdatePublicKey 一起校验 checksums.txt 的签名
+	PublicKeys []string
+
+	// CachePath 检查结果的缓存文件，CacheTTL 内再次检查直接使用缓存；为空或 TTL <= 0 不缓存
+	CachePath string
+	CacheTTL  time.Duration
+}
+
+// UpdateInfo 检查更新的结果
+type UpdateInfo struct {
+	Current   string    `json:"current"`
+	Latest    string    `json:"latest"`
+	Available bool      `json:"available"`
+	Notes     string    `json:"notes,omitempty"` // 发布说明（Release Body）
+	URL       string    `json:"url,omitempty"`
+	Source    string    `json:"source"` // 渠道、固定版本与地址；变化后缓存失效
+	CheckedAt time.Time `json:"checked_at"`
+}
+
 // Updater 更新器
 type Updater struct {
-	current string
-	logger  *Logger
+	current  string
+	opts     UpdateOptions
+	logger   *Logger
+	execPath string // 要替换的二进制，为空时使用当前可执行文件
 }
 
-// NewUpdater 创建新更新器
+// NewUpdater 创建新更新器（stable 渠道，GitHub）
 func NewUpdater(current string, logger *Logger) *Updater {
+	return NewUpdaterWithOptions(current, UpdateOptions{}, logger)
+}
+
+// NewUpdaterWithOptions 按渠道、固定版本与镜像地址创建更新器
+func NewUpdaterWithOptions(current string, opts UpdateOptions, logger *Logger) *Updater {
+	if opts.Channel == "" {
+		opts.Channel = ChannelStable
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultUpdateBaseURL
+	}
 	return &Updater{
 		current: current,
+		opts:    opts,
 		logger:  logger,
 	}
 }
 
-// Check 检查更新
+// Check 检查更新（有缓存时使用缓存），返回可更新到的版本
 func (u *Updater) Check() (string, bool, error) {
-	u.logger.Info("%s", i18n.T("info"))
+	info, err := u.CheckInfo(false)
+	if err != nil || !info.Available {
+		return "", false, err
+	}
+	return info.Latest, true, nil
+}
 
-	release, err := fetchLatestRelease()
+// CheckInfo 检查更新并返回发布说明；refresh 为 true 时忽略缓存
+func (u *Updater) CheckInfo(refresh bool) (UpdateInfo, error) {
+	if !refresh {
+		if info, ok := u.loadCache(); ok {
+			u.logger.Debug("Using cached update check from %s: latest %s", info.CheckedAt.Format(time.RFC3339), info.Latest)
+			return info, nil
+		}
+	}
+
+	u.logger.Info("Checking for updates: current %s, source %s", u.current, u.source())
+	release, err := u.fetchRelease()
 	if err != nil {
-		return "", false, err
+		return UpdateInfo{}, err
 	}
 
-	if release.TagName != u.current {
-		u.logger.Info("%s %s %s", i18n.T("info"), i18n.T("settings_title"), release.TagName)
-		return release.TagName, true, nil
+	available, err := u.isNewer(release)
+	if err != nil {
+		return UpdateInfo{}, err
 	}
 
-	u.logger.Info("%s %s", i18n.T("info"), i18n.T("info"))
-	return "", false, nil
+	info := UpdateInfo{
+		Current:   u.current,
+		Latest:    release.TagName,
+		Available: available,
+		Notes:     release.Body,
+		URL:       release.HTMLURL,
+		Source:    u.source(),
+		CheckedAt: time.Now(),
+	}
+	if available {
+		u.logger.Info("Update available: %s -> %s", u.current, release.TagName)
+	} else {
+		u.logger.Info("No update available: current %s, latest %s", u.current, release.TagName)
+	}
+	u.saveCache(info)
+	return info, nil
 }
 
-// DoUpdate 执行更新
-func (u *Updater) DoUpdate() error {
-	release, err := fetchLatestRelease()
+// isNewer 发布版本是否应替换当前版本：固定版本只要不同即替换；两者都是语义化版本时按版本比较（不降级）；
+// 否则（如 nightly 标签）比较发布的二进制与当前二进制的校验和
+func (u *Updater) isNewer(release Release) (bool, error) {
+	if release.TagName == u.current {
+		return false, nil
+	}
+	if u.opts.Version != "" {
+		return true, nil
+	}
+	if cmp, ok := CompareVersions(release.TagName, u.current); ok {
+		return cmp > 0, nil
+	}
+
+	expected, err := u.releaseChecksum(release, binaryName())
 	if err != nil {
-		return err
+		return false, err
+	}
+	execPath, err := u.ExecPath()
+	if err != nil {
+		return false, err
 	}
+	data, err := os.ReadFile(execPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", execPath, err)
+	}
+	return !strings.EqualFold(expected, fmt.Sprintf("%x", sha256.Sum256(data))), nil
+}
 
-	latest := release.TagName
-	hasUpdate := latest != u.current
+// source 更新来源描述，用于日志与缓存失效判断
+func (u *Updater) source() string {
+	s := u.opts.Channel
+	if u.opts.Version != "" {
+		s += "@" + u.opts.Version
+	}
+	return s + " " + u.opts.BaseURL
+}
 
-	if !hasUpdate {
-		u.logger.Info("%s", i18n.T("info"))
-		return nil
+// loadCache 读取未过期且来源与当前版本一致的检查结果
+func (u *Updater) loadCache() (UpdateInfo, bool) {
+	if u.opts.CachePath == "" || u.opts.CacheTTL <= 0 {
+		return UpdateInfo{}, false
+	}
+	data, err := os.ReadFile(u.opts.CachePath)
+	if err != nil {
+		return UpdateInfo{}, false
 	}
+	var info UpdateInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return UpdateInfo{}, false
+	}
+	age := time.Since(info.CheckedAt)
+	if info.Current != u.current || info.Source != u.source() || age < 0 || age >= u.opts.CacheTTL {
+		return UpdateInfo{}, false
+	}
+	return info, true
+}
 
-	u.logger.Info("%s %s", i18n.T("info"), latest)
+// saveCache 保存检查结果；失败只记录 DEBUG 日志（非 root 用户通常无法写入）
+func (u *Updater) saveCache(info UpdateInfo) {
+	if u.opts.CachePath == "" || u.opts.CacheTTL <= 0 {
+		return
+	}
+	data, err := json.Marshal(info)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(u.opts.CachePath), 0755); err == nil {
+			err = os.WriteFile(u.opts.CachePath, data, 0644)
+		}
+	}
+	if err != nil {
+		u.logger.Debug("Failed to cache update check in %s: %v", u.opts.CachePath, err)
+	}
+}
 
-	// 构建下载 URL
-	osName := runtime.GOOS
-	arch := runtime.GOARCH
-	downloadURL := fmt.Sprintf("%s/%s/server-toolkit-%s-%s", dlBase, latest, osName, arch)
+// DoUpdate 下载、校验并替换当前二进制，原文件保留为 .bak；返回安装的版本，已是最新时返回空
+func (u *Updater) DoUpdate() (string, error) {
+	release, err := u.fetchRelease()
+	if err != nil {
+		return "", err
+	}
 
+	latest := release.TagName
+	newer, err := u.isNewer(release)
+	if err != nil {
+		return "", err
+	}
+	if !newer {
+		u.logger.Info("Already up to date: current %s, latest %s", u.current, latest)
+		return "", nil
+	}
+
+	u.logger.Info("Updating %s -> %s (channel %s)", u.current, latest, u.opts.Channel)
+
+	assetName := binaryName()
+	downloadURL := u.assetURL(release, assetName)
 	u.logger.Info("%s", fmt.Sprintf(i18n.T("log_fetching_url"), downloadURL))
 
-	// 下载文件
-	resp, err := httpClient.Get(downloadURL)
+	// 读取下载内容（用于校验 + 写文件）
+	binaryData, err := download(downloadURL)
 	if err != nil {
-		return fmt.Errorf(i18n.T("err_operation_failed"), err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("HTTP request failed: %s", resp.Status)
+	// 校验 SHA256（release 需提供 checksums 资产）
+	if err := u.verifyReleaseSHA256(binaryData, assetName, release); err != nil {
+		return "", err
 	}
 
-	// 读取下载内容（用于校验 + 写文件）
-	binaryData, err := io.ReadAll(resp.Body)
+	execPath, err := u.ExecPath()
 	if err != nil {
-		return fmt.Errorf(i18n.T("err_operation_failed"), err)
+		return "", err
+	}
+
+	// nightly 的标签不变：内容与当前二进制相同则无需替换
+	if current, err := os.ReadFile(execPath); err == nil && sha256.Sum256(current) == sha256.Sum256(binaryData) {
+		u.logger.Info("Already up to date: %s", latest)
+		return "", nil
+	}
+
+	if u.opts.DryRun {
+		NewDryRunManager(true, u.logger).LogFileOperation("replace", execPath)
+		return latest, nil
+	}
+
+	if err := replaceBinary(execPath, binaryData); err != nil {
+		return "", err
 	}
 
-	// 校验 SHA256（若 release 提供 checksums 资产）
-	if err := verifyReleaseSHA256(binaryData, fmt.Sprintf("server-toolkit-%s-%s", osName, arch), release, u.logger); err != nil {
+	u.logger.Info("Updated %s to %s, previous binary saved as %s", execPath, latest, execPath+".bak")
+	return latest, nil
+}
+
+// Rollback 与上次更新保留的 .bak 互换，恢复更新前的二进制（再次执行可撤销回滚）
+func (u *Updater) Rollback() error {
+	execPath, err := u.ExecPath()
+	if err != nil {
 		return err
 	}
+	backupPath := execPath + ".bak"
+	if _, err := os.Stat(backupPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no backup binary found at %s", backupPath)
+		}
+		return fmt.Errorf("failed to stat %s: %w", backupPath, err)
+	}
 
-	// 创建临时文件
-	tmpFile, err := os.CreateTemp("", "server-toolkit-")
+	if u.opts.DryRun {
+		NewDryRunManager(true, u.logger).LogFileOperation("restore", backupPath)
+		return nil
+	}
+
+	tmpPath := execPath + ".rollback"
+	if err := os.Rename(execPath, tmpPath); err != nil {
+		return fmt.Errorf("failed to move %s: %w", execPath, err)
+	}
+	if err := os.Rename(backupPath, execPath); err != nil {
+		_ = os.Rename(tmpPath, execPath)
+		return fmt.Errorf("failed to restore %s: %w", backupPath, err)
+	}
+	if err := os.Rename(tmpPath, backupPath); err != nil {
+		return fmt.Errorf("failed to keep %s as backup: %w", tmpPath, err)
+	}
+
+	u.logger.Info("Rolled back %s to the previous binary", execPath)
+	return nil
+}
+
+// HasBackup 是否存在可回滚的 .bak
+func (u *Updater) HasBackup() bool {
+	execPath, err := u.ExecPath()
 	if err != nil {
-		return fmt.Errorf(i18n.T("err_operation_failed"), err)
+		return false
 	}
-	defer os.Remove(tmpFile.Name())
+	_, err = os.Stat(execPath + ".bak")
+	return err == nil
+}
 
-	// 写入临时文件
-	_, err = io.Copy(tmpFile, bytes.NewReader(binaryData))
+// ExecPath 要替换的二进制路径（解析符号链接）
+func (u *Updater) ExecPath() (string, error) {
+	if u.execPath != "" {
+		return u.execPath, nil
+	}
+	path, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path, nil
+}
+
+// replaceBinary 在同一目录写入临时文件后替换，原文件改名为 .bak
+func replaceBinary(execPath string, data []byte) error {
+	// 临时文件与目标在同一文件系统，rename 才是原子的
+	tmpFile, err := os.CreateTemp(filepath.Dir(execPath), ".server-toolkit-")
 	if err != nil {
 		return fmt.Errorf(i18n.T("err_operation_failed"), err)
 	}
-	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
 
-	// 设置可执行权限
-	if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
+	if _, err := io.Copy(tmpFile, bytes.NewReader(data)); err != nil {
+		tmpFile.Close()
 		return fmt.Errorf(i18n.T("err_operation_failed"), err)
 	}
+	tmpFile.Close()
 
-	// 获取当前可执行文件路径
-	execPath, err := os.Executable()
-	if err != nil {
+	if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
 		return fmt.Errorf(i18n.T("err_operation_failed"), err)
 	}
 
-	// 备份当前文件
 	backupPath := execPath + ".bak"
 	if err := os.Rename(execPath, backupPath); err != nil {
 		return fmt.Errorf(i18n.T("err_operation_failed"), err)
 	}
 
-	// 移动新文件
 	if err := os.Rename(tmpFile.Name(), execPath); err != nil {
 		// 失败时恢复备份
 		os.Rename(backupPath, execPath)
 		return fmt.Errorf(i18n.T("err_operation_failed"), err)
 	}
-
-	u.logger.Info("%s", i18n.T("success"))
 	return nil
 }
 
@@ -159,62 +376,105 @@ func (u *Updater) GetCurrentVersion() string {
 	return u.current
 }
 
-func verifyReleaseSHA256(binaryData []byte, binaryName string, release Release, logger *Logger) error {
-	var checksumURL string
+func (u *Updater) verifyReleaseSHA256(binaryData []byte, binaryName string, release Release) error {
+	expected, err := u.releaseChecksum(release, binaryName)
+	if err != nil {
+		return err
+	}
+
+	actualSum := sha256.Sum256(binaryData)
+	actual := fmt.Sprintf("%x", actualSum)
+	if !strings.EqualFold(expected, actual) {
+		return fmt.Errorf("checksum mismatch for %s", binaryName)
+	}
+
+	u.logger.Info("Checksum verified for %s", binaryName)
+	return nil
+}
+
+// releaseChecksum 从发布的 checksums 资产中读取指定文件的 SHA256；checksums 须带有受信任公钥的签名
+func (u *Updater) releaseChecksum(release Release, binaryName string) (string, error) {
+	var checksumName, checksumURL string
 	for _, asset := range release.Assets {
 		if asset.Name == "checksums.txt" || asset.Name == "checksums.sha256" {
-			checksumURL = asset.BrowserDownloadURL
+			checksumName, checksumURL = asset.Name, asset.BrowserDownloadURL
 			break
 		}
 	}
 
 	if checksumURL == "" {
-		return fmt.Errorf("checksum asset not found for release %s", release.TagName)
+		return "", fmt.Errorf("checksum asset not found for release %s", release.TagName)
 	}
 
-	resp, err := httpClient.Get(checksumURL)
+	// 先确认有可用的公钥，避免无意义的下载
+	keys, err := parsePublicKeys(append([]string{UpdatePublicKey}, u.opts.PublicKeys...))
 	if err != nil {
-		return fmt.Errorf("failed to download checksum file: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to download checksum file: HTTP %s", resp.Status)
+	checksumData, err := download(checksumURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download checksum file: %w", err)
 	}
 
-	checksumData, err := io.ReadAll(resp.Body)
+	// 缺少签名时拒绝更新（fail closed）
+	sigName := checksumName + ChecksumsSignatureSuffix
+	sigData, err := download(u.assetURL(release, sigName))
+	if err != nil {
+		return "", fmt.Errorf("signature %s not available for release %s, refusing to update: %w", sigName, release.TagName, err)
+	}
+	comment, err := VerifySignature(checksumData, sigData, keys)
 	if err != nil {
-		return fmt.Errorf("failed to read checksum file: %w", err)
+		return "", fmt.Errorf("%s for release %s: %w", sigName, release.TagName, err)
 	}
+	if err := checkSignatureComment(release.TagName, comment); err != nil {
+		return "", fmt.Errorf("%s for release %s: %w", sigName, release.TagName, err)
+	}
+	u.logger.Debug("Verified signature of %s for release %s", checksumName, release.TagName)
 
 	expected, found := extractChecksumForFile(string(checksumData), binaryName)
 	if !found {
-		return fmt.Errorf("checksum for %s not found in checksum asset", binaryName)
+		return "", fmt.Errorf("checksum for %s not found in checksum asset", binaryName)
 	}
+	return expected, nil
+}
 
-	actualSum := sha256.Sum256(binaryData)
-	actual := fmt.Sprintf("%x", actualSum)
-	if !strings.EqualFold(expected, actual) {
-		return fmt.Errorf("checksum mismatch for %s", binaryName)
+// checkSignatureComment 确认签名属于该发布：发布流程把 "server-toolkit <tag>" 写入 trusted comment，
+// nightly 为 "server-toolkit nightly <commit>"，防止把其他版本的 checksums 与签名重放到此标签下
+func checkSignatureComment(tag, comment string) error {
+	expected := "server-toolkit " + tag
+	if tag == ChannelNightly {
+		if comment == expected || strings.HasPrefix(comment, expected+" ") {
+			return nil
+		}
+	} else if comment == expected {
+		return nil
 	}
+	return fmt.Errorf("trusted comment %q does not match release %s", comment, tag)
+}
 
-	logger.Info("Checksum verified for %s", binaryName)
-	return nil
+// binaryName 当前平台的发布二进制名称
+func binaryName() string {
+	return fmt.Sprintf("server-toolkit-%s-%s", runtime.GOOS, runtime.GOARCH)
 }
 
-func fetchLatestRelease() (Release, error) {
-	resp, err := httpClient.Get(apiURL)
-	if err != nil {
-		return Release{}, fmt.Errorf("failed to fetch latest release: %w", err)
+// fetchRelease 按固定版本或渠道获取发布信息：固定版本 /releases/tags/{v}，nightly /releases/tags/nightly，否则 /releases/latest
+func (u *Updater) fetchRelease() (Release, error) {
+	url := u.opts.BaseURL + "/releases/latest"
+	switch {
+	case u.opts.Version != "":
+		url = u.opts.BaseURL + "/releases/tags/" + u.opts.Version
+	case u.opts.Channel == ChannelNightly:
+		url = u.opts.BaseURL + "/releases/tags/nightly"
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return Release{}, fmt.Errorf("HTTP request failed: %s", resp.Status)
+	data, err := download(url)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to fetch release: %w", err)
 	}
 
 	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	if err := json.Unmarshal(data, &release); err != nil {
 		return Release{}, fmt.Errorf("failed to decode release response: %w", err)
 	}
 
@@ -225,6 +485,39 @@ func fetchLatestRelease() (Release, error) {
 	return release, nil
 }
 
+// assetURL 资产下载地址：优先使用发布信息中的 browser_download_url
+func (u *Updater) assetURL(release Release, name string) string {
+	for _, asset := range release.Assets {
+		if asset.Name == name && asset.BrowserDownloadURL != "" {
+			return asset.BrowserDownloadURL
+		}
+	}
+	base := dlBase
+	if u.opts.BaseURL != DefaultUpdateBaseURL {
+		base = u.opts.BaseURL + "/releases/download"
+	}
+	return fmt.Sprintf("%s/%s/%s", base, release.TagName, name)
+}
+
+// download 下载 URL 的全部内容
+func download(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP request failed: %s: %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return data, nil
+}
+
 func extractChecksumForFile(content, filename string) (string, bool) {
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
@@ -0,0 +1,412 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// releaseStub 模拟发布镜像：tag -> 该版本的二进制内容
+type releaseStub struct {
+	server   *httptest.Server
+	binaries map[string][]byte
+	latest   string
+	// checksums 覆盖 checksums.txt 的内容（模拟篡改），为空时按二进制计算
+	checksums map[string]string
+	// tampered 替换发布后的 checksums.txt 内容，签名仍针对原内容
+	tampered map[string]string
+	// unsigned 不提供 checksums.txt.minisig 的版本
+	unsigned map[string]bool
+	// replayed 在该标签下提供另一版本的二进制、checksums 与签名（tag -> 原标签）
+	replayed map[string]string
+	// apiRequests 发布信息请求次数
+	apiRequests int
+
+	keyID      uint64
+	privateKey ed25519.PrivateKey
+	publicKey  string
+}
+
+func newReleaseStub(t *testing.T) *releaseStub {
+	s := &releaseStub{
+		binaries:  map[string][]byte{},
+		checksums: map[string]string{},
+		tampered:  map[string]string{},
+		unsigned:  map[string]bool{},
+		replayed:  map[string]string{},
+	}
+	s.keyID, s.privateKey, s.publicKey = newSigningKey(t, 0x1122334455667788)
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+// newSigningKey 生成 minisign 密钥对，返回私钥与 base64 公钥
+func newSigningKey(t *testing.T, id uint64) (uint64, ed25519.PrivateKey, string) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return id, priv, PublicKey{ID: id, Key: pub}.String()
+}
+
+// signMinisign 生成与 minisign -S -l 相同格式的分离签名
+func signMinisign(data []byte, id uint64, key ed25519.PrivateKey, comment string) []byte {
+	signature := ed25519.Sign(key, data)
+	raw := binary.LittleEndian.AppendUint64([]byte(sigAlgEd), id)
+	raw = append(raw, signature...)
+	global := ed25519.Sign(key, append(bytes.Clone(signature), comment...))
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw), comment, base64.StdEncoding.EncodeToString(global)))
+}
+
+// signatureCommentFor 发布流程写入签名的 trusted comment
+func signatureCommentFor(tag string) string {
+	if tag == ChannelNightly {
+		return "server-toolkit nightly 0123456789abcdef"
+	}
+	return "server-toolkit " + tag
+}
+
+// checksumsFor 发布时生成的 checksums.txt 内容
+func (s *releaseStub) checksumsFor(tag string) string {
+	if sums, ok := s.checksums[tag]; ok {
+		return sums
+	}
+	return fmt.Sprintf("%x  %s\n", sha256.Sum256(s.binaries[tag]), stubBinaryName())
+}
+
+func (s *releaseStub) serve(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+	switch {
+	case path == "/releases/latest":
+		s.apiRequests++
+		s.writeRelease(w, s.latest)
+	case strings.HasPrefix(path, "/releases/tags/"):
+		s.apiRequests++
+		s.writeRelease(w, strings.TrimPrefix(path, "/releases/tags/"))
+	case strings.HasPrefix(path, "/releases/download/"):
+		parts := strings.Split(strings.TrimPrefix(path, "/releases/download/"), "/")
+		if _, ok := s.binaries[parts[0]]; !ok || len(parts) != 2 {
+			http.NotFound(w, r)
+			return
+		}
+		if src, ok := s.replayed[parts[0]]; ok {
+			parts[0] = src
+		}
+		data := s.binaries[parts[0]]
+		switch parts[1] {
+		case "checksums.txt":
+			if sums, ok := s.tampered[parts[0]]; ok {
+				fmt.Fprint(w, sums)
+				return
+			}
+			fmt.Fprint(w, s.checksumsFor(parts[0]))
+			return
+		case "checksums.txt" + ChecksumsSignatureSuffix:
+			if s.unsigned[parts[0]] {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(signMinisign([]byte(s.checksumsFor(parts[0])), s.keyID, s.privateKey, signatureCommentFor(parts[0])))
+			return
+		}
+		if parts[1] != stubBinaryName() {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *releaseStub) writeRelease(w http.ResponseWriter, tag string) {
+	if _, ok := s.binaries[tag]; !ok {
+		http.NotFound(w, nil)
+		return
+	}
+	type asset struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"tag_name":   tag,
+		"prerelease": tag == ChannelNightly,
+		"body":       "notes for " + tag,
+		"assets": []asset{
+			// 二进制不列出：按 {base}/releases/download/{tag}/{name} 下载
+			{Name: "checksums.txt", URL: s.server.URL + "/releases/download/" + tag + "/checksums.txt"},
+		},
+	})
+}
+
+func stubBinaryName() string {
+	return fmt.Sprintf("server-toolkit-%s-%s", runtime.GOOS, runtime.GOARCH)
+}
+
+func newTestUpdater(t *testing.T, stub *releaseStub, current string, opts UpdateOptions) (*Updater, string) {
+	execPath := filepath.Join(t.TempDir(), "server-toolkit")
+	require.NoError(t, os.WriteFile(execPath, []byte("binary "+current), 0755))
+	opts.BaseURL = stub.server.URL + "/"
+	if opts.PublicKeys == nil {
+		opts.PublicKeys = []string{stub.publicKey}
+	}
+	u := NewUpdaterWithOptions(current, opts, NewLogger(ERROR, os.Stdout))
+	u.execPath = execPath
+	return u, execPath
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestDoUpdateAndRollback(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.latest = "v0.2.0"
+
+	u, execPath := newTestUpdater(t, stub, "v0.1.0", UpdateOptions{})
+	assert.False(t, u.HasBackup())
+
+	installed, err := u.DoUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", installed)
+	assert.Equal(t, "binary v0.2.0", readFile(t, execPath))
+	assert.Equal(t, "binary v0.1.0", readFile(t, execPath+".bak"))
+	info, err := os.Stat(execPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	// 回滚与 .bak 互换，再次执行可撤销
+	require.NoError(t, u.Rollback())
+	assert.Equal(t, "binary v0.1.0", readFile(t, execPath))
+	assert.Equal(t, "binary v0.2.0", readFile(t, execPath+".bak"))
+	require.NoError(t, u.Rollback())
+	assert.Equal(t, "binary v0.2.0", readFile(t, execPath))
+}
+
+func TestDoUpdateChannelsAndPinning(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.binaries["v0.1.5"] = []byte("binary v0.1.5")
+	stub.binaries[ChannelNightly] = []byte("binary nightly")
+	stub.latest = "v0.2.0"
+
+	u, execPath := newTestUpdater(t, stub, "v0.1.0", UpdateOptions{Version: "v0.1.5"})
+	installed, err := u.DoUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.1.5", installed)
+	assert.Equal(t, "binary v0.1.5", readFile(t, execPath))
+
+	u, execPath = newTestUpdater(t, stub, "v0.1.0", UpdateOptions{Channel: ChannelNightly})
+	installed, err = u.DoUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, ChannelNightly, installed)
+	assert.Equal(t, "binary nightly", readFile(t, execPath))
+
+	// nightly 标签不变：内容相同则不再替换
+	installed, err = u.DoUpdate()
+	require.NoError(t, err)
+	assert.Empty(t, installed)
+	assert.Equal(t, "binary v0.1.0", readFile(t, execPath+".bak"))
+
+	// 已是最新版本
+	u, _ = newTestUpdater(t, stub, "v0.2.0", UpdateOptions{})
+	installed, err = u.DoUpdate()
+	require.NoError(t, err)
+	assert.Empty(t, installed)
+
+	// 不存在的版本
+	u, _ = newTestUpdater(t, stub, "v0.1.0", UpdateOptions{Version: "v9.9.9"})
+	_, err = u.DoUpdate()
+	require.Error(t, err)
+}
+
+// 二进制被篡改：签名的 checksums 与下载内容不符
+func TestDoUpdateRejectsChecksumMismatch(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.checksums["v0.2.0"] = fmt.Sprintf("%x  %s\n", sha256.Sum256([]byte("other")), stubBinaryName())
+	stub.latest = "v0.2.0"
+
+	u, execPath := newTestUpdater(t, stub, "v0.1.0", UpdateOptions{})
+	_, err := u.DoUpdate()
+	require.ErrorContains(t, err, "checksum mismatch")
+	assert.Equal(t, "binary v0.1.0", readFile(t, execPath))
+	assert.NoFileExists(t, execPath+".bak")
+}
+
+func TestDoUpdateVerifiesSignature(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.latest = "v0.2.0"
+
+	rejects := func(opts UpdateOptions, contains string) {
+		t.Helper()
+		u, execPath := newTestUpdater(t, stub, "v0.1.0", opts)
+		_, err := u.DoUpdate()
+		require.ErrorContains(t, err, contains)
+		assert.Equal(t, "binary v0.1.0", readFile(t, execPath))
+		assert.NoFileExists(t, execPath+".bak")
+	}
+
+	// checksums 被篡改（与替换后的二进制一致），签名不再匹配
+	stub.binaries["v0.2.0"] = []byte("evil binary")
+	stub.tampered["v0.2.0"] = fmt.Sprintf("%x  %s\n", sha256.Sum256([]byte("evil binary")), stubBinaryName())
+	stub.checksums["v0.2.0"] = fmt.Sprintf("%x  %s\n", sha256.Sum256([]byte("binary v0.2.0")), stubBinaryName())
+	rejects(UpdateOptions{}, "signature verification failed")
+	delete(stub.tampered, "v0.2.0")
+	delete(stub.checksums, "v0.2.0")
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+
+	// 缺少签名：拒绝更新
+	stub.unsigned["v0.2.0"] = true
+	rejects(UpdateOptions{}, "refusing to update")
+	delete(stub.unsigned, "v0.2.0")
+
+	// 未配置任何公钥
+	rejects(UpdateOptions{PublicKeys: []string{}}, "no trusted update signing key")
+
+	// 重放其他版本的 checksums 与签名：内容与签名都有效，但 trusted comment 属于 v0.1.5
+	stub.binaries["v0.1.5"] = []byte("binary v0.1.5")
+	stub.replayed["v0.2.0"] = "v0.1.5"
+	rejects(UpdateOptions{}, `trusted comment "server-toolkit v0.1.5" does not match release v0.2.0`)
+	delete(stub.replayed, "v0.2.0")
+
+	// 非受信任公钥的签名
+	_, _, other := newSigningKey(t, 42)
+	rejects(UpdateOptions{PublicKeys: []string{other}}, "untrusted key")
+
+	// 额外配置的公钥与其他公钥并存
+	u, execPath := newTestUpdater(t, stub, "v0.1.0", UpdateOptions{PublicKeys: []string{other, stub.publicKey}})
+	installed, err := u.DoUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", installed)
+	assert.Equal(t, "binary v0.2.0", readFile(t, execPath))
+}
+
+func TestDoUpdateDryRun(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.latest = "v0.2.0"
+
+	u, execPath := newTestUpdater(t, stub, "v0.1.0", UpdateOptions{DryRun: true})
+	installed, err := u.DoUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", installed)
+	assert.Equal(t, "binary v0.1.0", readFile(t, execPath))
+
+	require.ErrorContains(t, u.Rollback(), "no backup binary")
+}
+
+func TestCheckComparesVersions(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.binaries["v0.2.0-beta.1"] = []byte("binary v0.2.0-beta.1")
+	stub.binaries[ChannelNightly] = []byte("binary nightly")
+	stub.latest = "v0.2.0"
+
+	// 本地版本更新时不提示“降级”更新
+	for _, current := range []string{"v0.3.0", "v0.2.0", "v0.2.0-3-gabc1234"} {
+		u, execPath := newTestUpdater(t, stub, current, UpdateOptions{})
+		latest, available, err := u.Check()
+		require.NoError(t, err)
+		assert.False(t, available, current)
+		assert.Empty(t, latest)
+
+		installed, err := u.DoUpdate()
+		require.NoError(t, err)
+		assert.Empty(t, installed)
+		assert.Equal(t, "binary "+current, readFile(t, execPath))
+	}
+
+	u, _ := newTestUpdater(t, stub, "v0.2.0-beta.1", UpdateOptions{})
+	info, err := u.CheckInfo(true)
+	require.NoError(t, err)
+	assert.True(t, info.Available)
+	assert.Equal(t, "v0.2.0", info.Latest)
+	assert.Equal(t, "notes for v0.2.0", info.Notes)
+
+	// 固定版本允许降级
+	u, _ = newTestUpdater(t, stub, "v0.2.0", UpdateOptions{Version: "v0.2.0-beta.1"})
+	_, available, err := u.Check()
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	// nightly 标签无法比较版本：按二进制校验和判断
+	u, execPath := newTestUpdater(t, stub, "v0.2.0-5-gabc1234", UpdateOptions{Channel: ChannelNightly})
+	_, available, err = u.Check()
+	require.NoError(t, err)
+	assert.True(t, available)
+	require.NoError(t, os.WriteFile(execPath, []byte("binary nightly"), 0755))
+	_, available, err = u.Check()
+	require.NoError(t, err)
+	assert.False(t, available)
+}
+
+func TestCheckUsesCache(t *testing.T) {
+	stub := newReleaseStub(t)
+	stub.binaries["v0.2.0"] = []byte("binary v0.2.0")
+	stub.binaries["v0.3.0"] = []byte("binary v0.3.0")
+	stub.latest = "v0.2.0"
+
+	cachePath := filepath.Join(t.TempDir(), "cache", "update-check.json")
+	opts := UpdateOptions{CachePath: cachePath, CacheTTL: time.Hour}
+	u, _ := newTestUpdater(t, stub, "v0.1.0", opts)
+
+	latest, available, err := u.Check()
+	require.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, "v0.2.0", latest)
+	assert.Equal(t, 1, stub.apiRequests)
+
+	// TTL 内直接使用缓存
+	stub.latest = "v0.3.0"
+	latest, _, err = u.Check()
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", latest)
+	assert.Equal(t, 1, stub.apiRequests)
+
+	// 强制刷新
+	info, err := u.CheckInfo(true)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.3.0", info.Latest)
+	assert.Equal(t, 2, stub.apiRequests)
+
+	// 当前版本或来源变化后缓存失效
+	u, _ = newTestUpdater(t, stub, "v0.3.0", opts)
+	_, available, err = u.Check()
+	require.NoError(t, err)
+	assert.False(t, available)
+	assert.Equal(t, 3, stub.apiRequests)
+
+	opts.Version = "v0.2.0"
+	u, _ = newTestUpdater(t, stub, "v0.3.0", opts)
+	_, available, err = u.Check()
+	require.NoError(t, err)
+	assert.True(t, available)
+	assert.Equal(t, 4, stub.apiRequests)
+
+	// 过期
+	opts.CacheTTL = time.Nanosecond
+	u, _ = newTestUpdater(t, stub, "v0.3.0", opts)
+	_, _, err = u.Check()
+	require.NoError(t, err)
+	assert.Equal(t, 5, stub.apiRequests)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本（SemVer 2.0），前缀 v 可选，构建元数据（+xxx）不参与比较
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          []string // 如 beta.1 -> [beta 1]
+}
+
+// describeSuffix git describe 追加的 -N-gHASH[-dirty]：表示标签之后的本地构建，比较时视同该标签
+var describeSuffix = regexp.MustCompile(`^(.*?)-?\d+-g[0-9a-f]{4,40}(-dirty)?$`)
+
+// ParseVersion 解析 v1.2.3、1.2.3-beta.1、v0.1.0-beta.1-5-gabc1234 等版本；无法解析时返回 false
+func ParseVersion(s string) (Version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if m := describeSuffix.FindStringSubmatch(pre); m != nil {
+			pre, hasPre = m[1], m[1] != ""
+		}
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (len(p) > 1 && p[0] == '0') {
+			return Version{}, false
+		}
+		nums[i] = n
+	}
+
+	v := Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}
+	if hasPre {
+		v.Prerelease = strings.Split(pre, ".")
+		for _, id := range v.Prerelease {
+			if id == "" {
+				return Version{}, false
+			}
+		}
+	}
+	return v, true
+}
+
+// Compare 比较两个版本：a < b 返回 -1，相等返回 0，a > b 返回 1
+func (a Version) Compare(b Version) int {
+	for _, d := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if d[0] != d[1] {
+			return sign(d[0] - d[1])
+		}
+	}
+
+	// 有预发布标识的版本低于正式版
+	switch {
+	case len(a.Prerelease) == 0 && len(b.Prerelease) == 0:
+		return 0
+	case len(a.Prerelease) == 0:
+		return 1
+	case len(b.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.Prerelease) && i < len(b.Prerelease); i++ {
+		if c := comparePrereleaseID(a.Prerelease[i], b.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(a.Prerelease) - len(b.Prerelease))
+}
+
+// comparePrereleaseID 数字标识按数值比较且低于字母标识，字母标识按 ASCII 比较
+func comparePrereleaseID(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return sign(na - nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// CompareVersions 比较两个版本字符串；任一无法解析时 ok 为 false
+func CompareVersions(a, b string) (cmp int, ok bool) {
+	va, okA := ParseVersion(a)
+	vb, okB := ParseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	return va.Compare(vb), true
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{
+		"v0.1.0-alpha",
+		"v0.1.0-alpha.1",
+		"v0.1.0-alpha.beta",
+		"v0.1.0-beta",
+		"v0.1.0-beta.1",
+		"v0.1.0-beta.2",
+		"v0.1.0-beta.11",
+		"v0.1.0-rc.1",
+		"0.1.0",
+		"v0.1.1",
+		"v0.2.0",
+		"v1.0.0",
+	}
+	for i := range ordered {
+		for j := range ordered {
+			cmp, ok := CompareVersions(ordered[i], ordered[j])
+			assert.True(t, ok)
+			assert.Equal(t, sign(i-j), cmp, "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+
+	// 构建元数据与 git describe 后缀不参与比较
+	for _, pair := range [][2]string{
+		{"v0.1.0+build.5", "v0.1.0"},
+		{"v0.1.0-5-gabc1234", "v0.1.0"},
+		{"v0.1.0-5-gabc1234-dirty", "v0.1.0"},
+		{"v0.1.0-beta.1-3-gdeadbeef", "v0.1.0-beta.1"},
+	} {
+		cmp, ok := CompareVersions(pair[0], pair[1])
+		assert.True(t, ok, pair[0])
+		assert.Zero(t, cmp, pair[0])
+	}
+
+	for _, s := range []string{"dev", "nightly", "v1.2", "v1.02.0", "v1.2.3-", "v1.2.3-beta..1"} {
+		_, ok := ParseVersion(s)
+		assert.False(t, ok, s)
+	}
+}
@@ -0,0 +1,296 @@
+// Package history 记录修改系统的操作（操作者、目标文件及修改前后的校验和、结果），
+// 写入只追加的审计日志，与调试日志分开保存
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// 操作结果
+const (
+	ResultOK    = "ok"
+	ResultError = "error"
+)
+
+var (
+	// logPath 审计日志路径，为空时不记录（由 main 按配置设置）
+	logPath string
+	mu      sync.Mutex
+)
+
+// SetPath 设置审计日志路径并返回原路径；为空表示不记录
+func SetPath(path string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	old := logPath
+	logPath = path
+	return old
+}
+
+// Path 当前审计日志路径
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return logPath
+}
+
+// Actor 执行操作的管理员
+type Actor struct {
+	User      string `json:"user"`                 // 进程的有效用户
+	SudoUser  string `json:"sudo_user,omitempty"`  // sudo 之前的真实用户（SUDO_USER）
+	TTY       string `json:"tty,omitempty"`        // 登录终端
+	SSHSource string `json:"ssh_source,omitempty"` // SSH 来源地址（SSH_CONNECTION）
+}
+
+// String 显示用的操作者描述，如 alice (sudo root) via 203.0.113.5:51234
+func (a Actor) String() string {
+	s := a.User
+	if a.SudoUser != "" && a.SudoUser != a.User {
+		s = fmt.Sprintf("%s (sudo %s)", a.SudoUser, a.User)
+	}
+	if a.SSHSource != "" {
+		s += " via " + a.SSHSource
+	}
+	return s
+}
+
+// FileChange 目标文件修改前后的 SHA-256（文件不存在时为空）
+type FileChange struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Changed 文件内容是否发生变化
+func (f FileChange) Changed() bool {
+	return f.Before != f.After
+}
+
+// Record 一条审计记录
+type Record struct {
+	Time      time.Time    `json:"time"`
+	Actor     Actor        `json:"actor"`
+	Module    string       `json:"module"`
+	Operation string       `json:"operation"`
+	Detail    string       `json:"detail,omitempty"`
+	Root      string       `json:"root,omitempty"` // 设置 --root 时的目标根目录
+	Files     []FileChange `json:"files,omitempty"`
+	Result    string       `json:"result"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// Op 进行中的操作；nil 表示不记录
+type Op struct {
+	record Record
+}
+
+// Begin 开始记录一次修改操作并计算目标文件当前的校验和；dry-run 或未设置审计日志时返回 nil
+func Begin(dryRun bool, module, operation, detail string, files ...string) *Op {
+	if dryRun || Path() == "" {
+		return nil
+	}
+	op := &Op{record: Record{
+		Actor:     CurrentActor(),
+		Module:    module,
+		Operation: operation,
+		Detail:    detail,
+	}}
+	if system.HasRoot() {
+		op.record.Root = system.Root()
+	}
+	for _, path := range files {
+		op.record.Files = append(op.record.Files, FileChange{Path: path, Before: checksum(path)})
+	}
+	return op
+}
+
+// End 计算修改后的校验和并追加记录；写入审计日志失败只记录警告，不影响操作结果
+func (op *Op) End(err error, logger *internal.Logger) {
+	if op == nil {
+		return
+	}
+	if werr := op.end(err); werr != nil && logger != nil {
+		logger.Warn("Failed to write audit record: %v", werr)
+	}
+}
+
+func (op *Op) end(err error) error {
+	r := op.record
+	r.Time = time.Now()
+	r.Result = ResultOK
+	if err != nil {
+		r.Result = ResultError
+		r.Error = err.Error()
+	}
+	for i := range r.Files {
+		r.Files[i].After = checksum(r.Files[i].Path)
+	}
+	return appendRecord(r)
+}
+
+func appendRecord(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if logPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(logPath), err)
+	}
+	// 只追加；不截断、不改写已有记录
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", logPath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log %s: %w", logPath, err)
+	}
+	return nil
+}
+
+// checksum 文件内容的 SHA-256；文件不存在或无法读取时返回空
+func checksum(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Load 读取全部审计记录（按写入顺序）；无法解析的行被跳过
+func Load() ([]Record, error) {
+	path := Path()
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err == nil {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return records, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// Matches 记录是否包含查询词（模块、操作、详情、操作者、文件、结果，不区分大小写）
+func (r Record) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	fields := []string{r.Module, r.Operation, r.Detail, r.Actor.String(), r.Actor.TTY, r.Result, r.Error}
+	for _, f := range r.Files {
+		fields = append(fields, f.Path)
+	}
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentActor 识别当前操作者：有效用户、SUDO_USER、登录终端与 SSH 来源
+func CurrentActor() Actor {
+	a := Actor{User: strconv.Itoa(os.Geteuid())}
+	if u, err := user.Current(); err == nil {
+		a.User = u.Username
+	}
+	a.SudoUser = os.Getenv("SUDO_USER")
+	if tty, err := os.Readlink("/proc/self/fd/0"); err == nil && strings.HasPrefix(tty, "/dev/") {
+		a.TTY = tty
+	}
+	a.SSHSource = sshSource(lookupSessionEnv("SSH_CONNECTION"))
+	return a
+}
+
+// sshSource 从 SSH_CONNECTION（客户端 IP、端口、服务端 IP、端口）取出客户端地址
+func sshSource(conn string) string {
+	fields := strings.Fields(conn)
+	if len(fields) < 2 {
+		return ""
+	}
+	return net.JoinHostPort(fields[0], fields[1])
+}
+
+// lookupSessionEnv 读取环境变量；sudo 默认会清除 SSH_*，此时沿父进程向上查找登录会话中的值
+func lookupSessionEnv(key string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	pid := os.Getppid()
+	for i := 0; i < 8 && pid > 1; i++ {
+		if v := procEnv(pid, key); v != "" {
+			return v
+		}
+		pid = parentPID(pid)
+	}
+	return ""
+}
+
+// procEnv 读取 /proc/PID/environ 中的变量（需要相应权限）
+func procEnv(pid int, key string) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+	if err != nil {
+		return ""
+	}
+	for _, kv := range strings.Split(string(data), "\x00") {
+		if v, ok := strings.CutPrefix(kv, key+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// parentPID 读取 /proc/PID/stat 中的父进程号；失败返回 0
+func parentPID(pid int) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0
+	}
+	// 进程名可能含空格，从最后一个 ')' 之后解析：state ppid ...
+	s := string(data)
+	fields := strings.Fields(s[strings.LastIndex(s, ")")+1:])
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
@@ -0,0 +1,75 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginEnd(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "log", "audit.log")
+	old := SetPath(logPath)
+	t.Cleanup(func() { SetPath(old) })
+	t.Setenv("SUDO_USER", "alice")
+	t.Setenv("SSH_CONNECTION", "203.0.113.5 51234 10.0.0.2 22")
+
+	target := filepath.Join(dir, "hosts")
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+
+	// dry-run 不记录
+	assert.Nil(t, Begin(true, "hostname", "save_hosts", "", target))
+
+	op := Begin(false, "hostname", "save_hosts", "2 entries", target)
+	require.NoError(t, os.WriteFile(target, []byte("127.0.0.1 localhost\n"), 0644))
+	op.End(nil, logger)
+
+	op = Begin(false, "ssh", "reload_sshd", "")
+	op.End(errors.New("failed to reload ssh service"), logger)
+
+	// 无法解析的行被跳过
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, _ = f.WriteString("not json\n")
+	require.NoError(t, f.Close())
+
+	info, err := os.Stat(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	records, err := Load()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	r := records[0]
+	assert.Equal(t, "alice", r.Actor.SudoUser)
+	assert.Equal(t, "203.0.113.5:51234", r.Actor.SSHSource)
+	assert.Contains(t, r.Actor.String(), "alice (sudo ")
+	assert.Equal(t, ResultOK, r.Result)
+	require.Len(t, r.Files, 1)
+	assert.Empty(t, r.Files[0].Before)
+	assert.Len(t, r.Files[0].After, 64)
+	assert.True(t, r.Files[0].Changed())
+
+	assert.Equal(t, ResultError, records[1].Result)
+	assert.Equal(t, "failed to reload ssh service", records[1].Error)
+
+	assert.True(t, records[0].Matches("HOSTS"))
+	assert.True(t, records[1].Matches("error"))
+	assert.False(t, records[1].Matches("hostname"))
+}
+
+func TestDisabled(t *testing.T) {
+	old := SetPath("")
+	t.Cleanup(func() { SetPath(old) })
+
+	assert.Nil(t, Begin(false, "ssh", "reload_sshd", ""))
+	records, err := Load()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -32,6 +32,27 @@ var enUS = map[string]string{
 	"hostname_wizard_confirm_apply":    "Confirm apply above actions?",
 	"hostname_wizard_applying":         "Applying...",
 	"hostname_wizard_done":             "Done. Press Enter to go back",
+	"hostname_wizard_pretty":           "Pretty hostname (optional, e.g. \"Web Server 01\"): ",
+	"hostname_wizard_kinds":            "Hostname type:",
+	"hostname_wizard_kind_static":      "Static (/etc/hostname, survives reboot)",
+	"hostname_wizard_kind_transient":   "Transient (running kernel, lost on reboot)",
+	"hostname_wizard_kind_required":    "Select at least one of static or transient",
+	"hostname_wizard_refs":             "Configs referencing the old hostname:",
+	"hostname_wizard_refs_none":        "No references found",
+	"hostname_wizard_options_keys":     "↑/↓ move  Space toggle  Enter continue  Esc back",
+	"hostname_wizard_action_static":    "Set static hostname only (applies after reboot): %s",
+	"hostname_wizard_action_transient": "Set transient hostname only (lost on reboot): %s",
+	"hostname_wizard_action_pretty":    "Set pretty hostname: %s",
+	"hostname_wizard_action_ref":       "Update old hostname in %s",
+	"hostname_impact_certificate":      "Self-signed certificate still names the old host (%s); regenerate it",
+	"hostname_impact_docker_swarm":     "Docker Swarm node %s still reports the old hostname until dockerd restarts",
+	"hostname_wizard_suggestions":      "Suggestions:",
+	"hostname_wizard_suggest_keys":     "↑/↓ select  Tab fill in",
+	"hostname_wizard_template_failed":  "Naming template skipped: %v",
+	"hostname_source_ptr":              "reverse DNS",
+	"hostname_source_metadata":         "cloud metadata",
+	"hostname_source_template":         "template",
+	"hostname_wizard_forward_warn":     "Warning: FQDN does not resolve back to this host: %v",
 	"cloudinit_not_found":              "cloud-init not found; skipped",
 	"cloudinit_preserve_confirm":       "Write preserve_hostname: true (prevent cloud-init from overriding hostname)?",
 
@@ -70,12 +91,356 @@ var enUS = map[string]string{
 	"ssh_wizard_disable_pwd_warning": "Warning: this will modify /etc/ssh/sshd_config and reload the SSH service. Ensure the target user has working SSH keys, or you may lose access.",
 	"ssh_wizard_no_keys":             "No installed SSH keys detected. Install SSH keys before disabling password login.",
 
+	// Automatic Security Updates
+	"menu_autoupgrade":           "Automatic Security Updates",
+	"autoupgrade_title":          "Automatic Security Updates",
+	"autoupgrade_status":         "Current status:",
+	"autoupgrade_backend":        "Backend",
+	"autoupgrade_installed":      "Installed",
+	"autoupgrade_enabled":        "Automatic updates enabled",
+	"autoupgrade_active":         "Service/timer active",
+	"autoupgrade_auto_reboot":    "Auto reboot",
+	"autoupgrade_notify":         "Email notification",
+	"autoupgrade_unsupported":    "Automatic security updates are only supported on Debian and RedHat family systems",
+	"autoupgrade_enable_prompt":  "Press Enter to configure and enable automatic security updates",
+	"autoupgrade_reboot_prompt":  "Reboot automatically when an update requires it?",
+	"autoupgrade_reboot_time":    "Reboot time (HH:MM, empty = immediately): ",
+	"autoupgrade_invalid_time":   "Invalid time format, expected HH:MM",
+	"autoupgrade_email":          "Notification email (optional): ",
+	"autoupgrade_invalid_email":  "Invalid email address, expected a plain address like ops@example.com",
+	"autoupgrade_actions":        "Actions to apply:",
+	"autoupgrade_action_install": "Install package: %s",
+	"autoupgrade_action_config":  "Write security-only update configuration",
+	"autoupgrade_action_service": "Enable and start the update service/timer",
+	"autoupgrade_confirm_apply":  "Confirm apply above actions?",
+	"autoupgrade_applying":       "Configuring automatic updates...",
+	"autoupgrade_success":        "Automatic security updates enabled (%s)",
+	"autoupgrade_done":           "Done. Press Enter to go back",
+
+	// Packages
+	"menu_packages":             "Package Management",
+	"packages_title":            "Package Management",
+	"packages_manager":          "Package manager: %s",
+	"packages_check":            "Check for updates",
+	"packages_tools":            "Install common tools",
+	"packages_upgrade":          "Preview and upgrade all",
+	"packages_up_to_date":       "All packages are up to date",
+	"packages_pending":          "Pending upgrades: %d (security: %d)",
+	"packages_security_list":    "Security updates:",
+	"packages_all_list":         "All pending upgrades:",
+	"packages_more":             "... and %d more",
+	"packages_upgrade_prompt":   "Press Enter to upgrade all packages",
+	"packages_tools_prompt":     "Select tools to install:",
+	"packages_search":           "Search and install packages",
+	"packages_search_prompt":    "Search the package repositories (one keyword, e.g. nginx):",
+	"packages_search_results":   "Results for %q: %d",
+	"packages_search_truncated": "Showing the first %d; refine the keyword to narrow down",
+	"packages_search_none":      "No packages match %q",
+	"packages_tools_hint":       "Space to toggle, Enter to install",
+	"packages_installed_mark":   "✓",
+	"packages_none_selected":    "No packages selected",
+	"packages_actions":          "Actions to apply:",
+	"packages_action_install":   "Install: %s",
+	"packages_action_upgrade":   "Upgrade %d packages",
+	"packages_confirm_apply":    "Confirm apply above actions?",
+	"packages_running":          "Running, output below (↑/↓ to scroll):",
+	"packages_done":             "Done. Press Enter to go back",
+
+	// Fail2ban
+	"menu_fail2ban":           "Fail2ban Brute-force Protection",
+	"fail2ban_title":          "Fail2ban (sshd jail)",
+	"fail2ban_status":         "Current status:",
+	"fail2ban_installed":      "Installed",
+	"fail2ban_active":         "Service active",
+	"fail2ban_configured":     "server-toolkit jail",
+	"fail2ban_jail":           "sshd port (backend)",
+	"fail2ban_counters":       "Failed now: %d, banned now: %d, total banned: %d",
+	"fail2ban_banned":         "Banned IPs:",
+	"fail2ban_no_banned":      "(none)",
+	"fail2ban_keys":           "e: enable/re-apply  Enter: unban selected  r: refresh  Esc: back",
+	"fail2ban_actions":        "Actions to apply:",
+	"fail2ban_action_install": "Install package: fail2ban",
+	"fail2ban_action_jail":    "Write jail.d/server-toolkit.local (port %s, backend %s)",
+	"fail2ban_action_limits":  "maxretry %d, findtime %s, bantime %s",
+	"fail2ban_action_service": "Enable and start fail2ban, reload jails",
+	"fail2ban_confirm_apply":  "Confirm apply above actions?",
+	"fail2ban_confirm_unban":  "Unban %s from the sshd jail?",
+	"fail2ban_applying":       "Applying...",
+	"fail2ban_enabled":        "Fail2ban sshd jail enabled",
+	"fail2ban_unbanned":       "Unbanned %s",
+	"fail2ban_done":           "Done. Press Enter to go back",
+
+	// Dashboard
+	"menu_dashboard":               "System Information",
+	"dashboard_title":              "System Information",
+	"dashboard_system":             "System:",
+	"dashboard_hostname":           "Hostname",
+	"dashboard_os":                 "OS",
+	"dashboard_kernel":             "Kernel",
+	"dashboard_virt":               "Virt",
+	"dashboard_uptime":             "Uptime",
+	"dashboard_resources":          "Resources:",
+	"dashboard_cpu":                "CPU",
+	"dashboard_load":               "Load",
+	"dashboard_memory":             "Memory",
+	"dashboard_swap":               "Swap",
+	"dashboard_disks":              "Disks:",
+	"dashboard_network":            "Network:",
+	"dashboard_up":                 "up",
+	"dashboard_down":               "down",
+	"dashboard_private_ip":         "Private IP",
+	"dashboard_public_ip":          "Public IP",
+	"dashboard_public_ip_failed":   "unavailable",
+	"dashboard_public_ip_disabled": "not queried (enable public_ip_lookup)",
+	"dashboard_public_ip_skipped":  "not queried in dry-run / --root mode",
+	"dashboard_keys":               "Auto refresh  r: refresh now  Esc: back",
+
+	// Services
+	"menu_services":               "Service Management",
+	"services_title":              "Services",
+	"services_filter":             "Filter:",
+	"services_filter_placeholder": "name, description or state",
+	"services_empty":              "No matching services",
+	"services_keys":               "/: filter  Enter: details  s/x/r: start/stop/restart  e/d: enable/disable  l: reload  Esc: back",
+	"services_detail_keys":        "↑/↓ to scroll, Esc to go back",
+	"services_confirm":            "Run %s on %s?",
+	"services_running":            "Running %s on %s...",
+	"services_action_done":        "%s %s: done",
+	"services_journal":            "Recent journal:",
+	"services_journal_empty":      "(no journal entries)",
+
+	// Cloud-init user-data export
+	"menu_cloudinit_export":         "Export cloud-init User-data",
+	"cloudinit_export_title":        "Export cloud-init User-data",
+	"cloudinit_export_users":        "Users",
+	"cloudinit_export_users_value":  "%d user(s), %d key(s)",
+	"cloudinit_export_default_user": "Keep distro default user",
+	"cloudinit_export_manage_hosts": "manage_etc_hosts",
+	"cloudinit_export_pwauth":       "Password login (ssh_pwauth)",
+	"cloudinit_export_keys":         "↑/↓ scroll  d/m/p: toggle options  s: save  Esc: back",
+	"cloudinit_export_path":         "Save user-data to:",
+	"cloudinit_export_path_hint":    "Enter to save, Esc to cancel (existing file is backed up)",
+	"cloudinit_export_saving":       "Saving...",
+	"cloudinit_export_saved":        "User-data written to %s",
+	"cloudinit_export_done":         "Done. Press Enter to go back",
+
+	// Cloud-init status
+	"menu_cloudinit":               "Cloud-init",
+	"menu_cloudinit_status":        "Status and Configuration",
+	"cloudinit_title":              "Cloud-init",
+	"cloudinit_not_installed":      "cloud-init is not installed",
+	"cloudinit_status":             "Status",
+	"cloudinit_datasource":         "Datasource",
+	"cloudinit_disabled":           "Disabled (cloud-init.disabled)",
+	"cloudinit_config_files":       "Config files",
+	"cloudinit_modules":            "Modules on boot:",
+	"cloudinit_no_modules":         "(no relevant modules configured)",
+	"cloudinit_warnings":           "Cloud-init may undo toolkit changes:",
+	"cloudinit_warn_hostname":      "%s resets the hostname (%s)",
+	"cloudinit_warn_etc_hosts":     "%s rewrites /etc/hosts from templates (%s)",
+	"cloudinit_warn_ssh_keys":      "%s regenerates SSH host keys (%s)",
+	"cloudinit_warn_ssh_pwauth":    "%s re-enables SSH password login (%s)",
+	"cloudinit_warn_next_instance": " — only on the next instance-id change / re-image",
+	"cloudinit_merged_config":      "Merged cloud.cfg + cloud.cfg.d/*.cfg:",
+	"cloudinit_config_keys":        "↑/↓ to scroll, Esc to go back",
+	"cloudinit_keys_disable":       "c: merged config  x: disable cloud-init  r: refresh  Esc: back",
+	"cloudinit_keys_enable":        "c: merged config  x: re-enable cloud-init  r: refresh  Esc: back",
+	"cloudinit_confirm_disable":    "Create /etc/cloud/cloud-init.disabled? cloud-init will not run on future boots.",
+	"cloudinit_confirm_enable":     "Remove /etc/cloud/cloud-init.disabled and re-enable cloud-init?",
+	"cloudinit_applying":           "Applying...",
+	"cloudinit_disabled_done":      "cloud-init disabled",
+	"cloudinit_enabled_done":       "cloud-init re-enabled",
+	"cloudinit_done":               "Done. Press Enter to go back",
+
+	// Hosts editor
+	"menu_hosts":                "/etc/hosts Editor",
+	"hosts_title":               "/etc/hosts",
+	"hosts_ip":                  "IP address",
+	"hosts_names":               "Hostnames (canonical first, space separated)",
+	"hosts_col_names":           "Hostnames",
+	"hosts_comment":             "Comment",
+	"hosts_comment_placeholder": "optional",
+	"hosts_empty":               "(no entries)",
+	"hosts_add":                 "Add entry",
+	"hosts_edit":                "Edit entry",
+	"hosts_edit_keys":           "Tab/↑/↓: switch field  Enter: apply  Esc: cancel",
+	"hosts_keys":                "a: add  Enter/e: edit  d: delete  s: save  r: reload  Esc: back",
+	"hosts_problems":            "Problems:",
+	"hosts_problem_duplicate":   "%s is listed more than once for %s",
+	"hosts_problem_conflict":    "%s resolves to different addresses: %s",
+	"hosts_problem_invalid":     "invalid entry: %s",
+	"hosts_unsaved":             "Unsaved changes (press s to save)",
+	"hosts_confirm_delete":      "Delete %s %s?",
+	"hosts_system_entry":        "This is a default loopback/multicast entry; removing it may break local name resolution.",
+	"hosts_save_with_problems":  "%d problem(s) detected",
+	"hosts_confirm_save":        "Write changes to /etc/hosts? (a backup is created first)",
+	"hosts_confirm_discard":     "Discard unsaved changes?",
+	"hosts_saving":              "Saving...",
+	"hosts_saved":               "/etc/hosts saved",
+
+	// Network
+	"menu_network":            "Network Configuration",
+	"network_title":           "Network",
+	"network_stack":           "Configuration stack: %s",
+	"network_stack_unknown":   "not detected (read-only)",
+	"network_dns_current":     "DNS: %s",
+	"network_interfaces":      "Interfaces:",
+	"network_no_interfaces":   "(no interfaces)",
+	"network_up":              "up",
+	"network_down":            "down",
+	"network_routes":          "Routes via %s:",
+	"network_keys":            "↑/↓: select  Enter/e: static config  r: refresh  Esc: back",
+	"network_keys_readonly":   "↑/↓: select  r: refresh  Esc: back",
+	"network_static_for":      "Static configuration for %s",
+	"network_addresses":       "Addresses (CIDR, comma separated)",
+	"network_gateway4":        "IPv4 gateway",
+	"network_gateway6":        "IPv6 gateway",
+	"network_dns":             "DNS servers",
+	"network_search":          "Search domains",
+	"network_form_keys":       "Tab/↑/↓: switch field  Enter: preview  Esc: cancel",
+	"network_preview":         "Changes (%s):",
+	"network_plan_file":       "--- %s",
+	"network_plan_commands":   "Commands:",
+	"network_guard_note":      "The change is reverted automatically unless confirmed within %d seconds.",
+	"network_confirm_apply":   "Apply this configuration?",
+	"network_applying":        "Applying...",
+	"network_applied":         "Network configuration applied",
+	"network_guard_countdown": "Still connected? Reverting in %d s unless you confirm.",
+	"network_guard_keys":      "y: keep new configuration  n: revert now",
+	"network_kept":            "New network configuration kept",
+	"network_reverted":        "Network configuration reverted",
+
+	// DNS resolver
+	"menu_resolver":                    "DNS Resolver",
+	"resolver_title":                   "DNS Resolver",
+	"resolver_mode":                    "/etc/resolv.conf managed by: %s",
+	"resolver_symlink":                 "symlink -> %s",
+	"resolver_nameservers":             "Effective nameservers:",
+	"resolver_search":                  "Search domains: %s",
+	"resolver_dot":                     "DNS-over-TLS: %s",
+	"resolver_dot_default":             "default",
+	"resolver_dot_hint":                "←/→: change (yes requires a server name, e.g. 1.1.1.1#cloudflare-dns.com)",
+	"resolver_target_systemd-resolved": "Changes are written to /etc/systemd/resolved.conf.d/90-server-toolkit.conf",
+	"resolver_target_networkmanager":   "Changes are written as global DNS to /etc/NetworkManager/conf.d/90-server-toolkit.conf",
+	"resolver_target_resolvconf":       "Changes are written to /etc/resolvconf/resolv.conf.d/head",
+	"resolver_target_static":           "Changes are written directly to /etc/resolv.conf",
+	"resolver_keys":                    "Enter/e: edit  r: refresh  Esc: back",
+	"resolver_nameservers_input":       "Nameservers (comma separated)",
+	"resolver_form_keys":               "Tab/↑/↓: switch field  Enter: preview  Esc: cancel",
+	"resolver_symlink_warn":            "/etc/resolv.conf is a symlink to %s. Writing a static file replaces the link; whatever maintains the target will no longer update it.",
+	"resolver_symlink_confirm":         "Replace the symlink with a static file?",
+	"resolver_preview":                 "Changes:",
+	"resolver_confirm_apply":           "Apply these DNS settings? (backups are created first)",
+	"resolver_applied":                 "DNS settings applied",
+
+	// Security audit
+	"menu_audit":                    "Security Audit",
+	"audit_title":                   "Security Audit",
+	"audit_running":                 "Running read-only checks...",
+	"audit_score":                   "Score: %d/100 (%s)",
+	"audit_checks":                  "%d checks, %d findings, %d skipped",
+	"audit_no_findings":             "No issues found",
+	"audit_fix":                     "Fix: %s (press Enter)",
+	"audit_fix_manual":              "No toolkit action; fix manually",
+	"audit_skipped":                 "Skipped %s: %s",
+	"audit_exported":                "Report written to %s",
+	"audit_keys":                    "↑/↓: select  Enter: fix  t/m/j: export text/Markdown/JSON  r: rerun  Esc: back",
+	"audit_ssh_root_login":          "SSH allows root login with a password",
+	"audit_ssh_password_auth":       "SSH password authentication is enabled",
+	"audit_ssh_empty_passwords":     "SSH permits empty passwords",
+	"audit_ssh_weak_algorithms":     "SSH offers weak algorithms",
+	"audit_keys_invalid":            "Unparseable authorized_keys entry",
+	"audit_keys_dsa":                "DSA key in authorized_keys",
+	"audit_keys_weak_rsa":           "RSA key shorter than 2048 bits",
+	"audit_etc_world_writable":      "World-writable files in /etc",
+	"audit_accounts_uid0":           "Non-root account with UID 0",
+	"audit_accounts_empty_password": "Accounts with an empty password",
+	"audit_firewall_disabled":       "No active firewall",
+	"audit_updates_security":        "Pending security updates",
+	"audit_updates_auto_disabled":   "Automatic security updates are disabled",
+	"audit_time_unsynced":           "Clock not synchronized with NTP",
+
+	// 配置漂移
+	"menu_drift":                 "Config Drift",
+	"drift_title":                "Config Drift",
+	"drift_state":                "State file: %s",
+	"drift_updated":              "Last applied: %s",
+	"drift_no_state":             "Nothing recorded yet. Values are recorded when the toolkit changes the hostname, /etc/hosts, sshd options or authorized_keys.",
+	"drift_none":                 "✓ Live configuration matches the last applied values",
+	"drift_found":                "%d item(s) differ from the last applied values",
+	"drift_want":                 "Applied: %s",
+	"drift_have":                 "Live:    %s",
+	"drift_missing":              "(missing)",
+	"drift_kind_hostname":        "Hostname",
+	"drift_kind_hosts":           "/etc/hosts",
+	"drift_kind_sshd":            "sshd_config",
+	"drift_kind_authorized_keys": "authorized_keys",
+	"drift_keys":                 "↑/↓ select  Enter re-apply all  r refresh  Esc back",
+	"drift_confirm":              "Re-apply %d item(s)? Files are backed up before writing.",
+	"drift_applying":             "Re-applying...",
+	"drift_applied":              "✓ Recorded values re-applied",
+
+	// 操作历史
+	"menu_history":               "History",
+	"history_title":              "Change History",
+	"history_path":               "Audit log: %s",
+	"history_empty":              "No recorded changes",
+	"history_errors_only":        "Showing failed operations only",
+	"history_filter_placeholder": "module, operation, user, file...",
+	"history_time":               "Time:  %s",
+	"history_actor":              "Actor: %s",
+	"history_tty":                "TTY:   %s",
+	"history_keys":               "↑/↓ select  / filter  e failed only  r refresh  Esc back",
+
+	// 日志查看
+	"menu_logs":               "Logs",
+	"logs_title":              "Toolkit Log",
+	"logs_operation_title":    "Operation Log",
+	"logs_path":               "Log file: %s",
+	"logs_level":              "Level: %s and above",
+	"logs_search":             "Search:",
+	"logs_search_placeholder": "text to search",
+	"logs_empty":              "(no matching log entries)",
+	"logs_no_file":            "Logging to stdout; set log_path to view the log here",
+	"logs_keys":               "↑/↓ PgUp/PgDn scroll  ←/→ pan  v level  / search  Esc back",
+	"logs_view_hint":          "l: view the log of this operation",
+
+	// 自更新
+	"menu_update":               "Self-update",
+	"update_title":              "Self-update",
+	"update_checking":           "Checking for updates...",
+	"update_current":            "Current version: %s",
+	"update_channel":            "Channel: %s",
+	"update_pinned":             "Pinned version: %s",
+	"update_base_url":           "Mirror: %s",
+	"update_up_to_date":         "Already up to date (%s)",
+	"update_has_backup":         "A backup of the previous binary (.bak) is available for rollback",
+	"update_keys":               "Enter update  b rollback  r check again  Esc back",
+	"update_confirm":            "Download, verify and replace %s with %s?",
+	"update_confirm_rollback":   "Restore the binary saved before the last update?",
+	"update_applying":           "Updating...",
+	"update_installed":          "Installed %s; the previous binary was saved as .bak",
+	"update_rolled_back":        "Restored the previous binary",
+	"update_restart":            "Restart server-toolkit to use the new binary",
+	"update_checked_at":         "Last checked: %s",
+	"update_notes_title":        "Release notes for %s",
+	"update_notes_empty":        "This release has no release notes",
+	"update_notes_keys":         "↑/↓ scroll  Enter continue  Esc back",
+	"app_nonroot":               "Running without root: read-only screens work, changes need sudo",
+	"menu_root_required":        "Requires root: run sudo server-toolkit, or enable dry-run in Settings to preview",
+	"root_required_title":       "Root required",
+	"root_required_desc":        "This action changes the system and requires root privileges.",
+	"root_required_dryrun_hint": "Tip: enable dry-run in Settings to preview changes without root",
+	"root_required_keys":        "s restart with sudo  Esc back",
+	"root_sudo_missing":         "sudo not found; run server-toolkit as root",
+
 	// Settings
 	"settings_title":       "Settings",
 	"settings_language":    "Language",
 	"settings_dryrun":      "Dry-run Mode",
 	"settings_loglevel":    "Log Level",
 	"settings_autoupdate":  "Auto Update",
+	"settings_publicip":    "Look up Public IP",
 	"settings_lang_curr":   "Current: %s",
 	"settings_dryrun_on":   "Enable to preview operations without executing",
 	"settings_dryrun_off":  "Disable to actually execute operations",
@@ -83,6 +448,7 @@ var enUS = map[string]string{
 	"settings_save_failed": "Failed to save settings: %v",
 	"update_available":     "Update available: %s",
 	"update_check_failed":  "Update check failed",
+	"root_target":          "Target root: %s (offline)",
 
 	// Common
 	"confirm":      "Confirm this operation?",
@@ -32,6 +32,27 @@ var zhCN = map[string]string{
 	"hostname_wizard_confirm_apply":    "确认执行以上操作？",
 	"hostname_wizard_applying":         "正在执行...",
 	"hostname_wizard_done":             "完成。按 Enter 返回",
+	"hostname_wizard_pretty":           "友好名称（可选，如 \"Web Server 01\"）：",
+	"hostname_wizard_kinds":            "主机名类型：",
+	"hostname_wizard_kind_static":      "静态（/etc/hostname，重启后保留）",
+	"hostname_wizard_kind_transient":   "临时（当前内核，重启后丢失）",
+	"hostname_wizard_kind_required":    "静态与临时至少选择一项",
+	"hostname_wizard_refs":             "引用旧主机名的配置：",
+	"hostname_wizard_refs_none":        "未发现引用",
+	"hostname_wizard_options_keys":     "↑/↓ 移动  空格 勾选  Enter 继续  Esc 返回",
+	"hostname_wizard_action_static":    "仅设置静态主机名（重启后生效）：%s",
+	"hostname_wizard_action_transient": "仅设置临时主机名（重启后丢失）：%s",
+	"hostname_wizard_action_pretty":    "设置友好名称：%s",
+	"hostname_wizard_action_ref":       "更新 %s 中的旧主机名",
+	"hostname_impact_certificate":      "自签名证书仍使用旧主机名（%s），需重新生成",
+	"hostname_impact_docker_swarm":     "Docker Swarm 节点 %s 在 dockerd 重启前仍上报旧主机名",
+	"hostname_wizard_suggestions":      "候选名称：",
+	"hostname_wizard_suggest_keys":     "↑/↓ 选择  Tab 填入",
+	"hostname_wizard_template_failed":  "已跳过命名模板：%v",
+	"hostname_source_ptr":              "反向解析",
+	"hostname_source_metadata":         "云元数据",
+	"hostname_source_template":         "命名模板",
+	"hostname_wizard_forward_warn":     "警告：FQDN 未解析回本机地址：%v",
 	"cloudinit_not_found":              "未检测到 cloud-init，已跳过",
 	"cloudinit_preserve_confirm":       "写入 preserve_hostname: true（防止 cloud-init 覆盖主机名）？",
 
@@ -70,12 +91,356 @@ var zhCN = map[string]string{
 	"ssh_wizard_disable_pwd_warning": "警告：此操作会修改 /etc/ssh/sshd_config 并重载 SSH 服务。请确保目标用户已安装可用的公钥，否则可能导致无法登录。",
 	"ssh_wizard_no_keys":             "未检测到已安装的公钥，请先安装 SSH 公钥后再禁用密码登录",
 
+	// 自动安全更新
+	"menu_autoupgrade":           "自动安全更新",
+	"autoupgrade_title":          "自动安全更新",
+	"autoupgrade_status":         "当前状态：",
+	"autoupgrade_backend":        "方案",
+	"autoupgrade_installed":      "已安装",
+	"autoupgrade_enabled":        "已启用自动更新",
+	"autoupgrade_active":         "服务/定时器运行中",
+	"autoupgrade_auto_reboot":    "自动重启",
+	"autoupgrade_notify":         "邮件通知",
+	"autoupgrade_unsupported":    "自动安全更新仅支持 Debian 与 RedHat 系发行版",
+	"autoupgrade_enable_prompt":  "按 Enter 配置并启用自动安全更新",
+	"autoupgrade_reboot_prompt":  "更新需要时是否自动重启？",
+	"autoupgrade_reboot_time":    "重启时间（HH:MM，留空表示立即）: ",
+	"autoupgrade_invalid_time":   "时间格式无效，应为 HH:MM",
+	"autoupgrade_email":          "通知邮箱（可选）: ",
+	"autoupgrade_invalid_email":  "邮箱地址无效，应为 ops@example.com 这样的单个地址",
+	"autoupgrade_actions":        "将执行：",
+	"autoupgrade_action_install": "安装软件包：%s",
+	"autoupgrade_action_config":  "写入仅安全更新配置",
+	"autoupgrade_action_service": "启用并启动更新服务/定时器",
+	"autoupgrade_confirm_apply":  "确认执行以上操作？",
+	"autoupgrade_applying":       "正在配置自动更新...",
+	"autoupgrade_success":        "已启用自动安全更新（%s）",
+	"autoupgrade_done":           "完成。按 Enter 返回",
+
+	// 软件包
+	"menu_packages":             "软件包管理",
+	"packages_title":            "软件包管理",
+	"packages_manager":          "包管理器: %s",
+	"packages_check":            "检查更新",
+	"packages_tools":            "安装常用工具",
+	"packages_upgrade":          "预览并升级全部",
+	"packages_up_to_date":       "所有软件包均为最新",
+	"packages_pending":          "待升级: %d 个（安全更新: %d 个）",
+	"packages_security_list":    "安全更新：",
+	"packages_all_list":         "全部待升级：",
+	"packages_more":             "... 以及另外 %d 个",
+	"packages_upgrade_prompt":   "按 Enter 升级全部软件包",
+	"packages_search":           "搜索并安装软件包",
+	"packages_search_prompt":    "在软件源中搜索（单个关键字，如 nginx）：",
+	"packages_search_results":   "%q 的搜索结果：%d 个",
+	"packages_search_truncated": "仅显示前 %d 个，可换用更具体的关键字",
+	"packages_search_none":      "没有匹配 %q 的软件包",
+	"packages_tools_prompt":     "选择要安装的工具：",
+	"packages_tools_hint":       "空格勾选，Enter 安装",
+	"packages_installed_mark":   "✓",
+	"packages_none_selected":    "未选择任何软件包",
+	"packages_actions":          "将执行：",
+	"packages_action_install":   "安装：%s",
+	"packages_action_upgrade":   "升级 %d 个软件包",
+	"packages_confirm_apply":    "确认执行以上操作？",
+	"packages_running":          "执行中，输出如下（↑/↓ 滚动）：",
+	"packages_done":             "完成。按 Enter 返回",
+
+	// Fail2ban
+	"menu_fail2ban":           "Fail2ban 防暴力破解",
+	"fail2ban_title":          "Fail2ban（sshd jail）",
+	"fail2ban_status":         "当前状态：",
+	"fail2ban_installed":      "已安装",
+	"fail2ban_active":         "服务运行中",
+	"fail2ban_configured":     "server-toolkit jail",
+	"fail2ban_jail":           "sshd 端口（后端）",
+	"fail2ban_counters":       "当前失败：%d，当前封禁：%d，累计封禁：%d",
+	"fail2ban_banned":         "已封禁 IP：",
+	"fail2ban_no_banned":      "（无）",
+	"fail2ban_keys":           "e：启用/重新应用  Enter：解封所选  r：刷新  Esc：返回",
+	"fail2ban_actions":        "将执行以下操作：",
+	"fail2ban_action_install": "安装软件包：fail2ban",
+	"fail2ban_action_jail":    "写入 jail.d/server-toolkit.local（端口 %s，后端 %s）",
+	"fail2ban_action_limits":  "maxretry %d，findtime %s，bantime %s",
+	"fail2ban_action_service": "启用并启动 fail2ban，重载 jail",
+	"fail2ban_confirm_apply":  "确认执行以上操作？",
+	"fail2ban_confirm_unban":  "从 sshd jail 解封 %s？",
+	"fail2ban_applying":       "正在应用...",
+	"fail2ban_enabled":        "已启用 Fail2ban sshd jail",
+	"fail2ban_unbanned":       "已解封 %s",
+	"fail2ban_done":           "完成。按 Enter 返回",
+
+	// Dashboard
+	"menu_dashboard":               "系统信息",
+	"dashboard_title":              "系统信息",
+	"dashboard_system":             "系统：",
+	"dashboard_hostname":           "主机名",
+	"dashboard_os":                 "系统",
+	"dashboard_kernel":             "内核",
+	"dashboard_virt":               "虚拟化",
+	"dashboard_uptime":             "运行时间",
+	"dashboard_resources":          "资源：",
+	"dashboard_cpu":                "CPU",
+	"dashboard_load":               "负载",
+	"dashboard_memory":             "内存",
+	"dashboard_swap":               "交换分区",
+	"dashboard_disks":              "磁盘：",
+	"dashboard_network":            "网络：",
+	"dashboard_up":                 "启用",
+	"dashboard_down":               "停用",
+	"dashboard_private_ip":         "内网 IP",
+	"dashboard_public_ip":          "公网 IP",
+	"dashboard_public_ip_failed":   "获取失败",
+	"dashboard_public_ip_disabled": "未查询（可开启 public_ip_lookup）",
+	"dashboard_public_ip_skipped":  "dry-run 与 --root 模式下不查询",
+	"dashboard_keys":               "自动刷新  r：立即刷新  Esc：返回",
+
+	// Services
+	"menu_services":               "服务管理",
+	"services_title":              "服务管理",
+	"services_filter":             "过滤：",
+	"services_filter_placeholder": "名称、描述或状态",
+	"services_empty":              "没有匹配的服务",
+	"services_keys":               "/：过滤  Enter：详情  s/x/r：启动/停止/重启  e/d：开机启用/禁用  l：刷新  Esc：返回",
+	"services_detail_keys":        "↑/↓ 滚动，Esc 返回",
+	"services_confirm":            "对 %[2]s 执行 %[1]s？",
+	"services_running":            "正在对 %[2]s 执行 %[1]s...",
+	"services_action_done":        "%[2]s %[1]s：完成",
+	"services_journal":            "最近日志：",
+	"services_journal_empty":      "（无日志）",
+
+	// Cloud-init user-data export
+	"menu_cloudinit_export":         "导出 cloud-init user-data",
+	"cloudinit_export_title":        "导出 cloud-init user-data",
+	"cloudinit_export_users":        "用户",
+	"cloudinit_export_users_value":  "%d 个用户，%d 个公钥",
+	"cloudinit_export_default_user": "保留发行版默认用户",
+	"cloudinit_export_manage_hosts": "manage_etc_hosts",
+	"cloudinit_export_pwauth":       "密码登录（ssh_pwauth）",
+	"cloudinit_export_keys":         "↑/↓ 滚动  d/m/p: 切换选项  s: 保存  Esc: 返回",
+	"cloudinit_export_path":         "保存 user-data 到：",
+	"cloudinit_export_path_hint":    "Enter 保存，Esc 取消（已有文件会先备份）",
+	"cloudinit_export_saving":       "正在保存...",
+	"cloudinit_export_saved":        "user-data 已写入 %s",
+	"cloudinit_export_done":         "完成。按 Enter 返回",
+
+	// Cloud-init status
+	"menu_cloudinit":               "Cloud-init",
+	"menu_cloudinit_status":        "状态与配置",
+	"cloudinit_title":              "Cloud-init",
+	"cloudinit_not_installed":      "未安装 cloud-init",
+	"cloudinit_status":             "状态",
+	"cloudinit_datasource":         "数据源",
+	"cloudinit_disabled":           "已禁用（cloud-init.disabled）",
+	"cloudinit_config_files":       "配置文件数",
+	"cloudinit_modules":            "启动时执行的模块：",
+	"cloudinit_no_modules":         "（未配置相关模块）",
+	"cloudinit_warnings":           "cloud-init 可能撤销本工具的修改：",
+	"cloudinit_warn_hostname":      "%s 会重置主机名（%s）",
+	"cloudinit_warn_etc_hosts":     "%s 会按模板重写 /etc/hosts（%s）",
+	"cloudinit_warn_ssh_keys":      "%s 会重新生成 SSH 主机密钥（%s）",
+	"cloudinit_warn_ssh_pwauth":    "%s 会重新启用 SSH 密码登录（%s）",
+	"cloudinit_warn_next_instance": "，仅在 instance-id 变化（重新制作镜像、克隆）后的首次启动时执行",
+	"cloudinit_merged_config":      "合并后的 cloud.cfg + cloud.cfg.d/*.cfg：",
+	"cloudinit_config_keys":        "↑/↓ 滚动，Esc 返回",
+	"cloudinit_keys_disable":       "c: 合并配置  x: 禁用 cloud-init  r: 刷新  Esc: 返回",
+	"cloudinit_keys_enable":        "c: 合并配置  x: 重新启用 cloud-init  r: 刷新  Esc: 返回",
+	"cloudinit_confirm_disable":    "创建 /etc/cloud/cloud-init.disabled？之后启动时 cloud-init 将不再运行。",
+	"cloudinit_confirm_enable":     "删除 /etc/cloud/cloud-init.disabled 并重新启用 cloud-init？",
+	"cloudinit_applying":           "正在执行...",
+	"cloudinit_disabled_done":      "已禁用 cloud-init",
+	"cloudinit_enabled_done":       "已重新启用 cloud-init",
+	"cloudinit_done":               "完成。按 Enter 返回",
+
+	// Hosts editor
+	"menu_hosts":                "/etc/hosts 编辑",
+	"hosts_title":               "/etc/hosts",
+	"hosts_ip":                  "IP 地址",
+	"hosts_names":               "主机名（规范名在前，空格分隔）",
+	"hosts_col_names":           "主机名",
+	"hosts_comment":             "注释",
+	"hosts_comment_placeholder": "可选",
+	"hosts_empty":               "（无条目）",
+	"hosts_add":                 "新增条目",
+	"hosts_edit":                "编辑条目",
+	"hosts_edit_keys":           "Tab/↑/↓: 切换字段  Enter: 应用  Esc: 取消",
+	"hosts_keys":                "a: 新增  Enter/e: 编辑  d: 删除  s: 保存  r: 重新读取  Esc: 返回",
+	"hosts_problems":            "问题：",
+	"hosts_problem_duplicate":   "%s 在 %s 上重复出现",
+	"hosts_problem_conflict":    "%s 指向不同地址：%s",
+	"hosts_problem_invalid":     "无效条目：%s",
+	"hosts_unsaved":             "有未保存的修改（按 s 保存）",
+	"hosts_confirm_delete":      "删除 %s %s？",
+	"hosts_system_entry":        "这是默认的回环/组播条目，删除后可能影响本地名称解析。",
+	"hosts_save_with_problems":  "检测到 %d 个问题",
+	"hosts_confirm_save":        "将修改写入 /etc/hosts？（写入前会先备份）",
+	"hosts_confirm_discard":     "放弃未保存的修改？",
+	"hosts_saving":              "正在保存...",
+	"hosts_saved":               "/etc/hosts 已保存",
+
+	// Network
+	"menu_network":            "网络配置",
+	"network_title":           "网络",
+	"network_stack":           "配置方案：%s",
+	"network_stack_unknown":   "未检测到（只读）",
+	"network_dns_current":     "DNS：%s",
+	"network_interfaces":      "网络接口：",
+	"network_no_interfaces":   "（无接口）",
+	"network_up":              "up",
+	"network_down":            "down",
+	"network_routes":          "经由 %s 的路由：",
+	"network_keys":            "↑/↓：选择  Enter/e：静态配置  r：刷新  Esc：返回",
+	"network_keys_readonly":   "↑/↓：选择  r：刷新  Esc：返回",
+	"network_static_for":      "%s 的静态配置",
+	"network_addresses":       "地址（CIDR，逗号分隔）",
+	"network_gateway4":        "IPv4 网关",
+	"network_gateway6":        "IPv6 网关",
+	"network_dns":             "DNS 服务器",
+	"network_search":          "搜索域",
+	"network_form_keys":       "Tab/↑/↓：切换字段  Enter：预览  Esc：取消",
+	"network_preview":         "变更（%s）：",
+	"network_plan_file":       "--- %s",
+	"network_plan_commands":   "命令：",
+	"network_guard_note":      "若 %d 秒内未确认，将自动回滚。",
+	"network_confirm_apply":   "应用此配置？",
+	"network_applying":        "正在应用...",
+	"network_applied":         "网络配置已应用",
+	"network_guard_countdown": "连接仍正常？%d 秒后自动回滚，除非确认保留。",
+	"network_guard_keys":      "y：保留新配置  n：立即回滚",
+	"network_kept":            "已保留新的网络配置",
+	"network_reverted":        "网络配置已回滚",
+
+	// DNS resolver
+	"menu_resolver":                    "DNS 解析",
+	"resolver_title":                   "DNS 解析",
+	"resolver_mode":                    "/etc/resolv.conf 管理方：%s",
+	"resolver_symlink":                 "符号链接 -> %s",
+	"resolver_nameservers":             "生效的 nameserver：",
+	"resolver_search":                  "搜索域：%s",
+	"resolver_dot":                     "DNS-over-TLS：%s",
+	"resolver_dot_default":             "默认",
+	"resolver_dot_hint":                "←/→：切换（yes 需要服务器名，如 1.1.1.1#cloudflare-dns.com）",
+	"resolver_target_systemd-resolved": "修改将写入 /etc/systemd/resolved.conf.d/90-server-toolkit.conf",
+	"resolver_target_networkmanager":   "修改将作为全局 DNS 写入 /etc/NetworkManager/conf.d/90-server-toolkit.conf",
+	"resolver_target_resolvconf":       "修改将写入 /etc/resolvconf/resolv.conf.d/head",
+	"resolver_target_static":           "修改将直接写入 /etc/resolv.conf",
+	"resolver_keys":                    "Enter/e：编辑  r：刷新  Esc：返回",
+	"resolver_nameservers_input":       "nameserver（逗号分隔）",
+	"resolver_form_keys":               "Tab/↑/↓：切换字段  Enter：预览  Esc：取消",
+	"resolver_symlink_warn":            "/etc/resolv.conf 是指向 %s 的符号链接。写入静态文件会替换该链接，链接目标的维护方将不再更新它。",
+	"resolver_symlink_confirm":         "用静态文件替换该符号链接？",
+	"resolver_preview":                 "变更：",
+	"resolver_confirm_apply":           "应用这些 DNS 设置？（会先备份）",
+	"resolver_applied":                 "DNS 设置已应用",
+
+	// Security audit
+	"menu_audit":                    "安全审计",
+	"audit_title":                   "安全审计",
+	"audit_running":                 "正在执行只读检查...",
+	"audit_score":                   "得分：%d/100（%s）",
+	"audit_checks":                  "%d 项检查，%d 个问题，%d 项跳过",
+	"audit_no_findings":             "未发现问题",
+	"audit_fix":                     "修复：%s（按 Enter）",
+	"audit_fix_manual":              "无对应功能，需手动处理",
+	"audit_skipped":                 "已跳过 %s：%s",
+	"audit_exported":                "报告已写入 %s",
+	"audit_keys":                    "↑/↓：选择  Enter：修复  t/m/j：导出文本/Markdown/JSON  r：重新检查  Esc：返回",
+	"audit_ssh_root_login":          "SSH 允许 root 使用密码登录",
+	"audit_ssh_password_auth":       "SSH 启用了密码认证",
+	"audit_ssh_empty_passwords":     "SSH 允许空密码",
+	"audit_ssh_weak_algorithms":     "SSH 提供弱加密算法",
+	"audit_keys_invalid":            "authorized_keys 中有无法解析的条目",
+	"audit_keys_dsa":                "authorized_keys 中有 DSA 密钥",
+	"audit_keys_weak_rsa":           "RSA 密钥短于 2048 位",
+	"audit_etc_world_writable":      "/etc 中存在所有人可写的文件",
+	"audit_accounts_uid0":           "存在 UID 为 0 的非 root 账户",
+	"audit_accounts_empty_password": "存在空密码账户",
+	"audit_firewall_disabled":       "未启用防火墙",
+	"audit_updates_security":        "有待安装的安全更新",
+	"audit_updates_auto_disabled":   "未启用自动安全更新",
+	"audit_time_unsynced":           "系统时钟未与 NTP 同步",
+
+	// 配置漂移
+	"menu_drift":                 "配置漂移",
+	"drift_title":                "配置漂移",
+	"drift_state":                "状态文件：%s",
+	"drift_updated":              "上次应用：%s",
+	"drift_no_state":             "尚无记录。本工具修改主机名、/etc/hosts、sshd 选项或 authorized_keys 后会记录期望值。",
+	"drift_none":                 "✓ 当前配置与上次应用的值一致",
+	"drift_found":                "%d 项与上次应用的值不一致",
+	"drift_want":                 "期望：%s",
+	"drift_have":                 "当前：%s",
+	"drift_missing":              "（缺失）",
+	"drift_kind_hostname":        "主机名",
+	"drift_kind_hosts":           "/etc/hosts",
+	"drift_kind_sshd":            "sshd_config",
+	"drift_kind_authorized_keys": "authorized_keys",
+	"drift_keys":                 "↑/↓ 选择  Enter 全部重新应用  r 刷新  Esc 返回",
+	"drift_confirm":              "重新应用 %d 项？写入前会备份文件。",
+	"drift_applying":             "正在重新应用...",
+	"drift_applied":              "✓ 已重新应用记录的值",
+
+	// 操作历史
+	"menu_history":               "操作历史",
+	"history_title":              "操作历史",
+	"history_path":               "审计日志：%s",
+	"history_empty":              "暂无修改记录",
+	"history_errors_only":        "仅显示失败的操作",
+	"history_filter_placeholder": "模块、操作、用户、文件...",
+	"history_time":               "时间：%s",
+	"history_actor":              "操作者：%s",
+	"history_tty":                "终端：%s",
+	"history_keys":               "↑/↓ 选择  / 过滤  e 仅失败  r 刷新  Esc 返回",
+
+	// 日志查看
+	"menu_logs":               "日志",
+	"logs_title":              "工具日志",
+	"logs_operation_title":    "本次操作日志",
+	"logs_path":               "日志文件：%s",
+	"logs_level":              "级别：%s 及以上",
+	"logs_search":             "搜索：",
+	"logs_search_placeholder": "搜索文本",
+	"logs_empty":              "（没有匹配的日志）",
+	"logs_no_file":            "日志输出到标准输出；设置 log_path 后可在此查看",
+	"logs_keys":               "↑/↓ PgUp/PgDn 滚动  ←/→ 左右移动  v 级别  / 搜索  Esc 返回",
+	"logs_view_hint":          "l：查看本次操作的日志",
+
+	// 自更新
+	"menu_update":               "自更新",
+	"update_title":              "自更新",
+	"update_checking":           "正在检查更新...",
+	"update_current":            "当前版本：%s",
+	"update_channel":            "更新渠道：%s",
+	"update_pinned":             "固定版本：%s",
+	"update_base_url":           "镜像地址：%s",
+	"update_up_to_date":         "已是最新版本（%s）",
+	"update_has_backup":         "已保留更新前的二进制（.bak），可回滚",
+	"update_keys":               "Enter 更新  b 回滚  r 重新检查  Esc 返回",
+	"update_confirm":            "下载、校验并将 %s 替换为 %s？",
+	"update_confirm_rollback":   "恢复上次更新前的二进制？",
+	"update_applying":           "正在更新...",
+	"update_installed":          "已安装 %s，原二进制保存为 .bak",
+	"update_rolled_back":        "已恢复更新前的二进制",
+	"update_restart":            "重新启动 server-toolkit 以使用新的二进制",
+	"update_checked_at":         "上次检查：%s",
+	"update_notes_title":        "%s 发布说明",
+	"update_notes_empty":        "该版本没有发布说明",
+	"update_notes_keys":         "↑/↓ 滚动  Enter 继续  Esc 返回",
+	"app_nonroot":               "非 root 运行：只读界面可用，修改系统需要 sudo",
+	"menu_root_required":        "需要 root 权限：请使用 sudo server-toolkit 运行，或在设置中开启 dry-run 预览",
+	"root_required_title":       "需要 root 权限",
+	"root_required_desc":        "此操作会修改系统，需要 root 权限。",
+	"root_required_dryrun_hint": "提示：在设置中开启 dry-run 可在无 root 时预览将要执行的修改",
+	"root_required_keys":        "s 通过 sudo 重新启动  Esc 返回",
+	"root_sudo_missing":         "未找到 sudo，请以 root 身份运行 server-toolkit",
+
 	// 设置
 	"settings_title":       "设置",
 	"settings_language":    "语言设置",
 	"settings_dryrun":      "Dry-run 模式",
 	"settings_loglevel":    "日志级别",
 	"settings_autoupdate":  "自动更新",
+	"settings_publicip":    "查询公网 IP",
 	"settings_lang_curr":   "当前: %s",
 	"settings_dryrun_on":   "开启后只显示操作，不实际执行",
 	"settings_dryrun_off":  "关闭后将实际执行操作",
@@ -83,6 +448,7 @@ var zhCN = map[string]string{
 	"settings_save_failed": "保存设置失败: %v",
 	"update_available":     "发现新版本: %s",
 	"update_check_failed":  "更新检查失败",
+	"root_target":          "目标根目录: %s（离线）",
 
 	// 通用
 	"confirm":      "确认执行此操作？",
@@ -0,0 +1,200 @@
+package audit
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// Severity 问题严重程度
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// weight 每个问题从 100 分中扣除的分数
+func (s Severity) weight() int {
+	switch s {
+	case SeverityCritical:
+		return 25
+	case SeverityHigh:
+		return 15
+	case SeverityMedium:
+		return 8
+	default:
+		return 3
+	}
+}
+
+// rank 用于排序（越严重越靠前）
+func (s Severity) rank() int {
+	switch s {
+	case SeverityCritical:
+		return 0
+	case SeverityHigh:
+		return 1
+	case SeverityMedium:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// 修复动作：对应 TUI 中可以修复该问题的界面
+const (
+	FixDisablePassword = "ssh.disable_pwd"
+	FixListKeys        = "ssh.list_keys"
+	FixAutoUpgrade     = "system.autoupgrade"
+	FixPackages        = "system.packages"
+	FixServices        = "system.services"
+)
+
+// Finding 检查发现的问题
+type Finding struct {
+	ID       string   `json:"id"`
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Title    string   `json:"title"`
+	Detail   string   `json:"detail,omitempty"`
+	Fix      string   `json:"fix,omitempty"` // 修复动作，为空表示需手动处理
+}
+
+// CheckStatus 单项检查结果
+type CheckStatus string
+
+const (
+	StatusPass    CheckStatus = "pass"
+	StatusFail    CheckStatus = "fail"
+	StatusSkipped CheckStatus = "skipped"
+)
+
+// CheckResult 单项检查的结果
+type CheckResult struct {
+	ID     string      `json:"id"`
+	Status CheckStatus `json:"status"`
+	Reason string      `json:"reason,omitempty"` // 跳过原因
+}
+
+// Report 审计报告
+type Report struct {
+	Hostname    string        `json:"hostname"`
+	Distro      string        `json:"distro,omitempty"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Score       int           `json:"score"`
+	Grade       string        `json:"grade"`
+	Checks      []CheckResult `json:"checks"`
+	Findings    []Finding     `json:"findings"`
+}
+
+// Check 一项只读检查
+type Check struct {
+	ID  string
+	Run func(a *Auditor) ([]Finding, error)
+}
+
+// errSkipped 检查在当前环境下不适用
+type errSkipped struct{ reason string }
+
+func (e errSkipped) Error() string { return e.reason }
+
+func skip(reason string) error { return errSkipped{reason: reason} }
+
+// Auditor 执行审计检查（只读，不修改系统）
+type Auditor struct {
+	logger *internal.Logger
+	distro *system.DistroInfo
+	checks []Check
+}
+
+// NewAuditor 创建审计器，使用全部内置检查
+func NewAuditor(logger *internal.Logger) *Auditor {
+	logger = logger.With(internal.Fields{Module: "audit"})
+	distro, _ := system.DetectDistro()
+	return &Auditor{logger: logger, distro: distro, checks: DefaultChecks()}
+}
+
+// DefaultChecks 内置检查列表
+func DefaultChecks() []Check {
+	return []Check{
+		{ID: "sshd", Run: checkSSHD},
+		{ID: "authorized_keys", Run: checkAuthorizedKeys},
+		{ID: "etc_permissions", Run: checkEtcPermissions},
+		{ID: "accounts", Run: checkAccounts},
+		{ID: "firewall", Run: checkFirewall},
+		{ID: "security_updates", Run: checkSecurityUpdates},
+		{ID: "auto_upgrades", Run: checkAutoUpgrades},
+		{ID: "time_sync", Run: checkTimeSync},
+	}
+}
+
+// Run 执行全部检查并计算得分
+func (a *Auditor) Run() *Report {
+	r := &Report{GeneratedAt: time.Now()}
+	if system.HasRoot() {
+		if data, err := os.ReadFile(system.RootPath("/etc/hostname")); err == nil {
+			r.Hostname = strings.TrimSpace(string(data))
+		}
+	} else {
+		r.Hostname, _ = system.GetHostname()
+	}
+	if a.distro != nil {
+		r.Distro = a.distro.Pretty
+	}
+
+	for _, c := range a.checks {
+		findings, err := c.Run(a)
+		result := CheckResult{ID: c.ID, Status: StatusPass}
+		switch {
+		case err != nil:
+			result.Status = StatusSkipped
+			// 只保留首行，命令的多行 stderr 记录在日志中
+			result.Reason, _, _ = strings.Cut(err.Error(), "\n")
+			if _, ok := err.(errSkipped); !ok {
+				a.logger.Warn("Audit check %s failed: %v", c.ID, err)
+			}
+		case len(findings) > 0:
+			result.Status = StatusFail
+			for i := range findings {
+				findings[i].Check = c.ID
+			}
+			r.Findings = append(r.Findings, findings...)
+		}
+		r.Checks = append(r.Checks, result)
+	}
+
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return r.Findings[i].Severity.rank() < r.Findings[j].Severity.rank()
+	})
+	r.Score, r.Grade = Score(r.Findings)
+	return r
+}
+
+// Score 从 100 分按问题严重程度扣分，返回得分与等级
+func Score(findings []Finding) (int, string) {
+	score := 100
+	for _, f := range findings {
+		score -= f.Severity.weight()
+	}
+	score = max(score, 0)
+
+	switch {
+	case score >= 90:
+		return score, "A"
+	case score >= 80:
+		return score, "B"
+	case score >= 70:
+		return score, "C"
+	case score >= 60:
+		return score, "D"
+	default:
+		return score, "F"
+	}
+}
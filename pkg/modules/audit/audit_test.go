@@ -0,0 +1,128 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rsaKey 构造指定模数位数的 ssh-rsa 公钥数据
+func rsaKey(bits int) string {
+	var blob []byte
+	put := func(b []byte) {
+		blob = binary.BigEndian.AppendUint32(blob, uint32(len(b)))
+		blob = append(blob, b...)
+	}
+	n := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	put([]byte("ssh-rsa"))
+	put([]byte{0x01, 0x00, 0x01})
+	put(append([]byte{0}, n.Bytes()...))
+	return base64.StdEncoding.EncodeToString(blob)
+}
+
+func findingIDs(findings []Finding) []string {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func TestSSHDFindings(t *testing.T) {
+	// 未配置时按默认值：允许密码登录，root 仅允许密钥
+	assert.Equal(t, []string{"ssh.password_auth"}, findingIDs(SSHDFindings(map[string]string{})))
+
+	settings := sshdFindingSettings(ssh.ParseEffective(`port 22
+permitrootlogin yes
+passwordauthentication no
+permitemptypasswords no
+ciphers chacha20-poly1305@openssh.com,aes256-cbc
+macs hmac-sha2-256-etm@openssh.com,hmac-sha1
+kexalgorithms curve25519-sha256,diffie-hellman-group14-sha1
+`))
+	findings := SSHDFindings(settings)
+	assert.Equal(t, []string{"ssh.root_login", "ssh.weak_algorithms"}, findingIDs(findings))
+	assert.Equal(t, "aes256-cbc, hmac-sha1, diffie-hellman-group14-sha1", findings[1].Detail)
+}
+
+func TestAuthorizedKeysFindings(t *testing.T) {
+	assert.Equal(t, 1024, RSAKeyBits(rsaKey(1024)))
+	assert.Equal(t, 0, RSAKeyBits("not-base64!"))
+
+	content := "# comment\n" +
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl ok@host\n" +
+		`from="10.0.0.0/8",no-pty ssh-rsa ` + rsaKey(1024) + " weak@host\n" +
+		"ssh-rsa " + rsaKey(4096) + " strong@host\n" +
+		"ssh-dss AAAAB3NzaC1kc3M= old@host\n" +
+		"garbage\n"
+
+	findings := AuthorizedKeysFindings("alice", "/home/alice/.ssh/authorized_keys", content)
+	assert.Equal(t, []string{"keys.weak_rsa", "keys.dsa", "keys.invalid"}, findingIDs(findings))
+	assert.Equal(t, "alice: /home/alice/.ssh/authorized_keys:3 (1024 bits)", findings[0].Detail)
+	assert.Equal(t, FixListKeys, findings[1].Fix)
+}
+
+func TestAuditorRunOnRoot(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string, perm os.FileMode) {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), perm))
+		require.NoError(t, os.Chmod(full, perm))
+	}
+	write("etc/hostname", "web-01\n", 0644)
+	write("etc/passwd", "root:x:0:0:root:/root:/bin/bash\ntoor:x:0:0::/root:/bin/sh\nalice:x:1000:1000::/home/alice:/bin/bash\n", 0644)
+	write("etc/shadow", "root:$6$hash:19000::::::\nalice::19000::::::\n", 0640)
+	write("etc/ssh/sshd_config", "PermitRootLogin yes\n", 0644)
+	write("etc/ssh/sshd_config.d/50-cloud.conf", "PasswordAuthentication no\n", 0644)
+	write("etc/open.conf", "x\n", 0666)
+	write("home/alice/.ssh/authorized_keys", "ssh-dss AAAAB3NzaC1kc3M= old@host\n", 0600)
+
+	require.NoError(t, system.SetRoot(dir))
+	t.Cleanup(func() { _ = system.SetRoot("/") })
+	oldRunner := system.SetRunner(system.NewFakeRunner())
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	a := &Auditor{logger: internal.NewLogger(internal.ERROR, os.Stdout), checks: DefaultChecks()}
+	r := a.Run()
+
+	assert.Equal(t, "web-01", r.Hostname)
+	assert.Equal(t, []string{
+		"accounts.uid0", "accounts.empty_password",
+		"ssh.root_login", "keys.dsa", "etc.world_writable",
+	}, findingIDs(r.Findings))
+	assert.Equal(t, "/etc/open.conf", r.Findings[4].Detail)
+	assert.Equal(t, 100-25-25-15-15-15, r.Score)
+	assert.Equal(t, "F", r.Grade)
+
+	statuses := make(map[string]CheckStatus)
+	for _, c := range r.Checks {
+		statuses[c.ID] = c.Status
+	}
+	assert.Equal(t, StatusFail, statuses["sshd"])
+	assert.Equal(t, StatusSkipped, statuses["firewall"])
+	assert.Equal(t, StatusSkipped, statuses["security_updates"])
+
+	data, err := r.Export(FormatJSON)
+	require.NoError(t, err)
+	var decoded Report
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r.Score, decoded.Score)
+	assert.Len(t, decoded.Findings, 5)
+
+	md, err := r.Export(FormatMarkdown)
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "| critical | Non-root account with UID 0 | toor | manual |")
+	assert.Contains(t, string(md), "| sshd | fail |")
+	assert.Contains(t, r.Text(), "[HIGH] DSA key in authorized_keys\n    alice: /home/alice/.ssh/authorized_keys:1\n    fix: SSH > List keys\n")
+}
@@ -0,0 +1,428 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/pkg/modules/autoupgrade"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+var (
+	sshdConfigFile = "/etc/ssh/sshd_config"
+	shadowFile     = "/etc/shadow"
+	etcDir         = "/etc"
+)
+
+// minRSABits 低于该长度的 RSA 公钥视为弱密钥
+const minRSABits = 2048
+
+// maxDetailItems 详情中最多列出的条目数
+const maxDetailItems = 10
+
+// sshdDefaults 未显式配置时 sshd 的默认值（OpenSSH 7.0+）
+var sshdDefaults = map[string]string{
+	"permitrootlogin":        "prohibit-password",
+	"passwordauthentication": "yes",
+	"permitemptypasswords":   "no",
+}
+
+// sshdSettings 读取 sshd 生效配置（sshd -T，失败时解析 sshd_config.d 与 sshd_config）
+func sshdSettings() (map[string]string, error) {
+	effective, err := ssh.ReadEffectiveSettings(system.RootPath(sshdConfigFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, skip("sshd_config not found")
+		}
+		return nil, err
+	}
+	return sshdFindingSettings(effective), nil
+}
+
+// sshdFindingSettings 取出审计关心的选项（小写值）
+func sshdFindingSettings(effective ssh.EffectiveSettings) map[string]string {
+	settings := make(map[string]string)
+	for _, key := range []string{"permitrootlogin", "passwordauthentication", "permitemptypasswords", "ciphers", "macs", "kexalgorithms"} {
+		if value, ok := effective.Get(key); ok {
+			settings[key] = strings.ToLower(value)
+		}
+	}
+	return settings
+}
+
+// checkSSHD root 登录、密码登录、空密码与弱算法
+func checkSSHD(_ *Auditor) ([]Finding, error) {
+	settings, err := sshdSettings()
+	if err != nil {
+		return nil, err
+	}
+	return SSHDFindings(settings), nil
+}
+
+// SSHDFindings 根据 sshd 生效配置生成问题列表
+func SSHDFindings(settings map[string]string) []Finding {
+	get := func(key string) string {
+		if v, ok := settings[key]; ok {
+			return v
+		}
+		return sshdDefaults[key]
+	}
+
+	var findings []Finding
+	if get("permitrootlogin") == "yes" {
+		findings = append(findings, Finding{
+			ID: "ssh.root_login", Severity: SeverityHigh,
+			Title:  "SSH allows root login with a password",
+			Detail: "PermitRootLogin yes",
+		})
+	}
+	if get("passwordauthentication") == "yes" {
+		findings = append(findings, Finding{
+			ID: "ssh.password_auth", Severity: SeverityMedium,
+			Title:  "SSH password authentication is enabled",
+			Detail: "PasswordAuthentication yes",
+			Fix:    FixDisablePassword,
+		})
+	}
+	if get("permitemptypasswords") == "yes" {
+		findings = append(findings, Finding{
+			ID: "ssh.empty_passwords", Severity: SeverityCritical,
+			Title:  "SSH permits empty passwords",
+			Detail: "PermitEmptyPasswords yes",
+		})
+	}
+
+	var weak []string
+	for _, key := range []string{"ciphers", "macs", "kexalgorithms"} {
+		for _, alg := range strings.Split(settings[key], ",") {
+			if alg = strings.TrimSpace(alg); alg != "" && weakAlgorithm(key, alg) {
+				weak = append(weak, alg)
+			}
+		}
+	}
+	if len(weak) > 0 {
+		findings = append(findings, Finding{
+			ID: "ssh.weak_algorithms", Severity: SeverityMedium,
+			Title:  "SSH offers weak ciphers, MACs or key exchange algorithms",
+			Detail: strings.Join(weak, ", "),
+		})
+	}
+	return findings
+}
+
+// weakAlgorithm CBC/RC4/3DES 加密、MD5/SHA-1/64 位 MAC 与 SHA-1 密钥交换
+func weakAlgorithm(kind, alg string) bool {
+	switch kind {
+	case "ciphers":
+		for _, s := range []string{"-cbc", "arcfour", "3des", "blowfish", "cast128"} {
+			if strings.Contains(alg, s) {
+				return true
+			}
+		}
+	case "macs":
+		for _, p := range []string{"hmac-md5", "hmac-sha1", "umac-64", "hmac-ripemd160"} {
+			if strings.HasPrefix(alg, p) {
+				return true
+			}
+		}
+	case "kexalgorithms":
+		return strings.HasSuffix(alg, "-sha1") || strings.Contains(alg, "group1-")
+	}
+	return false
+}
+
+// checkAuthorizedKeys 各用户 authorized_keys 中的 DSA、短 RSA 与无法解析的密钥
+func checkAuthorizedKeys(_ *Auditor) ([]Finding, error) {
+	users, err := system.ListUsers()
+	if err != nil {
+		return nil, skip(fmt.Sprintf("failed to read /etc/passwd: %v", err))
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	for _, u := range users {
+		path := filepath.Join(u.HomeDir, ".ssh", "authorized_keys")
+		if u.HomeDir == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		data, err := os.ReadFile(system.RootPath(path))
+		if err != nil {
+			continue
+		}
+		findings = append(findings, AuthorizedKeysFindings(u.Username, path, string(data))...)
+	}
+	return findings, nil
+}
+
+// AuthorizedKeysFindings 检查单个 authorized_keys 文件的内容
+func AuthorizedKeysFindings(user, path, content string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		where := fmt.Sprintf("%s: %s:%d", user, path, i+1)
+
+		// 跳过行首的选项（如 from="..."、command="..."）
+		fields := strings.Fields(line)
+		keyType, keyData := "", ""
+		for j := range fields {
+			if ssh.ValidateKey(strings.Join(fields[j:], " ")) == nil {
+				keyType, keyData = fields[j], fields[j+1]
+				break
+			}
+		}
+
+		switch {
+		case keyType == "":
+			findings = append(findings, Finding{
+				ID: "keys.invalid", Severity: SeverityLow,
+				Title: "Unparseable entry in authorized_keys", Detail: where, Fix: FixListKeys,
+			})
+		case keyType == "ssh-dss":
+			findings = append(findings, Finding{
+				ID: "keys.dsa", Severity: SeverityHigh,
+				Title: "DSA key in authorized_keys", Detail: where, Fix: FixListKeys,
+			})
+		case keyType == "ssh-rsa":
+			if bits := RSAKeyBits(keyData); bits > 0 && bits < minRSABits {
+				findings = append(findings, Finding{
+					ID: "keys.weak_rsa", Severity: SeverityHigh,
+					Title:  "RSA key shorter than 2048 bits in authorized_keys",
+					Detail: fmt.Sprintf("%s (%d bits)", where, bits),
+					Fix:    FixListKeys,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// RSAKeyBits 从 ssh-rsa 公钥数据（base64 线格式：string 类型, mpint e, mpint n）读取模数位数，解析失败返回 0
+func RSAKeyBits(data string) int {
+	blob, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return 0
+	}
+	var parts [][]byte
+	for len(parts) < 3 {
+		if len(blob) < 4 {
+			return 0
+		}
+		n := binary.BigEndian.Uint32(blob)
+		if uint64(len(blob)-4) < uint64(n) {
+			return 0
+		}
+		parts = append(parts, blob[4:4+n])
+		blob = blob[4+n:]
+	}
+	if string(parts[0]) != "ssh-rsa" {
+		return 0
+	}
+	return new(big.Int).SetBytes(parts[2]).BitLen()
+}
+
+// checkEtcPermissions /etc 下所有人可写的文件与目录（带粘滞位的目录除外）
+func checkEtcPermissions(_ *Auditor) ([]Finding, error) {
+	root := system.RootPath(etcDir)
+	var writable []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		mode := info.Mode()
+		if mode.Perm()&0002 == 0 || (mode.IsDir() && mode&fs.ModeSticky != 0) {
+			return nil
+		}
+		writable = append(writable, system.GuestPath(path))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(writable) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		ID: "etc.world_writable", Severity: SeverityHigh,
+		Title:  "World-writable files in /etc",
+		Detail: summarize(writable),
+	}}, nil
+}
+
+// checkAccounts 除 root 外 UID 为 0 的用户与空密码用户
+func checkAccounts(_ *Auditor) ([]Finding, error) {
+	users, err := system.ListUsers()
+	if err != nil {
+		return nil, skip(fmt.Sprintf("failed to read /etc/passwd: %v", err))
+	}
+
+	var findings []Finding
+	for _, u := range users {
+		if u.UID == 0 && u.Username != "root" {
+			findings = append(findings, Finding{
+				ID: "accounts.uid0", Severity: SeverityCritical,
+				Title: "Non-root account with UID 0", Detail: u.Username,
+			})
+		}
+	}
+
+	// 非 root 运行时无法读取 shadow，只检查 passwd
+	if data, err := os.ReadFile(system.RootPath(shadowFile)); err == nil {
+		var empty []string
+		for _, line := range strings.Split(string(data), "\n") {
+			parts := strings.Split(line, ":")
+			if len(parts) >= 2 && parts[0] != "" && parts[1] == "" {
+				empty = append(empty, parts[0])
+			}
+		}
+		if len(empty) > 0 {
+			findings = append(findings, Finding{
+				ID: "accounts.empty_password", Severity: SeverityCritical,
+				Title: "Accounts with an empty password", Detail: summarize(empty),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// checkFirewall ufw、firewalld、nftables 或 iptables 中至少一个在过滤入站流量
+func checkFirewall(_ *Auditor) ([]Finding, error) {
+	if system.HasRoot() {
+		return nil, skip("not available with --root")
+	}
+	if firewallActive() {
+		return nil, nil
+	}
+	return []Finding{{
+		ID: "firewall.disabled", Severity: SeverityMedium,
+		Title:  "No active firewall",
+		Detail: "ufw, firewalld, nftables and iptables have no filtering rules",
+	}}, nil
+}
+
+func firewallActive() bool {
+	if system.CommandExists("ufw") {
+		if res, err := system.QueryCommand("ufw", "status"); err == nil && strings.Contains(res.Stdout, "Status: active") {
+			return true
+		}
+	}
+	if active, _ := system.NewServiceManager().IsActive("firewalld"); active {
+		return true
+	}
+	if system.CommandExists("nft") {
+		if res, err := system.QueryCommand("nft", "list", "ruleset"); err == nil && strings.Contains(res.Stdout, "hook input") {
+			return true
+		}
+	}
+	if system.CommandExists("iptables") {
+		if res, err := system.QueryCommand("iptables", "-S", "INPUT"); err == nil {
+			for _, line := range strings.Split(res.Stdout, "\n") {
+				line = strings.TrimSpace(line)
+				if strings.HasPrefix(line, "-A ") || (strings.HasPrefix(line, "-P ") && !strings.HasSuffix(line, "ACCEPT")) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkSecurityUpdates 待安装的安全更新
+func checkSecurityUpdates(a *Auditor) ([]Finding, error) {
+	if a.distro == nil {
+		return nil, skip("unknown distribution")
+	}
+	mgr, err := system.DetectPackageManager(a.distro.Family)
+	if err != nil {
+		return nil, skip(err.Error())
+	}
+	upgrades, err := mgr.ListUpgrades()
+	if err != nil {
+		return nil, err
+	}
+
+	var security []string
+	for _, u := range upgrades {
+		if u.Security {
+			security = append(security, u.Name)
+		}
+	}
+	if len(security) == 0 {
+		return nil, nil
+	}
+	return []Finding{{
+		ID: "updates.security", Severity: SeverityHigh,
+		Title:  fmt.Sprintf("%d pending security update(s)", len(security)),
+		Detail: summarize(security),
+		Fix:    FixPackages,
+	}}, nil
+}
+
+// checkAutoUpgrades 自动安全更新是否启用
+func checkAutoUpgrades(a *Auditor) ([]Finding, error) {
+	if a.distro == nil {
+		return nil, skip("unknown distribution")
+	}
+	status, err := autoupgrade.NewManager(a.distro, true, a.logger).Status()
+	if err != nil {
+		return nil, err
+	}
+	if !status.Supported {
+		return nil, skip("not supported on " + a.distro.ID)
+	}
+	if status.Enabled {
+		return nil, nil
+	}
+	return []Finding{{
+		ID: "updates.auto_disabled", Severity: SeverityMedium,
+		Title:  "Automatic security updates are disabled",
+		Detail: status.Backend,
+		Fix:    FixAutoUpgrade,
+	}}, nil
+}
+
+// checkTimeSync 系统时钟是否已通过 NTP 同步
+func checkTimeSync(_ *Auditor) ([]Finding, error) {
+	if system.HasRoot() {
+		return nil, skip("not available with --root")
+	}
+	if !system.CommandExists("timedatectl") {
+		return nil, skip("timedatectl not found")
+	}
+	res, err := system.QueryCommand("timedatectl", "show", "-p", "NTPSynchronized", "--value")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(res.Stdout) == "yes" {
+		return nil, nil
+	}
+	return []Finding{{
+		ID: "time.unsynced", Severity: SeverityMedium,
+		Title:  "System clock is not synchronized with NTP",
+		Detail: "NTPSynchronized=" + strings.TrimSpace(res.Stdout),
+		Fix:    FixServices,
+	}}, nil
+}
+
+// summarize 列出前若干项并注明剩余数量
+func summarize(items []string) string {
+	if len(items) <= maxDetailItems {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(items[:maxDetailItems], ", "), len(items)-maxDetailItems)
+}
@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format 报告导出格式
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatMarkdown Format = "markdown"
+	FormatJSON     Format = "json"
+)
+
+// ParseFormat 解析格式名（支持 md/txt 简写）
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "text", "txt":
+		return FormatText, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unknown report format: %q (expected text, markdown or json)", s)
+}
+
+// Ext 导出文件的扩展名
+func (f Format) Ext() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatJSON:
+		return ".json"
+	default:
+		return ".txt"
+	}
+}
+
+// Export 按格式导出报告
+func (r *Report) Export(f Format) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode report: %w", err)
+		}
+		return append(data, '\n'), nil
+	case FormatMarkdown:
+		return []byte(r.Markdown()), nil
+	default:
+		return []byte(r.Text()), nil
+	}
+}
+
+// Markdown 以 Markdown 表格输出
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Security audit: %s\n\n", r.Hostname)
+	if r.Distro != "" {
+		fmt.Fprintf(&b, "- Distribution: %s\n", r.Distro)
+	}
+	fmt.Fprintf(&b, "- Generated: %s\n", r.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "- Score: **%d/100 (%s)**\n\n", r.Score, r.Grade)
+
+	b.WriteString("## Findings\n\n")
+	if len(r.Findings) == 0 {
+		b.WriteString("No issues found.\n")
+	} else {
+		b.WriteString("| Severity | Finding | Detail | Fix |\n|---|---|---|---|\n")
+		for _, f := range r.Findings {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Severity, mdEscape(f.Title), mdEscape(f.Detail), fixLabel(f.Fix))
+		}
+	}
+
+	b.WriteString("\n## Checks\n\n| Check | Status |\n|---|---|\n")
+	for _, c := range r.Checks {
+		status := string(c.Status)
+		if c.Reason != "" {
+			status += " (" + mdEscape(c.Reason) + ")"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", c.ID, status)
+	}
+	return b.String()
+}
+
+// Text 以纯文本输出
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Security audit: %s\n", r.Hostname)
+	if r.Distro != "" {
+		fmt.Fprintf(&b, "Distribution:   %s\n", r.Distro)
+	}
+	fmt.Fprintf(&b, "Generated:      %s\n", r.GeneratedAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(&b, "Score:          %d/100 (%s)\n\n", r.Score, r.Grade)
+
+	if len(r.Findings) == 0 {
+		b.WriteString("No issues found.\n")
+	}
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "[%s] %s\n", strings.ToUpper(string(f.Severity)), f.Title)
+		if f.Detail != "" {
+			fmt.Fprintf(&b, "    %s\n", f.Detail)
+		}
+		if f.Fix != "" {
+			fmt.Fprintf(&b, "    fix: %s\n", fixLabel(f.Fix))
+		}
+	}
+
+	b.WriteString("\nChecks:\n")
+	for _, c := range r.Checks {
+		fmt.Fprintf(&b, "  %-18s %s", c.ID, c.Status)
+		if c.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", c.Reason)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// fixLabel 修复动作对应的菜单路径
+func fixLabel(fix string) string {
+	switch fix {
+	case FixDisablePassword:
+		return "SSH > Disable password login"
+	case FixListKeys:
+		return "SSH > List keys"
+	case FixAutoUpgrade:
+		return "System > Automatic security updates"
+	case FixPackages:
+		return "System > Packages"
+	case FixServices:
+		return "System > Services"
+	case "":
+		return "manual"
+	}
+	return fix
+}
+
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
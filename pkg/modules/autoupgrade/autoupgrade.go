@@ -0,0 +1,473 @@
+package autoupgrade
+
+import (
+	"bufio"
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+const (
+	debianPackage = "unattended-upgrades"
+	debianService = "unattended-upgrades"
+	redhatPackage = "dnf-automatic"
+	redhatTimer   = "dnf-automatic.timer"
+)
+
+var (
+	unattendedUpgradesFile = "/etc/apt/apt.conf.d/50unattended-upgrades"
+	autoUpgradesFile       = "/etc/apt/apt.conf.d/20auto-upgrades"
+	dnfAutomaticFile       = "/etc/dnf/automatic.conf"
+)
+
+var rebootTimeRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Options 自动安全更新选项
+type Options struct {
+	AutoReboot bool   // 更新需要时自动重启
+	RebootTime string // 自动重启时间（HH:MM），为空表示立即
+	Email      string // 通知邮箱，为空表示不发送邮件
+}
+
+// Status 自动安全更新状态
+type Status struct {
+	Supported  bool
+	Backend    string
+	Installed  bool
+	Enabled    bool
+	Active     bool
+	AutoReboot bool
+	RebootTime string
+	Email      string
+}
+
+// Manager 自动安全更新管理器
+type Manager struct {
+	distro *system.DistroInfo
+	dryRun bool
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewManager 创建自动安全更新管理器
+func NewManager(distro *system.DistroInfo, dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "autoupgrade"})
+	return &Manager{
+		distro: distro,
+		dryRun: dryRun,
+		logger: logger,
+		drm:    internal.NewDryRunManager(dryRun, logger),
+	}
+}
+
+// ValidateRebootTime 验证自动重启时间（HH:MM）
+func ValidateRebootTime(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !rebootTimeRegex.MatchString(value) {
+		return fmt.Errorf("invalid reboot time %q (expected HH:MM)", value)
+	}
+	return nil
+}
+
+// ValidateEmail 验证通知邮箱：须为单个纯地址（不含显示名），且不含会破坏 apt 配置或 ini 文件的双引号、分号、反斜杠与换行
+func ValidateEmail(value string) error {
+	if value == "" {
+		return nil
+	}
+	if strings.ContainsAny(value, "\";\\\r\n") {
+		return fmt.Errorf("invalid email address %q", value)
+	}
+	addr, err := mail.ParseAddress(value)
+	if err != nil || addr.Address != value {
+		return fmt.Errorf("invalid email address %q", value)
+	}
+	return nil
+}
+
+// Backend 返回当前发行版使用的自动更新方案名称
+func (m *Manager) Backend() string {
+	if m.distro == nil {
+		return ""
+	}
+	switch m.distro.Family {
+	case system.Debian:
+		return debianPackage
+	case system.RedHat:
+		return redhatPackage
+	default:
+		return ""
+	}
+}
+
+// Enable 启用自动安全更新
+func (m *Manager) Enable(opts Options) error {
+	if err := ValidateRebootTime(opts.RebootTime); err != nil {
+		return err
+	}
+	if err := ValidateEmail(opts.Email); err != nil {
+		return err
+	}
+	if m.distro == nil {
+		return fmt.Errorf("unsupported distribution")
+	}
+
+	switch m.distro.Family {
+	case system.Debian:
+		return m.enableDebian(opts)
+	case system.RedHat:
+		return m.enableRedHat(opts)
+	default:
+		return fmt.Errorf("automatic security updates are not supported on %s", m.distro.ID)
+	}
+}
+
+func (m *Manager) enableDebian(opts Options) error {
+	content, err := RenderUnattendedUpgrades(m.distro, opts)
+	if err != nil {
+		return err
+	}
+	if err := m.installPackage(debianPackage); err != nil {
+		return err
+	}
+
+	if err := m.writeFile(unattendedUpgradesFile, content); err != nil {
+		return err
+	}
+	if err := m.writeFile(autoUpgradesFile, RenderAutoUpgrades()); err != nil {
+		return err
+	}
+
+	if err := m.enableService(debianService); err != nil {
+		return err
+	}
+
+	m.logger.Info("Enabled unattended-upgrades (auto reboot: %v)", opts.AutoReboot)
+	return nil
+}
+
+func (m *Manager) enableRedHat(opts Options) error {
+	if err := m.installPackage(redhatPackage); err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(system.RootPath(dnfAutomaticFile))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", dnfAutomaticFile, err)
+	}
+
+	if err := m.writeFile(dnfAutomaticFile, PatchDnfAutomatic(string(current), opts)); err != nil {
+		return err
+	}
+
+	if err := m.enableService(redhatTimer); err != nil {
+		return err
+	}
+
+	m.logger.Info("Enabled dnf-automatic (auto reboot: %v)", opts.AutoReboot)
+	return nil
+}
+
+func (m *Manager) installPackage(pkg string) error {
+	if m.dryRun {
+		m.drm.LogOperation("Would install package: %s", pkg)
+		return nil
+	}
+	if err := system.InstallPackage(pkg); err != nil {
+		return fmt.Errorf("failed to install %s: %w", pkg, err)
+	}
+	return nil
+}
+
+func (m *Manager) enableService(name string) error {
+	if m.dryRun {
+		m.drm.LogServiceOperation("enable and start", name)
+		return nil
+	}
+	return system.NewServiceManager().EnableAndStart(name)
+}
+
+func (m *Manager) writeFile(path, content string) error {
+	path = system.RootPath(path)
+	if m.dryRun {
+		m.drm.LogFileWrite(path, content)
+		return nil
+	}
+
+	backupPath, err := system.BackupFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to backup %s: %w", path, err)
+	}
+	if backupPath != "" {
+		m.logger.Info("Backed up: %s -> %s", path, backupPath)
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode()
+	}
+	if err := system.SafeWrite(path, []byte(content), perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	m.logger.Info("Written to %s", path)
+	return nil
+}
+
+// Status 获取自动安全更新状态
+func (m *Manager) Status() (*Status, error) {
+	status := &Status{Backend: m.Backend()}
+	if status.Backend == "" {
+		return status, nil
+	}
+	status.Supported = true
+
+	if mgr, err := system.DetectPackageManager(m.distro.Family); err == nil {
+		status.Installed, _ = mgr.IsInstalled(status.Backend)
+	}
+
+	svc := system.NewServiceManager()
+
+	switch m.distro.Family {
+	case system.Debian:
+		if data, err := os.ReadFile(system.RootPath(autoUpgradesFile)); err == nil {
+			status.Enabled = aptConfigValue(string(data), "APT::Periodic::Unattended-Upgrade") == "1"
+		}
+		if data, err := os.ReadFile(system.RootPath(unattendedUpgradesFile)); err == nil {
+			content := string(data)
+			status.AutoReboot = aptConfigValue(content, "Unattended-Upgrade::Automatic-Reboot") == "true"
+			status.RebootTime = aptConfigValue(content, "Unattended-Upgrade::Automatic-Reboot-Time")
+			status.Email = aptConfigValue(content, "Unattended-Upgrade::Mail")
+		}
+		status.Active, _ = svc.IsActive(debianService)
+
+	case system.RedHat:
+		if data, err := os.ReadFile(system.RootPath(dnfAutomaticFile)); err == nil {
+			content := string(data)
+			status.Enabled = iniValue(content, "commands", "apply_updates") == "yes"
+			reboot := iniValue(content, "commands", "reboot")
+			status.AutoReboot = reboot != "" && reboot != "never"
+			if status.AutoReboot {
+				status.RebootTime = rebootTimeFromCommand(iniValue(content, "commands", "reboot_command"))
+			}
+			if strings.Contains(iniValue(content, "emitters", "emit_via"), "email") {
+				status.Email = iniValue(content, "email", "email_to")
+			}
+		}
+		status.Active, _ = svc.IsActive(redhatTimer)
+	}
+
+	return status, nil
+}
+
+// RenderUnattendedUpgrades 生成 50unattended-upgrades 内容（仅安全更新）
+//
+// 安全更新来源按 ID/ID_LIKE 选择：Ubuntu 衍生版（Mint、Pop!_OS、Zorin 等）使用
+// Ubuntu 仓库，但 ${distro_id}/${distro_codename} 是衍生版自己的值，因此按
+// 仓库来源匹配任意代号的 -security；其他有独立仓库的衍生版（Kali、Raspbian、
+// Devuan 等）没有可靠的安全来源，直接拒绝，避免写出什么都匹配不到的配置。
+func RenderUnattendedUpgrades(distro *system.DistroInfo, opts Options) (string, error) {
+	if distro == nil {
+		return "", fmt.Errorf("unsupported distribution")
+	}
+
+	var b strings.Builder
+	b.WriteString("// written by server-toolkit: install security updates automatically\n")
+
+	switch {
+	case distro.ID == "ubuntu":
+		b.WriteString("Unattended-Upgrade::Allowed-Origins {\n")
+		b.WriteString("\t\"${distro_id}:${distro_codename}-security\";\n")
+		b.WriteString("\t\"${distro_id}ESMApps:${distro_codename}-apps-security\";\n")
+		b.WriteString("\t\"${distro_id}ESM:${distro_codename}-infra-security\";\n")
+		b.WriteString("};\n")
+	case distroLike(distro, "ubuntu"):
+		b.WriteString("Unattended-Upgrade::Origins-Pattern {\n")
+		b.WriteString("\t\"origin=Ubuntu,archive=*-security,label=Ubuntu\";\n")
+		b.WriteString("};\n")
+	case distro.ID == "debian":
+		b.WriteString("Unattended-Upgrade::Origins-Pattern {\n")
+		b.WriteString("\t\"origin=Debian,codename=${distro_codename},label=Debian-Security\";\n")
+		b.WriteString("\t\"origin=Debian,codename=${distro_codename}-security,label=Debian-Security\";\n")
+		b.WriteString("};\n")
+	default:
+		return "", fmt.Errorf("security update origins for %s are unknown; configure unattended-upgrades manually", distro.ID)
+	}
+
+	b.WriteString("Unattended-Upgrade::Remove-Unused-Kernel-Packages \"true\";\n")
+	b.WriteString("Unattended-Upgrade::Remove-Unused-Dependencies \"true\";\n")
+
+	if opts.AutoReboot {
+		b.WriteString("Unattended-Upgrade::Automatic-Reboot \"true\";\n")
+		if opts.RebootTime != "" {
+			fmt.Fprintf(&b, "Unattended-Upgrade::Automatic-Reboot-Time \"%s\";\n", opts.RebootTime)
+		}
+	} else {
+		b.WriteString("Unattended-Upgrade::Automatic-Reboot \"false\";\n")
+	}
+
+	if opts.Email != "" {
+		fmt.Fprintf(&b, "Unattended-Upgrade::Mail \"%s\";\n", opts.Email)
+		b.WriteString("Unattended-Upgrade::MailReport \"on-change\";\n")
+	}
+
+	return b.String(), nil
+}
+
+// distroLike 判断 ID_LIKE 是否包含指定发行版
+func distroLike(distro *system.DistroInfo, id string) bool {
+	for _, like := range strings.Fields(distro.IDLike) {
+		if like == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderAutoUpgrades 生成 20auto-upgrades 内容
+func RenderAutoUpgrades() string {
+	return "// written by server-toolkit\n" +
+		"APT::Periodic::Update-Package-Lists \"1\";\n" +
+		"APT::Periodic::Unattended-Upgrade \"1\";\n"
+}
+
+// PatchDnfAutomatic 修改 automatic.conf：仅安全更新 + 自动应用
+func PatchDnfAutomatic(content string, opts Options) string {
+	lines := splitLines(content)
+
+	lines = setIniValue(lines, "commands", "upgrade_type", "security")
+	lines = setIniValue(lines, "commands", "download_updates", "yes")
+	lines = setIniValue(lines, "commands", "apply_updates", "yes")
+
+	if opts.AutoReboot {
+		lines = setIniValue(lines, "commands", "reboot", "when-needed")
+		when := "+5"
+		if opts.RebootTime != "" {
+			when = opts.RebootTime
+		}
+		lines = setIniValue(lines, "commands", "reboot_command",
+			fmt.Sprintf("\"shutdown -r %s 'Rebooting after applying package updates'\"", when))
+	} else {
+		lines = setIniValue(lines, "commands", "reboot", "never")
+	}
+
+	if opts.Email != "" {
+		lines = setIniValue(lines, "emitters", "emit_via", "stdio,email")
+		lines = setIniValue(lines, "email", "email_to", opts.Email)
+	} else {
+		lines = setIniValue(lines, "emitters", "emit_via", "stdio")
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// aptConfigValue 读取 apt.conf 风格的 `Key "value";` 值
+func aptConfigValue(content, key string) string {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "//") || !strings.HasPrefix(line, key) {
+			continue
+		}
+		rest := strings.TrimSpace(strings.TrimPrefix(line, key))
+		if !strings.HasPrefix(rest, "\"") {
+			continue
+		}
+		rest = strings.TrimSuffix(strings.TrimSpace(rest), ";")
+		return strings.Trim(rest, "\"")
+	}
+	return ""
+}
+
+// iniValue 读取 ini 文件中指定 section 的值
+func iniValue(content, section, key string) string {
+	current := ""
+	for _, line := range splitLines(content) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			current = strings.Trim(trimmed, "[]")
+			continue
+		}
+		if current != section || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		parts := strings.SplitN(trimmed, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// setIniValue 设置 ini 文件中指定 section 的值（不存在时追加）
+func setIniValue(lines []string, section, key, value string) []string {
+	newLine := fmt.Sprintf("%s = %s", key, value)
+	current := ""
+	sectionEnd := -1
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			current = strings.Trim(trimmed, "[]")
+			continue
+		}
+		if current != section {
+			continue
+		}
+		if trimmed != "" {
+			sectionEnd = i
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		parts := strings.SplitN(trimmed, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			lines[i] = newLine
+			return lines
+		}
+	}
+
+	if sectionEnd == -1 {
+		// section 不存在（或为空）：查找 section 头
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "["+section+"]" {
+				sectionEnd = i
+				break
+			}
+		}
+	}
+
+	if sectionEnd == -1 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, "["+section+"]", newLine)
+	}
+
+	insertAt := sectionEnd + 1
+	return append(lines[:insertAt], append([]string{newLine}, lines[insertAt:]...)...)
+}
+
+func rebootTimeFromCommand(command string) string {
+	fields := strings.Fields(strings.Trim(command, "\"'"))
+	for i, field := range fields {
+		if field == "-r" && i+1 < len(fields) && rebootTimeRegex.MatchString(fields[i+1]) {
+			return fields[i+1]
+		}
+	}
+	return ""
+}
+
+func splitLines(content string) []string {
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
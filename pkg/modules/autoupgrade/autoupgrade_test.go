@@ -0,0 +1,115 @@
+package autoupgrade
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRebootTime(t *testing.T) {
+	assert.NoError(t, ValidateRebootTime(""))
+	assert.NoError(t, ValidateRebootTime("02:00"))
+	assert.NoError(t, ValidateRebootTime("23:59"))
+	assert.Error(t, ValidateRebootTime("24:00"))
+	assert.Error(t, ValidateRebootTime("2:00"))
+	assert.Error(t, ValidateRebootTime("now"))
+}
+
+func TestValidateEmail(t *testing.T) {
+	assert.NoError(t, ValidateEmail(""))
+	assert.NoError(t, ValidateEmail("ops@example.com"))
+	assert.NoError(t, ValidateEmail("ops+alerts@mail.example.com"))
+	assert.Error(t, ValidateEmail("ops"))
+	assert.Error(t, ValidateEmail("Ops <ops@example.com>"))
+	assert.Error(t, ValidateEmail("ops@example.com, root@example.com"))
+	// 引号与换行会注入 Unattended-Upgrade::Mail "..."; 或 dnf-automatic 的 email_to
+	assert.Error(t, ValidateEmail(`ops@example.com"; Unattended-Upgrade::Automatic-Reboot "true`))
+	assert.Error(t, ValidateEmail("\"ops\"@example.com"))
+	assert.Error(t, ValidateEmail("ops@example.com\nemit_via = command"))
+
+	mgr := NewManager(&system.DistroInfo{ID: "debian", Family: system.Debian}, true, internal.NewLogger(internal.ERROR, os.Stdout))
+	require.ErrorContains(t, mgr.Enable(Options{Email: "ops@example.com\"\n"}), "invalid email address")
+}
+
+func TestRenderUnattendedUpgradesDebian(t *testing.T) {
+	content, err := RenderUnattendedUpgrades(&system.DistroInfo{ID: "debian", Family: system.Debian}, Options{AutoReboot: true, RebootTime: "03:30", Email: "ops@example.com"})
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "Unattended-Upgrade::Origins-Pattern {")
+	assert.Contains(t, content, "label=Debian-Security")
+	assert.Equal(t, "true", aptConfigValue(content, "Unattended-Upgrade::Automatic-Reboot"))
+	assert.Equal(t, "03:30", aptConfigValue(content, "Unattended-Upgrade::Automatic-Reboot-Time"))
+	assert.Equal(t, "ops@example.com", aptConfigValue(content, "Unattended-Upgrade::Mail"))
+}
+
+func TestRenderUnattendedUpgradesUbuntuWithoutReboot(t *testing.T) {
+	content, err := RenderUnattendedUpgrades(&system.DistroInfo{ID: "ubuntu", IDLike: "debian", Family: system.Debian}, Options{})
+	require.NoError(t, err)
+
+	assert.Contains(t, content, "Unattended-Upgrade::Allowed-Origins {")
+	assert.Contains(t, content, "${distro_codename}-security")
+	assert.Equal(t, "false", aptConfigValue(content, "Unattended-Upgrade::Automatic-Reboot"))
+	assert.Empty(t, aptConfigValue(content, "Unattended-Upgrade::Mail"))
+}
+
+func TestRenderUnattendedUpgradesDerivatives(t *testing.T) {
+	mint := &system.DistroInfo{ID: "linuxmint", IDLike: "ubuntu debian", Family: system.Debian}
+	content, err := RenderUnattendedUpgrades(mint, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, content, "origin=Ubuntu,archive=*-security")
+	assert.NotContains(t, content, "${distro_id}")
+	assert.NotContains(t, content, "Debian-Security")
+
+	for _, id := range []string{"kali", "raspbian", "devuan"} {
+		_, err := RenderUnattendedUpgrades(&system.DistroInfo{ID: id, IDLike: "debian", Family: system.Debian}, Options{})
+		assert.Error(t, err, id)
+	}
+}
+
+func TestRenderAutoUpgrades(t *testing.T) {
+	content := RenderAutoUpgrades()
+	assert.Equal(t, "1", aptConfigValue(content, "APT::Periodic::Update-Package-Lists"))
+	assert.Equal(t, "1", aptConfigValue(content, "APT::Periodic::Unattended-Upgrade"))
+}
+
+func TestPatchDnfAutomaticKeepsExistingLayout(t *testing.T) {
+	original := `[commands]
+# What kind of upgrade to perform
+upgrade_type = default
+random_sleep = 0
+download_updates = yes
+apply_updates = no
+
+[emitters]
+emit_via = stdio
+
+[email]
+email_from = root@example.com
+`
+	patched := PatchDnfAutomatic(original, Options{AutoReboot: true, RebootTime: "02:00", Email: "ops@example.com"})
+
+	assert.Equal(t, "security", iniValue(patched, "commands", "upgrade_type"))
+	assert.Equal(t, "yes", iniValue(patched, "commands", "apply_updates"))
+	assert.Equal(t, "0", iniValue(patched, "commands", "random_sleep"))
+	assert.Equal(t, "when-needed", iniValue(patched, "commands", "reboot"))
+	assert.Equal(t, "02:00", rebootTimeFromCommand(iniValue(patched, "commands", "reboot_command")))
+	assert.Equal(t, "stdio,email", iniValue(patched, "emitters", "emit_via"))
+	assert.Equal(t, "ops@example.com", iniValue(patched, "email", "email_to"))
+	assert.Equal(t, "root@example.com", iniValue(patched, "email", "email_from"))
+	assert.Contains(t, patched, "# What kind of upgrade to perform")
+}
+
+func TestPatchDnfAutomaticFromEmptyIsIdempotent(t *testing.T) {
+	opts := Options{}
+	first := PatchDnfAutomatic("", opts)
+	second := PatchDnfAutomatic(first, opts)
+
+	require.Equal(t, first, second)
+	assert.Equal(t, "yes", iniValue(first, "commands", "apply_updates"))
+	assert.Equal(t, "never", iniValue(first, "commands", "reboot"))
+	assert.Equal(t, "stdio", iniValue(first, "emitters", "emit_via"))
+}
@@ -0,0 +1,416 @@
+package cloudinit
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+var (
+	cloudCfgFile       = "/etc/cloud/cloud.cfg"
+	cloudCfgDir        = "/etc/cloud/cloud.cfg.d"
+	disabledFile       = "/etc/cloud/cloud-init.disabled"
+	instanceDatasource = "/var/lib/cloud/instance/datasource"
+	sshdCloudInitConf  = "/etc/ssh/sshd_config.d/50-cloud-init.conf"
+)
+
+// 各阶段的模块列表键（按执行顺序）
+var moduleStages = []string{"cloud_init_modules", "cloud_config_modules", "cloud_final_modules"}
+
+// moduleFrequencies 常见模块的默认执行频率（未在配置中显式指定时）
+var moduleFrequencies = map[string]string{
+	"set_hostname":     FrequencyInstance,
+	"update_hostname":  FrequencyAlways,
+	"update_etc_hosts": FrequencyAlways,
+	"ssh":              FrequencyInstance,
+	"set_passwords":    FrequencyInstance,
+	"users_groups":     FrequencyInstance,
+	"write_files":      FrequencyInstance,
+}
+
+// 模块执行频率
+const (
+	FrequencyAlways   = "always"
+	FrequencyInstance = "once-per-instance"
+)
+
+// Module 将在启动时执行的 cloud-init 模块
+type Module struct {
+	Stage     string
+	Name      string
+	Frequency string // always / once-per-instance / once；未知时为空
+}
+
+// 警告类别（TUI 据此选择文案）
+const (
+	WarnHostname = "hostname"
+	WarnEtcHosts = "etc_hosts"
+	WarnSSHKeys  = "ssh_keys"
+	WarnPwauth   = "ssh_pwauth"
+)
+
+// Warning cloud-init 可能撤销本工具所做修改的情况
+type Warning struct {
+	Kind   string
+	Module string
+	Detail string
+	// NextInstance 模块按实例执行：只在 instance-id 变化（重新制作镜像、克隆）后的首次启动生效，普通重启不受影响
+	NextInstance bool
+}
+
+// perInstance 模块是否仅在新实例上执行（未知频率按每次启动处理）
+func perInstance(mod Module) bool {
+	return mod.Frequency != "" && mod.Frequency != FrequencyAlways
+}
+
+// Report cloud-init 状态与配置检查结果
+type Report struct {
+	Present    bool
+	Disabled   bool // 存在 /etc/cloud/cloud-init.disabled
+	Status     string
+	Detail     string
+	Datasource string
+	Errors     []string
+
+	ConfigFiles []string
+	Config      map[string]any // cloud.cfg 与 cloud.cfg.d/*.cfg 合并后的配置
+	Modules     []Module
+	Warnings    []Warning
+}
+
+// Manager cloud-init 管理器
+type Manager struct {
+	dryRun bool
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewManager 创建 cloud-init 管理器
+func NewManager(dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "cloudinit"})
+	return &Manager{
+		dryRun: dryRun,
+		logger: logger,
+		drm:    internal.NewDryRunManager(dryRun, logger),
+	}
+}
+
+// Inspect 读取 cloud-init 状态、数据源、合并配置与下次启动将执行的模块（只读）
+func (m *Manager) Inspect() (*Report, error) {
+	report := &Report{
+		Present:  hostname.IsPresent(),
+		Disabled: system.FileExists(system.RootPath(disabledFile)),
+	}
+	if !report.Present {
+		return report, nil
+	}
+
+	// 离线根目录下没有运行时状态
+	if !system.HasRoot() {
+		res, err := system.QueryCommand("cloud-init", "status", "--long")
+		// 出错时 cloud-init status 以非零退出，但仍输出状态
+		if res != nil && strings.TrimSpace(res.Stdout) != "" {
+			ParseStatus(res.Stdout, report)
+		} else if err != nil {
+			m.logger.Warn("cloud-init status failed: %v", err)
+		}
+	}
+	if report.Datasource == "" {
+		if data, err := os.ReadFile(system.RootPath(instanceDatasource)); err == nil {
+			report.Datasource = datasourceName(strings.TrimSpace(string(data)))
+		}
+	}
+
+	files, err := configFiles()
+	if err != nil {
+		return nil, err
+	}
+	report.ConfigFiles = files
+	report.Config, err = LoadConfig(files)
+	if err != nil {
+		return nil, err
+	}
+	report.Modules = ParseModules(report.Config)
+
+	sshdConfig, _ := os.ReadFile(ssh.DefaultConfigPath())
+	cloudInitSSHD, _ := os.ReadFile(system.RootPath(sshdCloudInitConf))
+	report.Warnings = Analyze(report.Config, report.Modules, string(sshdConfig), string(cloudInitSSHD))
+	return report, nil
+}
+
+// configFiles 返回 cloud.cfg 及按文件名排序的 cloud.cfg.d/*.cfg（cloud-init 的合并顺序）
+func configFiles() ([]string, error) {
+	var files []string
+	if path := system.RootPath(cloudCfgFile); system.FileExists(path) {
+		files = append(files, path)
+	}
+	matches, err := filepath.Glob(filepath.Join(system.RootPath(cloudCfgDir), "*.cfg"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", cloudCfgDir, err)
+	}
+	sort.Strings(matches)
+	return append(files, matches...), nil
+}
+
+// LoadConfig 依次读取并合并配置文件（后者覆盖前者，字典递归合并，列表整体替换）
+func LoadConfig(files []string) (map[string]any, error) {
+	merged := make(map[string]any)
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var doc map[string]any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		mergeConfig(merged, doc)
+	}
+	return merged, nil
+}
+
+func mergeConfig(dst, src map[string]any) {
+	for key, value := range src {
+		if srcMap, ok := value.(map[string]any); ok {
+			if dstMap, ok := dst[key].(map[string]any); ok {
+				mergeConfig(dstMap, srcMap)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// ParseStatus 解析 `cloud-init status --long` 输出
+func ParseStatus(output string, report *Report) {
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// 较旧版本把 detail 的值放在下一行；errors 可能是多行列表
+		switch {
+		case section == "detail":
+			report.Detail = line
+			section = ""
+			continue
+		case section == "errors" && strings.HasPrefix(line, "- "):
+			report.Errors = append(report.Errors, strings.TrimPrefix(line, "- "))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		section = ""
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "status":
+			report.Status = value
+		case "detail":
+			report.Detail = value
+			if value == "" {
+				section = "detail"
+			}
+		case "datasource":
+			report.Datasource = value
+		case "errors":
+			if value == "" {
+				section = "errors"
+			} else if value != "[]" {
+				report.Errors = append(report.Errors, strings.Trim(value, "[]"))
+			}
+		}
+	}
+	if report.Datasource == "" && strings.HasPrefix(report.Detail, "DataSource") {
+		report.Datasource = datasourceName(report.Detail)
+	}
+}
+
+// datasourceName 把 "DataSourceNoCloud [seed=...]" 转换为 "nocloud"
+func datasourceName(detail string) string {
+	name := strings.Fields(detail)
+	if len(name) == 0 {
+		return ""
+	}
+	first, _, _ := strings.Cut(name[0], ":")
+	first = strings.TrimPrefix(first, "DataSource")
+	first = strings.TrimSuffix(first, "Local")
+	return strings.ToLower(first)
+}
+
+// ParseModules 从合并配置中提取各阶段的模块列表
+func ParseModules(cfg map[string]any) []Module {
+	var modules []Module
+	for _, stage := range moduleStages {
+		items, _ := cfg[stage].([]any)
+		for _, item := range items {
+			var mod Module
+			switch v := item.(type) {
+			case string:
+				mod.Name = v
+			case []any:
+				if len(v) == 0 {
+					continue
+				}
+				mod.Name, _ = v[0].(string)
+				if len(v) > 1 {
+					mod.Frequency, _ = v[1].(string)
+				}
+			default:
+				continue
+			}
+			if mod.Name == "" {
+				continue
+			}
+			mod.Stage = stage
+			mod.Name = normalizeModuleName(mod.Name)
+			if mod.Frequency == "" {
+				mod.Frequency = moduleFrequencies[mod.Name]
+			}
+			modules = append(modules, mod)
+		}
+	}
+	return modules
+}
+
+func normalizeModuleName(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "cc_")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Analyze 检查 cloud-init 是否会在启动时撤销本工具的修改
+func Analyze(cfg map[string]any, modules []Module, sshdConfig, cloudInitSSHD string) []Warning {
+	enabled := make(map[string]Module)
+	for _, mod := range modules {
+		enabled[mod.Name] = mod
+	}
+
+	var warnings []Warning
+
+	if !configBool(cfg, "preserve_hostname", false) {
+		for _, name := range []string{"update_hostname", "set_hostname"} {
+			if mod, ok := enabled[name]; ok {
+				warnings = append(warnings, Warning{Kind: WarnHostname, Module: mod.Name, Detail: "preserve_hostname: false", NextInstance: perInstance(mod)})
+				break
+			}
+		}
+	}
+
+	if mod, ok := enabled["update_etc_hosts"]; ok {
+		if v := configString(cfg, "manage_etc_hosts"); v == "true" || v == "template" || v == "localhost" {
+			warnings = append(warnings, Warning{Kind: WarnEtcHosts, Module: mod.Name, Detail: "manage_etc_hosts: " + v, NextInstance: perInstance(mod)})
+		}
+	}
+
+	if mod, ok := enabled["ssh"]; ok && configBool(cfg, "ssh_deletekeys", true) {
+		warnings = append(warnings, Warning{Kind: WarnSSHKeys, Module: mod.Name, Detail: "ssh_deletekeys: true", NextInstance: perInstance(mod)})
+	}
+
+	// 本工具已禁用密码登录，但 cloud-init 会（或已经）通过 ssh_pwauth 重新启用
+	pwauth := configString(cfg, "ssh_pwauth")
+	passwordDisabled := false
+	if v, ok := ssh.GlobalOption(sshdConfig, "PasswordAuthentication"); ok {
+		passwordDisabled = strings.EqualFold(v, "no")
+	}
+	dropInEnables := false
+	if v, ok := ssh.GlobalOption(cloudInitSSHD, "PasswordAuthentication"); ok {
+		dropInEnables = strings.EqualFold(v, "yes")
+	}
+	if passwordDisabled || dropInEnables {
+		if mod, ok := enabled["set_passwords"]; ok && pwauth == "true" {
+			// 已写入的 drop-in 立即生效，不等到下一个实例
+			warnings = append(warnings, Warning{Kind: WarnPwauth, Module: "set_passwords", Detail: "ssh_pwauth: true", NextInstance: perInstance(mod) && !dropInEnables})
+		} else if dropInEnables {
+			warnings = append(warnings, Warning{Kind: WarnPwauth, Module: "set_passwords", Detail: sshdCloudInitConf})
+		}
+	}
+
+	return warnings
+}
+
+// configString 以字符串形式返回顶层配置值（布尔值转为 "true"/"false"）
+func configString(cfg map[string]any, key string) string {
+	switch v := cfg[key].(type) {
+	case string:
+		return strings.ToLower(strings.TrimSpace(v))
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func configBool(cfg map[string]any, key string, def bool) bool {
+	switch configString(cfg, key) {
+	case "true", "yes", "on", "1":
+		return true
+	case "false", "no", "off", "0":
+		return false
+	default:
+		return def
+	}
+}
+
+// RenderConfig 把合并配置格式化为 YAML 便于查看
+func RenderConfig(cfg map[string]any) (string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	return string(data), nil
+}
+
+// Disable 创建 /etc/cloud/cloud-init.disabled，之后启动时 cloud-init 不再运行
+func (m *Manager) Disable() error {
+	path := system.RootPath(disabledFile)
+	content := "# written by server-toolkit: cloud-init is disabled while this file exists\n"
+	if m.dryRun {
+		m.drm.LogFileWrite(path, content)
+		return nil
+	}
+
+	if err := system.SafeWrite(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	m.logger.Info("Disabled cloud-init: %s", path)
+	return nil
+}
+
+// Enable 删除 /etc/cloud/cloud-init.disabled
+func (m *Manager) Enable() error {
+	path := system.RootPath(disabledFile)
+	if !system.FileExists(path) {
+		return nil
+	}
+	if m.dryRun {
+		m.drm.LogFileOperation("Remove file", path)
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	m.logger.Info("Enabled cloud-init: removed %s", path)
+	return nil
+}
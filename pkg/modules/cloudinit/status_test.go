@@ -0,0 +1,127 @@
+package cloudinit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatus(t *testing.T) {
+	t.Run("legacy detail on next line", func(t *testing.T) {
+		out := "status: done\ntime: Thu, 01 Feb 2024 10:00:00 +0000\ndetail:\nDataSourceNoCloud [seed=/dev/sr0][dsmode=net]\n"
+		var r Report
+		ParseStatus(out, &r)
+		assert.Equal(t, "done", r.Status)
+		assert.Equal(t, "DataSourceNoCloud [seed=/dev/sr0][dsmode=net]", r.Detail)
+		assert.Equal(t, "nocloud", r.Datasource)
+		assert.Empty(t, r.Errors)
+	})
+
+	t.Run("extended status with errors", func(t *testing.T) {
+		out := "status: error\nextended_status: error - done\nboot_status_code: enabled-by-generator\n" +
+			"detail: DataSourceEc2Local\nerrors:\n\t- ('ssh', KeyError('x'))\nrecoverable_errors: {}\n"
+		var r Report
+		ParseStatus(out, &r)
+		assert.Equal(t, "error", r.Status)
+		assert.Equal(t, "ec2", r.Datasource)
+		assert.Equal(t, []string{"('ssh', KeyError('x'))"}, r.Errors)
+	})
+}
+
+func TestLoadConfigMergesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "cloud.cfg")
+	override := filepath.Join(dir, "99-override.cfg")
+	require.NoError(t, os.WriteFile(base, []byte(`
+preserve_hostname: false
+manage_etc_hosts: true
+system_info:
+  default_user:
+    name: debian
+    shell: /bin/bash
+cloud_init_modules:
+  - set_hostname
+  - update-etc-hosts
+  - [ssh, always]
+`), 0644))
+	require.NoError(t, os.WriteFile(override, []byte(`
+preserve_hostname: true
+system_info:
+  default_user:
+    name: admin
+`), 0644))
+
+	cfg, err := LoadConfig([]string{base, override})
+	require.NoError(t, err)
+	assert.Equal(t, true, cfg["preserve_hostname"])
+	user := cfg["system_info"].(map[string]any)["default_user"].(map[string]any)
+	assert.Equal(t, "admin", user["name"])
+	assert.Equal(t, "/bin/bash", user["shell"])
+
+	modules := ParseModules(cfg)
+	require.Len(t, modules, 3)
+	assert.Equal(t, Module{Stage: "cloud_init_modules", Name: "update_etc_hosts", Frequency: FrequencyAlways}, modules[1])
+	assert.Equal(t, "always", modules[2].Frequency)
+}
+
+func TestAnalyze(t *testing.T) {
+	modules := []Module{{Name: "update_hostname"}, {Name: "update_etc_hosts"}, {Name: "ssh"}, {Name: "set_passwords"}}
+	cfg := map[string]any{
+		"preserve_hostname": false,
+		"manage_etc_hosts":  true,
+		"ssh_pwauth":        true,
+	}
+
+	warnings := Analyze(cfg, modules, "PasswordAuthentication no\n", "")
+	kinds := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		kinds = append(kinds, w.Kind)
+	}
+	assert.Equal(t, []string{WarnHostname, WarnEtcHosts, WarnSSHKeys, WarnPwauth}, kinds)
+
+	cfg = map[string]any{"preserve_hostname": true, "manage_etc_hosts": false, "ssh_deletekeys": false}
+	assert.Empty(t, Analyze(cfg, modules, "PasswordAuthentication no\n", ""))
+
+	// cloud-init 已写入的 drop-in 会覆盖 sshd_config 中的设置
+	warnings = Analyze(cfg, nil, "", "PasswordAuthentication yes\n")
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarnPwauth, warnings[0].Kind)
+	assert.False(t, warnings[0].NextInstance)
+
+	// 按实例执行的模块只在 instance-id 变化后生效；每次启动执行的模块优先
+	modules = ParseModules(map[string]any{
+		"cloud_init_modules":   []any{"set_hostname", "update_hostname", "ssh"},
+		"cloud_config_modules": []any{[]any{"set_passwords", "always"}},
+	})
+	cfg = map[string]any{"ssh_pwauth": true}
+	warnings = Analyze(cfg, modules, "PasswordAuthentication no\n", "")
+	require.Len(t, warnings, 3)
+	assert.Equal(t, Warning{Kind: WarnHostname, Module: "update_hostname", Detail: "preserve_hostname: false"}, warnings[0])
+	assert.Equal(t, WarnSSHKeys, warnings[1].Kind)
+	assert.True(t, warnings[1].NextInstance)
+	assert.Equal(t, WarnPwauth, warnings[2].Kind)
+	assert.False(t, warnings[2].NextInstance)
+
+	modules = ParseModules(map[string]any{"cloud_init_modules": []any{"set_hostname"}})
+	warnings = Analyze(cfg, modules, "", "")
+	require.Len(t, warnings, 1)
+	assert.True(t, warnings[0].NextInstance)
+}
+
+func TestDisableEnable(t *testing.T) {
+	oldDisabled := disabledFile
+	disabledFile = filepath.Join(t.TempDir(), "cloud-init.disabled")
+	t.Cleanup(func() { disabledFile = oldDisabled })
+
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+	require.NoError(t, mgr.Disable())
+	assert.FileExists(t, disabledFile)
+
+	require.NoError(t, mgr.Enable())
+	assert.NoFileExists(t, disabledFile)
+	require.NoError(t, mgr.Enable())
+}
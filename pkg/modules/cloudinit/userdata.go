@@ -0,0 +1,304 @@
+package cloudinit
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+const (
+	// cloudConfigHeader user-data 必须以此行开头，cloud-init 才会按 cloud-config 解析
+	cloudConfigHeader = "#cloud-config"
+
+	// sshdDropInPath sshd 选项以 drop-in 方式写入（需 OpenSSH 8.2+ 的 Include sshd_config.d）。
+	// sshd 采用首次读到的取值，文件名须排在云镜像的 50-cloudimg-settings.conf 与 50-cloud-init.conf 之前
+	sshdDropInPath = "/etc/ssh/sshd_config.d/01-server-toolkit.conf"
+
+	defaultShell = "/bin/bash"
+	sudoNoPasswd = "ALL=(ALL) NOPASSWD:ALL"
+)
+
+var (
+	userNameRegex   = regexp.MustCompile(`^[a-z_][a-z0-9_-]*\$?$`)
+	sshdOptionRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+)
+
+// exportedSSHDOptions 从当前 sshd 生效配置导出的选项（PasswordAuthentication 由 ssh_pwauth 表达）
+var exportedSSHDOptions = []string{
+	"PermitRootLogin",
+	"PubkeyAuthentication",
+	"KbdInteractiveAuthentication",
+	"ChallengeResponseAuthentication",
+}
+
+// User 需要在新机器上创建的用户
+type User struct {
+	Name           string
+	Sudo           bool // 免密 sudo
+	Shell          string
+	Groups         []string
+	AuthorizedKeys []string
+}
+
+// SSHDOption sshd_config 选项（有序，Port 等可重复出现）
+type SSHDOption struct {
+	Key   string
+	Value string
+}
+
+// Spec 导出为 user-data 的期望配置
+type Spec struct {
+	Hostname        string // 短主机名
+	FQDN            string // 可选
+	ManageEtcHosts  bool
+	KeepDefaultUser bool // 保留发行版默认用户（ubuntu/debian 等）
+	Users           []User
+	PasswordAuth    bool
+	SSHDOptions     []SSHDOption
+}
+
+// UserData #cloud-config 文档结构（字段顺序即输出顺序）
+type UserData struct {
+	Hostname       string      `yaml:"hostname,omitempty"`
+	FQDN           string      `yaml:"fqdn,omitempty"`
+	ManageEtcHosts bool        `yaml:"manage_etc_hosts"`
+	Users          []any       `yaml:"users,omitempty"`
+	DisableRoot    *bool       `yaml:"disable_root,omitempty"`
+	SSHPwauth      bool        `yaml:"ssh_pwauth"`
+	WriteFiles     []WriteFile `yaml:"write_files,omitempty"`
+}
+
+// UserEntry users 列表中的用户项
+type UserEntry struct {
+	Name              string   `yaml:"name"`
+	Groups            []string `yaml:"groups,omitempty"`
+	Sudo              string   `yaml:"sudo,omitempty"`
+	Shell             string   `yaml:"shell,omitempty"`
+	LockPasswd        bool     `yaml:"lock_passwd"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys,omitempty"`
+}
+
+// WriteFile write_files 列表项
+type WriteFile struct {
+	Path        string `yaml:"path"`
+	Content     string `yaml:"content"`
+	Owner       string `yaml:"owner,omitempty"`
+	Permissions string `yaml:"permissions,omitempty"`
+}
+
+// Validate 校验配置（主机名、用户名、公钥、sshd 选项）
+func (s *Spec) Validate() error {
+	if s.Hostname != "" {
+		if err := hostname.ValidateHostname(s.Hostname); err != nil {
+			return err
+		}
+		if hostname.IsFQDN(s.Hostname) {
+			return fmt.Errorf("hostname must be a short name: %s", s.Hostname)
+		}
+	}
+	if s.FQDN != "" {
+		if err := hostname.ValidateFQDN(s.FQDN); err != nil {
+			return err
+		}
+		if s.Hostname != "" && hostname.GetShortHostname(s.FQDN) != s.Hostname {
+			return fmt.Errorf("FQDN %s does not start with hostname %s", s.FQDN, s.Hostname)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, u := range s.Users {
+		if !userNameRegex.MatchString(u.Name) {
+			return fmt.Errorf("invalid user name: %q", u.Name)
+		}
+		if seen[u.Name] {
+			return fmt.Errorf("duplicate user: %s", u.Name)
+		}
+		seen[u.Name] = true
+		for _, key := range u.AuthorizedKeys {
+			if err := ssh.ValidateKey(key); err != nil {
+				return fmt.Errorf("user %s: %w", u.Name, err)
+			}
+		}
+	}
+
+	for _, opt := range s.SSHDOptions {
+		if !sshdOptionRegex.MatchString(opt.Key) {
+			return fmt.Errorf("invalid sshd option: %q", opt.Key)
+		}
+		if strings.EqualFold(opt.Key, "PasswordAuthentication") {
+			return fmt.Errorf("PasswordAuthentication is controlled by PasswordAuth")
+		}
+		if strings.TrimSpace(opt.Value) == "" || strings.ContainsAny(opt.Value, "\r\n") {
+			return fmt.Errorf("invalid value for sshd option %s", opt.Key)
+		}
+	}
+	return nil
+}
+
+// Build 把 Spec 转换为 cloud-config 文档结构
+func Build(spec *Spec) (*UserData, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	ud := &UserData{
+		Hostname:       spec.Hostname,
+		FQDN:           spec.FQDN,
+		ManageEtcHosts: spec.ManageEtcHosts,
+		SSHPwauth:      spec.PasswordAuth,
+	}
+
+	if spec.KeepDefaultUser && len(spec.Users) > 0 {
+		ud.Users = append(ud.Users, "default")
+	}
+	for _, u := range spec.Users {
+		entry := UserEntry{
+			Name:              u.Name,
+			Groups:            u.Groups,
+			Shell:             u.Shell,
+			LockPasswd:        true,
+			SSHAuthorizedKeys: u.AuthorizedKeys,
+		}
+		if u.Sudo {
+			entry.Sudo = sudoNoPasswd
+		}
+		if u.Name == "root" {
+			// disable_root 默认为 true，会让 root 的 authorized_keys 失效
+			disableRoot := false
+			ud.DisableRoot = &disableRoot
+		}
+		ud.Users = append(ud.Users, entry)
+	}
+
+	if len(spec.SSHDOptions) > 0 {
+		var b strings.Builder
+		b.WriteString("# written by server-toolkit\n")
+		for _, opt := range spec.SSHDOptions {
+			fmt.Fprintf(&b, "%s %s\n", opt.Key, opt.Value)
+		}
+		ud.WriteFiles = append(ud.WriteFiles, WriteFile{
+			Path:        sshdDropInPath,
+			Content:     b.String(),
+			Owner:       "root:root",
+			Permissions: "0644",
+		})
+	}
+
+	return ud, nil
+}
+
+// Render 生成 #cloud-config user-data 文档
+func Render(spec *Spec) ([]byte, error) {
+	ud, err := Build(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(cloudConfigHeader + "\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(ud); err != nil {
+		return nil, fmt.Errorf("failed to encode user-data: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode user-data: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Collect 从当前系统收集主机名、用户公钥与 sshd 生效选项（含 sshd_config.d），生成可复用的 Spec
+func Collect(user string, logger *internal.Logger) (*Spec, error) {
+	spec := &Spec{ManageEtcHosts: true, PasswordAuth: true}
+
+	name, err := hostname.NewManager(true, logger).GetHostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+	name = hostname.NormalizeHostname(name)
+	spec.Hostname = hostname.GetShortHostname(name)
+	if hostname.IsFQDN(name) {
+		spec.FQDN = name
+	} else {
+		spec.FQDN = hostname.FQDNFromHosts(spec.Hostname)
+	}
+
+	if user != "" {
+		keys, err := ssh.NewManager(user, true, logger).List()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list keys for %s: %w", user, err)
+		}
+		u := User{Name: user, AuthorizedKeys: validKeys(keys)}
+		if user != "root" {
+			u.Sudo = true
+			u.Shell = defaultShell
+		}
+		spec.Users = append(spec.Users, u)
+	}
+
+	// 按生效配置导出：云镜像常在 sshd_config.d（如 60-cloudimg-settings.conf）中覆盖主文件
+	settings, err := ssh.ReadEffectiveSettings(ssh.DefaultConfigPath())
+	if err != nil {
+		logger.Warn("Failed to read sshd configuration: %v", err)
+		return spec, nil
+	}
+
+	if v, ok := settings.Get("PasswordAuthentication"); ok {
+		spec.PasswordAuth = !strings.EqualFold(v, "no")
+	}
+	if ports := settings.Ports(); len(ports) != 1 || ports[0] != "22" {
+		for _, port := range ports {
+			spec.SSHDOptions = append(spec.SSHDOptions, SSHDOption{Key: "Port", Value: port})
+		}
+	}
+	for _, key := range exportedSSHDOptions {
+		if v, ok := settings.Get(key); ok {
+			spec.SSHDOptions = append(spec.SSHDOptions, SSHDOption{Key: key, Value: v})
+		}
+	}
+
+	return spec, nil
+}
+
+// validKeys 过滤掉 authorized_keys 中的注释行与无效行（如带 options 前缀的密钥）
+func validKeys(keys []string) []string {
+	var out []string
+	for _, key := range keys {
+		if ssh.ValidateKey(key) == nil {
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// WriteUserData 把 user-data 写入文件（已存在时先备份）
+func WriteUserData(path string, data []byte, dryRun bool, logger *internal.Logger) error {
+	if dryRun {
+		internal.NewDryRunManager(dryRun, logger).LogFileWrite(path, string(data))
+		return nil
+	}
+
+	backupPath, err := system.BackupFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to backup %s: %w", path, err)
+	}
+	if backupPath != "" {
+		logger.Info("Backed up: %s -> %s", path, backupPath)
+	}
+
+	// user-data 可能包含公钥与用户信息，仅 owner 可读
+	if err := system.SafeWrite(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	logger.Info("Written cloud-init user-data to %s", path)
+	return nil
+}
@@ -0,0 +1,218 @@
+package cloudinit
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBfV2x0Ihl5Q7b6l9J1d0qk4gNw3Yt0h3m6nK2cYVx1a alice@laptop"
+
+// cloudConfigSchema cloud-init schema 中本工具会输出的顶层键及其类型
+var cloudConfigSchema = map[string]string{
+	"hostname":         "string",
+	"fqdn":             "string",
+	"manage_etc_hosts": "bool",
+	"users":            "list",
+	"disable_root":     "bool",
+	"ssh_pwauth":       "bool",
+	"write_files":      "list",
+}
+
+// userSchema cloud-init users 项允许的键及类型
+var userSchema = map[string]string{
+	"name":                "string",
+	"groups":              "list",
+	"sudo":                "string",
+	"shell":               "string",
+	"lock_passwd":         "bool",
+	"ssh_authorized_keys": "list",
+}
+
+// writeFileSchema cloud-init write_files 项允许的键及类型
+var writeFileSchema = map[string]string{
+	"path":        "string",
+	"content":     "string",
+	"owner":       "string",
+	"permissions": "string",
+}
+
+func yamlType(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+func assertMatchesSchema(t *testing.T, schema map[string]string, doc map[string]any, where string) {
+	t.Helper()
+	for key, value := range doc {
+		want, ok := schema[key]
+		if assert.Truef(t, ok, "%s: unknown key %q", where, key) {
+			assert.Equalf(t, want, yamlType(value), "%s: key %q", where, key)
+		}
+	}
+}
+
+func validateCloudConfig(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	require.True(t, strings.HasPrefix(string(data), "#cloud-config\n"), "missing #cloud-config header")
+
+	var doc map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+	assertMatchesSchema(t, cloudConfigSchema, doc, "top-level")
+
+	users, _ := doc["users"].([]any)
+	for _, item := range users {
+		switch u := item.(type) {
+		case string:
+			assert.Equal(t, "default", u)
+		case map[string]any:
+			require.Contains(t, u, "name")
+			assertMatchesSchema(t, userSchema, u, "users")
+		default:
+			t.Errorf("users: unexpected item %T", item)
+		}
+	}
+
+	files, _ := doc["write_files"].([]any)
+	for _, item := range files {
+		f, ok := item.(map[string]any)
+		require.True(t, ok, "write_files item must be a mapping")
+		require.Contains(t, f, "path")
+		assertMatchesSchema(t, writeFileSchema, f, "write_files")
+		if perm, ok := f["permissions"].(string); ok {
+			assert.Regexp(t, regexp.MustCompile(`^0[0-7]{3}$`), perm)
+		}
+	}
+	return doc
+}
+
+func TestRenderUserData(t *testing.T) {
+	spec := &Spec{
+		Hostname:       "web-01",
+		FQDN:           "web-01.example.com",
+		ManageEtcHosts: true,
+		Users: []User{
+			{Name: "alice", Sudo: true, Shell: "/bin/bash", Groups: []string{"adm"}, AuthorizedKeys: []string{testKey}},
+		},
+		PasswordAuth: false,
+		SSHDOptions: []SSHDOption{
+			{Key: "Port", Value: "2222"},
+			{Key: "PermitRootLogin", Value: "prohibit-password"},
+		},
+	}
+
+	data, err := Render(spec)
+	require.NoError(t, err)
+	doc := validateCloudConfig(t, data)
+
+	assert.Equal(t, "web-01", doc["hostname"])
+	assert.Equal(t, "web-01.example.com", doc["fqdn"])
+	assert.Equal(t, true, doc["manage_etc_hosts"])
+	assert.Equal(t, false, doc["ssh_pwauth"])
+	assert.NotContains(t, doc, "disable_root")
+
+	users := doc["users"].([]any)
+	require.Len(t, users, 1)
+	alice := users[0].(map[string]any)
+	assert.Equal(t, "alice", alice["name"])
+	assert.Equal(t, "ALL=(ALL) NOPASSWD:ALL", alice["sudo"])
+	assert.Equal(t, []any{testKey}, alice["ssh_authorized_keys"])
+
+	files := doc["write_files"].([]any)
+	require.Len(t, files, 1)
+	dropIn := files[0].(map[string]any)
+	assert.Equal(t, "/etc/ssh/sshd_config.d/01-server-toolkit.conf", dropIn["path"])
+	assert.Contains(t, dropIn["content"], "Port 2222\nPermitRootLogin prohibit-password\n")
+}
+
+func TestRenderUserDataRootAndDefaultUser(t *testing.T) {
+	data, err := Render(&Spec{
+		Hostname:        "db",
+		KeepDefaultUser: true,
+		Users:           []User{{Name: "root", AuthorizedKeys: []string{testKey}}},
+		PasswordAuth:    true,
+	})
+	require.NoError(t, err)
+	doc := validateCloudConfig(t, data)
+
+	users := doc["users"].([]any)
+	require.Len(t, users, 2)
+	assert.Equal(t, "default", users[0])
+	assert.Equal(t, false, doc["disable_root"])
+	assert.NotContains(t, doc, "write_files")
+}
+
+func TestCollectUsesSSHDDropIns(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(path, content string) {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	writeFile("etc/hostname", "web-01\n")
+	writeFile("etc/ssh/sshd_config", "Include /etc/ssh/sshd_config.d/*.conf\nPasswordAuthentication yes\nPermitRootLogin yes\n")
+	// Ubuntu 云镜像通过 drop-in 禁用密码登录，优先于主文件
+	writeFile("etc/ssh/sshd_config.d/60-cloudimg-settings.conf", "PasswordAuthentication no\n")
+	writeFile("etc/ssh/sshd_config.d/10-port.conf", "Port 2222\nPermitRootLogin prohibit-password\n")
+	require.NoError(t, system.SetRoot(dir))
+	t.Cleanup(func() { _ = system.SetRoot("/") })
+
+	spec, err := Collect("", internal.NewLogger(internal.ERROR, os.Stdout))
+	require.NoError(t, err)
+	assert.Equal(t, "web-01", spec.Hostname)
+	assert.False(t, spec.PasswordAuth)
+	assert.Equal(t, []SSHDOption{
+		{Key: "Port", Value: "2222"},
+		{Key: "PermitRootLogin", Value: "prohibit-password"},
+	}, spec.SSHDOptions)
+}
+
+func TestSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+	}{
+		{"fqdn as hostname", Spec{Hostname: "web.example.com"}},
+		{"fqdn mismatch", Spec{Hostname: "web", FQDN: "db.example.com"}},
+		{"bad user", Spec{Users: []User{{Name: "Bad User"}}}},
+		{"duplicate user", Spec{Users: []User{{Name: "bob"}, {Name: "bob"}}}},
+		{"bad key", Spec{Users: []User{{Name: "bob", AuthorizedKeys: []string{"not-a-key"}}}}},
+		{"password option", Spec{SSHDOptions: []SSHDOption{{Key: "PasswordAuthentication", Value: "no"}}}},
+		{"option injection", Spec{SSHDOptions: []SSHDOption{{Key: "Port", Value: "22\nPermitRootLogin yes"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(&tt.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteUserDataMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user-data")
+	data, err := Render(&Spec{Hostname: "web"})
+	require.NoError(t, err)
+
+	require.NoError(t, WriteUserData(path, data, false, internal.NewLogger(internal.ERROR, os.Stdout)))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
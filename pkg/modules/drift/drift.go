@@ -0,0 +1,352 @@
+// Package drift 比较系统当前配置与本工具最近一次应用的期望值（见 pkg/state），并可重新应用
+package drift
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// Kind 漂移项类型
+type Kind string
+
+const (
+	KindHostname       Kind = "hostname"
+	KindHosts          Kind = "hosts"
+	KindSSHD           Kind = "sshd"
+	KindAuthorizedKeys Kind = "authorized_keys"
+)
+
+// Item 一处与期望值不一致的配置
+type Item struct {
+	Kind Kind   `json:"kind"`
+	Key  string `json:"key"`  // 主机名为 "static"，hosts 为 IP，sshd 为选项名，公钥为用户名
+	Path string `json:"path"` // 被比较的文件
+	Want string `json:"want"`
+	Have string `json:"have"` // 为空表示缺失
+}
+
+// Report 漂移检测结果
+type Report struct {
+	StatePath string    `json:"state_path"`
+	Recorded  bool      `json:"recorded"` // 是否存在已记录的状态
+	Updated   time.Time `json:"updated,omitempty"`
+	Items     []Item    `json:"items"`
+
+	state *state.State
+}
+
+// Manager 漂移检测与重新应用
+type Manager struct {
+	dryRun bool
+	logger *internal.Logger
+}
+
+// NewManager 创建漂移管理器
+func NewManager(dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "drift"})
+	return &Manager{dryRun: dryRun, logger: logger}
+}
+
+// Detect 读取状态文件并与当前配置比较
+func (m *Manager) Detect() (*Report, error) {
+	st, err := state.Load()
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{
+		StatePath: state.Path(),
+		Recorded:  !st.Updated.IsZero(),
+		Updated:   st.Updated,
+		Items:     []Item{},
+		state:     st,
+	}
+
+	if st.Hostname != nil {
+		if item, ok := detectHostname(st.Hostname); ok {
+			r.Items = append(r.Items, item)
+		}
+	}
+	if len(st.Hosts) > 0 {
+		items, err := detectHosts(st.Hosts)
+		if err != nil {
+			return nil, err
+		}
+		r.Items = append(r.Items, items...)
+	}
+	if len(st.SSHD) > 0 {
+		items, err := detectSSHD(st.SSHD)
+		if err != nil {
+			return nil, err
+		}
+		r.Items = append(r.Items, items...)
+	}
+	r.Items = append(r.Items, m.detectKeys(st.AuthorizedKeys)...)
+	return r, nil
+}
+
+func detectHostname(want *state.Hostname) (Item, bool) {
+	path := system.RootPath("/etc/hostname")
+	var have string
+	if data, err := os.ReadFile(path); err == nil {
+		have = strings.TrimSpace(string(data))
+	}
+	if strings.EqualFold(have, want.Short) {
+		return Item{}, false
+	}
+	return Item{Kind: KindHostname, Key: "static", Path: path, Want: want.Short, Have: have}, true
+}
+
+// detectHosts 每个期望条目的 IP 需要有一行包含全部主机名
+func detectHosts(want []state.HostsEntry) ([]Item, error) {
+	f, err := hostname.LoadHosts()
+	if err != nil {
+		return nil, err
+	}
+	entries := f.Entries()
+	path := system.RootPath("/etc/hosts")
+
+	var items []Item
+	for _, w := range want {
+		var have string
+		matched := false
+		for _, e := range entries {
+			if e.IP != w.IP {
+				continue
+			}
+			if have == "" {
+				have = strings.Join(e.Names(), " ")
+			}
+			if containsAllFold(e.Names(), w.Names) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			items = append(items, Item{Kind: KindHosts, Key: w.IP, Path: path, Want: strings.Join(w.Names, " "), Have: have})
+		}
+	}
+	return items, nil
+}
+
+// detectSSHD 比较 sshd_config 主文件中的全局选项（本工具写入的位置）
+func detectSSHD(want map[string]state.Option) ([]Item, error) {
+	path := ssh.DefaultConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(want))
+	for key := range want {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var items []Item
+	for _, key := range keys {
+		have, _ := ssh.GlobalOption(string(data), key)
+		if !strings.EqualFold(have, want[key].Value) {
+			items = append(items, Item{Kind: KindSSHD, Key: key, Path: path, Want: want[key].Value, Have: have})
+		}
+	}
+	return items, nil
+}
+
+// detectKeys 每个缺失的公钥生成一项（authorized_keys 中多出的公钥不算漂移）
+func (m *Manager) detectKeys(want map[string][]string) []Item {
+	users := make([]string, 0, len(want))
+	for user := range want {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var items []Item
+	for _, user := range users {
+		path := "authorized_keys"
+		var installed []string
+		if info, err := system.GetUser(user); err != nil {
+			m.logger.Warn("Drift: %v", err)
+		} else {
+			path = system.RootPath(info.HomeDir + "/.ssh/authorized_keys")
+			installed, err = ssh.NewManager(user, false, m.logger).List()
+			if err != nil {
+				m.logger.Warn("Drift: %v", err)
+			}
+		}
+
+		have := make(map[string]bool, len(installed))
+		for _, key := range installed {
+			have[keyBlob(key)] = true
+		}
+		for _, key := range want[user] {
+			if !have[keyBlob(key)] {
+				items = append(items, Item{Kind: KindAuthorizedKeys, Key: user, Path: path, Want: key})
+			}
+		}
+	}
+	return items
+}
+
+// keyBlob 返回公钥的 base64 数据部分（忽略选项与注释）；无法识别时返回整行
+func keyBlob(line string) string {
+	for _, field := range strings.Fields(line) {
+		// 公钥数据以 4 字节长度开头，base64 编码后总是以 AAAA 开头
+		if strings.HasPrefix(field, "AAAA") {
+			return field
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+func containsAllFold(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Reapply 把报告中的漂移项恢复为期望值
+func (m *Manager) Reapply(r *Report) error {
+	var hosts []Item
+	sshdOptions := make(map[string]string)
+	keys := make(map[string][]string)
+	var users []string
+
+	for _, item := range r.Items {
+		switch item.Kind {
+		case KindHostname:
+			want := r.state.Hostname
+			if err := hostname.NewManager(m.dryRun, m.logger).SetHostname(want.Short, want.FQDN); err != nil {
+				return err
+			}
+		case KindHosts:
+			hosts = append(hosts, item)
+		case KindSSHD:
+			sshdOptions[item.Key] = item.Want
+		case KindAuthorizedKeys:
+			if _, ok := keys[item.Key]; !ok {
+				users = append(users, item.Key)
+			}
+			keys[item.Key] = append(keys[item.Key], item.Want)
+		}
+	}
+
+	if len(hosts) > 0 {
+		if err := m.reapplyHosts(hosts); err != nil {
+			return err
+		}
+	}
+
+	if len(sshdOptions) > 0 {
+		cfg, err := ssh.NewConfig(ssh.DefaultConfigPath(), m.dryRun, m.logger)
+		if err != nil {
+			return err
+		}
+		if err := cfg.SetGlobalOptions(sshdOptions); err != nil {
+			return err
+		}
+		if system.HasRoot() {
+			m.logger.Info("Root %s set, skipping sshd reload", system.Root())
+		} else if err := ssh.ReloadSSHD(m.dryRun, m.logger); err != nil {
+			return err
+		}
+	}
+
+	for _, user := range users {
+		if _, err := ssh.NewManager(user, m.dryRun, m.logger).Install(keys[user], false); err != nil {
+			return fmt.Errorf("%s: %w", user, err)
+		}
+	}
+	return nil
+}
+
+// reapplyHosts 修改同 IP 的首个条目（保留注释），没有则追加
+func (m *Manager) reapplyHosts(items []Item) error {
+	f, err := hostname.LoadHosts()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		names := strings.Fields(item.Want)
+		entry := hostname.HostsEntry{IP: item.Key, Canonical: names[0], Aliases: names[1:]}
+
+		index := -1
+		for i, e := range f.Entries() {
+			if e.IP == item.Key {
+				index = i
+				entry.Comment = e.Comment
+				break
+			}
+		}
+		if index >= 0 {
+			err = f.Update(index, entry)
+		} else {
+			err = f.Add(entry)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Key, err)
+		}
+	}
+	return hostname.SaveHosts(f, m.dryRun, m.logger)
+}
+
+// WantLabel 期望值的显示文本（公钥只显示类型与注释）
+func (i Item) WantLabel() string {
+	if i.Kind != KindAuthorizedKeys {
+		return i.Want
+	}
+	fields := strings.Fields(i.Want)
+	for n, field := range fields {
+		if strings.HasPrefix(field, "AAAA") && n > 0 {
+			label := fields[n-1]
+			if n+1 < len(fields) {
+				label += " " + strings.Join(fields[n+1:], " ")
+			}
+			return label
+		}
+	}
+	return i.Want
+}
+
+// Text 以纯文本输出
+func (r *Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "State: %s", r.StatePath)
+	if r.Recorded {
+		fmt.Fprintf(&b, " (updated %s)", r.Updated.Format("2006-01-02 15:04:05 MST"))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case !r.Recorded:
+		b.WriteString("No applied state recorded yet.\n")
+	case len(r.Items) == 0:
+		b.WriteString("No drift detected.\n")
+	}
+	for _, item := range r.Items {
+		have := item.Have
+		if have == "" {
+			have = "(missing)"
+		}
+		fmt.Fprintf(&b, "[%s] %s\n    want: %s\n    have: %s\n    file: %s\n", item.Kind, item.Key, item.WantLabel(), have, item.Path)
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package drift
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	keyA = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl a@host"
+	keyB = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBKmYQ9aC2b8c0lR5wq3sF1dJzvQ0b3l7yEwGZ1xW9Fq b@host"
+)
+
+func TestDetectAndReapply(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	write("etc/hostname", "localhost\n")
+	write("etc/hosts", "127.0.0.1\tlocalhost\n127.0.1.1\tubuntu # installer\n")
+	write("etc/ssh/sshd_config", "PasswordAuthentication yes\nPubkeyAuthentication yes\n")
+	write("etc/passwd", "alice:x:1000:1000::/home/alice:/bin/bash\n")
+	// 选项与注释不同但公钥相同：不算漂移
+	write("home/alice/.ssh/authorized_keys", `no-pty `+keyA[:len(keyA)-7]+" renamed\n")
+
+	require.NoError(t, system.SetRoot(dir))
+	t.Cleanup(func() { _ = system.SetRoot("/") })
+	fake := system.NewFakeRunner()
+	fake.Paths = map[string]bool{}
+	oldRunner := system.SetRunner(fake)
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	require.NoError(t, state.Update(func(st *state.State) {
+		st.SetHostname("web-01", "web-01.example.com")
+		st.SetHostsEntry("127.0.1.1", []string{"web-01.example.com", "web-01"})
+		st.SetHostsEntry("10.0.0.5", []string{"db"})
+		st.SetSSHDOptions(map[string]string{"PasswordAuthentication": "no", "PubkeyAuthentication": "YES"})
+		st.AddAuthorizedKeys("alice", []string{keyA, keyB}, false)
+	}))
+	assert.FileExists(t, filepath.Join(dir, "var/lib/server-toolkit/state.json"))
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	m := NewManager(false, logger)
+	r, err := m.Detect()
+	require.NoError(t, err)
+	assert.True(t, r.Recorded)
+	assert.Equal(t, []Item{
+		{Kind: KindHostname, Key: "static", Path: filepath.Join(dir, "etc/hostname"), Want: "web-01", Have: "localhost"},
+		{Kind: KindHosts, Key: "127.0.1.1", Path: filepath.Join(dir, "etc/hosts"), Want: "web-01.example.com web-01", Have: "ubuntu"},
+		{Kind: KindHosts, Key: "10.0.0.5", Path: filepath.Join(dir, "etc/hosts"), Want: "db"},
+		{Kind: KindSSHD, Key: "PasswordAuthentication", Path: filepath.Join(dir, "etc/ssh/sshd_config"), Want: "no", Have: "yes"},
+		{Kind: KindAuthorizedKeys, Key: "alice", Path: filepath.Join(dir, "home/alice/.ssh/authorized_keys"), Want: keyB},
+	}, r.Items)
+
+	require.NoError(t, m.Reapply(r))
+
+	hosts, err := os.ReadFile(filepath.Join(dir, "etc/hosts"))
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1\tlocalhost\n127.0.1.1\tweb-01.example.com web-01 # installer\n10.0.0.5\tdb\n", string(hosts))
+
+	r, err = m.Detect()
+	require.NoError(t, err)
+	assert.Empty(t, r.Items)
+}
+
+func TestDetectWithoutState(t *testing.T) {
+	require.NoError(t, system.SetRoot(t.TempDir()))
+	t.Cleanup(func() { _ = system.SetRoot("/") })
+
+	r, err := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout)).Detect()
+	require.NoError(t, err)
+	assert.False(t, r.Recorded)
+	assert.Empty(t, r.Items)
+}
+
+func TestReportText(t *testing.T) {
+	r := &Report{StatePath: "/var/lib/server-toolkit/state.json", Recorded: true, Items: []Item{
+		{Kind: KindAuthorizedKeys, Key: "alice", Path: "/home/alice/.ssh/authorized_keys", Want: keyA},
+	}}
+	assert.Equal(t, "ssh-ed25519 a@host", r.Items[0].WantLabel())
+	assert.Contains(t, r.Text(), "[authorized_keys] alice\n    want: ssh-ed25519 a@host\n    have: (missing)\n")
+
+	r.Recorded = false
+	r.Items = nil
+	assert.Contains(t, r.Text(), "No applied state recorded yet.\n")
+}
@@ -0,0 +1,297 @@
+package fail2ban
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/modules/ssh"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+const (
+	packageName = "fail2ban"
+	serviceName = "fail2ban"
+	jailName    = "sshd"
+)
+
+var (
+	jailFile       = "/etc/fail2ban/jail.d/server-toolkit.local"
+	sshdConfigFile = "/etc/ssh/sshd_config"
+	authLogFile    = "/var/log/auth.log"
+)
+
+// Options sshd jail 选项
+type Options struct {
+	MaxRetry int    // 允许失败次数
+	FindTime string // 统计窗口（如 10m）
+	BanTime  string // 封禁时长（如 1h）
+}
+
+// DefaultOptions 默认 jail 选项
+func DefaultOptions() Options {
+	return Options{MaxRetry: 5, FindTime: "10m", BanTime: "1h"}
+}
+
+// Jail 生成 jail 配置所需的参数
+type Jail struct {
+	Ports   []string
+	Backend string // systemd / auto
+	LogPath string // Backend 为 auto 时使用
+	Options Options
+}
+
+// JailStatus `fail2ban-client status sshd` 的结果
+type JailStatus struct {
+	CurrentlyFailed int
+	TotalFailed     int
+	CurrentlyBanned int
+	TotalBanned     int
+	BannedIPs       []string
+}
+
+// Status fail2ban 状态
+type Status struct {
+	Installed  bool
+	Active     bool
+	Configured bool // server-toolkit 的 jail 文件已存在
+	Jail       *JailStatus
+}
+
+// Manager fail2ban 管理器
+type Manager struct {
+	distro *system.DistroInfo
+	dryRun bool
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewManager 创建 fail2ban 管理器
+func NewManager(distro *system.DistroInfo, dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "fail2ban"})
+	return &Manager{
+		distro: distro,
+		dryRun: dryRun,
+		logger: logger,
+		drm:    internal.NewDryRunManager(dryRun, logger),
+	}
+}
+
+// ChooseBackend 按发行版选择日志后端：有 auth.log 的 Debian 系读文件，
+// Alpine 读 /var/log/messages，其余（含无 rsyslog 的 Debian 12+）读 systemd journal
+func ChooseBackend(family system.DistroFamily, authLogExists bool) (backend, logPath string) {
+	switch family {
+	case system.Debian:
+		if authLogExists {
+			return "auto", authLogFile
+		}
+		return "systemd", ""
+	case system.Alpine:
+		return "auto", "/var/log/messages"
+	default:
+		return "systemd", ""
+	}
+}
+
+// PlanJail 根据当前系统生成 jail 参数（读取 sshd 端口与日志后端）
+func (m *Manager) PlanJail(opts Options) (*Jail, error) {
+	if m.distro == nil {
+		return nil, fmt.Errorf("unsupported distribution")
+	}
+
+	// 端口以 sshd 生效配置为准（含 sshd_config.d 中的 drop-in）
+	settings, err := ssh.ReadEffectiveSettings(system.RootPath(sshdConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	backend, logPath := ChooseBackend(m.distro.Family, system.FileExists(system.RootPath(authLogFile)))
+	return &Jail{
+		Ports:   settings.Ports(),
+		Backend: backend,
+		LogPath: logPath,
+		Options: opts,
+	}, nil
+}
+
+// RenderJail 生成 jail.d/server-toolkit.local 内容
+func RenderJail(jail *Jail) string {
+	opts := jail.Options
+	if opts.MaxRetry <= 0 {
+		opts.MaxRetry = DefaultOptions().MaxRetry
+	}
+	if opts.FindTime == "" {
+		opts.FindTime = DefaultOptions().FindTime
+	}
+	if opts.BanTime == "" {
+		opts.BanTime = DefaultOptions().BanTime
+	}
+
+	var b strings.Builder
+	b.WriteString("# written by server-toolkit: sshd brute-force protection\n")
+	fmt.Fprintf(&b, "[%s]\n", jailName)
+	b.WriteString("enabled = true\n")
+	fmt.Fprintf(&b, "port = %s\n", strings.Join(jail.Ports, ","))
+	fmt.Fprintf(&b, "backend = %s\n", jail.Backend)
+	if jail.LogPath != "" {
+		fmt.Fprintf(&b, "logpath = %s\n", jail.LogPath)
+	}
+	fmt.Fprintf(&b, "maxretry = %d\n", opts.MaxRetry)
+	fmt.Fprintf(&b, "findtime = %s\n", opts.FindTime)
+	fmt.Fprintf(&b, "bantime = %s\n", opts.BanTime)
+	return b.String()
+}
+
+// Enable 安装 fail2ban、写入 sshd jail 并启用服务
+func (m *Manager) Enable(opts Options) error {
+	jail, err := m.PlanJail(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := m.installPackage(); err != nil {
+		return err
+	}
+	if err := m.writeJail(RenderJail(jail)); err != nil {
+		return err
+	}
+	if err := m.enableService(); err != nil {
+		return err
+	}
+
+	m.logger.Info("Enabled fail2ban sshd jail (port %s, backend %s)", strings.Join(jail.Ports, ","), jail.Backend)
+	return nil
+}
+
+func (m *Manager) installPackage() error {
+	if m.dryRun {
+		m.drm.LogOperation("Would install package: %s", packageName)
+		return nil
+	}
+	if err := system.InstallPackage(packageName); err != nil {
+		return fmt.Errorf("failed to install %s: %w", packageName, err)
+	}
+	return nil
+}
+
+func (m *Manager) writeJail(content string) error {
+	path := system.RootPath(jailFile)
+	if m.dryRun {
+		m.drm.LogFileWrite(path, content)
+		return nil
+	}
+
+	if err := system.EnsureDir(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	backupPath, err := system.BackupFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to backup %s: %w", path, err)
+	}
+	if backupPath != "" {
+		m.logger.Info("Backed up: %s -> %s", path, backupPath)
+	}
+
+	if err := system.SafeWrite(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	m.logger.Info("Written to %s", path)
+	return nil
+}
+
+func (m *Manager) enableService() error {
+	if m.dryRun {
+		m.drm.LogServiceOperation("enable and start", serviceName)
+		m.drm.LogServiceOperation("reload", serviceName)
+		return nil
+	}
+
+	svc := system.NewServiceManager()
+	if err := svc.EnableAndStart(serviceName); err != nil {
+		return err
+	}
+	// 服务此前已运行时需重载才能加载新的 jail
+	return svc.Reload(serviceName)
+}
+
+// Status 获取 fail2ban 状态（只读）
+func (m *Manager) Status() (*Status, error) {
+	status := &Status{Configured: system.FileExists(system.RootPath(jailFile))}
+
+	if m.distro != nil {
+		if mgr, err := system.DetectPackageManager(m.distro.Family); err == nil {
+			status.Installed, _ = mgr.IsInstalled(packageName)
+		}
+	}
+
+	status.Active, _ = system.NewServiceManager().IsActive(serviceName)
+	if !status.Active {
+		return status, nil
+	}
+
+	res, err := system.QueryCommand("fail2ban-client", "status", jailName)
+	if err != nil {
+		// jail 未启用时 fail2ban-client 返回非零，不视为错误
+		m.logger.Debug("fail2ban-client status %s: %v", jailName, err)
+		return status, nil
+	}
+	status.Jail = ParseJailStatus(res.Stdout)
+	return status, nil
+}
+
+// ParseJailStatus 解析 `fail2ban-client status <jail>` 输出
+func ParseJailStatus(output string) *JailStatus {
+	status := &JailStatus{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " |`-")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "Currently failed":
+			status.CurrentlyFailed, _ = strconv.Atoi(value)
+		case "Total failed":
+			status.TotalFailed, _ = strconv.Atoi(value)
+		case "Currently banned":
+			status.CurrentlyBanned, _ = strconv.Atoi(value)
+		case "Total banned":
+			status.TotalBanned, _ = strconv.Atoi(value)
+		case "Banned IP list":
+			status.BannedIPs = strings.Fields(value)
+		}
+	}
+	return status
+}
+
+// Unban 解封 sshd jail 中的 IP
+func (m *Manager) Unban(ip string) error {
+	if net.ParseIP(ip) == nil {
+		return fmt.Errorf("invalid IP address: %s", ip)
+	}
+	if system.HasRoot() {
+		return fmt.Errorf("cannot unban on offline root %s", system.Root())
+	}
+
+	args := []string{"set", jailName, "unbanip", ip}
+	if m.dryRun {
+		m.drm.LogCommand("fail2ban-client", args...)
+		return nil
+	}
+
+	if _, err := system.RunCommand("fail2ban-client", args...); err != nil {
+		return fmt.Errorf("failed to unban %s: %w", ip, err)
+	}
+
+	m.logger.Info("Unbanned %s from %s jail", ip, jailName)
+	return nil
+}
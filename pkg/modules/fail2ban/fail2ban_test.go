@@ -0,0 +1,89 @@
+package fail2ban
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChooseBackend(t *testing.T) {
+	backend, logPath := ChooseBackend(system.Debian, true)
+	assert.Equal(t, "auto", backend)
+	assert.Equal(t, "/var/log/auth.log", logPath)
+
+	backend, logPath = ChooseBackend(system.Debian, false)
+	assert.Equal(t, "systemd", backend)
+	assert.Empty(t, logPath)
+
+	backend, _ = ChooseBackend(system.RedHat, true)
+	assert.Equal(t, "systemd", backend)
+}
+
+func TestRenderJail(t *testing.T) {
+	content := RenderJail(&Jail{
+		Ports:   []string{"2222", "22"},
+		Backend: "auto",
+		LogPath: "/var/log/auth.log",
+		Options: Options{MaxRetry: 3},
+	})
+
+	assert.Contains(t, content, "[sshd]\nenabled = true\n")
+	assert.Contains(t, content, "port = 2222,22\n")
+	assert.Contains(t, content, "backend = auto\n")
+	assert.Contains(t, content, "logpath = /var/log/auth.log\n")
+	assert.Contains(t, content, "maxretry = 3\n")
+	assert.Contains(t, content, "bantime = 1h\n")
+}
+
+func TestPlanJailFollowsSSHDPort(t *testing.T) {
+	dir := t.TempDir()
+	oldSSHD, oldAuthLog := sshdConfigFile, authLogFile
+	t.Cleanup(func() { sshdConfigFile, authLogFile = oldSSHD, oldAuthLog })
+
+	sshdConfigFile = filepath.Join(dir, "sshd_config")
+	authLogFile = filepath.Join(dir, "auth.log")
+	require.NoError(t, os.WriteFile(sshdConfigFile, []byte("Port 2222\nMatch User git\n  Port 2200\n"), 0644))
+
+	mgr := NewManager(&system.DistroInfo{ID: "debian", Family: system.Debian}, true, internal.NewLogger(internal.ERROR, os.Stdout))
+	jail, err := mgr.PlanJail(DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2222"}, jail.Ports)
+	assert.Equal(t, "systemd", jail.Backend)
+
+	// sshd_config.d 中的端口同样生效，且先于主配置读取
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sshd_config.d"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sshd_config.d", "10-port.conf"), []byte("Port 2022\n"), 0644))
+	jail, err = mgr.PlanJail(DefaultOptions())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2022", "2222"}, jail.Ports)
+}
+
+func TestParseJailStatus(t *testing.T) {
+	output := `Status for the jail: sshd
+|- Filter
+|  |- Currently failed:	2
+|  |- Total failed:	41
+|  ` + "`" + `- Journal matches:	_SYSTEMD_UNIT=sshd.service + _COMM=sshd
+` + "`" + `- Actions
+   |- Currently banned:	2
+   |- Total banned:	7
+   ` + "`" + `- Banned IP list:	203.0.113.5 2001:db8::1
+`
+	status := ParseJailStatus(output)
+	assert.Equal(t, 2, status.CurrentlyFailed)
+	assert.Equal(t, 41, status.TotalFailed)
+	assert.Equal(t, 2, status.CurrentlyBanned)
+	assert.Equal(t, 7, status.TotalBanned)
+	assert.Equal(t, []string{"203.0.113.5", "2001:db8::1"}, status.BannedIPs)
+}
+
+func TestUnbanRejectsInvalidIP(t *testing.T) {
+	mgr := NewManager(nil, true, internal.NewLogger(internal.ERROR, os.Stdout))
+	require.Error(t, mgr.Unban("not-an-ip"))
+	require.NoError(t, mgr.Unban("203.0.113.5"))
+}
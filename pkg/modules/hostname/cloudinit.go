@@ -4,11 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 )
 
@@ -22,12 +22,12 @@ const (
 // IsPresent 检测 cloud-init 是否存在
 func IsPresent() bool {
 	// 检查 /etc/cloud 目录
-	if _, err := os.Stat(cloudInitDir); err != nil {
+	if _, err := os.Stat(system.RootPath(cloudInitDir)); err != nil {
 		return false
 	}
 
 	// 检查 cloud-init 命令
-	if _, err := exec.LookPath("cloud-init"); err != nil {
+	if !system.GuestCommandExists("cloud-init") {
 		return false
 	}
 
@@ -35,7 +35,7 @@ func IsPresent() bool {
 }
 
 // SetPreserveHostname 设置 cloud-init preserve_hostname
-func SetPreserveHostname(dryRun bool, logger *internal.Logger) error {
+func SetPreserveHostname(dryRun bool, logger *internal.Logger) (err error) {
 	drm := internal.NewDryRunManager(dryRun, logger)
 
 	if !IsPresent() {
@@ -43,7 +43,8 @@ func SetPreserveHostname(dryRun bool, logger *internal.Logger) error {
 		return nil
 	}
 
-	cfgPath := filepath.Join(cloudInitCfgDir, preserveHostnameCfg)
+	cfgDir := system.RootPath(cloudInitCfgDir)
+	cfgPath := filepath.Join(cfgDir, preserveHostnameCfg)
 
 	// 检查文件是否已存在且配置正确
 	if _, err := os.Stat(cfgPath); err == nil {
@@ -64,6 +65,9 @@ func SetPreserveHostname(dryRun bool, logger *internal.Logger) error {
 		}
 	}
 
+	op := history.Begin(dryRun, "hostname", "preserve_hostname", "", cfgPath)
+	defer func() { op.End(err, logger) }()
+
 	// 备份文件
 	if !dryRun {
 		backupPath, err := system.BackupFile(cfgPath)
@@ -83,8 +87,8 @@ func SetPreserveHostname(dryRun bool, logger *internal.Logger) error {
 		return nil
 	}
 
-	if err := os.MkdirAll(cloudInitCfgDir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", cloudInitCfgDir, err)
+	if err := os.MkdirAll(cfgDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", cfgDir, err)
 	}
 
 	perm := os.FileMode(0644)
@@ -100,7 +104,7 @@ func SetPreserveHostname(dryRun bool, logger *internal.Logger) error {
 }
 
 // PatchHostsTemplates 修补 cloud-init hosts 模板
-func PatchHostsTemplates(shortName, fqdn string, dryRun bool, logger *internal.Logger) error {
+func PatchHostsTemplates(shortName, fqdn string, dryRun bool, logger *internal.Logger) (err error) {
 	drm := internal.NewDryRunManager(dryRun, logger)
 
 	if !IsPresent() {
@@ -109,12 +113,13 @@ func PatchHostsTemplates(shortName, fqdn string, dryRun bool, logger *internal.L
 	}
 
 	// 检查模板目录
-	if _, err := os.Stat(hostsTemplateDir); err != nil {
+	templateDir := system.RootPath(hostsTemplateDir)
+	if _, err := os.Stat(templateDir); err != nil {
 		if os.IsNotExist(err) {
-			logger.Info("Cloud-init templates directory not found: %s", hostsTemplateDir)
+			logger.Info("Cloud-init templates directory not found: %s", templateDir)
 			return nil
 		}
-		return fmt.Errorf("failed to stat %s: %w", hostsTemplateDir, err)
+		return fmt.Errorf("failed to stat %s: %w", templateDir, err)
 	}
 
 	// 构建新行
@@ -125,7 +130,7 @@ func PatchHostsTemplates(shortName, fqdn string, dryRun bool, logger *internal.L
 	newLine += " " + shortName
 
 	// 遍历模板文件
-	pattern := filepath.Join(hostsTemplateDir, "hosts.*.tmpl")
+	pattern := filepath.Join(templateDir, "hosts.*.tmpl")
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return fmt.Errorf("failed to glob templates: %w", err)
@@ -136,6 +141,9 @@ func PatchHostsTemplates(shortName, fqdn string, dryRun bool, logger *internal.L
 		return nil
 	}
 
+	op := history.Begin(dryRun, "hostname", "patch_hosts_templates", shortName, matches...)
+	defer func() { op.End(err, logger) }()
+
 	patched := 0
 	for _, templateFile := range matches {
 		// 检查模板是否包含 127.0.1.1
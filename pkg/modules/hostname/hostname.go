@@ -3,10 +3,11 @@ package hostname
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 )
 
@@ -14,6 +15,21 @@ const (
 	hostnameFile = "/etc/hostname"
 )
 
+// SetOptions 设置主机名的选项（对应 hostnamectl 的 --static/--transient/--pretty）
+type SetOptions struct {
+	Static    bool   // 静态主机名：写入 /etc/hostname，重启后生效
+	Transient bool   // 临时主机名：内核当前主机名，重启后丢失
+	Pretty    string // 友好名称（PRETTY_HOSTNAME），空表示不修改
+
+	// References 需要一并更新的旧主机名引用（来自 ScanImpact，可按需筛选）
+	References *ImpactReport
+}
+
+// DefaultSetOptions 同时设置静态与临时主机名
+func DefaultSetOptions() SetOptions {
+	return SetOptions{Static: true, Transient: true}
+}
+
 // Manager 主机名管理器
 type Manager struct {
 	dryRun bool
@@ -23,6 +39,7 @@ type Manager struct {
 
 // NewManager 创建主机名管理器
 func NewManager(dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "hostname"})
 	return &Manager{
 		dryRun: dryRun,
 		logger: logger,
@@ -30,16 +47,46 @@ func NewManager(dryRun bool, logger *internal.Logger) *Manager {
 	}
 }
 
-// SetHostname 设置主机名
+// SetHostname 设置主机名（静态 + 临时）
 func (m *Manager) SetHostname(short, fqdn string) error {
+	return m.SetHostnameWithOptions(short, fqdn, DefaultSetOptions())
+}
+
+// SetHostnameWithOptions 按选项设置静态/临时/友好主机名，并可更新引用旧主机名的配置
+func (m *Manager) SetHostnameWithOptions(short, fqdn string, opts SetOptions) (err error) {
+	if !opts.Static && !opts.Transient {
+		return fmt.Errorf("at least one of static or transient hostname must be set")
+	}
+
+	var files []string
+	if opts.Static {
+		files = append(files, system.RootPath(hostnameFile))
+	}
+	if opts.Pretty != "" {
+		files = append(files, system.RootPath(machineInfoFile))
+	}
+	op := history.Begin(m.dryRun, "hostname", "set_hostname", strings.TrimSpace(short+" "+fqdn), files...)
+	defer func() { op.End(err, m.logger) }()
+
 	// 设置主机名
-	if err := m.setHostname(short); err != nil {
+	if err := m.setHostname(short, opts); err != nil {
 		return err
 	}
 
-	// 写入 /etc/hostname
-	if err := m.writeHostnameFile(short); err != nil {
-		return err
+	// 写入 /etc/hostname（仅静态主机名）
+	if opts.Static {
+		if err := m.writeHostnameFile(short); err != nil {
+			return err
+		}
+		if !m.dryRun {
+			state.Record(m.logger, func(st *state.State) { st.SetHostname(short, fqdn) })
+		}
+	}
+
+	if opts.Pretty != "" {
+		if err := m.setPrettyHostname(opts.Pretty); err != nil {
+			return err
+		}
 	}
 
 	m.logger.Info("Hostname set to: %s", short)
@@ -47,30 +94,58 @@ func (m *Manager) SetHostname(short, fqdn string) error {
 		m.logger.Info("FQDN set to: %s", fqdn)
 	}
 
+	if opts.References != nil {
+		if err := m.UpdateReferences(opts.References, short, fqdn); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// hostnamectlArgs 生成 hostnamectl set-hostname 参数；两者都设置时不加开关（兼容旧版 hostnamectl）
+func hostnamectlArgs(name string, opts SetOptions) []string {
+	args := []string{"set-hostname", name}
+	switch {
+	case opts.Static && !opts.Transient:
+		args = append(args, "--static")
+	case opts.Transient && !opts.Static:
+		args = append(args, "--transient")
+	}
+	return args
+}
+
 // setHostname 设置主机名（使用 hostnamectl 或 hostname）
-func (m *Manager) setHostname(name string) error {
+func (m *Manager) setHostname(name string, opts SetOptions) error {
+	args := hostnamectlArgs(name, opts)
 	if m.dryRun {
-		m.drm.LogCommand("hostnamectl", "set-hostname", name)
+		m.drm.LogCommand("hostnamectl", args...)
+		return nil
+	}
+
+	// 离线根目录：只写 /etc/hostname，不修改本机主机名
+	if system.HasRoot() {
+		m.logger.Info("Root %s set, skipping hostnamectl", system.Root())
 		return nil
 	}
 
 	// 优先使用 hostnamectl
-	if _, err := exec.LookPath("hostnamectl"); err == nil {
-		cmd := exec.Command("hostnamectl", "set-hostname", name)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("hostnamectl failed: %w", err)
+	if system.CommandExists("hostnamectl") {
+		if _, err := system.RunCommand("hostnamectl", args...); err != nil {
+			return fmt.Errorf("failed to set hostname: %w", err)
 		}
 		return nil
 	}
 
+	// 仅设置静态主机名：由 /etc/hostname 在重启后生效
+	if !opts.Transient {
+		return nil
+	}
+
 	// 降级到 hostname
-	if _, err := exec.LookPath("hostname"); err == nil {
-		cmd := exec.Command("hostname", name)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("hostname failed: %w", err)
+	if system.CommandExists("hostname") {
+		if _, err := system.RunCommand("hostname", name); err != nil {
+			return fmt.Errorf("failed to set hostname: %w", err)
 		}
 		return nil
 	}
@@ -78,51 +153,79 @@ func (m *Manager) setHostname(name string) error {
 	return fmt.Errorf("no hostname command found")
 }
 
+// setPrettyHostname 设置友好名称（hostnamectl --pretty，否则直接写 /etc/machine-info）
+func (m *Manager) setPrettyHostname(pretty string) error {
+	if strings.ContainsAny(pretty, "\r\n") {
+		return fmt.Errorf("pretty hostname must be a single line")
+	}
+
+	if !system.HasRoot() && (m.dryRun || system.CommandExists("hostnamectl")) {
+		if m.dryRun {
+			m.drm.LogCommand("hostnamectl", "set-hostname", "--pretty", pretty)
+			return nil
+		}
+		if _, err := system.RunCommand("hostnamectl", "set-hostname", "--pretty", pretty); err != nil {
+			return fmt.Errorf("failed to set pretty hostname: %w", err)
+		}
+		return nil
+	}
+
+	path := system.RootPath(machineInfoFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return m.rewriteFile(path, setMachineInfoPretty(string(data), pretty))
+}
+
 // writeHostnameFile 写入 /etc/hostname
 func (m *Manager) writeHostnameFile(name string) error {
+	path := system.RootPath(hostnameFile)
+
 	// 备份文件
 	if !m.dryRun {
-		backupPath, err := system.BackupFile(hostnameFile)
+		backupPath, err := system.BackupFile(path)
 		if err != nil {
-			return fmt.Errorf("failed to backup %s: %w", hostnameFile, err)
+			return fmt.Errorf("failed to backup %s: %w", path, err)
 		}
 		if backupPath != "" {
-			m.logger.Info("Backed up: %s -> %s", hostnameFile, backupPath)
+			m.logger.Info("Backed up: %s -> %s", path, backupPath)
 		}
 	}
 
 	// 写入文件
 	data := []byte(name + "\n")
 	if m.dryRun {
-		m.drm.LogFileWrite(hostnameFile, string(data))
+		m.drm.LogFileWrite(path, string(data))
 		return nil
 	}
 
-	if err := system.SafeWrite(hostnameFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", hostnameFile, err)
+	if err := system.SafeWrite(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 
-	m.logger.Info("Written to %s: %s", hostnameFile, name)
+	m.logger.Info("Written to %s: %s", path, name)
 	return nil
 }
 
 // GetHostname 获取当前主机名
 func (m *Manager) GetHostname() (string, error) {
+	// 离线根目录：以目标系统的 /etc/hostname 为准
+	if system.HasRoot() {
+		return m.ReadHostnameFile()
+	}
+
 	// 优先使用 hostnamectl
-	if _, err := exec.LookPath("hostnamectl"); err == nil {
-		cmd := exec.Command("hostnamectl", "--static")
-		output, err := cmd.Output()
-		if err == nil {
-			return strings.TrimSpace(string(output)), nil
+	if system.CommandExists("hostnamectl") {
+		if res, err := system.QueryCommand("hostnamectl", "--static"); err == nil {
+			return strings.TrimSpace(res.Stdout), nil
 		}
 	}
 
 	// 降级到 hostname
-	if _, err := exec.LookPath("hostname"); err == nil {
-		cmd := exec.Command("hostname")
-		output, err := cmd.Output()
-		if err == nil {
-			return strings.TrimSpace(string(output)), nil
+	if system.CommandExists("hostname") {
+		if res, err := system.QueryCommand("hostname"); err == nil {
+			return strings.TrimSpace(res.Stdout), nil
 		}
 	}
 
@@ -131,7 +234,7 @@ func (m *Manager) GetHostname() (string, error) {
 
 // ReadHostnameFile 读取 /etc/hostname
 func (m *Manager) ReadHostnameFile() (string, error) {
-	data, err := system.ReadFile(hostnameFile)
+	data, err := system.ReadFile(system.RootPath(hostnameFile))
 	if err != nil {
 		if os.IsNotExist(err) {
 			return "", nil
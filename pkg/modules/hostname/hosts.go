@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 )
 
@@ -29,15 +31,19 @@ const (
 )
 
 // UpdateHosts 更新 /etc/hosts
-func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, logger *internal.Logger) error {
+func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, logger *internal.Logger) (err error) {
 	drm := internal.NewDryRunManager(dryRun, logger)
+	path := system.RootPath(hostsFile)
+
+	op := history.Begin(dryRun, "hostname", "update_hosts", newName, path)
+	defer func() { op.End(err, logger) }()
 
 	// 读取 /etc/hosts
 	var lines []string
-	file, err := os.Open(hostsFile)
+	file, err := os.Open(path)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to open %s: %w", hostsFile, err)
+			return fmt.Errorf("failed to open %s: %w", path, err)
 		}
 		// 文件不存在：视为新建
 	} else {
@@ -47,18 +53,18 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 			lines = append(lines, scanner.Text())
 		}
 		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("failed to read %s: %w", hostsFile, err)
+			return fmt.Errorf("failed to read %s: %w", path, err)
 		}
 	}
 
 	// 备份文件
 	if !dryRun {
-		backupPath, err := backupFileFn(hostsFile)
+		backupPath, err := backupFileFn(path)
 		if err != nil {
-			return fmt.Errorf("failed to backup %s: %w", hostsFile, err)
+			return fmt.Errorf("failed to backup %s: %w", path, err)
 		}
 		if backupPath != "" {
-			logger.Info("Backed up: %s -> %s", hostsFile, backupPath)
+			logger.Info("Backed up: %s -> %s", path, backupPath)
 		}
 	}
 
@@ -69,9 +75,11 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 	}
 	newLine += " " + newName
 
-	// 根据模式更新
+	// 根据模式更新；written 记录实际写入的条目，供漂移检测使用
 	newLines := make([]string, 0, len(lines)+1)
 	updated := false
+	var written []state.HostsEntry
+	newEntry := state.HostsEntry{IP: "127.0.1.1", Names: strings.Fields(newLine)[1:]}
 
 	if mode == Replace127 {
 		// 替换 127.0.1.1 行
@@ -80,7 +88,8 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 				if !updated {
 					newLines = append(newLines, newLine)
 					updated = true
-					logger.Info("Updated %s: replaced 127.0.1.1 line", hostsFile)
+					written = append(written, newEntry)
+					logger.Info("Updated %s: replaced 127.0.1.1 line", path)
 				}
 			} else {
 				newLines = append(newLines, line)
@@ -103,7 +112,10 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 			if found {
 				newLines = append(newLines, strings.Join(fields, " "))
 				updated = true
-				logger.Info("Updated %s: replaced hostname token", hostsFile)
+				if entry := parseHostsLine(strings.Join(fields, " ")); entry != nil {
+					written = append(written, state.HostsEntry{IP: entry.IP, Names: entry.Names()})
+				}
+				logger.Info("Updated %s: replaced hostname token", path)
 			} else {
 				newLines = append(newLines, line)
 			}
@@ -117,6 +129,7 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 		for _, line := range newLines {
 			if strings.TrimSpace(line) == newLine {
 				updated = true
+				written = append(written, newEntry)
 				break
 			}
 		}
@@ -128,7 +141,8 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 				insertAt := i + 1
 				newLines = append(newLines[:insertAt], append([]string{newLine}, newLines[insertAt:]...)...)
 				updated = true
-				logger.Info("Updated %s: inserted after 127.0.0.1", hostsFile)
+				written = append(written, newEntry)
+				logger.Info("Updated %s: inserted after 127.0.0.1", path)
 				break
 			}
 		}
@@ -137,29 +151,36 @@ func UpdateHosts(oldName, newName, fqdn string, mode UpdateMode, dryRun bool, lo
 	// 如果还是没有更新，追加到末尾
 	if !updated {
 		newLines = append(newLines, newLine)
-		logger.Info("Updated %s: appended to end", hostsFile)
+		written = append(written, newEntry)
+		logger.Info("Updated %s: appended to end", path)
 	}
 
 	// 写回文件
 	data := []byte(strings.Join(newLines, "\n") + "\n")
 	if dryRun {
-		drm.LogFileWrite(hostsFile, string(data))
+		drm.LogFileWrite(path, string(data))
 		return nil
 	}
 
-	if err := safeWriteFn(hostsFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write %s: %w", hostsFile, err)
+	if err := safeWriteFn(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 
-	logger.Info("Written to %s", hostsFile)
+	logger.Info("Written to %s", path)
+	state.Record(logger, func(st *state.State) {
+		for _, entry := range written {
+			st.SetHostsEntry(entry.IP, entry.Names)
+		}
+	})
 	return nil
 }
 
 // GetHostsEntries 解析 /etc/hosts
 func GetHostsEntries() ([]string, error) {
-	file, err := os.Open(hostsFile)
+	path := system.RootPath(hostsFile)
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open %s: %w", hostsFile, err)
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
 	}
 	defer file.Close()
 
@@ -174,7 +195,7 @@ func GetHostsEntries() ([]string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", hostsFile, err)
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
 	}
 
 	return entries, nil
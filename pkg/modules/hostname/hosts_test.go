@@ -6,11 +6,19 @@ import (
 	"testing"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// isolateState 把状态文件指向临时目录，避免测试写入 /var/lib
+func isolateState(t *testing.T) {
+	old := state.SetPath(filepath.Join(t.TempDir(), "state.json"))
+	t.Cleanup(func() { state.SetPath(old) })
+}
+
 func TestUpdateHostsInsertAfterNoPanicAndInsert(t *testing.T) {
+	isolateState(t)
 	tmpDir := t.TempDir()
 	hostsPath := filepath.Join(tmpDir, "hosts")
 	content := "127.0.0.1 localhost\n::1 localhost ip6-localhost ip6-loopback\n"
@@ -43,6 +51,7 @@ func TestUpdateHostsInsertAfterNoPanicAndInsert(t *testing.T) {
 }
 
 func TestUpdateHostsReplace127Idempotent(t *testing.T) {
+	isolateState(t)
 	tmpDir := t.TempDir()
 	hostsPath := filepath.Join(tmpDir, "hosts")
 	content := "127.0.0.1 localhost\n127.0.1.1 old old.example.com\n"
@@ -71,6 +80,43 @@ func TestUpdateHostsReplace127Idempotent(t *testing.T) {
 	text := string(out)
 	assert.Equal(t, 1, countLinesWithPrefix(text, "127.0.1.1"))
 	assert.Contains(t, text, "127.0.1.1 new-host.example.com new-host")
+
+	st, err := state.Load()
+	require.NoError(t, err)
+	require.Len(t, st.Hosts, 1)
+	assert.Equal(t, []string{"new-host.example.com", "new-host"}, st.Hosts[0].Names)
+}
+
+// ReplaceToken 只原地替换旧主机名：记录被修改的行，而不是从未写入的 127.0.1.1 行
+func TestUpdateHostsReplaceTokenRecordsWrittenEntry(t *testing.T) {
+	isolateState(t)
+	hostsPath := filepath.Join(t.TempDir(), "hosts")
+	content := "127.0.0.1 localhost\n10.0.0.5 old old-mgmt # primary\n"
+	require.NoError(t, os.WriteFile(hostsPath, []byte(content), 0644))
+
+	oldHostsFile, oldBackupFn, oldSafeWriteFn := hostsFile, backupFileFn, safeWriteFn
+	hostsFile = hostsPath
+	backupFileFn = func(string) (string, error) { return "", nil }
+	safeWriteFn = func(path string, data []byte, perm os.FileMode) error {
+		return os.WriteFile(path, data, perm)
+	}
+	t.Cleanup(func() {
+		hostsFile, backupFileFn, safeWriteFn = oldHostsFile, oldBackupFn, oldSafeWriteFn
+	})
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	require.NoError(t, UpdateHosts("old", "new-host", "new-host.example.com", ReplaceToken, false, logger))
+
+	out, err := os.ReadFile(hostsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1 localhost\n10.0.0.5 new-host old-mgmt # primary\n", string(out))
+	assert.Equal(t, 0, countLinesWithPrefix(string(out), "127.0.1.1"))
+
+	st, err := state.Load()
+	require.NoError(t, err)
+	require.Len(t, st.Hosts, 1)
+	assert.Equal(t, "10.0.0.5", st.Hosts[0].IP)
+	assert.Equal(t, []string{"new-host", "old-mgmt"}, st.Hosts[0].Names)
 }
 
 func countLinesWithPrefix(content, prefix string) int {
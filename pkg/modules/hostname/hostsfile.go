@@ -0,0 +1,325 @@
+package hostname
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// HostsEntry /etc/hosts 中的一条映射
+type HostsEntry struct {
+	IP        string
+	Canonical string
+	Aliases   []string
+	Comment   string // 行尾注释（不含 #）
+}
+
+// Names 返回规范名与别名
+func (e HostsEntry) Names() []string {
+	return append([]string{e.Canonical}, e.Aliases...)
+}
+
+// IsIPv6 是否为 IPv6 地址
+func (e HostsEntry) IsIPv6() bool {
+	ip := parseHostsIP(e.IP)
+	return ip != nil && ip.To4() == nil
+}
+
+// IsSystem 是否为发行版默认的回环/组播条目（localhost、ip6-allnodes 等）
+func (e HostsEntry) IsSystem() bool {
+	ip := parseHostsIP(e.IP)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() && e.IP != "127.0.1.1" {
+		return true
+	}
+	// fe00::0 ip6-localnet / ff00::0 ip6-mcastprefix / ff02::1 ip6-allnodes / ff02::2 ip6-allrouters
+	return ip.IsMulticast() || ip.Equal(net.ParseIP("fe00::")) || ip.Equal(net.ParseIP("ff00::"))
+}
+
+// Validate 校验 IP 与主机名
+func (e HostsEntry) Validate() error {
+	if parseHostsIP(e.IP) == nil {
+		return fmt.Errorf("invalid IP address: %q", e.IP)
+	}
+	if e.Canonical == "" {
+		return fmt.Errorf("hostname cannot be empty")
+	}
+	for _, name := range e.Names() {
+		if err := ValidateHostname(name); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if strings.ContainsAny(e.Comment, "\r\n") {
+		return fmt.Errorf("comment must be a single line")
+	}
+	return nil
+}
+
+// String 格式化为 hosts 行
+func (e HostsEntry) String() string {
+	line := e.IP + "\t" + strings.Join(e.Names(), " ")
+	if e.Comment != "" {
+		line += " # " + e.Comment
+	}
+	return line
+}
+
+// parseHostsIP 解析 IP（允许 fe80::1%eth0 这样的 zone）
+func parseHostsIP(s string) net.IP {
+	addr, _, _ := strings.Cut(s, "%")
+	return net.ParseIP(addr)
+}
+
+// hostsLine 文件中的一行；未修改的行按原文写回
+type hostsLine struct {
+	raw   string
+	entry *HostsEntry // 空行、注释行与无法解析的行为 nil
+}
+
+// HostsFile 结构化的 /etc/hosts（保留注释、空行与原有格式）
+type HostsFile struct {
+	lines []hostsLine
+}
+
+// ParseHosts 解析 hosts 文件内容
+func ParseHosts(content string) *HostsFile {
+	f := &HostsFile{}
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return f
+	}
+	for _, raw := range strings.Split(content, "\n") {
+		f.lines = append(f.lines, hostsLine{raw: raw, entry: parseHostsLine(raw)})
+	}
+	return f
+}
+
+func parseHostsLine(raw string) *HostsEntry {
+	data, comment, _ := strings.Cut(raw, "#")
+	fields := strings.Fields(data)
+	if len(fields) < 2 {
+		return nil
+	}
+	return &HostsEntry{
+		IP:        fields[0],
+		Canonical: fields[1],
+		Aliases:   fields[2:],
+		Comment:   strings.TrimSpace(comment),
+	}
+}
+
+// Entries 返回全部条目（按文件顺序）
+func (f *HostsFile) Entries() []HostsEntry {
+	var entries []HostsEntry
+	for _, line := range f.lines {
+		if line.entry != nil {
+			entries = append(entries, *line.entry)
+		}
+	}
+	return entries
+}
+
+// lineIndex 把条目序号转换为行号
+func (f *HostsFile) lineIndex(index int) (int, error) {
+	n := 0
+	for i, line := range f.lines {
+		if line.entry == nil {
+			continue
+		}
+		if n == index {
+			return i, nil
+		}
+		n++
+	}
+	return -1, fmt.Errorf("hosts entry %d not found", index)
+}
+
+// Add 追加条目
+func (f *HostsFile) Add(entry HostsEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	f.lines = append(f.lines, hostsLine{raw: entry.String(), entry: &entry})
+	return nil
+}
+
+// Update 修改第 index 条
+func (f *HostsFile) Update(index int, entry HostsEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+	i, err := f.lineIndex(index)
+	if err != nil {
+		return err
+	}
+	f.lines[i] = hostsLine{raw: entry.String(), entry: &entry}
+	return nil
+}
+
+// Remove 删除第 index 条
+func (f *HostsFile) Remove(index int) error {
+	i, err := f.lineIndex(index)
+	if err != nil {
+		return err
+	}
+	f.lines = append(f.lines[:i], f.lines[i+1:]...)
+	return nil
+}
+
+// Lookup 返回包含该主机名的全部条目
+func (f *HostsFile) Lookup(name string) []HostsEntry {
+	var found []HostsEntry
+	for _, entry := range f.Entries() {
+		for _, n := range entry.Names() {
+			if strings.EqualFold(n, name) {
+				found = append(found, entry)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// String 生成文件内容
+func (f *HostsFile) String() string {
+	if len(f.lines) == 0 {
+		return ""
+	}
+	raws := make([]string, 0, len(f.lines))
+	for _, line := range f.lines {
+		raws = append(raws, line.raw)
+	}
+	return strings.Join(raws, "\n") + "\n"
+}
+
+// 问题类型
+const (
+	HostsDuplicate = "duplicate" // 同一 IP 与主机名出现在多行
+	HostsConflict  = "conflict"  // 同一主机名在同一地址族内指向不同 IP
+	HostsInvalid   = "invalid"   // 无法解析的 IP 或主机名
+)
+
+// HostsProblem 检测到的问题
+type HostsProblem struct {
+	Kind string
+	Name string   // 主机名（invalid 时为原始行）
+	IPs  []string // 涉及的 IP
+}
+
+// Problems 检测重复、冲突与无效条目
+func (f *HostsFile) Problems() []HostsProblem {
+	var problems []HostsProblem
+
+	type key struct{ name, ip string }
+	seen := make(map[key]int)
+	// 主机名 -> 地址族 -> IP 列表（保持出现顺序）
+	byName := make(map[string]map[bool][]string)
+	var names []string
+
+	for _, entry := range f.Entries() {
+		if err := entry.Validate(); err != nil {
+			problems = append(problems, HostsProblem{Kind: HostsInvalid, Name: entry.String(), IPs: []string{entry.IP}})
+			continue
+		}
+		ip := parseHostsIP(entry.IP).String()
+		v6 := entry.IsIPv6()
+		for _, name := range entry.Names() {
+			name = strings.ToLower(name)
+			seen[key{name, ip}]++
+			if seen[key{name, ip}] == 2 {
+				problems = append(problems, HostsProblem{Kind: HostsDuplicate, Name: name, IPs: []string{ip}})
+			}
+
+			if byName[name] == nil {
+				byName[name] = make(map[bool][]string)
+				names = append(names, name)
+			}
+			if !containsString(byName[name][v6], ip) {
+				byName[name][v6] = append(byName[name][v6], ip)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v6 := range []bool{false, true} {
+			if ips := byName[name][v6]; len(ips) > 1 {
+				problems = append(problems, HostsProblem{Kind: HostsConflict, Name: name, IPs: ips})
+			}
+		}
+	}
+	return problems
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadHosts 读取 /etc/hosts；文件不存在时返回空文件
+func LoadHosts() (*HostsFile, error) {
+	path := system.RootPath(hostsFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &HostsFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ParseHosts(string(data)), nil
+}
+
+// SaveHosts 备份并写回 /etc/hosts
+func SaveHosts(f *HostsFile, dryRun bool, logger *internal.Logger) (err error) {
+	path := system.RootPath(hostsFile)
+	content := f.String()
+
+	op := history.Begin(dryRun, "hostname", "save_hosts", fmt.Sprintf("%d entries", len(f.Entries())), path)
+	defer func() { op.End(err, logger) }()
+
+	if dryRun {
+		internal.NewDryRunManager(dryRun, logger).LogFileWrite(path, content)
+		return nil
+	}
+
+	backupPath, err := backupFileFn(path)
+	if err != nil {
+		return fmt.Errorf("failed to backup %s: %w", path, err)
+	}
+	if backupPath != "" {
+		logger.Info("Backed up: %s -> %s", path, backupPath)
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode()
+	}
+	if err := safeWriteFn(path, []byte(content), perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	logger.Info("Written to %s", path)
+
+	// 记录用户维护的条目（发行版默认条目不参与漂移检测）
+	var managed []state.HostsEntry
+	for _, e := range f.Entries() {
+		if !e.IsSystem() {
+			managed = append(managed, state.HostsEntry{IP: e.IP, Names: e.Names()})
+		}
+	}
+	state.Record(logger, func(st *state.State) { st.ReplaceHosts(managed) })
+	return nil
+}
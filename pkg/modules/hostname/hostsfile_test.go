@@ -0,0 +1,119 @@
+package hostname
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const debianHosts = `127.0.0.1	localhost
+127.0.1.1	web-01.example.com web-01
+
+# The following lines are desirable for IPv6 capable hosts
+::1     localhost ip6-localhost ip6-loopback
+ff02::1 ip6-allnodes
+ff02::2 ip6-allrouters
+10.0.0.5 db.internal db # primary database
+`
+
+func TestParseHostsRoundTrip(t *testing.T) {
+	f := ParseHosts(debianHosts)
+	assert.Equal(t, debianHosts, f.String())
+
+	entries := f.Entries()
+	require.Len(t, entries, 6)
+	assert.Equal(t, HostsEntry{IP: "127.0.1.1", Canonical: "web-01.example.com", Aliases: []string{"web-01"}}, entries[1])
+	assert.Equal(t, "primary database", entries[5].Comment)
+
+	assert.True(t, entries[0].IsSystem())
+	assert.False(t, entries[1].IsSystem())
+	assert.True(t, entries[2].IsIPv6())
+	assert.True(t, entries[3].IsSystem())
+	assert.False(t, entries[5].IsIPv6())
+	assert.Empty(t, f.Problems())
+}
+
+func TestHostsFileEdit(t *testing.T) {
+	f := ParseHosts(debianHosts)
+
+	require.NoError(t, f.Add(HostsEntry{IP: "10.0.0.6", Canonical: "cache.internal", Aliases: []string{"cache"}}))
+	require.NoError(t, f.Update(5, HostsEntry{IP: "10.0.0.7", Canonical: "db.internal", Aliases: []string{"db"}, Comment: "moved"}))
+	require.NoError(t, f.Remove(3))
+
+	out := f.String()
+	assert.NotContains(t, out, "ip6-allnodes")
+	assert.Contains(t, out, "# The following lines are desirable for IPv6 capable hosts\n")
+	assert.Contains(t, out, "10.0.0.7\tdb.internal db # moved\n")
+	assert.True(t, len(out) > 0 && out[len(out)-1] == '\n')
+	assert.Contains(t, out, "10.0.0.6\tcache.internal cache\n")
+	assert.Len(t, f.Lookup("DB"), 1)
+
+	assert.Error(t, f.Add(HostsEntry{IP: "10.0.0.300", Canonical: "bad"}))
+	assert.Error(t, f.Add(HostsEntry{IP: "10.0.0.8", Canonical: "bad_name"}))
+	assert.Error(t, f.Update(99, HostsEntry{IP: "10.0.0.8", Canonical: "x"}))
+	assert.Error(t, f.Remove(99))
+}
+
+func TestHostsProblems(t *testing.T) {
+	f := ParseHosts(`127.0.0.1 localhost
+::1 localhost
+127.0.1.1 web-01
+10.0.0.5 web-01
+10.0.0.9 api
+10.0.0.9 api
+fe80::1%eth0 router
+not-an-ip broken
+`)
+
+	problems := f.Problems()
+	assert.Contains(t, problems, HostsProblem{Kind: HostsDuplicate, Name: "api", IPs: []string{"10.0.0.9"}})
+	assert.Contains(t, problems, HostsProblem{Kind: HostsConflict, Name: "web-01", IPs: []string{"127.0.1.1", "10.0.0.5"}})
+	assert.Contains(t, problems, HostsProblem{Kind: HostsInvalid, Name: "not-an-ip\tbroken", IPs: []string{"not-an-ip"}})
+	// localhost 同时映射 IPv4 与 IPv6 不算冲突
+	for _, p := range problems {
+		assert.NotEqual(t, "localhost", p.Name)
+		assert.NotEqual(t, "router", p.Name)
+	}
+}
+
+func TestSaveHosts(t *testing.T) {
+	isolateState(t)
+	hostsPath := filepath.Join(t.TempDir(), "hosts")
+	require.NoError(t, os.WriteFile(hostsPath, []byte(debianHosts), 0640))
+
+	oldHostsFile := hostsFile
+	hostsFile = hostsPath
+	t.Cleanup(func() { hostsFile = oldHostsFile })
+
+	f, err := LoadHosts()
+	require.NoError(t, err)
+	require.NoError(t, f.Add(HostsEntry{IP: "2001:db8::10", Canonical: "v6.internal"}))
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	require.NoError(t, SaveHosts(f, false, logger))
+
+	out, err := os.ReadFile(hostsPath)
+	require.NoError(t, err)
+	assert.Equal(t, debianHosts+"2001:db8::10\tv6.internal\n", string(out))
+
+	info, err := os.Stat(hostsPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	backups, _ := filepath.Glob(hostsPath + ".bak.*")
+	assert.Len(t, backups, 1)
+
+	// 只记录非系统条目
+	st, err := state.Load()
+	require.NoError(t, err)
+	var ips []string
+	for _, e := range st.Hosts {
+		ips = append(ips, e.IP)
+	}
+	assert.Equal(t, []string{"127.0.1.1", "10.0.0.5", "2001:db8::10"}, ips)
+}
@@ -0,0 +1,477 @@
+package hostname
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+var (
+	mailnameFile    = "/etc/mailname"
+	postfixMainCf   = "/etc/postfix/main.cf"
+	machineInfoFile = "/etc/machine-info"
+	motdFile        = "/etc/motd"
+
+	// certFiles 常见的发行版自签名证书（证书无法原地改名，只提示）
+	certFiles = []string{
+		"/etc/ssl/certs/ssl-cert-snakeoil.pem",
+		"/etc/pki/tls/certs/localhost.crt",
+	}
+)
+
+// 影响项类型
+const (
+	ImpactMailname    = "mailname"
+	ImpactPostfix     = "postfix"
+	ImpactHosts       = "hosts"
+	ImpactMachineInfo = "machine_info"
+	ImpactMOTD        = "motd"
+	ImpactCertificate = "certificate"
+	ImpactDockerSwarm = "docker_swarm"
+)
+
+// Impact 引用了旧主机名的配置
+type Impact struct {
+	Kind      string
+	Path      string   // 逻辑路径（docker_swarm 为空）
+	Lines     []string // 命中的行
+	Updatable bool     // 可自动替换；否则需手动处理
+}
+
+// ImpactReport 旧主机名的影响扫描结果
+type ImpactReport struct {
+	OldShort string
+	OldFQDN  string
+	Items    []Impact
+}
+
+// Updatable 返回可自动更新的项
+func (r *ImpactReport) Updatable() []Impact {
+	var items []Impact
+	for _, item := range r.Items {
+		if item.Updatable {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Manual 返回需要手动处理的项
+func (r *ImpactReport) Manual() []Impact {
+	var items []Impact
+	for _, item := range r.Items {
+		if !item.Updatable {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Select 只保留指定路径的可更新项（手动项保持不变）
+func (r *ImpactReport) Select(paths []string) *ImpactReport {
+	out := &ImpactReport{OldShort: r.OldShort, OldFQDN: r.OldFQDN}
+	for _, item := range r.Items {
+		if !item.Updatable || containsString(paths, item.Path) {
+			out.Items = append(out.Items, item)
+		}
+	}
+	return out
+}
+
+// names 旧名称（FQDN 优先，避免先替换短名破坏 FQDN）；localhost 等占位名称不参与替换
+func (r *ImpactReport) names() []string {
+	var names []string
+	for _, name := range []string{r.OldFQDN, r.OldShort} {
+		if name != "" && !isPlaceholderHostname(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isPlaceholderHostname 新装系统的占位主机名：回环地址与 postfix 默认配置中到处都是，不能当作本机名称替换
+func isPlaceholderHostname(name string) bool {
+	switch strings.ToLower(name) {
+	case "localhost", "localhost.localdomain":
+		return true
+	}
+	return false
+}
+
+// FQDNFromHosts 从 /etc/hosts 中找到以短主机名开头的 FQDN
+func FQDNFromHosts(short string) string {
+	hosts, err := LoadHosts()
+	if err != nil {
+		return ""
+	}
+	for _, entry := range hosts.Lookup(short) {
+		for _, name := range entry.Names() {
+			name = NormalizeHostname(name)
+			if strings.HasPrefix(name, short+".") && ValidateFQDN(name) == nil {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// ScanImpact 扫描引用旧主机名的配置：mailname、postfix、hosts 别名、machine-info、MOTD、
+// 自签名证书与 Docker Swarm 节点。旧名称为 localhost 等占位名称时不扫描，返回空结果
+func ScanImpact(oldShort, oldFQDN string) (*ImpactReport, error) {
+	r := &ImpactReport{OldShort: NormalizeHostname(oldShort), OldFQDN: NormalizeHostname(oldFQDN)}
+	if r.OldShort == "" && r.OldFQDN == "" {
+		return nil, fmt.Errorf("old hostname is empty")
+	}
+	if len(r.names()) == 0 {
+		return r, nil
+	}
+
+	for _, f := range []struct {
+		kind string
+		path string
+	}{
+		{ImpactMailname, mailnameFile},
+		{ImpactPostfix, postfixMainCf},
+		{ImpactHosts, hostsFile},
+		{ImpactMachineInfo, machineInfoFile},
+		{ImpactMOTD, motdFile},
+	} {
+		lines, err := r.scanFile(f.kind, f.path)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) > 0 {
+			r.Items = append(r.Items, Impact{Kind: f.kind, Path: f.path, Lines: lines, Updatable: true})
+		}
+	}
+
+	for _, path := range certFiles {
+		if subject, ok := r.certMatches(path); ok {
+			r.Items = append(r.Items, Impact{Kind: ImpactCertificate, Path: path, Lines: []string{subject}})
+		}
+	}
+
+	if node, ok := swarmNode(); ok {
+		r.Items = append(r.Items, Impact{Kind: ImpactDockerSwarm, Lines: []string{node}})
+	}
+
+	return r, nil
+}
+
+// scanFile 返回文件中引用旧名称的行
+func (r *ImpactReport) scanFile(kind, path string) ([]string, error) {
+	data, err := os.ReadFile(system.RootPath(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	_, found := r.rewrite(kind, string(data), "", "")
+	return found, nil
+}
+
+// postfixHostKeys main.cf 中只替换这些参数的取值，其余参数（mail_owner、路径等）即使含有旧名称也不动
+var postfixHostKeys = map[string]bool{
+	"myhostname":    true,
+	"mydomain":      true,
+	"myorigin":      true,
+	"mydestination": true,
+}
+
+// rewrite 按文件类型替换旧名称，返回新内容与命中的原始行：
+//   - mailname 与 main.cf 的主机名参数只替换完整的名称取值
+//   - hosts 只替换非回环地址行中的完整名称字段（127.0.1.1 等回环行由 UpdateHosts 负责）
+//   - machine-info 的 PRETTY_HOSTNAME 与 MOTD 为自由文本，按主机名边界替换
+func (r *ImpactReport) rewrite(kind, content, newShort, newFQDN string) (string, []string) {
+	var found []string
+	lines := strings.Split(content, "\n")
+	postfixKey := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		updated, ok := line, false
+		switch kind {
+		case ImpactMailname:
+			updated, ok = r.replaceTokens(line, newShort, newFQDN)
+		case ImpactPostfix:
+			// 以空白开头的行是上一参数的续行
+			if line[0] != ' ' && line[0] != '\t' {
+				key, _, _ := strings.Cut(line, "=")
+				postfixKey = strings.TrimSpace(key)
+			}
+			if !postfixHostKeys[postfixKey] {
+				continue
+			}
+			prefix, value := "", line
+			if k, v, ok := strings.Cut(line, "="); ok && line[0] != ' ' && line[0] != '\t' {
+				prefix, value = k+"=", v
+			}
+			value, ok = r.replaceTokens(value, newShort, newFQDN)
+			updated = prefix + value
+		case ImpactHosts:
+			entry := parseHostsLine(line)
+			if entry == nil {
+				continue
+			}
+			if ip := net.ParseIP(entry.IP); ip == nil || ip.IsLoopback() {
+				continue
+			}
+			data, comment, hasComment := strings.Cut(line, "#")
+			ipEnd := strings.Index(data, entry.IP) + len(entry.IP)
+			names, changed := r.replaceTokens(data[ipEnd:], newShort, newFQDN)
+			updated, ok = data[:ipEnd]+names, changed
+			if hasComment {
+				updated += "#" + comment
+			}
+		case ImpactMachineInfo:
+			if !strings.HasPrefix(trimmed, "PRETTY_HOSTNAME=") {
+				continue
+			}
+			updated, ok = r.replace(line, newShort, newFQDN)
+		default:
+			updated, ok = r.replace(line, newShort, newFQDN)
+		}
+		if ok {
+			found = append(found, trimmed)
+			lines[i] = updated
+		}
+	}
+	return strings.Join(lines, "\n"), found
+}
+
+// replacement 旧名称对应的新名称；newFQDN 为空时 FQDN 退化为短名
+func (r *ImpactReport) replacement(old, newShort, newFQDN string) string {
+	if newFQDN == "" {
+		newFQDN = newShort
+	}
+	if old == r.OldFQDN && old != r.OldShort {
+		return newFQDN
+	}
+	return newShort
+}
+
+// replaceTokens 只替换与旧名称完全相同（大小写不敏感）的取值，取值以空白或逗号分隔
+func (r *ImpactReport) replaceTokens(s, newShort, newFQDN string) (string, bool) {
+	isSep := func(c byte) bool { return c == ' ' || c == '\t' || c == ',' }
+	var b strings.Builder
+	changed := false
+	for i := 0; i < len(s); {
+		if isSep(s[i]) {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && !isSep(s[j]) {
+			j++
+		}
+		token := s[i:j]
+		for _, old := range r.names() {
+			if strings.EqualFold(token, old) {
+				token = r.replacement(old, newShort, newFQDN)
+				changed = true
+				break
+			}
+		}
+		b.WriteString(token)
+		i = j
+	}
+	return b.String(), changed
+}
+
+// replace 把自由文本中的旧名称替换为新名称
+func (r *ImpactReport) replace(line, newShort, newFQDN string) (string, bool) {
+	changed := false
+	for _, old := range r.names() {
+		var ok bool
+		line, ok = replaceName(line, old, r.replacement(old, newShort, newFQDN))
+		changed = changed || ok
+	}
+	return line, changed
+}
+
+// isNameChar 主机名字符（不含点）；下划线与斜杠也不视为边界，避免命中 mail_owner 或 /etc/postfix 这类标识与路径
+func isNameChar(c byte) bool {
+	return c == '-' || c == '_' || c == '/' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// replaceName 按主机名边界替换（大小写不敏感）：web-01 不会命中 web-010 或 web-01.example.com，
+// 但句末的 "web-01." 会命中
+func replaceName(line, old, repl string) (string, bool) {
+	if old == "" {
+		return line, false
+	}
+	lower := strings.ToLower(line)
+	old = strings.ToLower(old)
+
+	var b strings.Builder
+	changed := false
+	pos := 0
+	for {
+		i := strings.Index(lower[pos:], old)
+		if i < 0 {
+			break
+		}
+		start := pos + i
+		end := start + len(old)
+
+		before := start == 0 || !(isNameChar(line[start-1]) || line[start-1] == '.')
+		after := end == len(line) || !isNameChar(line[end]) &&
+			(line[end] != '.' || end+1 == len(line) || !isNameChar(line[end+1]))
+		if before && after {
+			b.WriteString(line[pos:start])
+			b.WriteString(repl)
+			changed = true
+		} else {
+			b.WriteString(line[pos:end])
+		}
+		pos = end
+	}
+	b.WriteString(line[pos:])
+	return b.String(), changed
+}
+
+// certMatches 自签名证书的 CN 或 SAN 是否为旧名称
+func (r *ImpactReport) certMatches(path string) (string, bool) {
+	data, err := os.ReadFile(system.RootPath(path))
+	if err != nil {
+		return "", false
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || cert.Subject.String() != cert.Issuer.String() {
+		return "", false
+	}
+
+	for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		for _, old := range r.names() {
+			if strings.EqualFold(name, old) {
+				return cert.Subject.String(), true
+			}
+		}
+	}
+	return "", false
+}
+
+// swarmNode 本机是否为 Docker Swarm 节点（节点名在加入时固定，不随主机名变化）
+func swarmNode() (string, bool) {
+	if system.HasRoot() || !system.CommandExists("docker") {
+		return "", false
+	}
+	res, err := system.QueryCommand("docker", "info", "--format", "{{.Swarm.LocalNodeState}} {{.Swarm.NodeID}}")
+	if err != nil {
+		return "", false
+	}
+	state, nodeID, _ := strings.Cut(strings.TrimSpace(res.Stdout), " ")
+	if state != "active" {
+		return "", false
+	}
+	return nodeID, true
+}
+
+// UpdateReferences 把可更新项中的旧名称替换为新名称（逐个备份后写回）
+func (m *Manager) UpdateReferences(r *ImpactReport, newShort, newFQDN string) (err error) {
+	var files []string
+	for _, item := range r.Updatable() {
+		files = append(files, system.RootPath(item.Path))
+	}
+	op := history.Begin(m.dryRun, "hostname", "update_references", newShort, files...)
+	defer func() { op.End(err, m.logger) }()
+
+	for _, item := range r.Updatable() {
+		path := system.RootPath(item.Path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		content, found := r.rewrite(item.Kind, string(data), newShort, newFQDN)
+		if len(found) == 0 {
+			continue
+		}
+
+		if err := m.rewriteFile(path, content); err != nil {
+			return err
+		}
+		if item.Kind == ImpactPostfix {
+			m.reloadPostfix()
+		}
+	}
+	return nil
+}
+
+// reloadPostfix 让 postfix 重新读取 main.cf（失败只记录警告）
+func (m *Manager) reloadPostfix() {
+	if m.dryRun {
+		m.drm.LogCommand("postfix", "reload")
+		return
+	}
+	if system.HasRoot() || !system.CommandExists("postfix") {
+		return
+	}
+	if _, err := system.RunCommand("postfix", "reload"); err != nil {
+		m.logger.Warn("Failed to reload postfix: %v", err)
+	}
+}
+
+// setMachineInfoPretty 设置 machine-info 中的 PRETTY_HOSTNAME（保留其他键）
+func setMachineInfoPretty(content, pretty string) string {
+	line := "PRETTY_HOSTNAME=" + strconv.Quote(pretty)
+	content = strings.TrimSuffix(content, "\n")
+
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+	for i, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "PRETTY_HOSTNAME=") {
+			lines[i] = line
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return strings.Join(append(lines, line), "\n") + "\n"
+}
+
+// rewriteFile 备份并写回文件（保留原权限）
+func (m *Manager) rewriteFile(path, content string) error {
+	if m.dryRun {
+		m.drm.LogFileWrite(path, content)
+		return nil
+	}
+
+	backupPath, err := backupFileFn(path)
+	if err != nil {
+		return fmt.Errorf("failed to backup %s: %w", path, err)
+	}
+	if backupPath != "" {
+		m.logger.Info("Backed up: %s -> %s", path, backupPath)
+	}
+
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode()
+	}
+	if err := safeWriteFn(path, []byte(content), perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	m.logger.Info("Written to %s", path)
+	return nil
+}
@@ -0,0 +1,197 @@
+package hostname
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceName(t *testing.T) {
+	cases := []struct {
+		line, want string
+		changed    bool
+	}{
+		{"myhostname = web-01", "myhostname = db-02", true},
+		{"Welcome to WEB-01.", "Welcome to db-02.", true},
+		{"10.0.0.1 web-010 web-01b", "10.0.0.1 web-010 web-01b", false},
+		{"web-01.example.com", "web-01.example.com", false},
+		{"mydestination = web-01, localhost", "mydestination = db-02, localhost", true},
+		{"node.web-01", "node.web-01", false},
+	}
+	for _, c := range cases {
+		got, changed := replaceName(c.line, "web-01", "db-02")
+		assert.Equal(t, c.want, got, c.line)
+		assert.Equal(t, c.changed, changed, c.line)
+	}
+}
+
+// setupImpactFiles 在临时目录中创建受影响的配置文件
+func setupImpactFiles(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]*string{
+		"mailname":     &mailnameFile,
+		"main.cf":      &postfixMainCf,
+		"hosts":        &hostsFile,
+		"machine-info": &machineInfoFile,
+		"motd":         &motdFile,
+	}
+	saved := make(map[*string]string)
+	for name, ptr := range files {
+		saved[ptr] = *ptr
+		*ptr = filepath.Join(dir, name)
+	}
+	oldCerts := certFiles
+	certFiles = nil
+	oldRunner := system.SetRunner(system.NewFakeRunner())
+	t.Cleanup(func() {
+		for ptr, v := range saved {
+			*ptr = v
+		}
+		certFiles = oldCerts
+		system.SetRunner(oldRunner)
+	})
+
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0640))
+	}
+	write("mailname", "web-01.example.com\n")
+	write("main.cf", "# myhostname = web-01.example.com\nmyhostname = web-01.example.com\nmydestination = $myhostname, web-01, localhost\nrelayhost =\n")
+	write("hosts", "127.0.0.1 localhost\n127.0.1.1 web-01.example.com web-01\n10.0.0.5 web-01-mgmt web-01\n")
+	write("machine-info", "PRETTY_HOSTNAME=\"web-01\"\nDEPLOYMENT=production\n")
+	write("motd", "Welcome to web-01.\n")
+	return dir
+}
+
+func TestScanImpact(t *testing.T) {
+	setupImpactFiles(t)
+
+	r, err := ScanImpact("web-01", "web-01.example.com")
+	require.NoError(t, err)
+
+	kinds := make(map[string]Impact)
+	for _, item := range r.Items {
+		kinds[item.Kind] = item
+	}
+	assert.Equal(t, []string{"web-01.example.com"}, kinds[ImpactMailname].Lines)
+	assert.Equal(t, []string{"myhostname = web-01.example.com", "mydestination = $myhostname, web-01, localhost"}, kinds[ImpactPostfix].Lines)
+	// 127.0.1.1 行由 UpdateHosts 处理，不重复列出
+	assert.Equal(t, []string{"10.0.0.5 web-01-mgmt web-01"}, kinds[ImpactHosts].Lines)
+	assert.Equal(t, []string{`PRETTY_HOSTNAME="web-01"`}, kinds[ImpactMachineInfo].Lines)
+	assert.Equal(t, []string{"Welcome to web-01."}, kinds[ImpactMOTD].Lines)
+	assert.Len(t, r.Updatable(), 5)
+	assert.Empty(t, r.Manual())
+
+	_, err = ScanImpact("", "")
+	assert.Error(t, err)
+}
+
+func TestUpdateReferences(t *testing.T) {
+	dir := setupImpactFiles(t)
+
+	r, err := ScanImpact("web-01", "web-01.example.com")
+	require.NoError(t, err)
+	// 只更新 mailname 与 postfix
+	r = r.Select([]string{mailnameFile, postfixMainCf})
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	require.NoError(t, NewManager(false, logger).UpdateReferences(r, "db-02", "db-02.example.com"))
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "db-02.example.com\n", read("mailname"))
+	assert.Equal(t, "# myhostname = web-01.example.com\nmyhostname = db-02.example.com\nmydestination = $myhostname, db-02, localhost\nrelayhost =\n", read("main.cf"))
+	assert.Contains(t, read("motd"), "web-01")
+
+	info, err := os.Stat(filepath.Join(dir, "main.cf"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	backups, _ := filepath.Glob(filepath.Join(dir, "mailname.bak.*"))
+	assert.Len(t, backups, 1)
+}
+
+// 常见主机名（mail、debian）也出现在参数名、路径与回环行中：只替换主机名参数的取值与非回环行的完整名称
+func TestUpdateReferencesOnlyTouchesHostnameValues(t *testing.T) {
+	setupImpactFiles(t)
+	mainCf := "mail_owner = postfix\n" +
+		"smtpd_banner = $myhostname ESMTP $mail_name (Debian/GNU)\n" +
+		"virtual_alias_maps = hash:/etc/postfix/virtual\n" +
+		"myhostname = mail.example.com\n" +
+		"mydestination = $myhostname, mail,\n" +
+		"    localhost.example.com, localhost\n"
+	hosts := "127.0.0.1 localhost mail\n::1 localhost ip6-localhost\n10.0.0.5 mail mail.example.com mailhost # mail server\n"
+	require.NoError(t, os.WriteFile(postfixMainCf, []byte(mainCf), 0644))
+	require.NoError(t, os.WriteFile(hostsFile, []byte(hosts), 0644))
+	require.NoError(t, os.WriteFile(mailnameFile, []byte("mail.example.com\n"), 0644))
+	require.NoError(t, os.WriteFile(motdFile, []byte("Logs are in /var/log/mail.log\n"), 0644))
+
+	r, err := ScanImpact("mail", "mail.example.com")
+	require.NoError(t, err)
+	kinds := make(map[string]Impact)
+	for _, item := range r.Items {
+		kinds[item.Kind] = item
+	}
+	assert.Equal(t, []string{"myhostname = mail.example.com", "mydestination = $myhostname, mail,"}, kinds[ImpactPostfix].Lines)
+	assert.Equal(t, []string{"10.0.0.5 mail mail.example.com mailhost # mail server"}, kinds[ImpactHosts].Lines)
+	assert.NotContains(t, kinds, ImpactMOTD)
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	require.NoError(t, NewManager(false, logger).UpdateReferences(r, "mx1", "mx1.example.com"))
+
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, strings.Replace(strings.Replace(mainCf, "myhostname = mail.example.com", "myhostname = mx1.example.com", 1),
+		"$myhostname, mail,", "$myhostname, mx1,", 1), read(postfixMainCf))
+	assert.Equal(t, "127.0.0.1 localhost mail\n::1 localhost ip6-localhost\n10.0.0.5 mx1 mx1.example.com mailhost # mail server\n", read(hostsFile))
+	assert.Equal(t, "mx1.example.com\n", read(mailnameFile))
+}
+
+// 新装系统的主机名常为 localhost：不扫描，避免改写回环行与 postfix 默认配置
+func TestScanImpactRefusesLocalhost(t *testing.T) {
+	setupImpactFiles(t)
+	require.NoError(t, os.WriteFile(hostsFile, []byte("127.0.0.1 localhost\n::1 localhost ip6-localhost\n"), 0644))
+
+	for _, name := range [][2]string{{"localhost", ""}, {"localhost", "localhost.localdomain"}} {
+		r, err := ScanImpact(name[0], name[1])
+		require.NoError(t, err)
+		assert.Empty(t, r.Items, name)
+	}
+}
+
+func TestSetHostnameWithOptions(t *testing.T) {
+	dir := setupImpactFiles(t)
+	fake := system.NewFakeRunner()
+	oldRunner := system.SetRunner(fake)
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	logger := internal.NewLogger(internal.ERROR, os.Stdout)
+	mgr := NewManager(false, logger)
+
+	assert.Error(t, mgr.SetHostnameWithOptions("db-02", "", SetOptions{}))
+
+	// 仅临时主机名：不写 /etc/hostname
+	require.NoError(t, mgr.SetHostnameWithOptions("db-02", "", SetOptions{Transient: true, Pretty: "Database 02"}))
+	assert.Equal(t, []string{
+		"hostnamectl set-hostname db-02 --transient",
+		`hostnamectl set-hostname --pretty "Database 02"`,
+	}, fake.Calls())
+
+	assert.Equal(t, "PRETTY_HOSTNAME=\"Database 02\"\nDEPLOYMENT=production\n",
+		setMachineInfoPretty("PRETTY_HOSTNAME=\"web-01\"\nDEPLOYMENT=production\n", "Database 02"))
+	assert.Equal(t, "PRETTY_HOSTNAME=\"db\"\n", setMachineInfoPretty("", "db"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "machine-info"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `PRETTY_HOSTNAME="web-01"`))
+}
@@ -0,0 +1,342 @@
+package hostname
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// metadataBaseURL 云厂商元数据服务（EC2/OpenStack/DigitalOcean 均使用链路本地地址）
+	metadataBaseURL = "http://169.254.169.254"
+	metadataTimeout = 2 * time.Second
+
+	lookupAddr = net.DefaultResolver.LookupAddr
+	lookupHost = net.DefaultResolver.LookupHost
+	primaryIP  = outboundIP
+	localAddrs = interfaceIPs
+)
+
+// 建议来源
+const (
+	SourcePTR      = "ptr"
+	SourceMetadata = "metadata"
+	SourceTemplate = "template"
+)
+
+// maxTemplateSeq 模板 {nn} 的最大序号
+const maxTemplateSeq = 99
+
+var (
+	templateVarRegex = regexp.MustCompile(`\{([a-z]+)\}`)
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+)
+
+// Suggestion 候选主机名
+type Suggestion struct {
+	Source string
+	Short  string
+	FQDN   string // 可能为空
+	Detail string // 来源说明（IP、云厂商等）
+}
+
+// Name 返回 FQDN（没有时返回短名）
+func (s Suggestion) Name() string {
+	if s.FQDN != "" {
+		return s.FQDN
+	}
+	return s.Short
+}
+
+// SuggestOptions 候选主机名的来源配置
+type SuggestOptions struct {
+	Template string // 命名模板，如 {role}-{region}-{nn}；空表示不使用
+	Role     string
+	Region   string // 为空时使用元数据中的区域
+	Domain   string // 模板生成名称追加的域名（可选）
+}
+
+// Metadata 从云厂商元数据服务读取的信息
+type Metadata struct {
+	Provider string
+	Hostname string
+	Region   string
+}
+
+// Suggest 汇总 PTR、云元数据与命名模板的候选主机名（已校验、去重）；
+// PTR 与元数据失败时静默跳过，模板无法确定空闲序号时返回错误（其余候选照常返回）
+func Suggest(opts SuggestOptions) ([]Suggestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*metadataTimeout)
+	defer cancel()
+
+	var candidates []Suggestion
+	if ip, err := primaryIP(); err == nil {
+		if names, err := lookupAddr(ctx, ip); err == nil {
+			for _, name := range names {
+				candidates = append(candidates, suggestionFor(SourcePTR, name, ip))
+			}
+		}
+	}
+
+	md, mdErr := FetchMetadata(ctx, metadataBaseURL)
+	if mdErr == nil && md.Hostname != "" {
+		candidates = append(candidates, suggestionFor(SourceMetadata, md.Hostname, md.Provider))
+	}
+
+	var templateErr error
+	if opts.Template != "" {
+		vars := map[string]string{"role": opts.Role, "region": opts.Region}
+		if vars["region"] == "" && mdErr == nil {
+			vars["region"] = md.Region
+		}
+		if name, err := nextTemplateName(opts.Template, vars, opts.Domain); err == nil {
+			candidates = append(candidates, suggestionFor(SourceTemplate, name, opts.Template))
+		} else {
+			templateErr = err
+		}
+	}
+
+	var out []Suggestion
+	seen := make(map[string]bool)
+	for _, s := range candidates {
+		if s.Short == "" || seen[s.Name()] {
+			continue
+		}
+		if ValidateHostname(s.Short) != nil || (s.FQDN != "" && ValidateFQDN(s.FQDN) != nil) {
+			continue
+		}
+		seen[s.Name()] = true
+		out = append(out, s)
+	}
+	return out, templateErr
+}
+
+// suggestionFor 把名称拆为短名与 FQDN
+func suggestionFor(source, name, detail string) Suggestion {
+	// PTR 结果以 "." 结尾
+	name = strings.TrimSuffix(NormalizeHostname(name), ".")
+	s := Suggestion{Source: source, Short: GetShortHostname(name), Detail: detail}
+	if IsFQDN(name) {
+		s.FQDN = name
+	}
+	return s
+}
+
+// ExpandTemplate 展开命名模板：{role}、{region} 取自 vars，{nn} 为两位序号；
+// 结果转小写，非法字符替换为 -
+func ExpandTemplate(tmpl string, vars map[string]string, seq int) (string, error) {
+	var missing []string
+	name := templateVarRegex.ReplaceAllStringFunc(tmpl, func(m string) string {
+		key := m[1 : len(m)-1]
+		if key == "nn" {
+			return fmt.Sprintf("%02d", seq)
+		}
+		v := strings.TrimSpace(vars[key])
+		if v == "" {
+			missing = append(missing, key)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template variable not set: %s", strings.Join(missing, ", "))
+	}
+
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-.")
+	if err := ValidateHostname(name); err != nil {
+		return "", fmt.Errorf("template %q produces invalid hostname %q: %w", tmpl, name, err)
+	}
+	return name, nil
+}
+
+// nextTemplateName 取 DNS 中尚未使用的最小序号；
+// 每次查询使用独立超时，只有明确的 NXDOMAIN 才视为空闲，其他解析失败直接报错
+func nextTemplateName(tmpl string, vars map[string]string, domain string) (string, error) {
+	if !strings.Contains(tmpl, "{nn}") {
+		name, err := ExpandTemplate(tmpl, vars, 0)
+		if err != nil {
+			return "", err
+		}
+		return withDomain(name, domain), nil
+	}
+
+	for seq := 1; seq <= maxTemplateSeq; seq++ {
+		name, err := ExpandTemplate(tmpl, vars, seq)
+		if err != nil {
+			return "", err
+		}
+		name = withDomain(name, domain)
+		free, err := nameIsFree(name)
+		if err != nil {
+			return "", err
+		}
+		if free {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no free sequence number for template %q", tmpl)
+}
+
+// nameIsFree 查询名称是否未被 DNS 使用
+func nameIsFree(name string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+
+	_, err := lookupHost(ctx, name)
+	if err == nil {
+		return false, nil
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return true, nil
+	}
+	return false, fmt.Errorf("failed to check whether %s is in use: %w", name, err)
+}
+
+func withDomain(name, domain string) string {
+	domain = strings.Trim(NormalizeHostname(domain), ".")
+	if domain == "" || IsFQDN(name) {
+		return name
+	}
+	return name + "." + domain
+}
+
+// FetchMetadata 依次尝试 EC2（IMDSv2）、OpenStack 与 DigitalOcean 的元数据接口
+func FetchMetadata(ctx context.Context, baseURL string) (*Metadata, error) {
+	client := &http.Client{Timeout: metadataTimeout}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	if md, err := fetchEC2(ctx, client, baseURL); err == nil {
+		return md, nil
+	}
+	if md, err := fetchOpenStack(ctx, client, baseURL); err == nil {
+		return md, nil
+	}
+	if md, err := fetchDigitalOcean(ctx, client, baseURL); err == nil {
+		return md, nil
+	}
+	return nil, fmt.Errorf("no cloud metadata service at %s", baseURL)
+}
+
+func fetchEC2(ctx context.Context, client *http.Client, baseURL string) (*Metadata, error) {
+	token, err := httpText(ctx, client, http.MethodPut, baseURL+"/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		return nil, err
+	}
+	header := map[string]string{"X-aws-ec2-metadata-token": token}
+
+	md := &Metadata{Provider: "ec2"}
+	if md.Hostname, err = httpText(ctx, client, http.MethodGet, baseURL+"/latest/meta-data/hostname", header); err != nil {
+		return nil, err
+	}
+	md.Region, _ = httpText(ctx, client, http.MethodGet, baseURL+"/latest/meta-data/placement/region", header)
+	return md, nil
+}
+
+func fetchOpenStack(ctx context.Context, client *http.Client, baseURL string) (*Metadata, error) {
+	body, err := httpText(ctx, client, http.MethodGet, baseURL+"/openstack/latest/meta_data.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Hostname         string `json:"hostname"`
+		AvailabilityZone string `json:"availability_zone"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenStack metadata: %w", err)
+	}
+	return &Metadata{Provider: "openstack", Hostname: doc.Hostname, Region: doc.AvailabilityZone}, nil
+}
+
+func fetchDigitalOcean(ctx context.Context, client *http.Client, baseURL string) (*Metadata, error) {
+	name, err := httpText(ctx, client, http.MethodGet, baseURL+"/metadata/v1/hostname", nil)
+	if err != nil {
+		return nil, err
+	}
+	md := &Metadata{Provider: "digitalocean", Hostname: name}
+	md.Region, _ = httpText(ctx, client, http.MethodGet, baseURL+"/metadata/v1/region", nil)
+	return md, nil
+}
+
+// httpText 发起请求并返回去除首尾空白的响应体（仅接受 200）
+func httpText(ctx context.Context, client *http.Client, method, url string, header map[string]string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: HTTP %d", method, url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// VerifyForward 检查 FQDN 是否解析到本机地址之一（正向确认）
+func VerifyForward(fqdn string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+
+	addrs, err := lookupHost(ctx, fqdn)
+	if err != nil {
+		return fmt.Errorf("%s does not resolve: %w", fqdn, err)
+	}
+	local, err := localAddrs()
+	if err != nil {
+		return fmt.Errorf("failed to list local addresses: %w", err)
+	}
+
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		for _, l := range local {
+			if ip != nil && ip.Equal(l) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%s resolves to %s, not to this host", fqdn, strings.Join(addrs, ", "))
+}
+
+// outboundIP 默认路由的源地址（UDP connect 不发送数据）
+func outboundIP() (string, error) {
+	conn, err := net.Dial("udp", "192.0.2.1:53")
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
+
+// interfaceIPs 本机全部接口地址
+func interfaceIPs() ([]net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipnet.IP)
+		}
+	}
+	return ips, nil
+}
@@ -0,0 +1,159 @@
+package hostname
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{"role": "Web", "region": "eu_west 1"}
+
+	name, err := ExpandTemplate("{role}-{region}-{nn}", vars, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "web-eu-west-1-03", name)
+
+	_, err = ExpandTemplate("{role}-{zone}-{nn}", vars, 1)
+	assert.ErrorContains(t, err, "zone")
+
+	_, err = ExpandTemplate("{role}", map[string]string{"role": "---"}, 1)
+	assert.Error(t, err)
+}
+
+// metadataStub 模拟元数据服务（只注册给定路径）
+func metadataStub(t *testing.T, routes map[string]string) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, ok := routes[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path != "/latest/api/token" && len(r.URL.Path) > 8 && r.URL.Path[:8] == "/latest/" &&
+			r.Header.Get("X-aws-ec2-metadata-token") != "tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestFetchMetadata(t *testing.T) {
+	ctx := context.Background()
+
+	ec2 := metadataStub(t, map[string]string{
+		"PUT /latest/api/token":                  "tok",
+		"GET /latest/meta-data/hostname":         "ip-10-0-0-5.eu-west-1.compute.internal\n",
+		"GET /latest/meta-data/placement/region": "eu-west-1",
+	})
+	md, err := FetchMetadata(ctx, ec2)
+	require.NoError(t, err)
+	assert.Equal(t, &Metadata{Provider: "ec2", Hostname: "ip-10-0-0-5.eu-west-1.compute.internal", Region: "eu-west-1"}, md)
+
+	openstack := metadataStub(t, map[string]string{
+		"GET /openstack/latest/meta_data.json": `{"hostname":"api-1.novalocal","availability_zone":"nova"}`,
+	})
+	md, err = FetchMetadata(ctx, openstack)
+	require.NoError(t, err)
+	assert.Equal(t, &Metadata{Provider: "openstack", Hostname: "api-1.novalocal", Region: "nova"}, md)
+
+	do := metadataStub(t, map[string]string{
+		"GET /metadata/v1/hostname": "droplet-1",
+		"GET /metadata/v1/region":   "fra1",
+	})
+	md, err = FetchMetadata(ctx, do)
+	require.NoError(t, err)
+	assert.Equal(t, &Metadata{Provider: "digitalocean", Hostname: "droplet-1", Region: "fra1"}, md)
+
+	_, err = FetchMetadata(ctx, metadataStub(t, nil))
+	assert.Error(t, err)
+}
+
+// stubResolver 替换 DNS 与本机地址查询
+func stubResolver(t *testing.T, ptr map[string][]string, hosts map[string][]string, local ...string) {
+	stubResolverErrors(t, ptr, hosts, nil, local...)
+}
+
+// stubResolverErrors 同 stubResolver，hostErrs 中的名称查询返回指定错误
+func stubResolverErrors(t *testing.T, ptr, hosts map[string][]string, hostErrs map[string]error, local ...string) {
+	oldAddr, oldHost, oldPrimary, oldLocal := lookupAddr, lookupHost, primaryIP, localAddrs
+	lookupAddr = func(_ context.Context, ip string) ([]string, error) {
+		if names, ok := ptr[ip]; ok {
+			return names, nil
+		}
+		return nil, errors.New("no PTR")
+	}
+	lookupHost = func(_ context.Context, name string) ([]string, error) {
+		if addrs, ok := hosts[name]; ok {
+			return addrs, nil
+		}
+		if err, ok := hostErrs[name]; ok {
+			return nil, err
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	primaryIP = func() (string, error) { return "203.0.113.10", nil }
+	localAddrs = func() ([]net.IP, error) {
+		var ips []net.IP
+		for _, l := range local {
+			ips = append(ips, net.ParseIP(l))
+		}
+		return ips, nil
+	}
+	t.Cleanup(func() { lookupAddr, lookupHost, primaryIP, localAddrs = oldAddr, oldHost, oldPrimary, oldLocal })
+}
+
+func TestSuggest(t *testing.T) {
+	stubResolver(t,
+		map[string][]string{"203.0.113.10": {"web-01.example.com.", "bad_name.example.com."}},
+		map[string][]string{"web-fra1-01.example.com": {"203.0.113.11"}},
+	)
+	oldURL := metadataBaseURL
+	metadataBaseURL = metadataStub(t, map[string]string{
+		"GET /metadata/v1/hostname": "web-01.example.com",
+		"GET /metadata/v1/region":   "fra1",
+	})
+	t.Cleanup(func() { metadataBaseURL = oldURL })
+
+	got, err := Suggest(SuggestOptions{Template: "{role}-{region}-{nn}", Role: "web", Domain: "example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, []Suggestion{
+		{Source: SourcePTR, Short: "web-01", FQDN: "web-01.example.com", Detail: "203.0.113.10"},
+		// 01 已被占用，取下一个序号；元数据中的同名结果被去重
+		{Source: SourceTemplate, Short: "web-fra1-02", FQDN: "web-fra1-02.example.com", Detail: "{role}-{region}-{nn}"},
+	}, got)
+}
+
+func TestSuggestTemplateLookupFailure(t *testing.T) {
+	stubResolverErrors(t, nil,
+		map[string][]string{"web-01.example.com": {"203.0.113.11"}},
+		map[string]error{"web-02.example.com": &net.DNSError{Err: "i/o timeout", Name: "web-02.example.com", IsTimeout: true}},
+	)
+	oldURL := metadataBaseURL
+	metadataBaseURL = metadataStub(t, nil)
+	t.Cleanup(func() { metadataBaseURL = oldURL })
+
+	// 超时不能当作名称空闲
+	got, err := Suggest(SuggestOptions{Template: "web-{nn}", Domain: "example.com"})
+	assert.ErrorContains(t, err, "web-02.example.com")
+	assert.Empty(t, got)
+}
+
+func TestVerifyForward(t *testing.T) {
+	stubResolver(t, nil, map[string][]string{
+		"web-01.example.com": {"203.0.113.10"},
+		"old.example.com":    {"198.51.100.1"},
+	}, "127.0.0.1", "203.0.113.10")
+
+	assert.NoError(t, VerifyForward("web-01.example.com"))
+	assert.ErrorContains(t, VerifyForward("old.example.com"), "198.51.100.1")
+	assert.ErrorContains(t, VerifyForward("missing.example.com"), "does not resolve")
+}
@@ -0,0 +1,233 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// DefaultGuardTimeout 未确认时自动回滚的等待时间（与 netplan try 一致）
+const DefaultGuardTimeout = 120 * time.Second
+
+const revertUnit = "server-toolkit-network-revert"
+
+var (
+	stateDir = "/var/lib/server-toolkit"
+
+	backupFileFn = system.BackupFile
+	safeWriteFn  = system.SafeWrite
+)
+
+// ErrAlreadyReverted 确认时回滚任务已经执行
+var ErrAlreadyReverted = errors.New("network change was already reverted")
+
+// Guard 已应用、等待确认的网络配置；到期未确认时由独立于本进程的任务回滚
+// （SSH 断开导致本进程退出也会回滚）
+type Guard struct {
+	Deadline time.Time
+
+	script string // 回滚脚本
+	done   string // 标记文件：存在表示已确认或已回滚
+	unit   bool   // 通过 systemd-run 定时
+}
+
+// Confirm 保留新配置，取消定时回滚
+func (g *Guard) Confirm() error {
+	if g == nil {
+		return nil
+	}
+	// 与回滚脚本（set -C）竞争创建标记文件，只有一方能成功
+	f, err := os.OpenFile(g.done, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return ErrAlreadyReverted
+	}
+	if err != nil {
+		return fmt.Errorf("failed to confirm network change: %w", err)
+	}
+	_, err = f.WriteString("confirmed\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to confirm network change: %w", err)
+	}
+	if g.unit {
+		// 标记文件已阻止回滚；停止 timer 只是清理
+		_, _ = system.RunCommand("systemctl", "stop", revertUnit+".timer")
+	}
+	return nil
+}
+
+// Revert 立即回滚（已确认或已回滚时不做任何事）
+func (g *Guard) Revert() error {
+	if g == nil {
+		return nil
+	}
+	if _, err := system.RunCommand("sh", g.script); err != nil {
+		return fmt.Errorf("failed to revert network change: %w", err)
+	}
+	return nil
+}
+
+// Expired 是否已超过确认期限
+func (g *Guard) Expired(now time.Time) bool {
+	return g != nil && !g.Deadline.IsZero() && now.After(g.Deadline)
+}
+
+// Apply 写入配置并执行生效命令；timeout 内未调用 Guard.Confirm 则自动回滚。
+// dry-run 与 --root 下只写文件（或只记录），返回 nil Guard
+func (m *Manager) Apply(plan *Plan, timeout time.Duration) (*Guard, error) {
+	if m.dryRun {
+		for _, f := range plan.Files {
+			m.drm.LogFileWrite(system.RootPath(f.Path), f.Content)
+		}
+		for _, argv := range plan.Apply {
+			m.drm.LogCommand(argv[0], argv[1:]...)
+		}
+		return nil, nil
+	}
+
+	if system.HasRoot() {
+		for _, f := range plan.Files {
+			if _, err := m.writeFile(f); err != nil {
+				return nil, err
+			}
+		}
+		m.logger.Info("Root %s set, network configuration written without applying", system.Root())
+		return nil, nil
+	}
+
+	// 先备份，再生成回滚脚本并安排定时，最后才改动配置
+	var restores []string
+	for _, f := range plan.Files {
+		backup, err := backupFileFn(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backup %s: %w", f.Path, err)
+		}
+		if backup != "" {
+			m.logger.Info("Backed up: %s -> %s", f.Path, backup)
+			restores = append(restores, fmt.Sprintf("cp -p %s %s", shellQuote(backup), shellQuote(f.Path)))
+		} else {
+			restores = append(restores, fmt.Sprintf("rm -f %s", shellQuote(f.Path)))
+		}
+	}
+
+	g, err := m.schedule(plan, restores, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range plan.Files {
+		if _, err := m.writeFile(f); err != nil {
+			_ = g.Revert()
+			return nil, err
+		}
+	}
+
+	for _, argv := range plan.Apply {
+		if _, err := system.RunCommand(argv[0], argv[1:]...); err != nil {
+			if rerr := g.Revert(); rerr != nil {
+				return nil, fmt.Errorf("%w (revert also failed: %v)", err, rerr)
+			}
+			return nil, fmt.Errorf("%w (configuration reverted)", err)
+		}
+	}
+
+	m.logger.Info("Network configuration applied; reverting at %s unless confirmed", g.Deadline.Format("15:04:05"))
+	return g, nil
+}
+
+// schedule 写入回滚脚本并通过 systemd-run（或 setsid 后台进程）定时执行
+func (m *Manager) schedule(plan *Plan, restores []string, timeout time.Duration) (*Guard, error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", stateDir, err)
+	}
+	g := &Guard{
+		Deadline: time.Now().Add(timeout),
+		script:   filepath.Join(stateDir, "network-revert.sh"),
+		done:     filepath.Join(stateDir, "network-revert.done"),
+	}
+	if err := os.Remove(g.done); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to reset %s: %w", g.done, err)
+	}
+	if err := os.WriteFile(g.script, []byte(RevertScript(plan, restores, g.done)), 0700); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", g.script, err)
+	}
+
+	secs := fmt.Sprintf("%d", int(timeout.Seconds()))
+	switch {
+	case system.CommandExists("systemd-run"):
+		// 清理上一次遗留的同名单元
+		_, _ = system.RunCommand("systemctl", "stop", revertUnit+".timer", revertUnit+".service")
+		_, _ = system.RunCommand("systemctl", "reset-failed", revertUnit+".service")
+		if _, err := system.RunCommand("systemd-run", "--unit="+revertUnit, "--on-active="+secs+"s", "/bin/sh", g.script); err != nil {
+			return nil, fmt.Errorf("failed to schedule revert: %w", err)
+		}
+		g.unit = true
+	case system.CommandExists("setsid"):
+		job := fmt.Sprintf(`setsid sh -c 'sleep %s; sh "%s"' </dev/null >/dev/null 2>&1 &`, secs, g.script)
+		if _, err := system.RunCommand("sh", "-c", job); err != nil {
+			return nil, fmt.Errorf("failed to schedule revert: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("neither systemd-run nor setsid found; refusing to apply without automatic revert")
+	}
+	return g, nil
+}
+
+// RevertScript 生成回滚脚本：只执行一次，恢复文件后执行回滚命令并重新应用旧配置
+func RevertScript(plan *Plan, restores []string, doneFile string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n" + generatedHeader + ": revert network change unless confirmed\n")
+	// set -C：标记文件已存在（已确认或已回滚）时重定向失败，与 Confirm 的 O_EXCL 互斥
+	b.WriteString("set -C\n")
+	fmt.Fprintf(&b, "{ echo reverted > %s; } 2>/dev/null || exit 0\n", shellQuote(doneFile))
+	b.WriteString("set +C\n")
+	for _, line := range restores {
+		b.WriteString(line + "\n")
+	}
+	for _, argv := range plan.Revert {
+		b.WriteString(shellJoin(argv) + "\n")
+	}
+	if len(plan.Files) > 0 {
+		for _, argv := range plan.Apply {
+			b.WriteString(shellJoin(argv) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// writeFile 写入计划中的文件（保留已有文件权限，新文件使用计划中的权限）
+func (m *Manager) writeFile(f FileChange) (string, error) {
+	path := system.RootPath(f.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	perm := f.Perm
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode()
+	}
+	if err := safeWriteFn(path, []byte(f.Content), perm); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	m.logger.Info("Written to %s", path)
+	return path, nil
+}
+
+// shellQuote 单引号转义
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellJoin(argv []string) string {
+	quoted := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
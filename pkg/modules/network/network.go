@@ -0,0 +1,221 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// Stack 网络配置方案
+type Stack string
+
+const (
+	StackNetplan        Stack = "netplan"
+	StackNetworkManager Stack = "networkmanager"
+	StackNetworkd       Stack = "networkd"
+	StackIfupdown       Stack = "ifupdown"
+	StackUnknown        Stack = ""
+)
+
+var (
+	netplanDir         = "/etc/netplan"
+	networkdDir        = "/etc/systemd/network"
+	interfacesFile     = "/etc/network/interfaces"
+	nmConnectionsDir   = "/etc/NetworkManager/system-connections"
+	resolvConfFile     = "/etc/resolv.conf"
+	networkdFilePrefix = "10-server-toolkit-"
+)
+
+// Route 路由表项
+type Route struct {
+	Dest    string // default 或 CIDR
+	Gateway string
+	Dev     string
+	Proto   string
+	Metric  int
+	IPv6    bool
+}
+
+// IsDefault 是否为默认路由
+func (r Route) IsDefault() bool {
+	return r.Dest == "default"
+}
+
+// Status 当前网络状态
+type Status struct {
+	Stack      Stack
+	Interfaces []system.NetInterface
+	Routes     []Route
+	DNS        []string // resolv.conf 中的 nameserver（不含本地 stub）
+}
+
+// Manager 网络配置管理器
+type Manager struct {
+	dryRun bool
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewManager 创建网络配置管理器
+func NewManager(dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "network"})
+	return &Manager{
+		dryRun: dryRun,
+		logger: logger,
+		drm:    internal.NewDryRunManager(dryRun, logger),
+	}
+}
+
+// DetectStack 检测正在使用的网络配置方案（netplan 优先，因为它会渲染到 NetworkManager/networkd）
+func DetectStack() Stack {
+	if hasFiles(netplanDir, "*.yaml") && system.GuestCommandExists("netplan") {
+		return StackNetplan
+	}
+
+	if system.HasRoot() {
+		// 离线根目录：只能根据配置文件判断
+		switch {
+		case hasFiles(nmConnectionsDir, "*") && system.GuestCommandExists("nmcli"):
+			return StackNetworkManager
+		case hasFiles(networkdDir, "*.network"):
+			return StackNetworkd
+		case system.FileExists(system.RootPath(interfacesFile)):
+			return StackIfupdown
+		}
+		return StackUnknown
+	}
+
+	svc := system.NewServiceManager()
+	if system.CommandExists("nmcli") {
+		if active, _ := svc.IsActive("NetworkManager"); active {
+			return StackNetworkManager
+		}
+	}
+	if active, _ := svc.IsActive("systemd-networkd"); active {
+		return StackNetworkd
+	}
+	if system.FileExists(interfacesFile) && system.CommandExists("ifup") {
+		return StackIfupdown
+	}
+	return StackUnknown
+}
+
+func hasFiles(dir, pattern string) bool {
+	matches, _ := filepath.Glob(filepath.Join(system.RootPath(dir), pattern))
+	return len(matches) > 0
+}
+
+// GetStatus 读取方案、接口地址、路由与 DNS
+func (m *Manager) GetStatus() (*Status, error) {
+	st := &Status{
+		Stack:      DetectStack(),
+		Interfaces: system.NetworkInterfaces(),
+	}
+
+	if !system.HasRoot() && system.CommandExists("ip") {
+		for _, v6 := range []bool{false, true} {
+			args := []string{"-4", "route", "show"}
+			if v6 {
+				args[0] = "-6"
+			}
+			res, err := system.QueryCommand("ip", args...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list routes: %w", err)
+			}
+			st.Routes = append(st.Routes, ParseRoutes(res.Stdout, v6)...)
+		}
+	}
+
+	if data, err := os.ReadFile(system.RootPath(resolvConfFile)); err == nil {
+		st.DNS = ParseResolvConf(string(data))
+	}
+	return st, nil
+}
+
+// ParseRoutes 解析 ip route show 输出
+func ParseRoutes(output string, v6 bool) []Route {
+	var routes []Route
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		r := Route{Dest: fields[0], IPv6: v6}
+		for i := 1; i+1 < len(fields); i++ {
+			switch fields[i] {
+			case "via":
+				r.Gateway = fields[i+1]
+			case "dev":
+				r.Dev = fields[i+1]
+			case "proto":
+				r.Proto = fields[i+1]
+			case "metric":
+				r.Metric, _ = strconv.Atoi(fields[i+1])
+			}
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// ParseResolvConf 返回 nameserver 列表（跳过 systemd-resolved 等本地 stub）
+func ParseResolvConf(content string) []string {
+	var servers []string
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		if ip := net.ParseIP(fields[1]); ip != nil && !ip.IsLoopback() {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers
+}
+
+// CurrentConfig 根据当前地址、默认路由与 DNS 生成静态配置的初始值
+func (st *Status) CurrentConfig(iface string) *StaticConfig {
+	c := &StaticConfig{Interface: iface, DNS: st.DNS}
+	for _, ni := range st.Interfaces {
+		if ni.Name != iface {
+			continue
+		}
+		for _, addr := range ni.Addrs {
+			ip, _, err := net.ParseCIDR(addr)
+			if err != nil || ip.IsLinkLocalUnicast() || ip.IsLoopback() {
+				continue
+			}
+			c.Addresses = append(c.Addresses, addr)
+		}
+	}
+	for _, r := range st.Routes {
+		if !r.IsDefault() || r.Dev != iface || r.Gateway == "" {
+			continue
+		}
+		if r.IPv6 && c.Gateway6 == "" {
+			c.Gateway6 = r.Gateway
+		} else if !r.IPv6 && c.Gateway4 == "" {
+			c.Gateway4 = r.Gateway
+		}
+	}
+	return c
+}
+
+// RoutesFor 返回经由该接口的路由
+func (st *Status) RoutesFor(iface string) []Route {
+	var routes []Route
+	for _, r := range st.Routes {
+		if r.Dev == iface {
+			routes = append(routes, r)
+		}
+	}
+	return routes
+}
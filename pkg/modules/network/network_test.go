@@ -0,0 +1,257 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoutesAndCurrentConfig(t *testing.T) {
+	routes := ParseRoutes(`default via 192.0.2.1 dev eth0 proto dhcp src 192.0.2.10 metric 100
+192.0.2.0/24 dev eth0 proto kernel scope link src 192.0.2.10 metric 100
+10.8.0.0/24 via 10.0.0.1 dev wg0
+`, false)
+	routes = append(routes, ParseRoutes("default via fe80::1 dev eth0 proto ra metric 1024 pref medium\n", true)...)
+
+	require.Len(t, routes, 4)
+	assert.Equal(t, Route{Dest: "default", Gateway: "192.0.2.1", Dev: "eth0", Proto: "dhcp", Metric: 100}, routes[0])
+	assert.True(t, routes[3].IPv6)
+
+	st := &Status{
+		Interfaces: []system.NetInterface{
+			{Name: "eth0", Addrs: []string{"192.0.2.10/24", "2001:db8::10/64", "fe80::1234/64"}},
+			{Name: "wg0", Addrs: []string{"10.8.0.2/24"}},
+		},
+		Routes: routes,
+		DNS:    ParseResolvConf("nameserver 127.0.0.53\nnameserver 1.1.1.1\noptions edns0\n"),
+	}
+	assert.Len(t, st.RoutesFor("eth0"), 3)
+	assert.Equal(t, &StaticConfig{
+		Interface: "eth0",
+		Addresses: []string{"192.0.2.10/24", "2001:db8::10/64"},
+		Gateway4:  "192.0.2.1",
+		Gateway6:  "fe80::1",
+		DNS:       []string{"1.1.1.1"},
+	}, st.CurrentConfig("eth0"))
+}
+
+func TestStaticConfigValidate(t *testing.T) {
+	valid := StaticConfig{Interface: "eth0", Addresses: []string{"192.0.2.10/24"}, Gateway4: "192.0.2.1", DNS: []string{"1.1.1.1"}}
+	assert.NoError(t, valid.Validate())
+
+	cases := []StaticConfig{
+		{Interface: "eth0;rm", Addresses: []string{"192.0.2.10/24"}},
+		{Interface: "eth0"},
+		{Interface: "eth0", Addresses: []string{"192.0.2.10"}},
+		{Interface: "eth0", Addresses: []string{"192.0.2.10/24"}, Gateway4: "2001:db8::1"},
+		{Interface: "eth0", Addresses: []string{"192.0.2.10/24"}, Gateway6: "2001:db8::1"},
+		{Interface: "eth0", Addresses: []string{"192.0.2.10/24"}, DNS: []string{"dns.example"}},
+		{Interface: "eth0", Addresses: []string{"192.0.2.10/24"}, Search: []string{"bad_domain"}},
+	}
+	for _, c := range cases {
+		assert.Error(t, c.Validate(), "%+v", c)
+	}
+}
+
+var dualStack = &StaticConfig{
+	Interface: "eth0",
+	Addresses: []string{"192.0.2.10/24", "192.0.2.11/24", "2001:db8::10/64"},
+	Gateway4:  "192.0.2.1",
+	Gateway6:  "2001:db8::1",
+	DNS:       []string{"1.1.1.1", "2606:4700:4700::1111"},
+	Search:    []string{"example.com"},
+}
+
+func TestRenderNetplan(t *testing.T) {
+	data, err := RenderNetplan(dualStack)
+	require.NoError(t, err)
+	assert.Equal(t, `# written by server-toolkit
+network:
+  version: 2
+  ethernets:
+    eth0:
+      dhcp4: false
+      dhcp6: false
+      addresses:
+        - 192.0.2.10/24
+        - 192.0.2.11/24
+        - 2001:db8::10/64
+      routes:
+        - to: default
+          via: 192.0.2.1
+        - to: ::/0
+          via: 2001:db8::1
+      nameservers:
+        addresses:
+          - 1.1.1.1
+          - 2606:4700:4700::1111
+        search:
+          - example.com
+`, string(data))
+}
+
+func TestRenderNetworkd(t *testing.T) {
+	assert.Equal(t, `# written by server-toolkit
+[Match]
+Name=eth0
+
+[Network]
+DHCP=no
+Address=192.0.2.10/24
+Address=192.0.2.11/24
+Address=2001:db8::10/64
+Gateway=192.0.2.1
+Gateway=2001:db8::1
+DNS=1.1.1.1
+DNS=2606:4700:4700::1111
+Domains=example.com
+`, RenderNetworkd(dualStack))
+}
+
+func TestRenderIfupdown(t *testing.T) {
+	original := `source /etc/network/interfaces.d/*
+
+auto lo
+iface lo inet loopback
+
+allow-hotplug eth0
+iface eth0 inet dhcp
+iface eth0 inet6 auto
+
+auto eth1
+iface eth1 inet dhcp
+`
+	out := RenderIfupdown(original, dualStack)
+	assert.Equal(t, `source /etc/network/interfaces.d/*
+
+auto lo
+iface lo inet loopback
+
+auto eth1
+iface eth1 inet dhcp
+
+# written by server-toolkit
+auto eth0
+iface eth0 inet static
+    address 192.0.2.10/24
+    address 192.0.2.11/24
+    gateway 192.0.2.1
+    dns-nameservers 1.1.1.1 2606:4700:4700::1111
+    dns-search example.com
+iface eth0 inet6 static
+    address 2001:db8::10/64
+    gateway 2001:db8::1
+`, out)
+
+	// 再次生成结果不变
+	assert.Equal(t, out, RenderIfupdown(out, dualStack))
+}
+
+func TestBuildPlanNetworkManager(t *testing.T) {
+	fake := system.NewFakeRunner().
+		On("nmcli -g GENERAL.CONNECTION device show eth0", system.FakeResponse{Stdout: "Wired connection 1\n"}).
+		On(`nmcli -g ipv4.method connection show "Wired connection 1"`, system.FakeResponse{Stdout: "auto\n"}).
+		On(`nmcli -g ipv6.addresses connection show "Wired connection 1"`, system.FakeResponse{Stdout: "2001\\:db8\\:\\:5/64\n"})
+	oldRunner := system.SetRunner(fake)
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	plan, err := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout)).BuildPlan(StackNetworkManager, dualStack)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Files)
+	assert.Equal(t, []string{
+		"nmcli", "connection", "modify", "Wired connection 1",
+		"ipv4.method", "manual", "ipv4.addresses", "192.0.2.10/24,192.0.2.11/24", "ipv4.gateway", "192.0.2.1",
+		"ipv4.dns", "1.1.1.1", "ipv4.dns-search", "example.com",
+		"ipv6.method", "manual", "ipv6.addresses", "2001:db8::10/64", "ipv6.gateway", "2001:db8::1",
+		"ipv6.dns", "2606:4700:4700::1111", "ipv6.dns-search", "example.com",
+	}, plan.Apply[0])
+	assert.Equal(t, []string{"nmcli", "connection", "up", "Wired connection 1"}, plan.Apply[1])
+	// 回滚恢复原来的设置（含反转义后的 IPv6 地址）
+	assert.Contains(t, strings.Join(plan.Revert[0], "|"), "ipv4.method|auto|")
+	assert.Contains(t, strings.Join(plan.Revert[0], "|"), "ipv6.addresses|2001:db8::5/64|")
+}
+
+// setupGuard 把文件路径与状态目录指向临时目录
+func setupGuard(t *testing.T, fake *system.FakeRunner) string {
+	dir := t.TempDir()
+	oldNetworkd, oldState, oldRunner := networkdDir, stateDir, system.SetRunner(fake)
+	networkdDir = filepath.Join(dir, "network")
+	stateDir = filepath.Join(dir, "state")
+	t.Cleanup(func() {
+		networkdDir, stateDir = oldNetworkd, oldState
+		system.SetRunner(oldRunner)
+	})
+	return dir
+}
+
+func TestApplyGuardedConfirm(t *testing.T) {
+	fake := system.NewFakeRunner()
+	dir := setupGuard(t, fake)
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	plan, err := mgr.BuildPlan(StackNetworkd, dualStack)
+	require.NoError(t, err)
+	g, err := mgr.Apply(plan, DefaultGuardTimeout)
+	require.NoError(t, err)
+	require.NotNil(t, g)
+
+	path := filepath.Join(dir, "network", "10-server-toolkit-eth0.network")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, RenderNetworkd(dualStack), string(data))
+
+	script, err := os.ReadFile(filepath.Join(dir, "state", "network-revert.sh"))
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "rm -f '"+path+"'\n")
+	assert.Contains(t, string(script), "'networkctl' 'reconfigure' 'eth0'\n")
+
+	calls := fake.Calls()
+	assert.Contains(t, calls, "systemd-run --unit=server-toolkit-network-revert --on-active=120s /bin/sh "+filepath.Join(dir, "state", "network-revert.sh"))
+	assert.Equal(t, "networkctl reconfigure eth0", calls[len(calls)-1])
+
+	assert.Contains(t, string(script), "set -C\n{ echo reverted > '"+filepath.Join(dir, "state", "network-revert.done")+"'; } 2>/dev/null || exit 0\n")
+
+	require.NoError(t, g.Confirm())
+	assert.FileExists(t, filepath.Join(dir, "state", "network-revert.done"))
+	assert.False(t, g.Expired(g.Deadline.Add(-1)))
+	assert.True(t, g.Expired(g.Deadline.Add(1)))
+}
+
+func TestConfirmAfterRevert(t *testing.T) {
+	fake := system.NewFakeRunner()
+	dir := setupGuard(t, fake)
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	plan, err := mgr.BuildPlan(StackNetworkd, dualStack)
+	require.NoError(t, err)
+	g, err := mgr.Apply(plan, DefaultGuardTimeout)
+	require.NoError(t, err)
+
+	// 定时任务先执行：标记文件已由回滚脚本创建
+	done := filepath.Join(dir, "state", "network-revert.done")
+	require.NoError(t, os.WriteFile(done, []byte("reverted\n"), 0600))
+
+	assert.ErrorIs(t, g.Confirm(), ErrAlreadyReverted)
+	data, err := os.ReadFile(done)
+	require.NoError(t, err)
+	assert.Equal(t, "reverted\n", string(data))
+}
+
+func TestApplyRevertsWhenCommandFails(t *testing.T) {
+	fake := system.NewFakeRunner().On("networkctl reload", system.FakeResponse{ExitCode: 1, Stderr: "bad config"})
+	dir := setupGuard(t, fake)
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	plan, err := mgr.BuildPlan(StackNetworkd, dualStack)
+	require.NoError(t, err)
+	_, err = mgr.Apply(plan, DefaultGuardTimeout)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reverted")
+	assert.Contains(t, fake.Calls(), "sh "+filepath.Join(dir, "state", "network-revert.sh"))
+}
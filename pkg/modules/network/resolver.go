@@ -0,0 +1,348 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// ResolverMode /etc/resolv.conf 的管理方
+type ResolverMode string
+
+const (
+	ResolverResolved       ResolverMode = "systemd-resolved"
+	ResolverNetworkManager ResolverMode = "networkmanager"
+	ResolverResolvconf     ResolverMode = "resolvconf"
+	ResolverStatic         ResolverMode = "static"
+)
+
+// DNS-over-TLS 取值（systemd-resolved 的 DNSOverTLS=）
+const (
+	DoTOff           = "no"
+	DoTOpportunistic = "opportunistic"
+	DoTStrict        = "yes"
+)
+
+// glibc 只使用前 3 个 nameserver
+const maxResolvNameservers = 3
+
+var (
+	resolvedConfFile   = "/etc/systemd/resolved.conf"
+	resolvedDropInDir  = "/etc/systemd/resolved.conf.d"
+	resolvedUpstream   = "/run/systemd/resolve/resolv.conf"
+	nmConfDir          = "/etc/NetworkManager/conf.d"
+	resolvconfHeadFile = "/etc/resolvconf/resolv.conf.d/head"
+	resolverFileName   = "90-server-toolkit.conf"
+)
+
+// ErrResolvConfSymlink /etc/resolv.conf 是符号链接，按静态方式写入会替换该链接
+var ErrResolvConfSymlink = errors.New("/etc/resolv.conf is a symlink; refusing to replace it without confirmation")
+
+// ResolverStatus 当前解析器状态
+type ResolverStatus struct {
+	Mode        ResolverMode
+	Symlink     string // resolv.conf 为符号链接时的目标
+	Nameservers []string
+	Search      []string
+	DNSOverTLS  string // 仅 systemd-resolved；未配置时为空
+}
+
+// ResolverConfig 要设置的解析器参数
+type ResolverConfig struct {
+	Nameservers []string // systemd-resolved 下可写作 IP#服务器名（DoT 证书校验）
+	Search      []string
+	DNSOverTLS  string
+
+	// ReplaceSymlink 静态模式下允许用普通文件替换符号链接形式的 resolv.conf
+	ReplaceSymlink bool
+}
+
+// DetectResolver 根据 resolv.conf 的链接目标与文件头判断其管理方
+func DetectResolver() (ResolverMode, string) {
+	path := system.RootPath(resolvConfFile)
+	var target string
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		target, _ = os.Readlink(path)
+		switch {
+		case strings.Contains(target, "systemd/resolve"):
+			return ResolverResolved, target
+		case strings.Contains(target, "resolvconf"):
+			return ResolverResolvconf, target
+		case strings.Contains(target, "NetworkManager"):
+			return ResolverNetworkManager, target
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ResolverStatic, target
+	}
+	header := strings.ToLower(string(data))
+	switch {
+	case strings.Contains(header, "generated by networkmanager"):
+		return ResolverNetworkManager, target
+	case strings.Contains(header, "resolvconf"):
+		return ResolverResolvconf, target
+	case strings.Contains(header, "systemd-resolved"):
+		return ResolverResolved, target
+	}
+	return ResolverStatic, target
+}
+
+// GetResolverStatus 读取管理方与生效的 nameserver、搜索域
+func (m *Manager) GetResolverStatus() (*ResolverStatus, error) {
+	st := &ResolverStatus{}
+	st.Mode, st.Symlink = DetectResolver()
+
+	source := resolvConfFile
+	if st.Mode == ResolverResolved {
+		st.DNSOverTLS = resolvedSetting("DNSOverTLS")
+		// stub 文件只含 127.0.0.53；上游服务器在 resolved 维护的另一份文件中
+		if system.FileExists(system.RootPath(resolvedUpstream)) {
+			source = resolvedUpstream
+		}
+	}
+
+	data, err := os.ReadFile(system.RootPath(source))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	st.Nameservers, st.Search = parseResolv(string(data))
+	return st, nil
+}
+
+// parseResolv 解析 nameserver 与 search/domain（后出现的 search/domain 覆盖前者）
+func parseResolv(content string) (servers, search []string) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			servers = append(servers, fields[1])
+		case "search", "domain":
+			search = fields[1:]
+		}
+	}
+	return servers, search
+}
+
+// resolvedSetting 读取 resolved.conf 及其 drop-in 中 [Resolve] 下某个键的最终值
+func resolvedSetting(key string) string {
+	files := []string{system.RootPath(resolvedConfFile)}
+	dropIns, _ := filepath.Glob(filepath.Join(system.RootPath(resolvedDropInDir), "*.conf"))
+	sort.Strings(dropIns)
+	files = append(files, dropIns...)
+
+	var value string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		section := ""
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				section = line
+				continue
+			}
+			if section != "[Resolve]" {
+				continue
+			}
+			if k, v, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == key {
+				value = strings.TrimSpace(v)
+			}
+		}
+	}
+	return value
+}
+
+// Validate 校验 nameserver、搜索域与 DoT 设置是否适用于该管理方
+func (c *ResolverConfig) Validate(mode ResolverMode) error {
+	if len(c.Nameservers) == 0 {
+		return fmt.Errorf("at least one nameserver is required")
+	}
+	for _, ns := range c.Nameservers {
+		addr, name, hasName := strings.Cut(ns, "#")
+		if net.ParseIP(addr) == nil {
+			return fmt.Errorf("invalid nameserver: %q", ns)
+		}
+		if hasName {
+			if mode != ResolverResolved {
+				return fmt.Errorf("nameserver %q: server names are only supported by systemd-resolved", ns)
+			}
+			if err := hostname.ValidateHostname(name); err != nil {
+				return fmt.Errorf("invalid server name in %q: %w", ns, err)
+			}
+		}
+	}
+	if (mode == ResolverStatic || mode == ResolverResolvconf) && len(c.Nameservers) > maxResolvNameservers {
+		return fmt.Errorf("resolv.conf supports at most %d nameservers", maxResolvNameservers)
+	}
+	for _, domain := range c.Search {
+		if err := hostname.ValidateHostname(domain); err != nil {
+			return fmt.Errorf("invalid search domain %q: %w", domain, err)
+		}
+	}
+
+	switch c.DNSOverTLS {
+	case "":
+	case DoTOff, DoTOpportunistic, DoTStrict:
+		if mode != ResolverResolved {
+			return fmt.Errorf("DNS-over-TLS requires systemd-resolved")
+		}
+	default:
+		return fmt.Errorf("invalid DNS-over-TLS mode: %q", c.DNSOverTLS)
+	}
+	return nil
+}
+
+// RenderResolvedDropIn 生成 resolved.conf drop-in（空赋值先清除主配置中的列表）
+func RenderResolvedDropIn(c *ResolverConfig) string {
+	var b strings.Builder
+	b.WriteString(generatedHeader + "\n[Resolve]\n")
+	b.WriteString("DNS=\nDNS=" + strings.Join(c.Nameservers, " ") + "\n")
+	b.WriteString("Domains=\n")
+	if len(c.Search) > 0 {
+		b.WriteString("Domains=" + strings.Join(c.Search, " ") + "\n")
+	}
+	if c.DNSOverTLS != "" {
+		b.WriteString("DNSOverTLS=" + c.DNSOverTLS + "\n")
+	}
+	return b.String()
+}
+
+// RenderNMGlobalDNS 生成 NetworkManager 全局 DNS 配置（优先于各连接的 DNS）
+func RenderNMGlobalDNS(c *ResolverConfig) string {
+	var b strings.Builder
+	b.WriteString(generatedHeader + "\n[global-dns]\n")
+	if len(c.Search) > 0 {
+		b.WriteString("searches=" + strings.Join(c.Search, ",") + "\n")
+	}
+	b.WriteString("\n[global-dns-domain-*]\n")
+	b.WriteString("servers=" + strings.Join(c.Nameservers, ",") + "\n")
+	return b.String()
+}
+
+// RenderResolvConf 替换 nameserver 与 search/domain 行，保留注释、options 等其他内容
+func RenderResolvConf(content string, c *ResolverConfig) string {
+	var kept []string
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (fields[0] == "nameserver" || fields[0] == "search" || fields[0] == "domain") {
+			continue
+		}
+		if strings.TrimSpace(line) == generatedHeader {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	var b strings.Builder
+	for _, line := range kept {
+		b.WriteString(line + "\n")
+	}
+	if len(kept) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString(generatedHeader + "\n")
+	for _, ns := range c.Nameservers {
+		b.WriteString("nameserver " + ns + "\n")
+	}
+	if len(c.Search) > 0 {
+		b.WriteString("search " + strings.Join(c.Search, " ") + "\n")
+	}
+	return b.String()
+}
+
+// ResolverPlan 根据管理方生成要写入的文件与使其生效的命令：
+// systemd-resolved 写 drop-in、NetworkManager 写全局 DNS、resolvconf 写 head，
+// 只有静态模式才直接改写 /etc/resolv.conf
+func (m *Manager) ResolverPlan(st *ResolverStatus, c *ResolverConfig) (*Plan, error) {
+	if err := c.Validate(st.Mode); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{}
+	switch st.Mode {
+	case ResolverResolved:
+		plan.Files = []FileChange{{Path: filepath.Join(resolvedDropInDir, resolverFileName), Content: RenderResolvedDropIn(c), Perm: 0644}}
+		plan.Apply = [][]string{{"systemctl", "restart", "systemd-resolved"}}
+
+	case ResolverNetworkManager:
+		plan.Files = []FileChange{{Path: filepath.Join(nmConfDir, resolverFileName), Content: RenderNMGlobalDNS(c), Perm: 0644}}
+		plan.Apply = [][]string{{"systemctl", "reload", "NetworkManager"}}
+
+	case ResolverResolvconf:
+		data, err := os.ReadFile(system.RootPath(resolvconfHeadFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", resolvconfHeadFile, err)
+		}
+		plan.Files = []FileChange{{Path: resolvconfHeadFile, Content: RenderResolvConf(string(data), c), Perm: 0644}}
+		plan.Apply = [][]string{{"resolvconf", "-u"}}
+
+	default:
+		if st.Symlink != "" && !c.ReplaceSymlink {
+			return nil, ErrResolvConfSymlink
+		}
+		data, err := os.ReadFile(system.RootPath(resolvConfFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", resolvConfFile, err)
+		}
+		plan.Files = []FileChange{{Path: resolvConfFile, Content: RenderResolvConf(string(data), c), Perm: 0644}}
+	}
+	return plan, nil
+}
+
+// ApplyResolver 备份并写入计划中的文件，然后执行生效命令（--root 下不执行命令）
+func (m *Manager) ApplyResolver(plan *Plan) error {
+	if m.dryRun {
+		for _, f := range plan.Files {
+			m.drm.LogFileWrite(system.RootPath(f.Path), f.Content)
+		}
+		for _, argv := range plan.Apply {
+			m.drm.LogCommand(argv[0], argv[1:]...)
+		}
+		return nil
+	}
+
+	for _, f := range plan.Files {
+		path := system.RootPath(f.Path)
+		if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			target, _ := os.Readlink(path)
+			m.logger.Warn("Replacing symlink %s -> %s with a regular file", path, target)
+		}
+		backup, err := backupFileFn(path)
+		if err != nil {
+			return fmt.Errorf("failed to backup %s: %w", path, err)
+		}
+		if backup != "" {
+			m.logger.Info("Backed up: %s -> %s", path, backup)
+		}
+		if _, err := m.writeFile(f); err != nil {
+			return err
+		}
+	}
+
+	if system.HasRoot() {
+		return nil
+	}
+	for _, argv := range plan.Apply {
+		if _, err := system.RunCommand(argv[0], argv[1:]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupResolver 把解析器相关路径指向临时目录
+func setupResolver(t *testing.T) string {
+	dir := t.TempDir()
+	old := []string{resolvConfFile, resolvedConfFile, resolvedDropInDir, resolvedUpstream, nmConfDir, resolvconfHeadFile}
+	resolvConfFile = filepath.Join(dir, "resolv.conf")
+	resolvedConfFile = filepath.Join(dir, "resolved.conf")
+	resolvedDropInDir = filepath.Join(dir, "resolved.conf.d")
+	resolvedUpstream = filepath.Join(dir, "run", "systemd", "resolve", "resolv.conf")
+	nmConfDir = filepath.Join(dir, "nm")
+	resolvconfHeadFile = filepath.Join(dir, "head")
+	t.Cleanup(func() {
+		resolvConfFile, resolvedConfFile, resolvedDropInDir, resolvedUpstream, nmConfDir, resolvconfHeadFile =
+			old[0], old[1], old[2], old[3], old[4], old[5]
+	})
+	return dir
+}
+
+func TestDetectResolver(t *testing.T) {
+	dir := setupResolver(t)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(resolvedUpstream), 0755))
+	require.NoError(t, os.WriteFile(resolvedUpstream, []byte("nameserver 1.1.1.1\nsearch example.com\n"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "run", "systemd", "resolve", "stub-resolv.conf"), resolvConfFile))
+	require.NoError(t, os.WriteFile(resolvedConfFile, []byte("[Resolve]\nDNSOverTLS=no\n"), 0644))
+	require.NoError(t, os.MkdirAll(resolvedDropInDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(resolvedDropInDir, "50-dot.conf"), []byte("[Resolve]\nDNSOverTLS=opportunistic\n"), 0644))
+
+	st, err := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout)).GetResolverStatus()
+	require.NoError(t, err)
+	assert.Equal(t, ResolverResolved, st.Mode)
+	assert.NotEmpty(t, st.Symlink)
+	assert.Equal(t, []string{"1.1.1.1"}, st.Nameservers)
+	assert.Equal(t, []string{"example.com"}, st.Search)
+	assert.Equal(t, DoTOpportunistic, st.DNSOverTLS)
+
+	require.NoError(t, os.Remove(resolvConfFile))
+	require.NoError(t, os.WriteFile(resolvConfFile, []byte("# Generated by NetworkManager\nnameserver 192.0.2.53\n"), 0644))
+	mode, target := DetectResolver()
+	assert.Equal(t, ResolverNetworkManager, mode)
+	assert.Empty(t, target)
+
+	require.NoError(t, os.WriteFile(resolvConfFile, []byte("nameserver 192.0.2.53\n"), 0644))
+	mode, _ = DetectResolver()
+	assert.Equal(t, ResolverStatic, mode)
+}
+
+func TestResolverConfigValidate(t *testing.T) {
+	dot := ResolverConfig{Nameservers: []string{"1.1.1.1#cloudflare-dns.com"}, DNSOverTLS: DoTStrict}
+	assert.NoError(t, dot.Validate(ResolverResolved))
+	assert.Error(t, dot.Validate(ResolverStatic))
+
+	tooMany := ResolverConfig{Nameservers: []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4"}}
+	assert.NoError(t, tooMany.Validate(ResolverResolved))
+	assert.Error(t, tooMany.Validate(ResolverStatic))
+
+	assert.Error(t, (&ResolverConfig{}).Validate(ResolverStatic))
+	assert.Error(t, (&ResolverConfig{Nameservers: []string{"dns.example"}}).Validate(ResolverStatic))
+	assert.Error(t, (&ResolverConfig{Nameservers: []string{"192.0.2.1"}, DNSOverTLS: "always"}).Validate(ResolverResolved))
+}
+
+func TestRenderResolverFiles(t *testing.T) {
+	c := &ResolverConfig{Nameservers: []string{"1.1.1.1#cloudflare-dns.com", "9.9.9.9"}, Search: []string{"example.com"}, DNSOverTLS: DoTOpportunistic}
+	assert.Equal(t, `# written by server-toolkit
+[Resolve]
+DNS=
+DNS=1.1.1.1#cloudflare-dns.com 9.9.9.9
+Domains=
+Domains=example.com
+DNSOverTLS=opportunistic
+`, RenderResolvedDropIn(c))
+
+	static := &ResolverConfig{Nameservers: []string{"192.0.2.53", "2001:db8::53"}, Search: []string{"example.com"}}
+	assert.Equal(t, `# written by server-toolkit
+[global-dns]
+searches=example.com
+
+[global-dns-domain-*]
+servers=192.0.2.53,2001:db8::53
+`, RenderNMGlobalDNS(static))
+
+	out := RenderResolvConf("# local resolver\nnameserver 10.0.0.1\ndomain corp.example\noptions edns0 rotate\n", static)
+	assert.Equal(t, `# local resolver
+options edns0 rotate
+
+# written by server-toolkit
+nameserver 192.0.2.53
+nameserver 2001:db8::53
+search example.com
+`, out)
+	assert.Equal(t, out, RenderResolvConf(out, static))
+}
+
+func TestResolverStaticSymlink(t *testing.T) {
+	dir := setupResolver(t)
+	oldRunner := system.SetRunner(system.NewFakeRunner())
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	target := filepath.Join(dir, "stub.conf")
+	require.NoError(t, os.WriteFile(target, []byte("nameserver 127.0.0.1\noptions edns0\n"), 0644))
+	require.NoError(t, os.Symlink(target, resolvConfFile))
+
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+	st := &ResolverStatus{Mode: ResolverStatic, Symlink: target}
+	c := &ResolverConfig{Nameservers: []string{"192.0.2.53"}}
+
+	_, err := mgr.ResolverPlan(st, c)
+	assert.ErrorIs(t, err, ErrResolvConfSymlink)
+
+	c.ReplaceSymlink = true
+	plan, err := mgr.ResolverPlan(st, c)
+	require.NoError(t, err)
+	require.NoError(t, mgr.ApplyResolver(plan))
+
+	info, err := os.Lstat(resolvConfFile)
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode()&os.ModeSymlink)
+	data, err := os.ReadFile(resolvConfFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "options edns0\n")
+	assert.Contains(t, string(data), "nameserver 192.0.2.53\n")
+
+	// 链接目标保持不变，原内容另有备份
+	data, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "nameserver 127.0.0.1\noptions edns0\n", string(data))
+	backups, _ := filepath.Glob(resolvConfFile + ".bak.*")
+	assert.Len(t, backups, 1)
+}
+
+func TestApplyResolverResolvedDropIn(t *testing.T) {
+	setupResolver(t)
+	fake := system.NewFakeRunner()
+	oldRunner := system.SetRunner(fake)
+	t.Cleanup(func() { system.SetRunner(oldRunner) })
+
+	mgr := NewManager(false, internal.NewLogger(internal.ERROR, os.Stdout))
+	c := &ResolverConfig{Nameservers: []string{"9.9.9.9#dns.quad9.net"}, DNSOverTLS: DoTStrict}
+	plan, err := mgr.ResolverPlan(&ResolverStatus{Mode: ResolverResolved}, c)
+	require.NoError(t, err)
+	require.NoError(t, mgr.ApplyResolver(plan))
+
+	data, err := os.ReadFile(filepath.Join(resolvedDropInDir, resolverFileName))
+	require.NoError(t, err)
+	assert.Equal(t, RenderResolvedDropIn(c), string(data))
+	assert.Equal(t, []string{"systemctl restart systemd-resolved"}, fake.Calls())
+}
@@ -0,0 +1,407 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Akuma-real/server-toolkit/pkg/modules/hostname"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+const (
+	generatedHeader = "# written by server-toolkit"
+	netplanFile     = "90-server-toolkit.yaml"
+)
+
+// ifaceNameRegex 内核接口名（最长 15 字节）
+var ifaceNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.@-]{1,15}$`)
+
+// StaticConfig 接口的静态地址配置
+type StaticConfig struct {
+	Interface string
+	Addresses []string // CIDR，可同时包含 IPv4 与 IPv6（第二个及以后为附加地址）
+	Gateway4  string
+	Gateway6  string
+	DNS       []string
+	Search    []string
+}
+
+// Validate 校验接口名、地址、网关与 DNS
+func (c *StaticConfig) Validate() error {
+	if !ifaceNameRegex.MatchString(c.Interface) {
+		return fmt.Errorf("invalid interface name: %q", c.Interface)
+	}
+	if len(c.Addresses) == 0 {
+		return fmt.Errorf("at least one address is required")
+	}
+
+	var has4, has6 bool
+	for _, addr := range c.Addresses {
+		ip, _, err := net.ParseCIDR(addr)
+		if err != nil {
+			return fmt.Errorf("invalid address %q (expected CIDR, e.g. 192.0.2.10/24)", addr)
+		}
+		if ip.To4() != nil {
+			has4 = true
+		} else {
+			has6 = true
+		}
+	}
+
+	if c.Gateway4 != "" {
+		ip := net.ParseIP(c.Gateway4)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid IPv4 gateway: %q", c.Gateway4)
+		}
+		if !has4 {
+			return fmt.Errorf("IPv4 gateway requires an IPv4 address")
+		}
+	}
+	if c.Gateway6 != "" {
+		ip := net.ParseIP(c.Gateway6)
+		if ip == nil || ip.To4() != nil {
+			return fmt.Errorf("invalid IPv6 gateway: %q", c.Gateway6)
+		}
+		if !has6 {
+			return fmt.Errorf("IPv6 gateway requires an IPv6 address")
+		}
+	}
+
+	for _, dns := range c.DNS {
+		if net.ParseIP(dns) == nil {
+			return fmt.Errorf("invalid DNS server: %q", dns)
+		}
+	}
+	for _, domain := range c.Search {
+		if err := hostname.ValidateHostname(domain); err != nil {
+			return fmt.Errorf("invalid search domain %q: %w", domain, err)
+		}
+	}
+	return nil
+}
+
+// split 按地址族拆分地址
+func (c *StaticConfig) split() (v4, v6 []string) {
+	for _, addr := range c.Addresses {
+		if ip, _, err := net.ParseCIDR(addr); err == nil && ip.To4() != nil {
+			v4 = append(v4, addr)
+		} else {
+			v6 = append(v6, addr)
+		}
+	}
+	return v4, v6
+}
+
+// FileChange 计划写入的文件
+type FileChange struct {
+	Path    string // 逻辑路径
+	Content string
+	Perm    os.FileMode
+}
+
+// Plan 应用静态配置的计划
+type Plan struct {
+	Stack  Stack
+	Files  []FileChange
+	Apply  [][]string // 使配置生效的命令
+	Revert [][]string // 额外的回滚命令（文件类方案回滚为恢复文件后重新执行 Apply）
+}
+
+// netplan 文档结构
+type netplanDoc struct {
+	Network netplanNetwork `yaml:"network"`
+}
+
+type netplanNetwork struct {
+	Version   int                        `yaml:"version"`
+	Ethernets map[string]netplanEthernet `yaml:"ethernets"`
+}
+
+type netplanEthernet struct {
+	DHCP4       bool               `yaml:"dhcp4"`
+	DHCP6       bool               `yaml:"dhcp6"`
+	Addresses   []string           `yaml:"addresses"`
+	Routes      []netplanRoute     `yaml:"routes,omitempty"`
+	Nameservers *netplanNameserver `yaml:"nameservers,omitempty"`
+}
+
+type netplanRoute struct {
+	To  string `yaml:"to"`
+	Via string `yaml:"via"`
+}
+
+type netplanNameserver struct {
+	Addresses []string `yaml:"addresses,omitempty"`
+	Search    []string `yaml:"search,omitempty"`
+}
+
+// RenderNetplan 生成 netplan 配置（文件名排序靠后，覆盖 50-cloud-init.yaml 中的同名接口）
+func RenderNetplan(c *StaticConfig) ([]byte, error) {
+	eth := netplanEthernet{Addresses: c.Addresses}
+	if c.Gateway4 != "" {
+		eth.Routes = append(eth.Routes, netplanRoute{To: "default", Via: c.Gateway4})
+	}
+	if c.Gateway6 != "" {
+		eth.Routes = append(eth.Routes, netplanRoute{To: "::/0", Via: c.Gateway6})
+	}
+	if len(c.DNS) > 0 || len(c.Search) > 0 {
+		eth.Nameservers = &netplanNameserver{Addresses: c.DNS, Search: c.Search}
+	}
+	doc := netplanDoc{Network: netplanNetwork{
+		Version:   2,
+		Ethernets: map[string]netplanEthernet{c.Interface: eth},
+	}}
+
+	var buf bytes.Buffer
+	buf.WriteString(generatedHeader + "\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode netplan config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode netplan config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderNetworkd 生成 systemd-networkd 的 .network 文件
+func RenderNetworkd(c *StaticConfig) string {
+	var b strings.Builder
+	b.WriteString(generatedHeader + "\n")
+	fmt.Fprintf(&b, "[Match]\nName=%s\n\n[Network]\nDHCP=no\n", c.Interface)
+	for _, addr := range c.Addresses {
+		fmt.Fprintf(&b, "Address=%s\n", addr)
+	}
+	for _, gw := range []string{c.Gateway4, c.Gateway6} {
+		if gw != "" {
+			fmt.Fprintf(&b, "Gateway=%s\n", gw)
+		}
+	}
+	for _, dns := range c.DNS {
+		fmt.Fprintf(&b, "DNS=%s\n", dns)
+	}
+	if len(c.Search) > 0 {
+		fmt.Fprintf(&b, "Domains=%s\n", strings.Join(c.Search, " "))
+	}
+	return b.String()
+}
+
+// ifupdownStanzaKeywords /etc/network/interfaces 中开始新段落的关键字
+var ifupdownStanzaKeywords = map[string]bool{
+	"iface": true, "auto": true, "mapping": true, "source": true,
+	"source-directory": true, "rename": true,
+}
+
+// RenderIfupdown 替换 interfaces 中该接口的 auto/allow-*/iface 段落，其余内容保持不变
+func RenderIfupdown(content string, c *StaticConfig) string {
+	var kept []string
+	inStanza := false
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		if strings.TrimSpace(line) == generatedHeader {
+			// 上次生成的段落标记，随新段落重新写入
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && (ifupdownStanzaKeywords[fields[0]] || strings.HasPrefix(fields[0], "allow-")) {
+			inStanza = false
+			switch {
+			case fields[0] == "iface" && len(fields) > 1 && fields[1] == c.Interface:
+				inStanza = true
+				continue
+			case fields[0] == "auto" || strings.HasPrefix(fields[0], "allow-"):
+				rest := removeWord(fields[1:], c.Interface)
+				if len(rest) == len(fields)-1 {
+					break
+				}
+				if len(rest) > 0 {
+					kept = append(kept, fields[0]+" "+strings.Join(rest, " "))
+				}
+				continue
+			}
+		}
+		if inStanza {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	for len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	v4, v6 := c.split()
+	var b strings.Builder
+	if len(kept) > 0 {
+		b.WriteString(strings.Join(kept, "\n") + "\n\n")
+	}
+	fmt.Fprintf(&b, "%s\nauto %s\n", generatedHeader, c.Interface)
+	dnsWritten := false
+	stanza := func(family string, addrs []string, gateway string) {
+		fmt.Fprintf(&b, "iface %s %s static\n", c.Interface, family)
+		for _, addr := range addrs {
+			fmt.Fprintf(&b, "    address %s\n", addr)
+		}
+		if gateway != "" {
+			fmt.Fprintf(&b, "    gateway %s\n", gateway)
+		}
+		if !dnsWritten {
+			// dns-* 由 resolvconf 读取，只需出现一次
+			if len(c.DNS) > 0 {
+				fmt.Fprintf(&b, "    dns-nameservers %s\n", strings.Join(c.DNS, " "))
+			}
+			if len(c.Search) > 0 {
+				fmt.Fprintf(&b, "    dns-search %s\n", strings.Join(c.Search, " "))
+			}
+			dnsWritten = true
+		}
+	}
+	if len(v4) > 0 {
+		stanza("inet", v4, c.Gateway4)
+	}
+	if len(v6) > 0 {
+		stanza("inet6", v6, c.Gateway6)
+	}
+	return b.String()
+}
+
+func removeWord(words []string, word string) []string {
+	var out []string
+	for _, w := range words {
+		if w != word {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// BuildPlan 为检测到的方案生成配置文件与生效命令
+func (m *Manager) BuildPlan(stack Stack, c *StaticConfig) (*Plan, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Stack: stack}
+	switch stack {
+	case StackNetplan:
+		data, err := RenderNetplan(c)
+		if err != nil {
+			return nil, err
+		}
+		// netplan 对非 0600 的配置文件会发出警告
+		plan.Files = append(plan.Files, FileChange{Path: filepath.Join(netplanDir, netplanFile), Content: string(data), Perm: 0600})
+		plan.Apply = [][]string{{"netplan", "generate"}, {"netplan", "apply"}}
+
+	case StackNetworkd:
+		path := filepath.Join(networkdDir, networkdFilePrefix+c.Interface+".network")
+		plan.Files = append(plan.Files, FileChange{Path: path, Content: RenderNetworkd(c), Perm: 0644})
+		plan.Apply = [][]string{{"networkctl", "reload"}, {"networkctl", "reconfigure", c.Interface}}
+
+	case StackIfupdown:
+		data, err := os.ReadFile(system.RootPath(interfacesFile))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", interfacesFile, err)
+		}
+		plan.Files = append(plan.Files, FileChange{Path: interfacesFile, Content: RenderIfupdown(string(data), c), Perm: 0644})
+		plan.Apply = [][]string{{"sh", "-c", fmt.Sprintf("ifdown --force %s; ifup %s", c.Interface, c.Interface)}}
+
+	case StackNetworkManager:
+		if system.HasRoot() {
+			return nil, fmt.Errorf("NetworkManager cannot be configured under --root")
+		}
+		conn, err := nmConnection(c.Interface)
+		if err != nil {
+			return nil, err
+		}
+		current, err := nmCurrentSettings(conn)
+		if err != nil {
+			return nil, err
+		}
+		plan.Apply = [][]string{
+			append([]string{"nmcli", "connection", "modify", conn}, nmStaticSettings(c)...),
+			{"nmcli", "connection", "up", conn},
+		}
+		plan.Revert = [][]string{
+			append([]string{"nmcli", "connection", "modify", conn}, current...),
+			{"nmcli", "connection", "up", conn},
+		}
+
+	default:
+		return nil, fmt.Errorf("no supported network configuration stack detected")
+	}
+	return plan, nil
+}
+
+// nmFields 回滚时需要恢复的 NetworkManager 连接属性
+var nmFields = []string{
+	"ipv4.method", "ipv4.addresses", "ipv4.gateway", "ipv4.dns", "ipv4.dns-search",
+	"ipv6.method", "ipv6.addresses", "ipv6.gateway", "ipv6.dns", "ipv6.dns-search",
+}
+
+// nmConnection 接口当前使用的连接名
+func nmConnection(iface string) (string, error) {
+	res, err := system.QueryCommand("nmcli", "-g", "GENERAL.CONNECTION", "device", "show", iface)
+	if err != nil {
+		return "", fmt.Errorf("failed to find NetworkManager connection for %s: %w", iface, err)
+	}
+	conn := strings.TrimSpace(res.Stdout)
+	if conn == "" || conn == "--" {
+		return "", fmt.Errorf("interface %s has no active NetworkManager connection", iface)
+	}
+	return conn, nil
+}
+
+// nmCurrentSettings 读取连接当前的地址设置（nmcli modify 参数形式）
+func nmCurrentSettings(conn string) ([]string, error) {
+	var args []string
+	for _, field := range nmFields {
+		res, err := system.QueryCommand("nmcli", "-g", field, "connection", "show", conn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of %s: %w", field, conn, err)
+		}
+		args = append(args, field, nmUnescape(strings.TrimSpace(res.Stdout)))
+	}
+	return args, nil
+}
+
+// nmUnescape 还原 nmcli -g 输出中转义的 ':' 与 '\'
+func nmUnescape(s string) string {
+	return strings.NewReplacer(`\:`, ":", `\\`, `\`).Replace(s)
+}
+
+// nmStaticSettings 静态配置对应的 nmcli modify 参数（未配置的地址族保持原样）
+func nmStaticSettings(c *StaticConfig) []string {
+	v4, v6 := c.split()
+	var dns4, dns6 []string
+	for _, dns := range c.DNS {
+		if net.ParseIP(dns).To4() != nil {
+			dns4 = append(dns4, dns)
+		} else {
+			dns6 = append(dns6, dns)
+		}
+	}
+
+	var args []string
+	family := func(prefix string, addrs []string, gateway string, dns []string) {
+		args = append(args,
+			prefix+".method", "manual",
+			prefix+".addresses", strings.Join(addrs, ","),
+			prefix+".gateway", gateway,
+			prefix+".dns", strings.Join(dns, ","),
+			prefix+".dns-search", strings.Join(c.Search, ","),
+		)
+	}
+	if len(v4) > 0 {
+		family("ipv4", v4, c.Gateway4, dns4)
+	}
+	if len(v6) > 0 {
+		family("ipv6", v6, c.Gateway6, dns6)
+	}
+	return args
+}
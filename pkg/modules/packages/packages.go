@@ -0,0 +1,158 @@
+package packages
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// CommonTools 常用工具（可在包管理界面勾选安装）
+var CommonTools = []string{
+	"curl",
+	"wget",
+	"vim",
+	"htop",
+	"git",
+	"tmux",
+	"unzip",
+	"rsync",
+	"jq",
+	"fail2ban",
+}
+
+// Manager 软件包管理器（包装 system.PackageManager，支持 dry-run 与输出流）
+type Manager struct {
+	pm     system.PackageManager
+	family system.DistroFamily
+	dryRun bool
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewManager 创建软件包管理器，family 用于把通用包名映射为发行版包名
+func NewManager(pm system.PackageManager, family system.DistroFamily, dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "packages"})
+	return &Manager{
+		pm:     pm,
+		family: family,
+		dryRun: dryRun,
+		logger: logger,
+		drm:    internal.NewDryRunManager(dryRun, logger),
+	}
+}
+
+// Name 返回底层包管理器名称
+func (m *Manager) Name() string {
+	return m.pm.Name()
+}
+
+// Refresh 刷新软件源索引，输出写入 out
+func (m *Manager) Refresh(out io.Writer) error {
+	if m.dryRun {
+		m.dryRunNote(out, "refresh package index")
+		return nil
+	}
+
+	m.pm.SetOutput(out)
+	defer m.pm.SetOutput(nil)
+
+	m.logger.Info("Refreshing package index (%s)", m.pm.Name())
+	if err := m.pm.Update(); err != nil {
+		return fmt.Errorf("%s: failed to refresh package index: %w", m.pm.Name(), err)
+	}
+	return nil
+}
+
+// PendingUpgrades 获取待升级的软件包（只读）
+func (m *Manager) PendingUpgrades() ([]system.PackageUpgrade, error) {
+	return m.pm.ListUpgrades()
+}
+
+// Search 在软件源中搜索软件包（只读）；搜索词为单个关键字，不能以 - 开头
+func (m *Manager) Search(term string) ([]system.PackageInfo, error) {
+	term = strings.TrimSpace(term)
+	if term == "" || strings.HasPrefix(term, "-") || strings.ContainsAny(term, " \t") {
+		return nil, fmt.Errorf("invalid search term %q", term)
+	}
+	pkgs, err := m.pm.Search(term)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to search %s: %w", m.pm.Name(), term, err)
+	}
+	return pkgs, nil
+}
+
+// InstalledStatus 返回每个包的安装状态
+func (m *Manager) InstalledStatus(pkgs []string) map[string]bool {
+	status := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		installed, _ := m.pm.IsInstalled(system.PackageName(m.family, pkg))
+		status[pkg] = installed
+	}
+	return status
+}
+
+// Install 安装软件包，输出写入 out
+func (m *Manager) Install(out io.Writer, pkgs []string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	pkgs = system.PackageNames(m.family, pkgs)
+	if m.dryRun {
+		m.dryRunNote(out, "install: "+strings.Join(pkgs, " "))
+		return nil
+	}
+
+	m.pm.SetOutput(out)
+	defer m.pm.SetOutput(nil)
+
+	m.logger.Info("Installing packages: %s", strings.Join(pkgs, " "))
+	if err := m.pm.Install(pkgs...); err != nil {
+		return fmt.Errorf("%s: failed to install %s: %w", m.pm.Name(), strings.Join(pkgs, " "), err)
+	}
+	m.logger.Info("Installed packages: %s", strings.Join(pkgs, " "))
+	return nil
+}
+
+// Upgrade 升级软件包（为空表示全部），输出写入 out
+func (m *Manager) Upgrade(out io.Writer, pkgs []string) error {
+	pkgs = system.PackageNames(m.family, pkgs)
+	target := strings.Join(pkgs, " ")
+	if target == "" {
+		target = "all packages"
+	}
+	if m.dryRun {
+		m.dryRunNote(out, "upgrade: "+target)
+		return nil
+	}
+
+	m.pm.SetOutput(out)
+	defer m.pm.SetOutput(nil)
+
+	m.logger.Info("Upgrading %s", target)
+	if err := m.pm.Upgrade(pkgs...); err != nil {
+		return fmt.Errorf("%s: failed to upgrade %s: %w", m.pm.Name(), target, err)
+	}
+	m.logger.Info("Upgraded %s", target)
+	return nil
+}
+
+func (m *Manager) dryRunNote(out io.Writer, op string) {
+	m.drm.LogOperation("Would %s (%s)", op, m.pm.Name())
+	if out != nil {
+		fmt.Fprintf(out, "[DRY-RUN] Would %s (%s)\n", op, m.pm.Name())
+	}
+}
+
+// SecurityUpgrades 过滤出安全更新
+func SecurityUpgrades(upgrades []system.PackageUpgrade) []system.PackageUpgrade {
+	var security []system.PackageUpgrade
+	for _, u := range upgrades {
+		if u.Security {
+			security = append(security, u)
+		}
+	}
+	return security
+}
@@ -0,0 +1,140 @@
+package packages
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePackageManager struct {
+	output    io.Writer
+	installed []string
+	calls     []string
+	failWith  error
+}
+
+func (f *fakePackageManager) Name() string          { return "fake" }
+func (f *fakePackageManager) SetOutput(w io.Writer) { f.output = w }
+
+func (f *fakePackageManager) run(op string, pkgs ...string) error {
+	f.calls = append(f.calls, fmt.Sprint(op, pkgs))
+	if f.output != nil {
+		fmt.Fprintf(f.output, "%s %v\n", op, pkgs)
+	}
+	return f.failWith
+}
+
+func (f *fakePackageManager) Update() error                    { return f.run("update") }
+func (f *fakePackageManager) Install(packages ...string) error { return f.run("install", packages...) }
+func (f *fakePackageManager) Remove(packages ...string) error  { return f.run("remove", packages...) }
+func (f *fakePackageManager) Upgrade(packages ...string) error { return f.run("upgrade", packages...) }
+
+func (f *fakePackageManager) IsInstalled(pkg string) (bool, error) {
+	for _, p := range f.installed {
+		if p == pkg {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakePackageManager) ListUpgrades() ([]system.PackageUpgrade, error) {
+	return []system.PackageUpgrade{
+		{Name: "openssl", Security: true},
+		{Name: "tzdata"},
+	}, nil
+}
+
+func (f *fakePackageManager) Search(term string) ([]system.PackageInfo, error) {
+	f.calls = append(f.calls, "search "+term)
+	return []system.PackageInfo{
+		{Name: "nginx", Summary: "web server"},
+		{Name: "nginx-extras", Summary: "web server (extended)"},
+	}, f.failWith
+}
+
+func TestInstallStreamsOutputAndResetsWriter(t *testing.T) {
+	pm := &fakePackageManager{}
+	mgr := NewManager(pm, system.Debian, false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	var out bytes.Buffer
+	require.NoError(t, mgr.Install(&out, []string{"curl", "htop"}))
+
+	assert.Equal(t, []string{"install[curl htop]"}, pm.calls)
+	assert.Contains(t, out.String(), "install [curl htop]")
+	assert.Nil(t, pm.output)
+}
+
+func TestDryRunDoesNotInvokePackageManager(t *testing.T) {
+	pm := &fakePackageManager{}
+	mgr := NewManager(pm, system.Debian, true, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	var out bytes.Buffer
+	require.NoError(t, mgr.Refresh(&out))
+	require.NoError(t, mgr.Install(&out, []string{"curl"}))
+	require.NoError(t, mgr.Upgrade(&out, nil))
+
+	assert.Empty(t, pm.calls)
+	assert.Contains(t, out.String(), "[DRY-RUN] Would install: curl")
+	assert.Contains(t, out.String(), "[DRY-RUN] Would upgrade: all packages")
+}
+
+func TestUpgradeWrapsError(t *testing.T) {
+	pm := &fakePackageManager{failWith: errors.New("exit status 100")}
+	mgr := NewManager(pm, system.Debian, false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	err := mgr.Upgrade(nil, []string{"openssl"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upgrade openssl")
+}
+
+func TestSecurityUpgradesAndInstalledStatus(t *testing.T) {
+	pm := &fakePackageManager{installed: []string{"curl"}}
+	mgr := NewManager(pm, system.Debian, false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	upgrades, err := mgr.PendingUpgrades()
+	require.NoError(t, err)
+	security := SecurityUpgrades(upgrades)
+	require.Len(t, security, 1)
+	assert.Equal(t, "openssl", security[0].Name)
+
+	status := mgr.InstalledStatus([]string{"curl", "htop"})
+	assert.True(t, status["curl"])
+	assert.False(t, status["htop"])
+}
+
+func TestSearch(t *testing.T) {
+	pm := &fakePackageManager{}
+	mgr := NewManager(pm, system.Debian, false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	pkgs, err := mgr.Search("  nginx ")
+	require.NoError(t, err)
+	assert.Len(t, pkgs, 2)
+	assert.Equal(t, []string{"search nginx"}, pm.calls)
+
+	// 搜索词不能为空，也不能被当作命令行选项
+	for _, term := range []string{"", " ", "-o=Dir::Etc=/tmp", "nginx extras"} {
+		_, err := mgr.Search(term)
+		assert.Error(t, err, term)
+	}
+	assert.Len(t, pm.calls, 1)
+}
+
+func TestInstallMapsPackageNamesPerFamily(t *testing.T) {
+	pm := &fakePackageManager{installed: []string{"vim-enhanced"}}
+	mgr := NewManager(pm, system.RedHat, false, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	require.NoError(t, mgr.Install(nil, []string{"vim", "curl"}))
+	assert.Equal(t, []string{"install[vim-enhanced curl]"}, pm.calls)
+
+	status := mgr.InstalledStatus([]string{"vim"})
+	assert.True(t, status["vim"])
+}
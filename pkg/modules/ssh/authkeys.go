@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
 )
 
 // AuthKeysManager authorized_keys 管理器
@@ -19,6 +20,7 @@ type AuthKeysManager struct {
 
 // NewAuthKeysManager 创建 authorized_keys 管理器
 func NewAuthKeysManager(path string, dryRun bool, logger *internal.Logger) *AuthKeysManager {
+	logger = logger.With(internal.Fields{Module: "ssh", Path: path})
 	return &AuthKeysManager{
 		path:   path,
 		dryRun: dryRun,
@@ -28,7 +30,10 @@ func NewAuthKeysManager(path string, dryRun bool, logger *internal.Logger) *Auth
 }
 
 // Append 追加密钥，返回新增数量
-func (m *AuthKeysManager) Append(keys []string, overwrite bool) (int, error) {
+func (m *AuthKeysManager) Append(keys []string, overwrite bool) (_ int, err error) {
+	op := history.Begin(m.dryRun, "ssh", "append_keys", fmt.Sprintf("%d keys", len(keys)), m.path)
+	defer func() { op.End(err, m.logger) }()
+
 	// 备份文件
 	if _, err := os.Stat(m.path); err == nil {
 		if !m.dryRun {
@@ -142,17 +147,19 @@ func (m *AuthKeysManager) Count() (int, error) {
 }
 
 // Clear 清空所有密钥
-func (m *AuthKeysManager) Clear() error {
+func (m *AuthKeysManager) Clear() (err error) {
 	if m.dryRun {
 		m.drm.LogFileOperation("Clear file", m.path)
 		return nil
 	}
 
+	op := history.Begin(m.dryRun, "ssh", "clear_keys", "", m.path)
+	defer func() { op.End(err, m.logger) }()
 	return os.WriteFile(m.path, []byte{}, 0600)
 }
 
 // RemoveKey 移除指定密钥
-func (m *AuthKeysManager) RemoveKey(key string) error {
+func (m *AuthKeysManager) RemoveKey(key string) (err error) {
 	keys, err := m.List()
 	if err != nil {
 		return err
@@ -170,6 +177,8 @@ func (m *AuthKeysManager) RemoveKey(key string) error {
 		return nil
 	}
 
+	op := history.Begin(m.dryRun, "ssh", "remove_key", "", m.path)
+	defer func() { op.End(err, m.logger) }()
 	content := strings.Join(newKeys, "\n")
 	return os.WriteFile(m.path, []byte(content), 0600)
 }
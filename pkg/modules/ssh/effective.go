@@ -0,0 +1,94 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// EffectiveSettings sshd 生效配置：小写选项名 -> 按 sshd 读取顺序出现的全部取值
+type EffectiveSettings map[string][]string
+
+// Get 返回选项的生效值（sshd 以首次出现的值为准）
+func (s EffectiveSettings) Get(key string) (string, bool) {
+	values := s[strings.ToLower(key)]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Ports 返回监听端口：Port 可出现多次且全部生效，未配置时为默认 22
+func (s EffectiveSettings) Ports() []string {
+	if ports := s["port"]; len(ports) > 0 {
+		return ports
+	}
+	return []string{"22"}
+}
+
+// ParseEffective 解析 sshd -T 输出（小写键 + 值）
+func ParseEffective(output string) EffectiveSettings {
+	settings := EffectiveSettings{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(key)
+		settings[key] = append(settings[key], strings.TrimSpace(value))
+	}
+	return settings
+}
+
+// addGlobal 追加一个配置文件中的全局选项（Match 之前）
+func (s EffectiveSettings) addGlobal(content string) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if strings.EqualFold(fields[0], "Match") {
+			break
+		}
+		if len(fields) >= 2 {
+			key := strings.ToLower(fields[0])
+			s[key] = append(s[key], strings.Join(fields[1:], " "))
+		}
+	}
+}
+
+// ReadEffectiveSettings 读取 path（已映射到目标根目录）对应的 sshd 生效配置：优先 sshd -T；
+// sshd 不可用（未安装、--root 离线模式或无权读取主机密钥）时按 sshd 的读取顺序解析
+// sshd_config.d/*.conf 与 sshd_config 的全局选项（Debian/Ubuntu/RHEL 在文件开头 Include 该目录）
+func ReadEffectiveSettings(path string) (EffectiveSettings, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	if !system.HasRoot() && system.CommandExists("sshd") {
+		if res, err := system.QueryCommand("sshd", "-T", "-f", path); err == nil {
+			return ParseEffective(res.Stdout), nil
+		}
+	}
+
+	main, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sshd_config: %w", err)
+	}
+	settings := EffectiveSettings{}
+	dropIns, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "sshd_config.d", "*.conf"))
+	sort.Strings(dropIns)
+	for _, dropIn := range dropIns {
+		if data, err := os.ReadFile(dropIn); err == nil {
+			settings.addGlobal(string(data))
+		}
+	}
+	settings.addGlobal(string(main))
+	return settings, nil
+}
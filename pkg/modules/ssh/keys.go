@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 )
 
@@ -31,6 +33,7 @@ type Manager struct {
 
 // NewManager 创建 SSH 密钥管理器
 func NewManager(user string, dryRun bool, logger *internal.Logger) *Manager {
+	logger = logger.With(internal.Fields{Module: "ssh", User: user})
 	return &Manager{
 		user:   user,
 		dryRun: dryRun,
@@ -187,7 +190,7 @@ func (m *Manager) filterAndValidateKeys(keys []string) ([]string, error) {
 }
 
 // Install 安装密钥
-func (m *Manager) Install(keys []string, overwrite bool) (int, error) {
+func (m *Manager) Install(keys []string, overwrite bool) (_ int, err error) {
 	// 获取用户信息
 	userInfo, err := system.GetUser(m.user)
 	if err != nil {
@@ -195,9 +198,12 @@ func (m *Manager) Install(keys []string, overwrite bool) (int, error) {
 	}
 
 	// 获取 authorized_keys 路径
-	sshDir := fmt.Sprintf("%s/.ssh", userInfo.HomeDir)
+	sshDir := system.RootPath(fmt.Sprintf("%s/.ssh", userInfo.HomeDir))
 	authKeysPath := fmt.Sprintf("%s/authorized_keys", sshDir)
 
+	op := history.Begin(m.dryRun, "ssh", "install_keys", fmt.Sprintf("%s: %d keys", m.user, len(keys)), authKeysPath)
+	defer func() { op.End(err, m.logger) }()
+
 	// 创建 SSH 目录
 	if !m.dryRun {
 		if err := os.MkdirAll(sshDir, 0700); err != nil {
@@ -260,6 +266,9 @@ func (m *Manager) Install(keys []string, overwrite bool) (int, error) {
 	// 如果没有新密钥需要添加
 	if added == 0 {
 		m.logger.Info("All keys already exist in authorized_keys")
+		if !m.dryRun {
+			m.recordKeys(keys, overwrite)
+		}
 		return 0, nil
 	}
 
@@ -280,9 +289,15 @@ func (m *Manager) Install(keys []string, overwrite bool) (int, error) {
 	}
 
 	m.logger.Info("Added %d keys to authorized_keys", added)
+	m.recordKeys(keys, overwrite)
 	return added, nil
 }
 
+// recordKeys 记录为用户安装的公钥，供漂移检测使用
+func (m *Manager) recordKeys(keys []string, overwrite bool) {
+	state.Record(m.logger, func(st *state.State) { st.AddAuthorizedKeys(m.user, keys, overwrite) })
+}
+
 // List 列出已安装的密钥
 func (m *Manager) List() ([]string, error) {
 	// 获取用户信息
@@ -292,7 +307,7 @@ func (m *Manager) List() ([]string, error) {
 	}
 
 	// 获取 authorized_keys 路径
-	authKeysPath := fmt.Sprintf("%s/.ssh/authorized_keys", userInfo.HomeDir)
+	authKeysPath := system.RootPath(fmt.Sprintf("%s/.ssh/authorized_keys", userInfo.HomeDir))
 
 	// 读取文件
 	file, err := os.Open(authKeysPath)
@@ -4,12 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/history"
+	"github.com/Akuma-real/server-toolkit/pkg/state"
 	"github.com/Akuma-real/server-toolkit/pkg/system"
 )
 
@@ -17,6 +18,11 @@ const (
 	sshdConfigPath = "/etc/ssh/sshd_config"
 )
 
+// DefaultConfigPath 返回 sshd_config 路径（设置 --root 时映射到目标根目录下）
+func DefaultConfigPath() string {
+	return system.RootPath(sshdConfigPath)
+}
+
 // Config SSH 配置
 type Config struct {
 	path   string
@@ -27,6 +33,7 @@ type Config struct {
 
 // NewConfig 创建 SSH 配置
 func NewConfig(path string, dryRun bool, logger *internal.Logger) (*Config, error) {
+	logger = logger.With(internal.Fields{Module: "ssh"})
 	return &Config{
 		path:   path,
 		dryRun: dryRun,
@@ -41,7 +48,10 @@ func (c *Config) SetGlobalOption(key, value string) error {
 }
 
 // SetGlobalOptions 设置多个全局选项（仅在 Match 之前），尽量单次读写
-func (c *Config) SetGlobalOptions(options map[string]string) error {
+func (c *Config) SetGlobalOptions(options map[string]string) (err error) {
+	op := history.Begin(c.dryRun, "ssh", "set_sshd_options", formatOptions(options), c.path)
+	defer func() { op.End(err, c.logger) }()
+
 	if _, err := os.Stat(c.path); err != nil {
 		return fmt.Errorf("sshd_config not found: %w", err)
 	}
@@ -169,7 +179,9 @@ func (c *Config) SetGlobalOptions(options map[string]string) error {
 	if err := system.SafeWrite(c.path, []byte(content), perm); err != nil {
 		return fmt.Errorf("failed to write sshd_config: %w", err)
 	}
-	_ = system.RestoreSELinuxContext(c.path)
+	if err := system.RestoreSELinuxContext(c.path); err != nil {
+		c.logger.Warn("%v", err)
+	}
 
 	if err := validateSSHDConfig(c.path); err != nil {
 		if backupPath != "" {
@@ -184,9 +196,22 @@ func (c *Config) SetGlobalOptions(options map[string]string) error {
 	for k, v := range options {
 		c.logger.Info("Set sshd_config option: %s = %s", k, v)
 	}
+	if c.path == DefaultConfigPath() {
+		state.Record(c.logger, func(st *state.State) { st.SetSSHDOptions(options) })
+	}
 	return nil
 }
 
+// formatOptions 按选项名排序的 "Key value" 列表，用于审计记录
+func formatOptions(options map[string]string) string {
+	pairs := make([]string, 0, len(options))
+	for k, v := range options {
+		pairs = append(pairs, k+" "+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
 // DisablePasswordAuth 禁用密码认证
 func (c *Config) DisablePasswordAuth() error {
 	if err := c.SetGlobalOptions(map[string]string{
@@ -213,7 +238,7 @@ func Restart() error {
 }
 
 // ReloadSSHD 重载 SSH 服务（兼容 service name: sshd/ssh）
-func ReloadSSHD(dryRun bool, logger *internal.Logger) error {
+func ReloadSSHD(dryRun bool, logger *internal.Logger) (err error) {
 	if dryRun {
 		if logger == nil {
 			return nil
@@ -224,6 +249,9 @@ func ReloadSSHD(dryRun bool, logger *internal.Logger) error {
 		return nil
 	}
 
+	op := history.Begin(dryRun, "ssh", "reload_sshd", "")
+	defer func() { op.End(err, logger) }()
+
 	svc := system.NewServiceManager()
 	if err := svc.Reload("sshd"); err == nil {
 		return nil
@@ -234,7 +262,7 @@ func ReloadSSHD(dryRun bool, logger *internal.Logger) error {
 	return fmt.Errorf("failed to reload ssh service (tried sshd, ssh)")
 }
 
-func restartSSHD(dryRun bool, logger *internal.Logger) error {
+func restartSSHD(dryRun bool, logger *internal.Logger) (err error) {
 	if dryRun {
 		if logger == nil {
 			return nil
@@ -245,6 +273,9 @@ func restartSSHD(dryRun bool, logger *internal.Logger) error {
 		return nil
 	}
 
+	op := history.Begin(dryRun, "ssh", "restart_sshd", "")
+	defer func() { op.End(err, logger) }()
+
 	svc := system.NewServiceManager()
 	if err := svc.Restart("sshd"); err == nil {
 		return nil
@@ -256,17 +287,13 @@ func restartSSHD(dryRun bool, logger *internal.Logger) error {
 }
 
 func validateSSHDConfig(path string) error {
-	sshdPath, err := exec.LookPath("sshd")
-	if err != nil {
+	if !system.GuestCommandExists("sshd") {
 		// 无 sshd 可执行文件：无法做语法校验，跳过
 		return nil
 	}
 
-	cmd := exec.Command(sshdPath, "-t", "-f", path)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
-	}
-	return nil
+	_, err := system.QueryGuestCommand("sshd", "-t", "-f", system.GuestPath(path))
+	return err
 }
 
 func restoreFromBackup(dstPath, backupPath string, perm os.FileMode) error {
@@ -276,3 +303,43 @@ func restoreFromBackup(dstPath, backupPath string, perm os.FileMode) error {
 	}
 	return system.SafeWrite(dstPath, data, perm)
 }
+
+// ParsePorts 解析 sshd_config 中的全局 Port（Match 之前），未配置时返回默认 22
+func ParsePorts(content string) []string {
+	var ports []string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if strings.EqualFold(fields[0], "Match") {
+			break
+		}
+		if strings.EqualFold(fields[0], "Port") && len(fields) >= 2 {
+			ports = append(ports, fields[1])
+		}
+	}
+	if len(ports) == 0 {
+		return []string{"22"}
+	}
+	return ports
+}
+
+// GlobalOption 返回 sshd_config 中全局（Match 之前）选项的首个取值（sshd 以首次出现为准）
+func GlobalOption(content, key string) (string, bool) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if strings.EqualFold(fields[0], "Match") {
+			break
+		}
+		if strings.EqualFold(fields[0], key) && len(fields) >= 2 {
+			return strings.Join(fields[1:], " "), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,194 @@
+// Package state 记录本工具最近一次应用的期望配置（主机名、hosts 条目、sshd 选项、公钥），
+// 供漂移检测比较与重新应用
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/Akuma-real/server-toolkit/pkg/system"
+)
+
+// currentVersion 状态文件格式版本
+const currentVersion = 1
+
+var (
+	stateFile = "/var/lib/server-toolkit/state.json"
+	mu        sync.Mutex
+)
+
+// Hostname 期望的静态主机名
+type Hostname struct {
+	Short string    `json:"short"`
+	FQDN  string    `json:"fqdn,omitempty"`
+	Set   time.Time `json:"set"`
+}
+
+// HostsEntry 期望存在于 /etc/hosts 的条目
+type HostsEntry struct {
+	IP    string    `json:"ip"`
+	Names []string  `json:"names"`
+	Set   time.Time `json:"set"`
+}
+
+// Option sshd_config 全局选项的期望值
+type Option struct {
+	Value string    `json:"value"`
+	Set   time.Time `json:"set"`
+}
+
+// State 状态文件内容
+type State struct {
+	Version        int                 `json:"version"`
+	Updated        time.Time           `json:"updated"`
+	Hostname       *Hostname           `json:"hostname,omitempty"`
+	Hosts          []HostsEntry        `json:"hosts,omitempty"`
+	SSHD           map[string]Option   `json:"sshd,omitempty"`            // 键为选项名（保留写入时的大小写）
+	AuthorizedKeys map[string][]string `json:"authorized_keys,omitempty"` // 用户 -> 公钥
+}
+
+// Path 状态文件路径（设置 --root 时位于目标根目录下）
+func Path() string {
+	return system.RootPath(stateFile)
+}
+
+// SetPath 修改状态文件路径并返回原路径（用于测试）
+func SetPath(path string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	old := stateFile
+	stateFile = path
+	return old
+}
+
+// Load 读取状态文件；文件不存在时返回空状态
+func Load() (*State, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+func load() (*State, error) {
+	st := &State{Version: currentVersion}
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", Path(), err)
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(), err)
+	}
+	return st, nil
+}
+
+// Update 读取状态、调用 fn 修改后原子写回
+func Update(fn func(st *State)) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	st, err := load()
+	if err != nil {
+		return err
+	}
+	fn(st)
+	st.Version = currentVersion
+	st.Updated = time.Now()
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := system.SafeWrite(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Record 更新状态文件；失败只记录警告，不影响已完成的配置修改
+func Record(logger *internal.Logger, fn func(st *State)) {
+	if err := Update(fn); err != nil {
+		logger.Warn("Failed to record applied state: %v", err)
+	}
+}
+
+// SetHostname 记录静态主机名
+func (st *State) SetHostname(short, fqdn string) {
+	st.Hostname = &Hostname{Short: short, FQDN: fqdn, Set: time.Now()}
+}
+
+// SetHostsEntry 记录 hosts 条目（同一 IP 只保留最新一条）
+func (st *State) SetHostsEntry(ip string, names []string) {
+	entry := HostsEntry{IP: ip, Names: names, Set: time.Now()}
+	for i := range st.Hosts {
+		if st.Hosts[i].IP == ip {
+			st.Hosts[i] = entry
+			return
+		}
+	}
+	st.Hosts = append(st.Hosts, entry)
+}
+
+// ReplaceHosts 用 hosts 编辑器保存的完整条目列表替换已记录的条目
+func (st *State) ReplaceHosts(entries []HostsEntry) {
+	now := time.Now()
+	st.Hosts = make([]HostsEntry, 0, len(entries))
+	for _, e := range entries {
+		e.Set = now
+		st.Hosts = append(st.Hosts, e)
+	}
+}
+
+// SetSSHDOptions 记录 sshd 选项（选项名不区分大小写）
+func (st *State) SetSSHDOptions(options map[string]string) {
+	if st.SSHD == nil {
+		st.SSHD = make(map[string]Option)
+	}
+	now := time.Now()
+	for key, value := range options {
+		for existing := range st.SSHD {
+			if strings.EqualFold(existing, key) {
+				delete(st.SSHD, existing)
+			}
+		}
+		st.SSHD[key] = Option{Value: value, Set: now}
+	}
+}
+
+// AddAuthorizedKeys 记录为用户安装的公钥；replace 为 true 时替换该用户已记录的公钥
+func (st *State) AddAuthorizedKeys(user string, keys []string, replace bool) {
+	if st.AuthorizedKeys == nil {
+		st.AuthorizedKeys = make(map[string][]string)
+	}
+	var merged []string
+	if !replace {
+		merged = st.AuthorizedKeys[user]
+	}
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key != "" && !containsString(merged, key) {
+			merged = append(merged, key)
+		}
+	}
+	st.AuthorizedKeys[user] = merged
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
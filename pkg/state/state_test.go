@@ -0,0 +1,66 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lib", "state.json")
+	old := SetPath(path)
+	t.Cleanup(func() { SetPath(old) })
+
+	// 文件不存在时返回空状态
+	st, err := Load()
+	require.NoError(t, err)
+	assert.Nil(t, st.Hostname)
+
+	require.NoError(t, Update(func(st *State) {
+		st.SetHostname("web-01", "web-01.example.com")
+		st.SetHostsEntry("127.0.1.1", []string{"web-01.example.com", "web-01"})
+		st.SetSSHDOptions(map[string]string{"PasswordAuthentication": "no"})
+		st.AddAuthorizedKeys("alice", []string{"ssh-ed25519 AAAA a@x"}, false)
+	}))
+	require.NoError(t, Update(func(st *State) {
+		st.SetHostsEntry("127.0.1.1", []string{"web-02"})
+		st.SetSSHDOptions(map[string]string{"passwordauthentication": "yes"})
+		st.AddAuthorizedKeys("alice", []string{"ssh-ed25519 AAAA a@x", "ssh-ed25519 BBBB b@x"}, false)
+	}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	st, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, currentVersion, st.Version)
+	assert.Equal(t, "web-01.example.com", st.Hostname.FQDN)
+	require.Len(t, st.Hosts, 1)
+	assert.Equal(t, []string{"web-02"}, st.Hosts[0].Names)
+	assert.Len(t, st.SSHD, 1)
+	assert.Equal(t, "yes", st.SSHD["passwordauthentication"].Value)
+	assert.Equal(t, []string{"ssh-ed25519 AAAA a@x", "ssh-ed25519 BBBB b@x"}, st.AuthorizedKeys["alice"])
+
+	require.NoError(t, Update(func(st *State) {
+		st.AddAuthorizedKeys("alice", []string{"ssh-ed25519 CCCC c@x"}, true)
+	}))
+	st, err = Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ssh-ed25519 CCCC c@x"}, st.AuthorizedKeys["alice"])
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	old := SetPath(path)
+	t.Cleanup(func() { SetPath(old) })
+
+	_, err := Load()
+	assert.Error(t, err)
+	// 损坏的状态文件不应被覆盖
+	assert.Error(t, Update(func(*State) {}))
+}
@@ -3,7 +3,6 @@ package system
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"time"
 )
@@ -111,16 +110,14 @@ func SafeWrite(path string, data []byte, perm os.FileMode) error {
 // RestoreSELinuxContext 恢复 SELinux 上下文
 func RestoreSELinuxContext(path string) error {
 	// 检查 restorecon 是否存在
-	if _, err := exec.LookPath("restorecon"); err != nil {
+	if !CommandExists("restorecon") {
 		// restorecon 不存在，跳过
 		return nil
 	}
 
-	// 执行 restorecon
-	cmd := exec.Command("restorecon", "-R", path)
-	if err := cmd.Run(); err != nil {
-		// SELinux 可能未启用，忽略错误
-		return nil
+	// 执行 restorecon；SELinux 未启用时也可能失败，由调用方决定是否忽略
+	if _, err := RunCommand("restorecon", "-R", path); err != nil {
+		return fmt.Errorf("failed to restore SELinux context: %w", err)
 	}
 
 	return nil
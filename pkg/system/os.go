@@ -16,6 +16,7 @@ const (
 	Arch
 	Alpine
 	Gentoo
+	SUSE
 	Unknown
 )
 
@@ -23,13 +24,14 @@ const (
 type DistroInfo struct {
 	Family  DistroFamily
 	ID      string
+	IDLike  string
 	Version string
 	Pretty  string
 }
 
 // DetectDistro 检测 Linux 发行版
 func DetectDistro() (*DistroInfo, error) {
-	file, err := os.Open("/etc/os-release")
+	file, err := os.Open(RootPath("/etc/os-release"))
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +59,8 @@ func DetectDistro() (*DistroInfo, error) {
 		switch key {
 		case "ID":
 			info.ID = value
+		case "ID_LIKE":
+			info.IDLike = value
 		case "VERSION_ID":
 			info.Version = value
 		case "PRETTY_NAME":
@@ -65,29 +69,64 @@ func DetectDistro() (*DistroInfo, error) {
 	}
 
 	// 判断发行版家族
-	info.Family = determineFamily(info.ID)
+	info.Family = determineFamily(info.ID, info.IDLike)
 
 	return info, nil
 }
 
-// determineFamily 根据发行版 ID 判断家族
-func determineFamily(id string) DistroFamily {
+// determineFamily 根据发行版 ID 判断家族，无法识别时依次尝试 ID_LIKE
+func determineFamily(id, idLike string) DistroFamily {
+	if family := familyFromID(id); family != Unknown {
+		return family
+	}
+	for _, like := range strings.Fields(idLike) {
+		if family := familyFromID(like); family != Unknown {
+			return family
+		}
+	}
+	return Unknown
+}
+
+// familyFromID 根据单个发行版 ID 判断家族
+func familyFromID(id string) DistroFamily {
 	switch id {
-	case "debian", "ubuntu", "linuxmint", "pop":
+	case "debian", "ubuntu", "linuxmint", "pop", "raspbian", "kali", "devuan":
 		return Debian
-	case "rhel", "centos", "fedora", "almalinux", "rocky", "ol":
+	case "rhel", "centos", "fedora", "almalinux", "rocky", "ol", "amzn":
 		return RedHat
-	case "arch", "manjaro":
+	case "arch", "manjaro", "endeavouros":
 		return Arch
 	case "alpine":
 		return Alpine
 	case "gentoo":
 		return Gentoo
+	case "suse", "opensuse", "opensuse-leap", "opensuse-tumbleweed", "sles", "sled":
+		return SUSE
 	default:
 		return Unknown
 	}
 }
 
+// String 返回发行版家族名称
+func (f DistroFamily) String() string {
+	switch f {
+	case Debian:
+		return "debian"
+	case RedHat:
+		return "redhat"
+	case Arch:
+		return "arch"
+	case Alpine:
+		return "alpine"
+	case Gentoo:
+		return "gentoo"
+	case SUSE:
+		return "suse"
+	default:
+		return "unknown"
+	}
+}
+
 // IsDebianBased 判断是否为 Debian 系
 func IsDebianBased() bool {
 	info, err := DetectDistro()
@@ -114,33 +153,3 @@ func GetHostname() (string, error) {
 	}
 	return hostname, nil
 }
-
-// GetSystemInfo 获取系统信息
-type SystemInfo struct {
-	OS     string
-	CPU    string
-	Memory string
-	Disk   string
-	Uptime string
-}
-
-// GetSystemInfo 获取系统信息
-func GetSystemInfo() (*SystemInfo, error) {
-	info := &SystemInfo{}
-
-	// 获取 OS 信息
-	distro, err := DetectDistro()
-	if err != nil {
-		info.OS = "Unknown"
-	} else {
-		info.OS = distro.Pretty
-	}
-
-	// TODO: 实现其他系统信息的获取
-	info.CPU = "N/A"
-	info.Memory = "N/A"
-	info.Disk = "N/A"
-	info.Uptime = "N/A"
-
-	return info, nil
-}
@@ -10,25 +10,33 @@ import (
 func TestDetermineFamily(t *testing.T) {
 	tests := []struct {
 		id     string
+		idLike string
 		family DistroFamily
 	}{
-		{"debian", Debian},
-		{"ubuntu", Debian},
-		{"linuxmint", Debian},
-		{"rhel", RedHat},
-		{"centos", RedHat},
-		{"fedora", RedHat},
-		{"almalinux", RedHat},
-		{"rocky", RedHat},
-		{"arch", Arch},
-		{"alpine", Alpine},
-		{"gentoo", Gentoo},
-		{"unknown", Unknown},
+		{"debian", "", Debian},
+		{"ubuntu", "debian", Debian},
+		{"linuxmint", "ubuntu debian", Debian},
+		{"rhel", "fedora", RedHat},
+		{"centos", "rhel fedora", RedHat},
+		{"fedora", "", RedHat},
+		{"almalinux", "rhel centos fedora", RedHat},
+		{"rocky", "rhel centos fedora", RedHat},
+		{"arch", "", Arch},
+		{"alpine", "", Alpine},
+		{"gentoo", "", Gentoo},
+		{"opensuse-leap", "suse opensuse", SUSE},
+		{"sles", "", SUSE},
+		{"zorin", "ubuntu debian", Debian},
+		{"eurolinux", "rhel fedora centos", RedHat},
+		{"cachyos", "arch", Arch},
+		{"postmarketos", "alpine", Alpine},
+		{"unknown", "", Unknown},
+		{"unknown", "nothing-known", Unknown},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.id, func(t *testing.T) {
-			assert.Equal(t, tt.family, determineFamily(tt.id))
+		t.Run(tt.id+"/"+tt.idLike, func(t *testing.T) {
+			assert.Equal(t, tt.family, determineFamily(tt.id, tt.idLike))
 		})
 	}
 }
@@ -1,118 +1,652 @@
 package system
 
 import (
+	"bufio"
+	"context"
 	"fmt"
-	"os/exec"
+	"io"
+	"regexp"
+	"strings"
+	"time"
 )
 
 // PackageManager 包管理器接口
 type PackageManager interface {
+	Name() string
 	Update() error
 	Install(packages ...string) error
 	Remove(packages ...string) error
+	Upgrade(packages ...string) error
 	IsInstalled(pkg string) (bool, error)
+	ListUpgrades() ([]PackageUpgrade, error)
+	// Search 按名称或描述搜索软件源中的包（只读）
+	Search(term string) ([]PackageInfo, error)
+	// SetOutput 设置命令输出（stdout/stderr）的写入目标，nil 表示丢弃
+	SetOutput(w io.Writer)
+}
+
+// PackageUpgrade 待升级的软件包
+type PackageUpgrade struct {
+	Name      string
+	Current   string
+	Candidate string
+	Source    string
+	Security  bool
+}
+
+// PackageInfo 搜索结果中的软件包
+type PackageInfo struct {
+	Name    string
+	Version string // 部分包管理器的搜索结果不含版本
+	Summary string
+}
+
+// 包管理命令的超时：安装与升级可能下载大量软件包，列出更新需要联网刷新元数据，均长于普通命令
+const (
+	packageCommandTimeout = time.Hour
+	packageQueryTimeout   = 5 * time.Minute
+)
+
+// runPackageCommand 执行包管理命令，输出同时写入 out（为 nil 时仅捕获）；
+// 设置根目录时经 chroot 在目标系统内执行
+func runPackageCommand(out io.Writer, name string, args ...string) error {
+	name, args = guestCommand(name, args...)
+	_, err := DefaultRunner().Run(context.Background(), Command{
+		Name:    name,
+		Args:    args,
+		Env:     []string{"DEBIAN_FRONTEND=noninteractive"},
+		Output:  out,
+		Timeout: packageCommandTimeout,
+	})
+	return err
+}
+
+// queryPackages 执行只读的包查询命令（列出更新、搜索；设置根目录时经 chroot）
+func queryPackages(name string, args ...string) (*Result, error) {
+	name, args = guestCommand(name, args...)
+	return DefaultRunner().Run(context.Background(), Command{Name: name, Args: args, ReadOnly: true, Timeout: packageQueryTimeout})
+}
+
+// packageInstalled 通过查询命令的退出码判断包是否已安装
+func packageInstalled(name string, args ...string) bool {
+	_, err := QueryGuestCommand(name, args...)
+	return err == nil
 }
 
 // AptManager apt 包管理器
-type AptManager struct{}
+type AptManager struct {
+	output io.Writer
+}
+
+func (m *AptManager) Name() string { return "apt" }
+
+func (m *AptManager) SetOutput(w io.Writer) { m.output = w }
 
 func (m *AptManager) Update() error {
-	cmd := exec.Command("apt-get", "update", "-y")
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "apt-get", "update", "-y")
 }
 
 func (m *AptManager) Install(packages ...string) error {
 	args := []string{"install", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("apt-get", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "apt-get", args...)
 }
 
 func (m *AptManager) Remove(packages ...string) error {
 	args := []string{"remove", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("apt-get", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "apt-get", args...)
+}
+
+func (m *AptManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return runPackageCommand(m.output, "apt-get", "upgrade", "-y")
+	}
+	args := []string{"install", "--only-upgrade", "-y"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "apt-get", args...)
 }
 
 func (m *AptManager) IsInstalled(pkg string) (bool, error) {
-	cmd := exec.Command("dpkg", "-s", pkg)
-	err := cmd.Run()
-	return err == nil, nil
+	return packageInstalled("dpkg", "-s", pkg), nil
+}
+
+func (m *AptManager) ListUpgrades() ([]PackageUpgrade, error) {
+	// 模拟升级，不修改系统，也不需要锁
+	res, err := queryPackages("apt-get", "-s", "-o", "Debug::NoLocking=1", "upgrade")
+	if err != nil {
+		return nil, err
+	}
+	return parseAptSimulate(res.Stdout), nil
+}
+
+func (m *AptManager) Search(term string) ([]PackageInfo, error) {
+	res, err := queryPackages("apt-cache", "search", term)
+	if err != nil {
+		return nil, err
+	}
+	return parseAptCacheSearch(res.Stdout), nil
 }
 
 // DnfManager dnf 包管理器
-type DnfManager struct{}
+type DnfManager struct {
+	output io.Writer
+}
+
+func (m *DnfManager) Name() string { return "dnf" }
+
+func (m *DnfManager) SetOutput(w io.Writer) { m.output = w }
 
 func (m *DnfManager) Update() error {
-	cmd := exec.Command("dnf", "update", "-y")
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "dnf", "makecache", "-y")
 }
 
 func (m *DnfManager) Install(packages ...string) error {
 	args := []string{"install", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("dnf", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "dnf", args...)
 }
 
 func (m *DnfManager) Remove(packages ...string) error {
 	args := []string{"remove", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("dnf", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "dnf", args...)
+}
+
+func (m *DnfManager) Upgrade(packages ...string) error {
+	args := []string{"upgrade", "-y"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "dnf", args...)
 }
 
 func (m *DnfManager) IsInstalled(pkg string) (bool, error) {
-	cmd := exec.Command("rpm", "-q", pkg)
-	err := cmd.Run()
-	return err == nil, nil
+	return packageInstalled("rpm", "-q", pkg), nil
+}
+
+func (m *DnfManager) ListUpgrades() ([]PackageUpgrade, error) {
+	return listRPMUpgrades("dnf")
+}
+
+func (m *DnfManager) Search(term string) ([]PackageInfo, error) {
+	return searchRPM("dnf", term)
 }
 
 // YumManager yum 包管理器（兼容旧系统）
-type YumManager struct{}
+type YumManager struct {
+	output io.Writer
+}
+
+func (m *YumManager) Name() string { return "yum" }
+
+func (m *YumManager) SetOutput(w io.Writer) { m.output = w }
 
 func (m *YumManager) Update() error {
-	cmd := exec.Command("yum", "update", "-y")
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "yum", "makecache", "-y")
 }
 
 func (m *YumManager) Install(packages ...string) error {
 	args := []string{"install", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("yum", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "yum", args...)
 }
 
 func (m *YumManager) Remove(packages ...string) error {
 	args := []string{"remove", "-y"}
 	args = append(args, packages...)
-	cmd := exec.Command("yum", args...)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-	return cmd.Run()
+	return runPackageCommand(m.output, "yum", args...)
+}
+
+func (m *YumManager) Upgrade(packages ...string) error {
+	args := []string{"update", "-y"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "yum", args...)
 }
 
 func (m *YumManager) IsInstalled(pkg string) (bool, error) {
-	cmd := exec.Command("rpm", "-q", pkg)
-	err := cmd.Run()
-	return err == nil, nil
+	return packageInstalled("rpm", "-q", pkg), nil
+}
+
+func (m *YumManager) ListUpgrades() ([]PackageUpgrade, error) {
+	return listRPMUpgrades("yum")
+}
+
+func (m *YumManager) Search(term string) ([]PackageInfo, error) {
+	return searchRPM("yum", term)
+}
+
+// ApkManager apk 包管理器（Alpine）
+type ApkManager struct {
+	output io.Writer
+}
+
+func (m *ApkManager) Name() string { return "apk" }
+
+func (m *ApkManager) SetOutput(w io.Writer) { m.output = w }
+
+func (m *ApkManager) Update() error {
+	return runPackageCommand(m.output, "apk", "update")
+}
+
+func (m *ApkManager) Install(packages ...string) error {
+	args := []string{"add", "--no-progress"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "apk", args...)
+}
+
+func (m *ApkManager) Remove(packages ...string) error {
+	args := []string{"del", "--no-progress"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "apk", args...)
+}
+
+func (m *ApkManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return runPackageCommand(m.output, "apk", "upgrade", "--no-progress")
+	}
+	args := []string{"add", "--upgrade", "--no-progress"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "apk", args...)
+}
+
+func (m *ApkManager) IsInstalled(pkg string) (bool, error) {
+	return packageInstalled("apk", "info", "-e", pkg), nil
+}
+
+func (m *ApkManager) ListUpgrades() ([]PackageUpgrade, error) {
+	res, err := queryPackages("apk", "version", "-l", "<")
+	if err != nil {
+		return nil, err
+	}
+	return parseApkVersion(res.Stdout), nil
+}
+
+func (m *ApkManager) Search(term string) ([]PackageInfo, error) {
+	res, err := queryPackages("apk", "search", "-v", term)
+	if err != nil {
+		return nil, err
+	}
+	return parseApkSearch(res.Stdout), nil
+}
+
+// PacmanManager pacman 包管理器（Arch）
+type PacmanManager struct {
+	output io.Writer
+}
+
+func (m *PacmanManager) Name() string { return "pacman" }
+
+func (m *PacmanManager) SetOutput(w io.Writer) { m.output = w }
+
+// Update 不单独刷新同步数据库：-Sy 后再 -S 安装属于 Arch 不支持的部分升级，
+// 刷新只随 Upgrade 的 -Syu 一并进行
+func (m *PacmanManager) Update() error {
+	return nil
+}
+
+func (m *PacmanManager) Install(packages ...string) error {
+	args := []string{"-S", "--noconfirm", "--needed"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "pacman", args...)
+}
+
+func (m *PacmanManager) Remove(packages ...string) error {
+	args := []string{"-R", "--noconfirm"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "pacman", args...)
+}
+
+func (m *PacmanManager) Upgrade(packages ...string) error {
+	// Arch 不支持部分升级：始终同步升级全部
+	args := []string{"-Syu", "--noconfirm"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "pacman", args...)
+}
+
+func (m *PacmanManager) IsInstalled(pkg string) (bool, error) {
+	return packageInstalled("pacman", "-Q", pkg), nil
+}
+
+func (m *PacmanManager) ListUpgrades() ([]PackageUpgrade, error) {
+	// checkupdates（pacman-contrib）在临时数据库中同步，不改动系统的同步数据库
+	if CommandExists("checkupdates") {
+		res, err := queryPackages("checkupdates")
+		// 无可用更新时 checkupdates 返回 2
+		if err != nil && ExitCode(err) != 2 {
+			return nil, err
+		}
+		return parsePacmanQu(res.Stdout), nil
+	}
+
+	// 未安装 pacman-contrib 时按本地同步数据库列出，不刷新
+	res, err := queryPackages("pacman", "-Qu")
+	// 无可用更新时 pacman -Qu 返回 1 且无输出
+	if err != nil && (ExitCode(err) != 1 || res.Stdout != "") {
+		return nil, err
+	}
+	return parsePacmanQu(res.Stdout), nil
+}
+
+func (m *PacmanManager) Search(term string) ([]PackageInfo, error) {
+	res, err := queryPackages("pacman", "-Ss", term)
+	// 没有匹配时 pacman -Ss 返回 1 且无输出
+	if err != nil && (ExitCode(err) != 1 || res.Stdout != "") {
+		return nil, err
+	}
+	return parsePacmanSearch(res.Stdout), nil
+}
+
+// ZypperManager zypper 包管理器（openSUSE/SLES）
+type ZypperManager struct {
+	output io.Writer
+}
+
+func (m *ZypperManager) Name() string { return "zypper" }
+
+func (m *ZypperManager) SetOutput(w io.Writer) { m.output = w }
+
+func (m *ZypperManager) Update() error {
+	return runPackageCommand(m.output, "zypper", "--non-interactive", "refresh")
+}
+
+func (m *ZypperManager) Install(packages ...string) error {
+	args := []string{"--non-interactive", "install"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "zypper", args...)
+}
+
+func (m *ZypperManager) Remove(packages ...string) error {
+	args := []string{"--non-interactive", "remove"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "zypper", args...)
+}
+
+func (m *ZypperManager) Upgrade(packages ...string) error {
+	args := []string{"--non-interactive", "update"}
+	args = append(args, packages...)
+	return runPackageCommand(m.output, "zypper", args...)
+}
+
+func (m *ZypperManager) IsInstalled(pkg string) (bool, error) {
+	return packageInstalled("rpm", "-q", pkg), nil
+}
+
+func (m *ZypperManager) ListUpgrades() ([]PackageUpgrade, error) {
+	res, err := queryPackages("zypper", "--non-interactive", "--quiet", "list-updates")
+	if err != nil {
+		return nil, err
+	}
+	return parseZypperListUpdates(res.Stdout), nil
+}
+
+func (m *ZypperManager) Search(term string) ([]PackageInfo, error) {
+	res, err := queryPackages("zypper", "--non-interactive", "--quiet", "search", "-t", "package", term)
+	// 104 表示没有匹配的包
+	if err != nil && ExitCode(err) != 104 {
+		return nil, err
+	}
+	return parseZypperSearch(res.Stdout), nil
+}
+
+// searchRPM 执行 dnf/yum search（没有匹配时 dnf 4 与 yum 返回 1）
+func searchRPM(tool, term string) ([]PackageInfo, error) {
+	res, err := queryPackages(tool, "-q", "search", term)
+	if err != nil && (ExitCode(err) != 1 || res.Stdout != "") {
+		return nil, err
+	}
+	return parseRPMSearch(res.Stdout), nil
+}
+
+// listRPMUpgrades 通过 check-update 获取待升级包（退出码 100 表示有可用更新）
+func listRPMUpgrades(tool string) ([]PackageUpgrade, error) {
+	output, err := checkUpdate(tool)
+	if err != nil {
+		return nil, err
+	}
+	upgrades := parseCheckUpdate(output)
+
+	// 安全更新子集：不支持 --security 时忽略
+	if secOutput, err := checkUpdate(tool, "--security"); err == nil {
+		security := make(map[string]bool)
+		for _, u := range parseCheckUpdate(secOutput) {
+			security[u.Name] = true
+		}
+		for i := range upgrades {
+			upgrades[i].Security = security[upgrades[i].Name]
+		}
+	}
+
+	return upgrades, nil
+}
+
+func checkUpdate(tool string, extra ...string) (string, error) {
+	args := append([]string{"-q", "check-update"}, extra...)
+	res, err := queryPackages(tool, args...)
+	// 退出码 100 表示有可用更新
+	if err != nil && ExitCode(err) != 100 {
+		return "", err
+	}
+	return res.Stdout, nil
+}
+
+var aptInstLineRegex = regexp.MustCompile(`^Inst (\S+) (?:\[(\S+)\] )?\((\S+) (.*)\)`)
+
+// parseAptSimulate 解析 `apt-get -s upgrade` 输出中的 Inst 行
+func parseAptSimulate(output string) []PackageUpgrade {
+	var upgrades []PackageUpgrade
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		match := aptInstLineRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		source := match[4]
+		if idx := strings.LastIndex(source, " ["); idx != -1 {
+			source = source[:idx]
+		}
+		upgrades = append(upgrades, PackageUpgrade{
+			Name:      match[1],
+			Current:   match[2],
+			Candidate: match[3],
+			Source:    source,
+			Security:  strings.Contains(strings.ToLower(source), "security"),
+		})
+	}
+	return upgrades
+}
+
+// parseCheckUpdate 解析 dnf/yum check-update 输出（name.arch version repo）
+func parseCheckUpdate(output string) []PackageUpgrade {
+	var upgrades []PackageUpgrade
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasPrefix(line, " ") {
+			continue
+		}
+		name := fields[0]
+		if idx := strings.LastIndex(name, "."); idx > 0 {
+			name = name[:idx]
+		}
+		upgrades = append(upgrades, PackageUpgrade{
+			Name:      name,
+			Candidate: fields[1],
+			Source:    fields[2],
+			Security:  strings.Contains(strings.ToLower(fields[2]), "security"),
+		})
+	}
+	return upgrades
+}
+
+var apkVersionLineRegex = regexp.MustCompile(`^(\S+?)-([0-9][^-\s]*-r[0-9]+)\s+<\s+(\S+)`)
+
+// parseApkVersion 解析 `apk version -l '<'` 输出
+func parseApkVersion(output string) []PackageUpgrade {
+	var upgrades []PackageUpgrade
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		match := apkVersionLineRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		upgrades = append(upgrades, PackageUpgrade{
+			Name:      match[1],
+			Current:   match[2],
+			Candidate: match[3],
+		})
+	}
+	return upgrades
+}
+
+// parsePacmanQu 解析 `pacman -Qu` 输出（name old -> new）
+func parsePacmanQu(output string) []PackageUpgrade {
+	var upgrades []PackageUpgrade
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "->" {
+			continue
+		}
+		upgrades = append(upgrades, PackageUpgrade{
+			Name:      fields[0],
+			Current:   fields[1],
+			Candidate: fields[3],
+		})
+	}
+	return upgrades
+}
+
+// parseZypperListUpdates 解析 `zypper list-updates` 表格输出
+func parseZypperListUpdates(output string) []PackageUpgrade {
+	var upgrades []PackageUpgrade
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		cols := strings.Split(scanner.Text(), "|")
+		if len(cols) < 6 {
+			continue
+		}
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		// 表头：S | Repository | Name | Current Version | Available Version | Arch
+		if cols[0] != "v" {
+			continue
+		}
+		upgrades = append(upgrades, PackageUpgrade{
+			Name:      cols[2],
+			Current:   cols[3],
+			Candidate: cols[4],
+			Source:    cols[1],
+		})
+	}
+	return upgrades
+}
+
+// parseAptCacheSearch 解析 `apt-cache search` 输出（name - summary）
+func parseAptCacheSearch(output string) []PackageInfo {
+	var pkgs []PackageInfo
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		name, summary, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		pkgs = append(pkgs, PackageInfo{Name: name, Summary: strings.TrimSpace(summary)})
+	}
+	return pkgs
+}
+
+// parseRPMSearch 解析 dnf/yum search 输出：dnf 4/yum 为 "name.arch : summary"，
+// dnf 5 为缩进的 "name.arch<Tab>summary"；同名不同架构只保留一个
+func parseRPMSearch(output string) []PackageInfo {
+	var pkgs []PackageInfo
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		name, summary, ok := strings.Cut(line, " : ")
+		if !ok {
+			name, summary, ok = strings.Cut(strings.TrimSpace(line), "\t")
+		}
+		name = strings.TrimSpace(name)
+		// 跳过分组标题与 yum 的摘要续行（名称为空）
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			continue
+		}
+		if idx := strings.LastIndex(name, "."); idx > 0 {
+			name = name[:idx]
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		pkgs = append(pkgs, PackageInfo{Name: name, Summary: strings.TrimSpace(summary)})
+	}
+	return pkgs
+}
+
+var apkSearchLineRegex = regexp.MustCompile(`^(\S+?)-([0-9][^-\s]*-r[0-9]+)(?:\s+-\s+(.*))?$`)
+
+// parseApkSearch 解析 `apk search -v` 输出（name-version - description）
+func parseApkSearch(output string) []PackageInfo {
+	var pkgs []PackageInfo
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		match := apkSearchLineRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		pkgs = append(pkgs, PackageInfo{Name: match[1], Version: match[2], Summary: strings.TrimSpace(match[3])})
+	}
+	return pkgs
+}
+
+// parsePacmanSearch 解析 `pacman -Ss` 输出（repo/name version，下一行缩进为描述）
+func parsePacmanSearch(output string) []PackageInfo {
+	var pkgs []PackageInfo
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if n := len(pkgs); n > 0 && pkgs[n-1].Summary == "" {
+				pkgs[n-1].Summary = strings.TrimSpace(line)
+			}
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		_, name, ok := strings.Cut(fields[0], "/")
+		if !ok {
+			continue
+		}
+		pkgs = append(pkgs, PackageInfo{Name: name, Version: fields[1]})
+	}
+	return pkgs
+}
+
+// parseZypperSearch 解析 `zypper search` 表格输出（S | Name | Summary | Type）
+func parseZypperSearch(output string) []PackageInfo {
+	var pkgs []PackageInfo
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		cols := strings.Split(scanner.Text(), "|")
+		if len(cols) < 4 {
+			continue
+		}
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+		if cols[1] == "" || cols[1] == "Name" || cols[3] != "package" {
+			continue
+		}
+		pkgs = append(pkgs, PackageInfo{Name: cols[1], Summary: cols[2]})
+	}
+	return pkgs
 }
 
 // DetectPackageManager 检测包管理器
@@ -122,12 +656,18 @@ func DetectPackageManager(family DistroFamily) (PackageManager, error) {
 		return &AptManager{}, nil
 	case RedHat:
 		// 检测是 dnf 还是 yum
-		if _, err := exec.LookPath("dnf"); err == nil {
+		if GuestCommandExists("dnf") {
 			return &DnfManager{}, nil
 		}
 		return &YumManager{}, nil
+	case Alpine:
+		return &ApkManager{}, nil
+	case Arch:
+		return &PacmanManager{}, nil
+	case SUSE:
+		return &ZypperManager{}, nil
 	default:
-		return nil, fmt.Errorf("unsupported package manager for family: %d", family)
+		return nil, fmt.Errorf("unsupported package manager for family: %s", family)
 	}
 }
 
@@ -143,6 +683,9 @@ func InstallPackage(pkg string) error {
 		return err
 	}
 
+	// 按发行版映射包名
+	pkg = PackageName(distro.Family, pkg)
+
 	// 检查是否已安装
 	installed, _ := mgr.IsInstalled(pkg)
 	if installed {
@@ -0,0 +1,133 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAptSimulate(t *testing.T) {
+	output := `Reading package lists...
+Building dependency tree...
+Calculating upgrade...
+The following packages will be upgraded:
+  libssl3 openssl tzdata
+3 upgraded, 0 newly installed, 0 to remove and 0 not upgraded.
+Inst libssl3 [3.0.11-1~deb12u1] (3.0.11-1~deb12u2 Debian-Security:12/stable-security [amd64])
+Inst openssl [3.0.11-1~deb12u1] (3.0.11-1~deb12u2 Debian-Security:12/stable-security [amd64])
+Inst tzdata [2024a-0+deb12u1] (2024b-0+deb12u1 Debian:12.7/stable [all])
+Conf libssl3 (3.0.11-1~deb12u2 Debian-Security:12/stable-security [amd64])
+`
+	upgrades := parseAptSimulate(output)
+	require.Len(t, upgrades, 3)
+
+	assert.Equal(t, PackageUpgrade{
+		Name:      "libssl3",
+		Current:   "3.0.11-1~deb12u1",
+		Candidate: "3.0.11-1~deb12u2",
+		Source:    "Debian-Security:12/stable-security",
+		Security:  true,
+	}, upgrades[0])
+	assert.True(t, upgrades[1].Security)
+	assert.False(t, upgrades[2].Security)
+	assert.Equal(t, "2024b-0+deb12u1", upgrades[2].Candidate)
+}
+
+func TestParseCheckUpdate(t *testing.T) {
+	output := `
+openssl-libs.x86_64                 1:3.0.7-27.el9_4           baseos
+kernel.x86_64                       5.14.0-427.20.1.el9_4      baseos
+tzdata.noarch                       2024a-1.el9                appstream
+Obsoleting Packages
+grub2-tools.x86_64                  1:2.06-80.el9              baseos
+    grub2-tools.x86_64              1:2.06-77.el9              @baseos
+`
+	upgrades := parseCheckUpdate(output)
+	require.Len(t, upgrades, 3)
+	assert.Equal(t, "openssl-libs", upgrades[0].Name)
+	assert.Equal(t, "1:3.0.7-27.el9_4", upgrades[0].Candidate)
+	assert.Equal(t, "baseos", upgrades[0].Source)
+	assert.Equal(t, "tzdata", upgrades[2].Name)
+}
+
+func TestParseApkVersion(t *testing.T) {
+	output := `Installed:                                Available:
+busybox-1.36.1-r15                      < 1.36.1-r19
+libcrypto3-3.1.4-r5                     < 3.1.7-r0
+`
+	upgrades := parseApkVersion(output)
+	require.Len(t, upgrades, 2)
+	assert.Equal(t, PackageUpgrade{Name: "busybox", Current: "1.36.1-r15", Candidate: "1.36.1-r19"}, upgrades[0])
+	assert.Equal(t, "libcrypto3", upgrades[1].Name)
+}
+
+func TestParsePacmanQu(t *testing.T) {
+	output := `linux 6.9.7.arch1-1 -> 6.9.8.arch1-1
+openssl 3.3.0-1 -> 3.3.1-1
+warning: something unrelated
+`
+	upgrades := parsePacmanQu(output)
+	require.Len(t, upgrades, 2)
+	assert.Equal(t, PackageUpgrade{Name: "openssl", Current: "3.3.0-1", Candidate: "3.3.1-1"}, upgrades[1])
+}
+
+func TestParseZypperListUpdates(t *testing.T) {
+	output := `S | Repository | Name    | Current Version | Available Version | Arch
+--+------------+---------+-----------------+-------------------+-------
+v | Update     | openssl | 3.1.4-9.1       | 3.1.4-9.2         | x86_64
+`
+	upgrades := parseZypperListUpdates(output)
+	require.Len(t, upgrades, 1)
+	assert.Equal(t, PackageUpgrade{Name: "openssl", Current: "3.1.4-9.1", Candidate: "3.1.4-9.2", Source: "Update"}, upgrades[0])
+}
+
+func TestParseSearchOutput(t *testing.T) {
+	assert.Equal(t, []PackageInfo{
+		{Name: "nginx", Summary: "small, powerful, scalable web/proxy server"},
+		{Name: "nginx-extras", Summary: "nginx web/proxy server (extended version)"},
+	}, parseAptCacheSearch("nginx - small, powerful, scalable web/proxy server\nnginx-extras - nginx web/proxy server (extended version)\n"))
+
+	// dnf 4：分组标题与多架构重复项
+	dnf4 := `======================== Name Exactly Matched: nginx ========================
+nginx.x86_64 : A high performance web server and reverse proxy server
+===================== Name & Summary Matched: nginx ======================
+nginx-mod-stream.x86_64 : Nginx stream modules
+nginx-mod-stream.i686 : Nginx stream modules
+`
+	assert.Equal(t, []PackageInfo{
+		{Name: "nginx", Summary: "A high performance web server and reverse proxy server"},
+		{Name: "nginx-mod-stream", Summary: "Nginx stream modules"},
+	}, parseRPMSearch(dnf4))
+
+	dnf5 := "Matched fields: name (exact)\n nginx.x86_64\tA high performance web server and reverse proxy server\n"
+	assert.Equal(t, []PackageInfo{{Name: "nginx", Summary: "A high performance web server and reverse proxy server"}}, parseRPMSearch(dnf5))
+
+	assert.Equal(t, []PackageInfo{
+		{Name: "nginx", Version: "1.26.2-r0", Summary: "HTTP and reverse proxy server (stable version)"},
+	}, parseApkSearch("nginx-1.26.2-r0 - HTTP and reverse proxy server (stable version)\n"))
+
+	pacman := `extra/nginx 1.26.2-1 [installed]
+    Lightweight HTTP server and IMAP/POP3 proxy server
+extra/nginx-mainline 1.27.1-1
+    Lightweight HTTP server and IMAP/POP3 proxy server, mainline release
+`
+	assert.Equal(t, []PackageInfo{
+		{Name: "nginx", Version: "1.26.2-1", Summary: "Lightweight HTTP server and IMAP/POP3 proxy server"},
+		{Name: "nginx-mainline", Version: "1.27.1-1", Summary: "Lightweight HTTP server and IMAP/POP3 proxy server, mainline release"},
+	}, parsePacmanSearch(pacman))
+
+	zypper := `S | Name        | Summary                          | Type
+--+-------------+----------------------------------+-----------
+  | nginx       | A HTTP server and IMAP/POP3 proxy | package
+  | nginx       | A HTTP server and IMAP/POP3 proxy | srcpackage
+`
+	assert.Equal(t, []PackageInfo{{Name: "nginx", Summary: "A HTTP server and IMAP/POP3 proxy"}}, parseZypperSearch(zypper))
+}
+
+func TestPackageName(t *testing.T) {
+	assert.Equal(t, "openssh", PackageName(Arch, "openssh-server"))
+	assert.Equal(t, "openssh-server", PackageName(Debian, "openssh-server"))
+	assert.Equal(t, "vim-enhanced", PackageName(RedHat, "vim"))
+	assert.Equal(t, "curl", PackageName(Alpine, "curl"))
+}
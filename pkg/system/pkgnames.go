@@ -0,0 +1,54 @@
+package system
+
+// packageNames 通用包名（以 Debian 命名为准）到各发行版家族包名的映射，
+// 未列出的家族沿用通用包名
+var packageNames = map[string]map[DistroFamily]string{
+	"openssh-server": {
+		Arch: "openssh",
+		SUSE: "openssh",
+	},
+	"openssh-client": {
+		RedHat: "openssh-clients",
+		Arch:   "openssh",
+		SUSE:   "openssh",
+	},
+	"vim": {
+		RedHat: "vim-enhanced",
+	},
+	"cron": {
+		RedHat: "cronie",
+		Arch:   "cronie",
+		SUSE:   "cronie",
+		Alpine: "cronie",
+	},
+	"dnsutils": {
+		RedHat: "bind-utils",
+		Arch:   "bind",
+		SUSE:   "bind-utils",
+		Alpine: "bind-tools",
+	},
+	"iproute2": {
+		RedHat: "iproute",
+	},
+	"python3-systemd": {
+		Arch: "python-systemd",
+		SUSE: "python3-systemd",
+	},
+}
+
+// PackageName 返回通用包名在指定发行版家族上的实际包名
+func PackageName(family DistroFamily, name string) string {
+	if mapped, ok := packageNames[name][family]; ok {
+		return mapped
+	}
+	return name
+}
+
+// PackageNames 批量映射包名
+func PackageNames(family DistroFamily, names []string) []string {
+	mapped := make([]string, 0, len(names))
+	for _, name := range names {
+		mapped = append(mapped, PackageName(family, name))
+	}
+	return mapped
+}
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	rootMu  sync.RWMutex
+	rootDir = "/"
+)
+
+// guestBinDirs 在目标根目录中查找命令的目录
+var guestBinDirs = []string{"/usr/local/sbin", "/usr/local/bin", "/usr/sbin", "/usr/bin", "/sbin", "/bin"}
+
+// SetRoot 设置目标根目录（如挂载的磁盘镜像或容器 rootfs），"" 或 "/" 表示本机
+func SetRoot(dir string) error {
+	if dir == "" {
+		dir = "/"
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("invalid root %s: %w", dir, err)
+	}
+	if !IsDirectory(abs) {
+		return fmt.Errorf("root %s is not a directory", abs)
+	}
+
+	rootMu.Lock()
+	defer rootMu.Unlock()
+	rootDir = abs
+	return nil
+}
+
+// Root 返回目标根目录
+func Root() string {
+	rootMu.RLock()
+	defer rootMu.RUnlock()
+	return rootDir
+}
+
+// HasRoot 是否设置了非本机根目录（此时不应对本机执行命令）
+func HasRoot() bool {
+	return Root() != "/"
+}
+
+// RootPath 把目标系统内的绝对路径映射为本机路径（如 /etc/hosts -> /mnt/image/etc/hosts）
+func RootPath(path string) string {
+	root := Root()
+	if root == "/" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// GuestPath 把本机路径还原为目标系统内的路径（RootPath 的逆操作）
+func GuestPath(path string) string {
+	root := Root()
+	if root == "/" {
+		return path
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return "/" + rel
+}
+
+// GuestCommandExists 检查命令是否存在于目标根目录中（未设置根目录时等同 CommandExists）
+func GuestCommandExists(name string) bool {
+	if !HasRoot() {
+		return CommandExists(name)
+	}
+	for _, dir := range guestBinDirs {
+		if info, err := os.Stat(RootPath(filepath.Join(dir, name))); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// guestCommand 设置了根目录时通过 chroot 在目标系统内执行命令
+func guestCommand(name string, args ...string) (string, []string) {
+	if !HasRoot() {
+		return name, args
+	}
+	return "chroot", append([]string{Root(), name}, args...)
+}
+
+// RunGuestCommand 在目标系统内执行会修改系统的命令（设置根目录时经 chroot）
+func RunGuestCommand(name string, args ...string) (*Result, error) {
+	name, args = guestCommand(name, args...)
+	return RunCommand(name, args...)
+}
+
+// QueryGuestCommand 在目标系统内执行只读命令（设置根目录时经 chroot）
+func QueryGuestCommand(name string, args ...string) (*Result, error) {
+	name, args = guestCommand(name, args...)
+	return QueryCommand(name, args...)
+}
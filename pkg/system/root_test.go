@@ -0,0 +1,82 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withRoot(t *testing.T, dir string) {
+	t.Helper()
+	require.NoError(t, SetRoot(dir))
+	t.Cleanup(func() { _ = SetRoot("/") })
+}
+
+func TestRootPathMapping(t *testing.T) {
+	assert.False(t, HasRoot())
+	assert.Equal(t, "/etc/hosts", RootPath("/etc/hosts"))
+
+	dir := t.TempDir()
+	withRoot(t, dir)
+
+	assert.True(t, HasRoot())
+	assert.Equal(t, filepath.Join(dir, "etc/hosts"), RootPath("/etc/hosts"))
+	assert.Equal(t, "/etc/hosts", GuestPath(RootPath("/etc/hosts")))
+	assert.Equal(t, "/tmp/other", GuestPath("/tmp/other"))
+}
+
+func TestSetRootRejectsMissingDir(t *testing.T) {
+	err := SetRoot(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+	assert.False(t, HasRoot())
+}
+
+func TestGuestCommandUsesChroot(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "usr/bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "usr/bin/dnf"), nil, 0755))
+	withRoot(t, dir)
+
+	fake := NewFakeRunner()
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	assert.True(t, GuestCommandExists("dnf"))
+	assert.False(t, GuestCommandExists("apt-get"))
+
+	_, err := RunGuestCommand("dnf", "install", "-y", "vim")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chroot " + dir + " dnf install -y vim"}, fake.Calls())
+}
+
+func TestDetectDistroUnderRoot(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "etc"), 0755))
+	osRelease := "ID=debian\nVERSION_ID=\"12\"\nPRETTY_NAME=\"Debian GNU/Linux 12\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "etc/os-release"), []byte(osRelease), 0644))
+	withRoot(t, dir)
+
+	distro, err := DetectDistro()
+	require.NoError(t, err)
+	assert.Equal(t, "debian", distro.ID)
+	assert.Equal(t, Debian, distro.Family)
+}
+
+func TestServiceEnableUnderRootIsOffline(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bin/systemctl"), nil, 0755))
+	withRoot(t, dir)
+
+	fake := NewFakeRunner()
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	svc := NewServiceManager()
+	require.NoError(t, svc.EnableAndStart("fail2ban"))
+	require.NoError(t, svc.Restart("fail2ban"))
+	assert.Equal(t, []string{"systemctl --root=" + dir + " enable fail2ban"}, fake.Calls())
+}
@@ -0,0 +1,271 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+)
+
+// Command 待执行的命令
+type Command struct {
+	Name    string
+	Args    []string
+	Env     []string      // 追加到当前环境变量之后
+	Stdin   io.Reader     // 可选
+	Output  io.Writer     // 可选：stdout/stderr 同时实时写入（仍会被捕获）
+	Timeout time.Duration // 0 表示不超时（仍受 ctx 约束）
+
+	// ReadOnly 只读命令（查询状态等），dry-run 模式下仍会执行
+	ReadOnly bool
+}
+
+// Argv 返回完整命令行参数
+func (c Command) Argv() []string {
+	return append([]string{c.Name}, c.Args...)
+}
+
+// String 返回便于日志阅读的命令行（含空格的参数加引号）
+func (c Command) String() string {
+	parts := make([]string, 0, len(c.Args)+1)
+	for _, arg := range c.Argv() {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Result 命令执行结果
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+	DryRun   bool // dry-run 模式下未实际执行
+}
+
+// CommandError 命令执行失败（包含退出码与 stderr）
+type CommandError struct {
+	Command  string
+	ExitCode int // -1 表示未能启动或被信号终止
+	Stderr   string
+	TimedOut bool
+	Err      error
+}
+
+func (e *CommandError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s", e.Command)
+	switch {
+	case e.TimedOut:
+		b.WriteString(": timed out")
+	case e.ExitCode >= 0:
+		fmt.Fprintf(&b, ": exit status %d", e.ExitCode)
+	default:
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	if e.Stderr != "" {
+		fmt.Fprintf(&b, ": %s", e.Stderr)
+	}
+	return b.String()
+}
+
+func (e *CommandError) Unwrap() error { return e.Err }
+
+// ExitCode 返回命令错误的退出码；非命令错误返回 -1，nil 返回 0
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.ExitCode
+	}
+	return -1
+}
+
+// Runner 命令执行器（可替换为 FakeRunner 以便测试）
+type Runner interface {
+	Run(ctx context.Context, cmd Command) (*Result, error)
+	LookPath(file string) (string, error)
+}
+
+// ExecRunner 基于 os/exec 的命令执行器
+type ExecRunner struct {
+	logger *internal.Logger
+	drm    *internal.DryRunManager
+}
+
+// NewExecRunner 创建命令执行器；dryRun 为 true 时非只读命令只记录不执行
+func NewExecRunner(dryRun bool, logger *internal.Logger) *ExecRunner {
+	r := &ExecRunner{logger: logger}
+	if logger != nil {
+		r.drm = internal.NewDryRunManager(dryRun, logger)
+	}
+	return r
+}
+
+// maxStderrInError 错误信息中保留的 stderr 最大长度
+const maxStderrInError = 2048
+
+func (r *ExecRunner) Run(ctx context.Context, c Command) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if !c.ReadOnly && r.drm != nil && r.drm.IsEnabled() {
+		r.drm.LogCommand(c.Name, c.Args...)
+		return &Result{DryRun: true}, nil
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	if r.logger != nil {
+		r.logger.Debug("exec: %s", c.String())
+	}
+
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	cmd.Stdin = c.Stdin
+
+	var stdout, stderr bytes.Buffer
+	if c.Output != nil {
+		out := &syncWriter{w: c.Output}
+		cmd.Stdout = io.MultiWriter(&stdout, out)
+		cmd.Stderr = io.MultiWriter(&stderr, out)
+	} else {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	if err == nil {
+		if r.logger != nil {
+			r.logger.With(internal.Fields{Operation: "exec", Duration: result.Duration}).Debug("exec ok: %s", c.Name)
+		}
+		return result, nil
+	}
+
+	cmdErr := &CommandError{
+		Command:  c.String(),
+		ExitCode: -1,
+		Stderr:   truncateTail(strings.TrimSpace(result.Stderr), maxStderrInError),
+		TimedOut: errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Err:      err,
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		cmdErr.ExitCode = exitErr.ExitCode()
+	}
+	result.ExitCode = cmdErr.ExitCode
+
+	if r.logger != nil {
+		r.logger.With(internal.Fields{Operation: "exec", Duration: result.Duration, Err: cmdErr}).Debug("exec failed: %s", c.Name)
+	}
+	return result, cmdErr
+}
+
+func (r *ExecRunner) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// syncWriter 保证 stdout/stderr 并发写入同一 Output 时不交错
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+func truncateTail(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return "..." + s[len(s)-limit:]
+}
+
+var (
+	runnerMu      sync.RWMutex
+	defaultRunner Runner = NewExecRunner(false, nil)
+)
+
+// DefaultRunner 返回当前全局命令执行器
+func DefaultRunner() Runner {
+	runnerMu.RLock()
+	defer runnerMu.RUnlock()
+	return defaultRunner
+}
+
+// SetRunner 替换全局命令执行器，返回原执行器（测试中用于恢复）
+func SetRunner(r Runner) Runner {
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	prev := defaultRunner
+	defaultRunner = r
+	return prev
+}
+
+// ConfigureRunner 按配置（dry-run、日志）重建全局命令执行器
+func ConfigureRunner(dryRun bool, logger *internal.Logger) {
+	SetRunner(NewExecRunner(dryRun, logger))
+}
+
+// 命令的默认超时，避免卡住的命令让界面一直停在执行中：只读查询应很快返回，
+// 修改系统的命令（重启服务、应用网络配置等）允许更长时间
+const (
+	DefaultQueryTimeout = 30 * time.Second
+	DefaultRunTimeout   = 5 * time.Minute
+)
+
+// RunCommand 执行会修改系统的命令（dry-run 模式下只记录），超过 DefaultRunTimeout 后终止
+func RunCommand(name string, args ...string) (*Result, error) {
+	return RunCommandContext(context.Background(), name, args...)
+}
+
+// RunCommandContext 同 RunCommand，ctx 取消或到期时终止命令
+func RunCommandContext(ctx context.Context, name string, args ...string) (*Result, error) {
+	return DefaultRunner().Run(ctx, Command{Name: name, Args: args, Timeout: DefaultRunTimeout})
+}
+
+// QueryCommand 执行只读命令（dry-run 模式下也会执行），超过 DefaultQueryTimeout 后终止
+func QueryCommand(name string, args ...string) (*Result, error) {
+	return QueryCommandContext(context.Background(), name, args...)
+}
+
+// QueryCommandContext 同 QueryCommand，ctx 取消或到期时终止命令
+func QueryCommandContext(ctx context.Context, name string, args ...string) (*Result, error) {
+	return DefaultRunner().Run(ctx, Command{Name: name, Args: args, ReadOnly: true, Timeout: DefaultQueryTimeout})
+}
+
+// CommandExists 检查命令是否存在于 PATH
+func CommandExists(name string) bool {
+	_, err := DefaultRunner().LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,85 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// FakeRunner 测试用命令执行器：记录调用，按命令行返回预设结果
+type FakeRunner struct {
+	mu sync.Mutex
+
+	// Responses 以完整命令行（Command.String()）或命令名为键的预设结果
+	Responses map[string]FakeResponse
+	// Paths LookPath 可找到的命令；为 nil 时所有命令都视为存在
+	Paths map[string]bool
+
+	calls []Command
+}
+
+// FakeResponse 预设的命令结果
+type FakeResponse struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// NewFakeRunner 创建测试用命令执行器
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{Responses: make(map[string]FakeResponse)}
+}
+
+// On 为命令行（或命令名）预设结果
+func (f *FakeRunner) On(cmdline string, resp FakeResponse) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Responses[cmdline] = resp
+	return f
+}
+
+func (f *FakeRunner) Run(_ context.Context, c Command) (*Result, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, c)
+	resp, ok := f.Responses[c.String()]
+	if !ok {
+		resp = f.Responses[c.Name]
+	}
+	f.mu.Unlock()
+
+	if c.Output != nil {
+		fmt.Fprint(c.Output, resp.Stdout)
+		fmt.Fprint(c.Output, resp.Stderr)
+	}
+
+	result := &Result{Stdout: resp.Stdout, Stderr: resp.Stderr, ExitCode: resp.ExitCode}
+	if resp.ExitCode != 0 {
+		return result, &CommandError{
+			Command:  c.String(),
+			ExitCode: resp.ExitCode,
+			Stderr:   strings.TrimSpace(resp.Stderr),
+			Err:      fmt.Errorf("exit status %d", resp.ExitCode),
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeRunner) LookPath(file string) (string, error) {
+	if f.Paths == nil || f.Paths[file] {
+		return "/usr/bin/" + file, nil
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+// Calls 返回已执行的命令行
+func (f *FakeRunner) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]string, 0, len(f.calls))
+	for _, c := range f.calls {
+		calls = append(calls, c.String())
+	}
+	return calls
+}
@@ -0,0 +1,156 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Akuma-real/server-toolkit/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecRunnerCapturesStderrAndExitCode(t *testing.T) {
+	r := NewExecRunner(false, nil)
+
+	res, err := r.Run(context.Background(), Command{Name: "sh", Args: []string{"-c", "echo out; echo oops >&2; exit 3"}})
+	require.Error(t, err)
+	assert.Equal(t, "out\n", res.Stdout)
+	assert.Equal(t, 3, res.ExitCode)
+	assert.Equal(t, 3, ExitCode(err))
+	assert.Contains(t, err.Error(), `sh -c "echo out; echo oops >&2; exit 3": exit status 3: oops`)
+}
+
+func TestExecRunnerTimeout(t *testing.T) {
+	r := NewExecRunner(false, nil)
+
+	_, err := r.Run(context.Background(), Command{Name: "sleep", Args: []string{"5"}, Timeout: 50 * time.Millisecond})
+	require.Error(t, err)
+
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.True(t, cmdErr.TimedOut)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestCommandHelpersSetTimeouts(t *testing.T) {
+	fake := NewFakeRunner()
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	_, _ = QueryCommand("hostname")
+	_, _ = RunCommand("hostname", "web-01")
+	require.Len(t, fake.calls, 2)
+	assert.Equal(t, DefaultQueryTimeout, fake.calls[0].Timeout)
+	assert.Equal(t, DefaultRunTimeout, fake.calls[1].Timeout)
+
+	// ctx 先于默认超时到期
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	SetRunner(NewExecRunner(false, nil))
+	_, err := QueryCommandContext(ctx, "sleep", "5")
+	var cmdErr *CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.True(t, cmdErr.TimedOut)
+}
+
+func TestExecRunnerStreamsOutput(t *testing.T) {
+	r := NewExecRunner(false, nil)
+
+	var out bytes.Buffer
+	res, err := r.Run(context.Background(), Command{Name: "sh", Args: []string{"-c", "echo hello; echo world >&2"}, Output: &out})
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", res.Stdout)
+	assert.Contains(t, out.String(), "hello")
+	assert.Contains(t, out.String(), "world")
+}
+
+func TestExecRunnerDryRunSkipsMutatingCommands(t *testing.T) {
+	r := NewExecRunner(true, internal.NewLogger(internal.ERROR, os.Stdout))
+
+	res, err := r.Run(context.Background(), Command{Name: "false"})
+	require.NoError(t, err)
+	assert.True(t, res.DryRun)
+
+	res, err = r.Run(context.Background(), Command{Name: "echo", Args: []string{"hi"}, ReadOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, "hi\n", res.Stdout)
+}
+
+func TestFakeRunnerSurfacesStderrThroughServiceManager(t *testing.T) {
+	fake := NewFakeRunner().On("systemctl start nginx", FakeResponse{
+		Stderr:   "Job for nginx.service failed because the control process exited with error code.",
+		ExitCode: 1,
+	})
+	fake.Paths = map[string]bool{"systemctl": true}
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	err := NewServiceManager().Start("nginx")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to start service nginx")
+	assert.Contains(t, err.Error(), "control process exited with error code")
+	assert.Equal(t, []string{"systemctl start nginx"}, fake.Calls())
+}
+
+func TestEnableAndStartOpenRCAddsToDefaultRunlevel(t *testing.T) {
+	fake := NewFakeRunner()
+	fake.Paths = map[string]bool{"rc-service": true, "rc-update": true}
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	require.NoError(t, NewServiceManager().EnableAndStart("fail2ban"))
+	assert.Equal(t, []string{"rc-update add fail2ban default", "rc-service fail2ban start"}, fake.Calls())
+
+	fake.On("rc-update add sshguard default", FakeResponse{Stderr: "rc-update: service `sshguard' does not exist", ExitCode: 1})
+	err := NewServiceManager().EnableAndStart("sshguard")
+	require.ErrorContains(t, err, "failed to enable service sshguard")
+	assert.NotContains(t, fake.Calls(), "rc-service sshguard start")
+}
+
+func TestPackageSearchTreatsNoMatchAsEmpty(t *testing.T) {
+	fake := NewFakeRunner().
+		On("pacman -Ss nosuch", FakeResponse{ExitCode: 1}).
+		On("zypper --non-interactive --quiet search -t package nosuch", FakeResponse{ExitCode: 104}).
+		On("dnf -q search nosuch", FakeResponse{Stderr: "Error: No matches found.", ExitCode: 1}).
+		On("apt-cache search nosuch", FakeResponse{Stderr: "E: Could not open lock file", ExitCode: 100})
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	for _, pm := range []PackageManager{&PacmanManager{}, &ZypperManager{}, &DnfManager{}} {
+		pkgs, err := pm.Search("nosuch")
+		require.NoError(t, err, pm.Name())
+		assert.Empty(t, pkgs, pm.Name())
+	}
+
+	_, err := (&AptManager{}).Search("nosuch")
+	assert.ErrorContains(t, err, "Could not open lock file")
+}
+
+func TestPackageManagersUseRunnerExitCodes(t *testing.T) {
+	fake := NewFakeRunner().
+		On("pacman -Qu", FakeResponse{ExitCode: 1}).
+		On("dnf -q check-update", FakeResponse{Stdout: "openssl.x86_64 1:3.0.7-27.el9 baseos\n", ExitCode: 100}).
+		On("dnf -q check-update --security", FakeResponse{ExitCode: 0})
+	fake.Paths = map[string]bool{}
+	prev := SetRunner(fake)
+	t.Cleanup(func() { SetRunner(prev) })
+
+	upgrades, err := (&PacmanManager{}).ListUpgrades()
+	require.NoError(t, err)
+	assert.Empty(t, upgrades)
+
+	fake.Paths = map[string]bool{"checkupdates": true}
+	fake.On("checkupdates", FakeResponse{ExitCode: 2})
+	upgrades, err = (&PacmanManager{}).ListUpgrades()
+	require.NoError(t, err)
+	assert.Empty(t, upgrades)
+	assert.Equal(t, []string{"pacman -Qu", "checkupdates"}, fake.Calls())
+
+	upgrades, err = (&DnfManager{}).ListUpgrades()
+	require.NoError(t, err)
+	require.Len(t, upgrades, 1)
+	assert.Equal(t, "openssl", upgrades[0].Name)
+}
@@ -2,12 +2,42 @@ package system
 
 import (
 	"fmt"
-	"os/exec"
+	"strings"
 )
 
 // ServiceManager 服务管理器
 type ServiceManager struct{}
 
+// runServiceCommand 执行服务管理命令（错误中包含 stderr）
+func runServiceCommand(name string, args ...string) error {
+	_, err := RunCommand(name, args...)
+	return err
+}
+
+// setRootEnabled 设置根目录时只修改目标系统的开机启动配置，不启动/停止任何服务
+func setRootEnabled(serviceName string, enable bool) error {
+	verb, rcVerb := "enable", "add"
+	if !enable {
+		verb, rcVerb = "disable", "del"
+	}
+
+	if GuestCommandExists("systemctl") {
+		if _, err := RunCommand("systemctl", "--root="+Root(), verb, serviceName); err != nil {
+			return fmt.Errorf("failed to %s service %s: %w", verb, serviceName, err)
+		}
+		return nil
+	}
+
+	if GuestCommandExists("rc-update") {
+		if _, err := RunGuestCommand("rc-update", rcVerb, serviceName, "default"); err != nil {
+			return fmt.Errorf("failed to %s service %s: %w", verb, serviceName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no service manager found in %s", Root())
+}
+
 // NewServiceManager 创建服务管理器
 func NewServiceManager() *ServiceManager {
 	return &ServiceManager{}
@@ -15,27 +45,32 @@ func NewServiceManager() *ServiceManager {
 
 // EnableAndStart 启用并启动服务
 func (m *ServiceManager) EnableAndStart(serviceName string) error {
+	// 离线根目录：只设置开机启动
+	if HasRoot() {
+		return setRootEnabled(serviceName, true)
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
+	if CommandExists("systemctl") {
 		// 启用服务
-		cmd := exec.Command("systemctl", "enable", serviceName)
-		if err := cmd.Run(); err != nil {
+		if err := runServiceCommand("systemctl", "enable", serviceName); err != nil {
 			return fmt.Errorf("failed to enable service %s: %w", serviceName, err)
 		}
 
 		// 启动服务
-		cmd = exec.Command("systemctl", "start", serviceName)
-		if err := cmd.Run(); err != nil {
+		if err := runServiceCommand("systemctl", "start", serviceName); err != nil {
 			return fmt.Errorf("failed to start service %s: %w", serviceName, err)
 		}
 
 		return nil
 	}
 
-	// 尝试 rc-service（OpenRC）
-	if _, err := exec.LookPath("rc-service"); err == nil {
-		cmd := exec.Command("rc-service", serviceName, "start")
-		if err := cmd.Run(); err != nil {
+	// 尝试 rc-service（OpenRC）：先加入 default 运行级别，否则重启后不会启动
+	if CommandExists("rc-service") {
+		if err := m.Enable(serviceName); err != nil {
+			return err
+		}
+		if err := runServiceCommand("rc-service", serviceName, "start"); err != nil {
 			return fmt.Errorf("failed to start service %s: %w", serviceName, err)
 		}
 		return nil
@@ -46,19 +81,22 @@ func (m *ServiceManager) EnableAndStart(serviceName string) error {
 
 // Restart 重启服务
 func (m *ServiceManager) Restart(serviceName string) error {
+	// 离线根目录中没有运行中的服务
+	if HasRoot() {
+		return nil
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
-		cmd := exec.Command("systemctl", "restart", serviceName)
-		if err := cmd.Run(); err != nil {
+	if CommandExists("systemctl") {
+		if err := runServiceCommand("systemctl", "restart", serviceName); err != nil {
 			return fmt.Errorf("failed to restart service %s: %w", serviceName, err)
 		}
 		return nil
 	}
 
 	// 尝试 rc-service
-	if _, err := exec.LookPath("rc-service"); err == nil {
-		cmd := exec.Command("rc-service", serviceName, "restart")
-		if err := cmd.Run(); err != nil {
+	if CommandExists("rc-service") {
+		if err := runServiceCommand("rc-service", serviceName, "restart"); err != nil {
 			return fmt.Errorf("failed to restart service %s: %w", serviceName, err)
 		}
 		return nil
@@ -69,11 +107,15 @@ func (m *ServiceManager) Restart(serviceName string) error {
 
 // Reload 重载服务
 func (m *ServiceManager) Reload(serviceName string) error {
+	// 离线根目录中没有运行中的服务
+	if HasRoot() {
+		return nil
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
+	if CommandExists("systemctl") {
 		// 尝试 reload
-		cmd := exec.Command("systemctl", "reload", serviceName)
-		if err := cmd.Run(); err != nil {
+		if err := runServiceCommand("systemctl", "reload", serviceName); err != nil {
 			// reload 失败，尝试 restart
 			return m.Restart(serviceName)
 		}
@@ -81,9 +123,8 @@ func (m *ServiceManager) Reload(serviceName string) error {
 	}
 
 	// 尝试 rc-service
-	if _, err := exec.LookPath("rc-service"); err == nil {
-		cmd := exec.Command("rc-service", serviceName, "restart")
-		if err := cmd.Run(); err != nil {
+	if CommandExists("rc-service") {
+		if err := runServiceCommand("rc-service", serviceName, "restart"); err != nil {
 			return fmt.Errorf("failed to reload service %s: %w", serviceName, err)
 		}
 		return nil
@@ -94,19 +135,22 @@ func (m *ServiceManager) Reload(serviceName string) error {
 
 // Stop 停止服务
 func (m *ServiceManager) Stop(serviceName string) error {
+	// 离线根目录中没有运行中的服务
+	if HasRoot() {
+		return nil
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
-		cmd := exec.Command("systemctl", "stop", serviceName)
-		if err := cmd.Run(); err != nil {
+	if CommandExists("systemctl") {
+		if err := runServiceCommand("systemctl", "stop", serviceName); err != nil {
 			return fmt.Errorf("failed to stop service %s: %w", serviceName, err)
 		}
 		return nil
 	}
 
 	// 尝试 rc-service
-	if _, err := exec.LookPath("rc-service"); err == nil {
-		cmd := exec.Command("rc-service", serviceName, "stop")
-		if err := cmd.Run(); err != nil {
+	if CommandExists("rc-service") {
+		if err := runServiceCommand("rc-service", serviceName, "stop"); err != nil {
 			return fmt.Errorf("failed to stop service %s: %w", serviceName, err)
 		}
 		return nil
@@ -115,19 +159,91 @@ func (m *ServiceManager) Stop(serviceName string) error {
 	return fmt.Errorf("no service manager found")
 }
 
+// Start 启动服务
+func (m *ServiceManager) Start(serviceName string) error {
+	// 离线根目录中没有运行中的服务
+	if HasRoot() {
+		return nil
+	}
+
+	if CommandExists("systemctl") {
+		if err := runServiceCommand("systemctl", "start", serviceName); err != nil {
+			return fmt.Errorf("failed to start service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	if CommandExists("rc-service") {
+		if err := runServiceCommand("rc-service", serviceName, "start"); err != nil {
+			return fmt.Errorf("failed to start service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no service manager found")
+}
+
+// Enable 设置服务开机启动（不立即启动）
+func (m *ServiceManager) Enable(serviceName string) error {
+	if HasRoot() {
+		return setRootEnabled(serviceName, true)
+	}
+
+	if CommandExists("systemctl") {
+		if err := runServiceCommand("systemctl", "enable", serviceName); err != nil {
+			return fmt.Errorf("failed to enable service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	if CommandExists("rc-update") {
+		if err := runServiceCommand("rc-update", "add", serviceName, "default"); err != nil {
+			return fmt.Errorf("failed to enable service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no service manager found")
+}
+
+// Disable 取消服务开机启动（不停止当前运行）
+func (m *ServiceManager) Disable(serviceName string) error {
+	if HasRoot() {
+		return setRootEnabled(serviceName, false)
+	}
+
+	if CommandExists("systemctl") {
+		if err := runServiceCommand("systemctl", "disable", serviceName); err != nil {
+			return fmt.Errorf("failed to disable service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	if CommandExists("rc-update") {
+		if err := runServiceCommand("rc-update", "del", serviceName, "default"); err != nil {
+			return fmt.Errorf("failed to disable service %s: %w", serviceName, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no service manager found")
+}
+
 // IsActive 检查服务是否激活
 func (m *ServiceManager) IsActive(serviceName string) (bool, error) {
+	if HasRoot() {
+		return false, nil
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
-		cmd := exec.Command("systemctl", "is-active", serviceName)
-		err := cmd.Run()
+	if CommandExists("systemctl") {
+		_, err := QueryCommand("systemctl", "is-active", "--quiet", serviceName)
 		return err == nil, nil
 	}
 
 	// 尝试 rc-service
-	if _, err := exec.LookPath("rc-service"); err == nil {
-		cmd := exec.Command("rc-service", serviceName, "status")
-		err := cmd.Run()
+	if CommandExists("rc-service") {
+		_, err := QueryCommand("rc-service", serviceName, "status")
 		return err == nil, nil
 	}
 
@@ -136,10 +252,14 @@ func (m *ServiceManager) IsActive(serviceName string) (bool, error) {
 
 // IsEnabled 检查服务是否启用
 func (m *ServiceManager) IsEnabled(serviceName string) (bool, error) {
+	if HasRoot() {
+		_, err := QueryCommand("systemctl", "--root="+Root(), "is-enabled", "--quiet", serviceName)
+		return err == nil, nil
+	}
+
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
-		cmd := exec.Command("systemctl", "is-enabled", serviceName)
-		err := cmd.Run()
+	if CommandExists("systemctl") {
+		_, err := QueryCommand("systemctl", "is-enabled", "--quiet", serviceName)
 		return err == nil, nil
 	}
 
@@ -149,18 +269,134 @@ func (m *ServiceManager) IsEnabled(serviceName string) (bool, error) {
 // GetServiceStatus 获取服务状态
 func (m *ServiceManager) GetServiceStatus(serviceName string) (string, error) {
 	// 检查 systemctl 是否存在
-	if _, err := exec.LookPath("systemctl"); err == nil {
-		cmd := exec.Command("systemctl", "status", serviceName)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
+	if CommandExists("systemctl") {
+		res, err := QueryCommand("systemctl", "status", "--no-pager", serviceName)
+		// 服务未运行时 systemctl status 返回 3，但输出仍然有效
+		if err != nil && res.Stdout == "" {
 			return "", fmt.Errorf("failed to get service status: %w", err)
 		}
-		return string(output), nil
+		return res.Stdout, nil
 	}
 
 	return "", fmt.Errorf("no service manager found")
 }
 
+// ServiceUnit 服务列表中的一项
+type ServiceUnit struct {
+	Name        string
+	Load        string // loaded / not-found（OpenRC 为空）
+	Active      string // active / inactive / failed / activating
+	Sub         string // running / exited / dead / started / stopped ...
+	Description string
+}
+
+// ListServices 列出服务（systemd 或 OpenRC）
+func (m *ServiceManager) ListServices() ([]ServiceUnit, error) {
+	if HasRoot() {
+		return nil, fmt.Errorf("service list is not available for offline root %s", Root())
+	}
+
+	if CommandExists("systemctl") {
+		output, err := serviceCommandOutput("systemctl", "list-units", "--type=service", "--all", "--no-legend", "--no-pager", "--plain")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		return parseSystemctlListUnits(output), nil
+	}
+
+	if CommandExists("rc-status") {
+		output, err := serviceCommandOutput("rc-status", "--all")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %w", err)
+		}
+		return parseRCStatus(output), nil
+	}
+
+	return nil, fmt.Errorf("no service manager found")
+}
+
+// JournalLines 获取服务最近的日志（仅 systemd）
+func (m *ServiceManager) JournalLines(serviceName string, lines int) ([]string, error) {
+	if !CommandExists("journalctl") {
+		return nil, fmt.Errorf("journalctl not found")
+	}
+
+	output, err := serviceCommandOutput("journalctl", "-u", serviceName, "-n", fmt.Sprint(lines), "--no-pager", "-o", "short-iso")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal for %s: %w", serviceName, err)
+	}
+
+	var result []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "-- ") {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result, nil
+}
+
+// serviceCommandOutput 执行只读命令并返回 stdout
+func serviceCommandOutput(name string, args ...string) (string, error) {
+	res, err := QueryCommand(name, args...)
+	if err != nil {
+		return "", err
+	}
+	return res.Stdout, nil
+}
+
+// parseSystemctlListUnits 解析 `systemctl list-units --no-legend --plain` 输出
+func parseSystemctlListUnits(output string) []ServiceUnit {
+	var units []ServiceUnit
+	for _, line := range strings.Split(output, "\n") {
+		// 失败的单元前可能带有 ● 标记
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "●"))
+		fields := strings.Fields(line)
+		if len(fields) < 4 || !strings.HasSuffix(fields[0], ".service") {
+			continue
+		}
+		units = append(units, ServiceUnit{
+			Name:        strings.TrimSuffix(fields[0], ".service"),
+			Load:        fields[1],
+			Active:      fields[2],
+			Sub:         fields[3],
+			Description: strings.Join(fields[4:], " "),
+		})
+	}
+	return units
+}
+
+// parseRCStatus 解析 `rc-status --all` 输出（"name [ started ]"）
+func parseRCStatus(output string) []ServiceUnit {
+	var units []ServiceUnit
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		open := strings.Index(line, "[")
+		end := strings.LastIndex(line, "]")
+		if open <= 0 || end < open {
+			continue
+		}
+		name := strings.TrimSpace(line[:open])
+		state := strings.TrimSpace(line[open+1 : end])
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		active := "inactive"
+		switch state {
+		case "started":
+			active = "active"
+		case "crashed":
+			active = "failed"
+		case "starting", "stopping":
+			active = "activating"
+		}
+		units = append(units, ServiceUnit{Name: name, Active: active, Sub: state})
+	}
+	return units
+}
+
 // 便捷函数
 func EnableAndStart(serviceName string) error {
 	m := NewServiceManager()
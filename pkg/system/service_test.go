@@ -0,0 +1,38 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSystemctlListUnits(t *testing.T) {
+	output := `cron.service                       loaded    active   running CRON daemon
+● nginx.service                    loaded    failed   failed  A high performance web server
+ssh.service                        loaded    active   running OpenBSD Secure Shell server
+foo.mount                          loaded    active   mounted /foo
+plymouth.service                   not-found inactive dead    plymouth.service
+`
+	units := parseSystemctlListUnits(output)
+	require.Len(t, units, 4)
+	assert.Equal(t, ServiceUnit{Name: "cron", Load: "loaded", Active: "active", Sub: "running", Description: "CRON daemon"}, units[0])
+	assert.Equal(t, "nginx", units[1].Name)
+	assert.Equal(t, "failed", units[1].Active)
+	assert.Equal(t, "not-found", units[3].Load)
+}
+
+func TestParseRCStatus(t *testing.T) {
+	output := `Runlevel: default
+ sshd                                       [  started  ]
+ crond                                      [  stopped  ]
+ nginx                                      [  crashed  ]
+Dynamic Runlevel: needed/wanted
+ sshd                                       [  started  ]
+`
+	units := parseRCStatus(output)
+	require.Len(t, units, 3)
+	assert.Equal(t, ServiceUnit{Name: "sshd", Active: "active", Sub: "started"}, units[0])
+	assert.Equal(t, "inactive", units[1].Active)
+	assert.Equal(t, "failed", units[2].Active)
+}
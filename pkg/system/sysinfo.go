@@ -0,0 +1,451 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	procRoot    = "/proc"
+	sysRoot     = "/sys"
+	publicIPURL = "https://api.ipify.org"
+)
+
+// pseudoFilesystems 不计入磁盘用量的虚拟文件系统
+var pseudoFilesystems = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true,
+	"cgroup": true, "cgroup2": true, "securityfs": true, "pstore": true, "bpf": true,
+	"debugfs": true, "tracefs": true, "configfs": true, "fusectl": true, "mqueue": true,
+	"hugetlbfs": true, "autofs": true, "binfmt_misc": true, "rpc_pipefs": true,
+	"nsfs": true, "ramfs": true, "efivarfs": true, "squashfs": true, "overlay": true,
+	"selinuxfs": true,
+}
+
+// CPUInfo CPU 信息
+type CPUInfo struct {
+	Model string
+	Cores int
+}
+
+// MemInfo 内存信息（字节）
+type MemInfo struct {
+	Total     uint64
+	Available uint64
+	SwapTotal uint64
+	SwapFree  uint64
+}
+
+// Used 已用内存
+func (m MemInfo) Used() uint64 {
+	if m.Available > m.Total {
+		return 0
+	}
+	return m.Total - m.Available
+}
+
+// LoadAvg 系统负载
+type LoadAvg struct {
+	One, Five, Fifteen float64
+}
+
+// DiskUsage 挂载点用量（字节）
+type DiskUsage struct {
+	Device string
+	Mount  string
+	FSType string
+	Total  uint64
+	Used   uint64
+	Avail  uint64
+}
+
+// NetInterface 网络接口
+type NetInterface struct {
+	Name  string
+	MAC   string
+	Up    bool
+	Addrs []string // CIDR 形式
+}
+
+// SystemInfo 系统信息
+type SystemInfo struct {
+	OS             string
+	Kernel         string
+	Hostname       string
+	Virtualization string
+	CPU            CPUInfo
+	Memory         MemInfo
+	Load           LoadAvg
+	Uptime         time.Duration
+	Disks          []DiskUsage
+	Interfaces     []NetInterface
+	PrivateIPs     []string
+}
+
+// GetSystemInfo 获取系统信息（只读取本机，不访问网络；公网 IP 见 GetPublicIP）
+func GetSystemInfo() (*SystemInfo, error) {
+	info := &SystemInfo{OS: "Unknown"}
+
+	if distro, err := DetectDistro(); err == nil && distro.Pretty != "" {
+		info.OS = distro.Pretty
+	}
+	info.Hostname, _ = os.Hostname()
+
+	if data, err := os.ReadFile(filepath.Join(procRoot, "sys/kernel/osrelease")); err == nil {
+		info.Kernel = strings.TrimSpace(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(procRoot, "cpuinfo")); err == nil {
+		info.CPU = parseCPUInfo(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(procRoot, "meminfo")); err == nil {
+		info.Memory = parseMemInfo(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(procRoot, "loadavg")); err == nil {
+		info.Load, _ = parseLoadAvg(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(procRoot, "uptime")); err == nil {
+		info.Uptime, _ = parseUptime(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(procRoot, "mounts")); err == nil {
+		info.Disks = diskUsage(parseMounts(string(data)))
+	}
+
+	info.Interfaces = NetworkInterfaces()
+	info.PrivateIPs = privateIPs(info.Interfaces)
+	info.Virtualization = DetectVirtualization()
+
+	return info, nil
+}
+
+// parseCPUInfo 解析 /proc/cpuinfo
+func parseCPUInfo(content string) CPUInfo {
+	var info CPUInfo
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "processor":
+			info.Cores++
+		case "model name", "Model", "cpu model", "Hardware":
+			// x86 为 model name，ARM 为 Model/Hardware，MIPS 为 cpu model
+			if info.Model == "" {
+				info.Model = value
+			}
+		}
+	}
+	return info
+}
+
+// parseMemInfo 解析 /proc/meminfo
+func parseMemInfo(content string) MemInfo {
+	var info MemInfo
+	var free, buffers, cached uint64
+	hasAvailable := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		value *= 1024 // kB
+
+		switch strings.TrimSuffix(fields[0], ":") {
+		case "MemTotal":
+			info.Total = value
+		case "MemAvailable":
+			info.Available = value
+			hasAvailable = true
+		case "MemFree":
+			free = value
+		case "Buffers":
+			buffers = value
+		case "Cached":
+			cached = value
+		case "SwapTotal":
+			info.SwapTotal = value
+		case "SwapFree":
+			info.SwapFree = value
+		}
+	}
+
+	// 旧内核（< 3.14）没有 MemAvailable
+	if !hasAvailable {
+		info.Available = free + buffers + cached
+	}
+	return info
+}
+
+// parseLoadAvg 解析 /proc/loadavg
+func parseLoadAvg(content string) (LoadAvg, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 3 {
+		return LoadAvg{}, fmt.Errorf("invalid loadavg: %q", content)
+	}
+	var values [3]float64
+	for i := range values {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return LoadAvg{}, fmt.Errorf("invalid loadavg: %w", err)
+		}
+		values[i] = v
+	}
+	return LoadAvg{One: values[0], Five: values[1], Fifteen: values[2]}, nil
+}
+
+// parseUptime 解析 /proc/uptime
+func parseUptime(content string) (time.Duration, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("invalid uptime: %q", content)
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid uptime: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// parseMounts 解析 /proc/mounts，过滤虚拟文件系统与重复设备
+func parseMounts(content string) []DiskUsage {
+	var mounts []DiskUsage
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		device, mount, fsType := fields[0], unescapeMount(fields[1]), fields[2]
+		if pseudoFilesystems[fsType] || strings.HasPrefix(fsType, "fuse.") {
+			continue
+		}
+		// 同一设备（如 btrfs 子卷、bind mount）只计一次
+		if seen[device] {
+			continue
+		}
+		seen[device] = true
+		mounts = append(mounts, DiskUsage{Device: device, Mount: mount, FSType: fsType})
+	}
+	return mounts
+}
+
+// unescapeMount 还原 /proc/mounts 中的八进制转义（如 \040 表示空格）
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func diskUsage(mounts []DiskUsage) []DiskUsage {
+	disks := make([]DiskUsage, 0, len(mounts))
+	for _, d := range mounts {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(d.Mount, &st); err != nil || st.Blocks == 0 {
+			continue
+		}
+		bsize := uint64(st.Bsize)
+		d.Total = st.Blocks * bsize
+		d.Used = (st.Blocks - st.Bfree) * bsize
+		d.Avail = st.Bavail * bsize
+		disks = append(disks, d)
+	}
+	return disks
+}
+
+// NetworkInterfaces 返回非回环网络接口及其地址
+func NetworkInterfaces() []NetInterface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var result []NetInterface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ni := NetInterface{
+			Name: iface.Name,
+			MAC:  iface.HardwareAddr.String(),
+			Up:   iface.Flags&net.FlagUp != 0,
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				ni.Addrs = append(ni.Addrs, addr.String())
+			}
+		}
+		result = append(result, ni)
+	}
+	return result
+}
+
+// privateIPs 返回接口上的私有地址（RFC 1918 / ULA）
+func privateIPs(ifaces []NetInterface) []string {
+	var ips []string
+	for _, iface := range ifaces {
+		for _, addr := range iface.Addrs {
+			ip, _, err := net.ParseCIDR(addr)
+			if err != nil {
+				continue
+			}
+			if ip.IsPrivate() {
+				ips = append(ips, ip.String())
+			}
+		}
+	}
+	return ips
+}
+
+var (
+	virtOnce sync.Once
+	virtType string
+)
+
+// DetectVirtualization 检测虚拟化类型（结果缓存），物理机返回 "none"
+func DetectVirtualization() string {
+	virtOnce.Do(func() {
+		virtType = detectVirtualization()
+	})
+	return virtType
+}
+
+func detectVirtualization() string {
+	if CommandExists("systemd-detect-virt") {
+		// 未检测到虚拟化时输出 none 并返回非零
+		if res, _ := QueryCommand("systemd-detect-virt"); res != nil {
+			if v := strings.TrimSpace(res.Stdout); v != "" {
+				return v
+			}
+		}
+	}
+
+	if FileExists("/.dockerenv") {
+		return "docker"
+	}
+	if FileExists("/run/.containerenv") {
+		return "podman"
+	}
+
+	cpuinfo, _ := os.ReadFile(filepath.Join(procRoot, "cpuinfo"))
+	vendor, _ := os.ReadFile(filepath.Join(sysRoot, "class/dmi/id/sys_vendor"))
+	product, _ := os.ReadFile(filepath.Join(sysRoot, "class/dmi/id/product_name"))
+	return virtualizationFromHints(string(cpuinfo), string(vendor)+" "+string(product))
+}
+
+// virtualizationFromHints 根据 cpuinfo 的 hypervisor 标志与 DMI 厂商信息推断虚拟化类型
+func virtualizationFromHints(cpuinfo, dmi string) string {
+	dmi = strings.ToLower(dmi)
+	switch {
+	case strings.Contains(dmi, "kvm"), strings.Contains(dmi, "qemu"):
+		return "kvm"
+	case strings.Contains(dmi, "vmware"):
+		return "vmware"
+	case strings.Contains(dmi, "virtualbox"):
+		return "oracle"
+	case strings.Contains(dmi, "microsoft"):
+		return "microsoft"
+	case strings.Contains(dmi, "xen"):
+		return "xen"
+	case strings.Contains(dmi, "amazon ec2"):
+		return "amazon"
+	}
+
+	for _, line := range strings.Split(cpuinfo, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if ok && strings.TrimSpace(key) == "flags" {
+			for _, flag := range strings.Fields(value) {
+				if flag == "hypervisor" {
+					return "vm"
+				}
+			}
+			break
+		}
+	}
+	return "none"
+}
+
+// GetPublicIP 通过外部服务查询公网 IP
+func GetPublicIP(timeout time.Duration) (string, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(publicIPURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to query public IP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to query public IP: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return "", fmt.Errorf("failed to read public IP: %w", err)
+	}
+	ip := strings.TrimSpace(string(body))
+	if net.ParseIP(ip) == nil {
+		return "", fmt.Errorf("invalid public IP response: %q", ip)
+	}
+	return ip, nil
+}
+
+// FormatBytes 以 1024 为基数格式化字节数
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatUptime 格式化运行时长（如 3d 4h 12m）
+func FormatUptime(d time.Duration) string {
+	d = d.Truncate(time.Minute)
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,92 @@
+package system
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCPUInfo(t *testing.T) {
+	content := `processor	: 0
+vendor_id	: GenuineIntel
+model name	: Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz
+flags		: fpu vme hypervisor
+
+processor	: 1
+model name	: Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz
+`
+	info := parseCPUInfo(content)
+	assert.Equal(t, 2, info.Cores)
+	assert.Equal(t, "Intel(R) Xeon(R) CPU E5-2680 v4 @ 2.40GHz", info.Model)
+	assert.Equal(t, "vm", virtualizationFromHints(content, ""))
+	assert.Equal(t, "kvm", virtualizationFromHints(content, "QEMU Standard PC"))
+	assert.Equal(t, "none", virtualizationFromHints("flags : fpu vme", ""))
+}
+
+func TestParseMemInfo(t *testing.T) {
+	info := parseMemInfo(`MemTotal:        2000000 kB
+MemFree:          500000 kB
+MemAvailable:    1500000 kB
+SwapTotal:       1024000 kB
+SwapFree:        1024000 kB
+`)
+	assert.Equal(t, uint64(2000000*1024), info.Total)
+	assert.Equal(t, uint64(1500000*1024), info.Available)
+	assert.Equal(t, uint64(500000*1024), info.Used())
+	assert.Equal(t, uint64(1024000*1024), info.SwapTotal)
+
+	legacy := parseMemInfo("MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 50 kB\nCached: 200 kB\n")
+	assert.Equal(t, uint64(350*1024), legacy.Available)
+}
+
+func TestParseLoadAvgAndUptime(t *testing.T) {
+	load, err := parseLoadAvg("0.52 0.58 0.59 1/467 12345\n")
+	require.NoError(t, err)
+	assert.Equal(t, LoadAvg{One: 0.52, Five: 0.58, Fifteen: 0.59}, load)
+
+	uptime, err := parseUptime("273600.51 1000000.00\n")
+	require.NoError(t, err)
+	assert.Equal(t, "3d 4h 0m", FormatUptime(uptime))
+
+	_, err = parseLoadAvg("garbage")
+	assert.Error(t, err)
+}
+
+func TestParseMounts(t *testing.T) {
+	mounts := parseMounts(`/dev/vda1 / ext4 rw,relatime 0 0
+proc /proc proc rw 0 0
+tmpfs /run tmpfs rw 0 0
+/dev/vda15 /boot/efi vfat rw 0 0
+/dev/vda1 /var/lib/docker ext4 rw 0 0
+/dev/sdb1 /mnt/my\040disk xfs rw 0 0
+`)
+	require.Len(t, mounts, 3)
+	assert.Equal(t, "/", mounts[0].Mount)
+	assert.Equal(t, "/boot/efi", mounts[1].Mount)
+	assert.Equal(t, "/mnt/my disk", mounts[2].Mount)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "1.5 KiB", FormatBytes(1536))
+	assert.Equal(t, "2.0 GiB", FormatBytes(2<<30))
+}
+
+func TestGetPublicIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("203.0.113.7\n"))
+	}))
+	defer server.Close()
+
+	old := publicIPURL
+	publicIPURL = server.URL
+	t.Cleanup(func() { publicIPURL = old })
+
+	ip, err := GetPublicIP(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", ip)
+}
@@ -18,14 +18,30 @@ type UserInfo struct {
 	Shell    string
 }
 
-// GetUserFromPasswd 从 /etc/passwd 获取用户信息
+// GetUserFromPasswd 从 /etc/passwd 获取用户信息（设置根目录时读取目标系统）
 func GetUserFromPasswd(username string) (*UserInfo, error) {
-	file, err := os.Open("/etc/passwd")
+	users, err := ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("user not found: %s", username)
+}
+
+// ListUsers 列出 /etc/passwd 中的全部用户（设置根目录时读取目标系统）
+func ListUsers() ([]UserInfo, error) {
+	file, err := os.Open(RootPath("/etc/passwd"))
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
+	var users []UserInfo
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -38,28 +54,26 @@ func GetUserFromPasswd(username string) (*UserInfo, error) {
 			continue
 		}
 
-		if parts[0] == username {
-			uid, err := strconv.Atoi(parts[2])
-			if err != nil {
-				return nil, err
-			}
-
-			gid, err := strconv.Atoi(parts[3])
-			if err != nil {
-				return nil, err
-			}
-
-			return &UserInfo{
-				Username: username,
-				UID:      uid,
-				GID:      gid,
-				HomeDir:  parts[5],
-				Shell:    parts[6],
-			}, nil
+		// 跳过 uid/gid 无效的行
+		uid, err := strconv.Atoi(parts[2])
+		if err != nil {
+			continue
 		}
+		gid, err := strconv.Atoi(parts[3])
+		if err != nil {
+			continue
+		}
+
+		users = append(users, UserInfo{
+			Username: parts[0],
+			UID:      uid,
+			GID:      gid,
+			HomeDir:  parts[5],
+			Shell:    parts[6],
+		})
 	}
 
-	return nil, fmt.Errorf("user not found: %s", username)
+	return users, scanner.Err()
 }
 
 // GetUser 获取用户信息
@@ -70,7 +84,7 @@ func GetUser(username string) (*UserInfo, error) {
 
 // LookupUID 通过 UID 获取用户信息
 func LookupUID(uid int) (*UserInfo, error) {
-	file, err := os.Open("/etc/passwd")
+	file, err := os.Open(RootPath("/etc/passwd"))
 	if err != nil {
 		return nil, err
 	}
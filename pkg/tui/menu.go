@@ -13,6 +13,8 @@ type MenuItem struct {
 	Submenu *MenuModel
 	Next    func(parent MenuModel) tea.Model
 	Action  func() tea.Cmd
+	// Disabled 非空时菜单项置灰，选择时显示该说明（如缺少 root 权限）
+	Disabled string
 }
 
 // MenuModel 菜单模型
@@ -95,6 +97,10 @@ func (m MenuModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cursor < len(m.choices) {
 				choice := &m.choices[m.cursor]
 				m.selected = choice.ID
+				if choice.Disabled != "" {
+					m.status = choice.Disabled
+					return m, nil
+				}
 				if choice.Submenu != nil {
 					m.status = ""
 					choice.Submenu.parent = &m
@@ -145,7 +151,7 @@ func (m MenuModel) View() string {
 		if i == m.cursor {
 			choiceLabel = CursorStyle.Render("> " + choiceLabel)
 		} else {
-			if choice.Submenu == nil && choice.Next == nil && choice.Action == nil {
+			if choice.Disabled != "" || (choice.Submenu == nil && choice.Next == nil && choice.Action == nil) {
 				choiceLabel = DimStyle.Render("  " + choiceLabel)
 			} else {
 				choiceLabel = NormalStyle.Render("  " + choiceLabel)
@@ -34,6 +34,17 @@ func TestMenuModel_EnterOnNextSwitchesModel(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestMenuModel_EnterOnDisabledShowsReason(t *testing.T) {
+	m := NewMenu("t", "", []MenuItem{
+		{ID: "a", Label: "A", Disabled: "needs root", Next: func(parent MenuModel) tea.Model { return stubModel{} }},
+	})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m2, ok := updated.(MenuModel)
+	assert.True(t, ok)
+	assert.Contains(t, m2.View(), "needs root")
+}
+
 func TestMenuModel_StatusClearedOnNavigation(t *testing.T) {
 	m := NewMenu("t", "", []MenuItem{
 		{ID: "a", Label: "A"},